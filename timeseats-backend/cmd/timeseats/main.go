@@ -1,14 +1,21 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"log"
-	"os"
 
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/api"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/config"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/infrastructure/database"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/infrastructure/escpos"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/infrastructure/export"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/infrastructure/paypay"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/infrastructure/pdf"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/infrastructure/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/infrastructure/square"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -20,7 +27,16 @@ import (
 // @schemes http https
 // @produce application/json
 // @consume application/json
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description "Bearer " followed by the token returned by /auth/login
 func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	if err := database.Init(); err != nil {
 		log.Fatal(err)
 	}
@@ -34,13 +50,92 @@ func main() {
 	db := database.GetDB()
 
 	productRepo := repositories.NewProductRepository(db)
+	categoryRepo := repositories.NewCategoryRepository(db)
+	modifierGroupRepo := repositories.NewModifierGroupRepository(db)
 	salesSlotRepo := repositories.NewSalesSlotRepository(db)
 	productInventoryRepo := repositories.NewProductInventoryRepository(db)
 	orderRepo := repositories.NewOrderRepository(db)
+	paymentRepo := repositories.NewPaymentRepository(db)
+	ticketSequenceRepo := repositories.NewTicketSequenceRepository(db)
+	staffRepo := repositories.NewStaffRepository(db)
+	staffSessionRepo := repositories.NewStaffSessionRepository(db)
+	terminalRepo := repositories.NewTerminalRepository(db)
+	auditLogRepo := repositories.NewAuditLogRepository(db)
+	reportRepo := repositories.NewReportRepository(db)
+	drawerSessionRepo := repositories.NewDrawerSessionRepository(db)
+	refundRepo := repositories.NewRefundRepository(db)
+	discountRepo := repositories.NewDiscountRepository(db)
+	receiptRepo := repositories.NewReceiptRepository(db)
+	printerRepo := repositories.NewPrinterRepository(db)
+	qrCodeRepo := repositories.NewQRCodeRepository(db)
+	transactor := repositories.NewTransactor(db)
+
+	eventBus := events.NewBus(cfg.EventHistorySize)
+	spooler := escpos.NewSpooler(cfg.PrintRetryInterval)
+	defer spooler.Close()
+	clock := services.NewSystemClock()
+	ticketNumbers := services.NewTicketNumberGenerator(ticketSequenceRepo, orderRepo, cfg.TicketNumberDigits)
 
-	productService := services.NewProductService(productRepo)
-	salesSlotService := services.NewSalesSlotService(salesSlotRepo, productInventoryRepo, productRepo)
-	orderService := services.NewOrderService(orderRepo, salesSlotRepo, productInventoryRepo, productRepo)
+	auditLogService := services.NewAuditLogService(auditLogRepo, clock)
+	productService := services.NewProductService(productRepo, categoryRepo, transactor, auditLogService)
+	categoryService := services.NewCategoryService(categoryRepo, productRepo, transactor, auditLogService)
+	modifierGroupService := services.NewModifierGroupService(modifierGroupRepo, productRepo, transactor, auditLogService)
+	salesSlotService := services.NewSalesSlotService(salesSlotRepo, productInventoryRepo, productRepo, transactor, eventBus, auditLogService)
+	orderService := services.NewOrderService(orderRepo, paymentRepo, salesSlotRepo, productInventoryRepo, productRepo, transactor, eventBus, clock, ticketNumbers, auditLogService)
+	pickupBoardService := services.NewPickupBoardService(orderRepo, salesSlotRepo, clock)
+	staffService := services.NewStaffService(staffRepo, staffSessionRepo, transactor, auditLogService)
+	authService := services.NewAuthService(staffRepo, staffSessionRepo, staffService, clock, cfg.SessionTTL)
+	terminalService := services.NewTerminalService(terminalRepo, transactor, clock, auditLogService)
+	reportService := services.NewReportService(reportRepo)
+	exportService := services.NewExportService(orderRepo, productInventoryRepo, export.Formats())
+	refundService := services.NewRefundService(orderRepo, paymentRepo, refundRepo, productInventoryRepo, transactor, eventBus, clock, auditLogService)
+	drawerService := services.NewDrawerService(drawerSessionRepo, paymentRepo, refundRepo, transactor, clock, auditLogService)
+	discountService := services.NewDiscountService(discountRepo, orderRepo, productRepo, transactor, eventBus, clock, auditLogService)
+	receiptService := services.NewReceiptService(receiptRepo, orderRepo, transactor, clock, auditLogService, services.ReceiptIssuer{
+		Name:               cfg.ReceiptIssuerName,
+		RegistrationNumber: cfg.ReceiptRegistrationNumber,
+	}, pdf.WriteReceipt)
+	var gateway services.PaymentGateway
+	if cfg.SquareAccessToken != "" {
+		gateway = square.NewClient(square.Config{
+			BaseURL:             cfg.SquareBaseURL,
+			AccessToken:         cfg.SquareAccessToken,
+			LocationID:          cfg.SquareLocationID,
+			WebhookSignatureKey: cfg.SquareWebhookSignatureKey,
+			WebhookURL:          cfg.SquareWebhookURL,
+		})
+	}
+	var qrGateway services.QRCodeGateway
+	if cfg.PayPayAPIKey != "" {
+		qrGateway = paypay.NewClient(paypay.Config{
+			BaseURL:    cfg.PayPayBaseURL,
+			APIKey:     cfg.PayPayAPIKey,
+			APISecret:  cfg.PayPayAPISecret,
+			MerchantID: cfg.PayPayMerchantID,
+		})
+	}
+	paymentService := services.NewPaymentService(orderRepo, paymentRepo, qrCodeRepo, gateway, qrGateway, transactor, eventBus, clock, auditLogService)
+	printerService := services.NewPrinterService(printerRepo, orderRepo, categoryRepo, terminalRepo, receiptService, spooler, transactor, clock, auditLogService)
+
+	created, err := authService.EnsureAdmin(context.Background(), cfg.AdminUsername, cfg.AdminPassword)
+	if err != nil {
+		log.Fatalf("failed to create admin account: %v", err)
+	}
+	if created {
+		log.Printf("Created admin account %q", cfg.AdminUsername)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if cfg.ReservationTTL > 0 {
+		sweeper := services.NewReservationSweeper(orderService, cfg.ReservationTTL, cfg.ReservationSweepInterval, clock)
+		go sweeper.Run(ctx)
+	}
+	go services.NewOrderTicketPrinter(eventBus, printerService).Run(ctx)
+	if qrGateway != nil {
+		go services.NewQRCodePoller(paymentService, cfg.PayPayPollInterval).Run(ctx)
+	}
 
 	app := fiber.New(fiber.Config{
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
@@ -56,14 +151,9 @@ func main() {
 		Prefork: false,
 	})
 
-	api.SetupRouter(app, productService, salesSlotService, orderService)
-
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
+	api.SetupRouter(app, productService, categoryService, modifierGroupService, salesSlotService, orderService, pickupBoardService, authService, staffService, terminalService, auditLogService, reportService, exportService, drawerService, refundService, discountService, receiptService, printerService, paymentService, eventBus)
 
-	if err := app.Listen(":" + port); err != nil {
+	if err := app.Listen(":" + cfg.Port); err != nil {
 		log.Fatal(err)
 	}
 }
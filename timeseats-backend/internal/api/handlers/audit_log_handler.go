@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditLogHandler struct {
+	auditLogService services.AuditLogService
+}
+
+func NewAuditLogHandler(auditLogService services.AuditLogService) *AuditLogHandler {
+	return &AuditLogHandler{auditLogService: auditLogService}
+}
+
+// @Summary Search the audit log
+// @Description Entries are returned newest first, at most 500 at a time.
+// @Tags audit-logs
+// @Security BearerAuth
+// @Produce json
+// @Param entityType query string false "Entity type, e.g. product, sales_slot, inventory, order, staff or terminal"
+// @Param entityId query string false "Entity ID"
+// @Param actorId query string false "Staff ID of the actor"
+// @Param from query string false "Start of the time range (RFC3339, inclusive)"
+// @Param to query string false "End of the time range (RFC3339, exclusive)"
+// @Param limit query int false "Maximum number of entries"
+// @Success 200 {array} AuditLogResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /audit-logs [get]
+func (h *AuditLogHandler) Search(c *fiber.Ctx) error {
+	filter := repositories.AuditLogFilter{
+		EntityType: c.Query("entityType"),
+		EntityID:   types.ID(c.Query("entityId")),
+		ActorID:    types.ID(c.Query("actorId")),
+		Limit:      c.QueryInt("limit"),
+	}
+
+	var err error
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		return err
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		return err
+	}
+
+	entries, err := h.auditLogService.Search(c.UserContext(), filter)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(NewAuditLogResponseList(entries))
+}
+
+func parseTimeQuery(c *fiber.Ctx, key string) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fiber.NewError(fiber.StatusBadRequest, "Invalid "+key+" time format")
+	}
+	return t, nil
+}
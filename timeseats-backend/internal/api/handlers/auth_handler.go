@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/api/middleware"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuthHandler struct {
+	authService services.AuthService
+}
+
+func NewAuthHandler(authService services.AuthService) *AuthHandler {
+	return &AuthHandler{authService: authService}
+}
+
+// @Summary Log in as a staff member
+// @Description Returns a bearer token for the Authorization header.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body LoginRequest true "Credentials"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /auth/login [post]
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	var req LoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	session, err := h.authService.Login(c.UserContext(), req.Username, req.Password)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(LoginResponse{
+		Token:     session.Token,
+		ExpiresAt: session.ExpiresAt,
+		Staff:     NewStaffResponse(session.Staff),
+	})
+}
+
+// @Summary Log out
+// @Tags auth
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	if err := h.authService.Logout(c.UserContext(), middleware.BearerToken(c)); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// @Summary Get the logged in staff member
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} StaffResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /auth/me [get]
+func (h *AuthHandler) Me(c *fiber.Ctx) error {
+	staff, ok := services.ActorFromContext(c.UserContext())
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "Authentication required")
+	}
+
+	return c.JSON(NewStaffResponse(staff))
+}
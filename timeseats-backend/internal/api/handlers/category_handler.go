@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CategoryHandler struct {
+	categoryService services.CategoryService
+}
+
+func NewCategoryHandler(categoryService services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{categoryService: categoryService}
+}
+
+// @Summary Create a product category
+// @Tags categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param category body CategoryRequest true "Category information"
+// @Success 201 {object} CategoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /categories [post]
+func (h *CategoryHandler) Create(c *fiber.Ctx) error {
+	var req CategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	category, err := h.categoryService.CreateCategory(c.UserContext(), req.Name, req.SortOrder)
+	if err != nil {
+		return categoryError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(NewCategoryResponse(category))
+}
+
+// @Summary Get all product categories
+// @Description Categories are in menu order.
+// @Tags categories
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} CategoryResponse
+// @Router /categories [get]
+func (h *CategoryHandler) GetAll(c *fiber.Ctx) error {
+	categories, err := h.categoryService.GetAllCategories(c.UserContext())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(NewCategoryResponseList(categories))
+}
+
+// @Summary Get a product category by ID
+// @Tags categories
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} CategoryResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /categories/{id} [get]
+func (h *CategoryHandler) GetByID(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	category, err := h.categoryService.GetCategory(c.UserContext(), types.ID(id))
+	if err != nil {
+		return categoryError(err)
+	}
+
+	return c.JSON(NewCategoryResponse(category))
+}
+
+// @Summary Update a product category
+// @Tags categories
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param category body CategoryRequest true "Category information"
+// @Success 200 {object} CategoryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /categories/{id} [put]
+func (h *CategoryHandler) Update(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	var req CategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	category, err := h.categoryService.UpdateCategory(c.UserContext(), types.ID(id), req.Name, req.SortOrder)
+	if err != nil {
+		return categoryError(err)
+	}
+
+	return c.JSON(NewCategoryResponse(category))
+}
+
+// @Summary Delete a product category
+// @Description Products in the category become uncategorized.
+// @Tags categories
+// @Security BearerAuth
+// @Param id path string true "Category ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Router /categories/{id} [delete]
+func (h *CategoryHandler) Delete(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	if err := h.categoryService.DeleteCategory(c.UserContext(), types.ID(id)); err != nil {
+		return categoryError(err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func categoryError(err error) error {
+	var notFound *repositories.ErrNotFound
+	if errors.As(err, &notFound) {
+		return fiber.NewError(fiber.StatusNotFound, "Category not found")
+	}
+	var serviceErr *services.ServiceError
+	if errors.As(err, &serviceErr) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
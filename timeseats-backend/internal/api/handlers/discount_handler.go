@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type DiscountHandler struct {
+	discountService services.DiscountService
+}
+
+func NewDiscountHandler(discountService services.DiscountService) *DiscountHandler {
+	return &DiscountHandler{discountService: discountService}
+}
+
+// @Summary Create a discount or coupon
+// @Description A discount with a code is a coupon the customer hands in; one without is picked by the cashier, e.g. a staff discount.
+// @Tags discounts
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param discount body DiscountRequest true "Discount information"
+// @Success 201 {object} DiscountResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /discounts [post]
+func (h *DiscountHandler) Create(c *fiber.Ctx) error {
+	var req DiscountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	input, ok := req.toInput()
+	if !ok {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid discount type")
+	}
+
+	discount, err := h.discountService.CreateDiscount(c.UserContext(), input)
+	if err != nil {
+		return discountError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(NewDiscountResponse(discount))
+}
+
+// @Summary Get all discounts and coupons
+// @Tags discounts
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} DiscountResponse
+// @Router /discounts [get]
+func (h *DiscountHandler) GetAll(c *fiber.Ctx) error {
+	discounts, err := h.discountService.GetAllDiscounts(c.UserContext())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(NewDiscountResponseList(discounts))
+}
+
+// @Summary Get a discount by ID
+// @Tags discounts
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Discount ID"
+// @Success 200 {object} DiscountResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /discounts/{id} [get]
+func (h *DiscountHandler) GetByID(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	discount, err := h.discountService.GetDiscount(c.UserContext(), types.ID(id))
+	if err != nil {
+		return discountError(err)
+	}
+
+	return c.JSON(NewDiscountResponse(discount))
+}
+
+// @Summary Update a discount
+// @Description Orders already discounted keep the terms they were given.
+// @Tags discounts
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Discount ID"
+// @Param discount body DiscountRequest true "Discount information"
+// @Success 200 {object} DiscountResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /discounts/{id} [put]
+func (h *DiscountHandler) Update(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	var req DiscountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	input, ok := req.toInput()
+	if !ok {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid discount type")
+	}
+
+	discount, err := h.discountService.UpdateDiscount(c.UserContext(), types.ID(id), input)
+	if err != nil {
+		return discountError(err)
+	}
+
+	return c.JSON(NewDiscountResponse(discount))
+}
+
+// @Summary Delete a discount
+// @Tags discounts
+// @Security BearerAuth
+// @Param id path string true "Discount ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Router /discounts/{id} [delete]
+func (h *DiscountHandler) Delete(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	if err := h.discountService.DeleteDiscount(c.UserContext(), types.ID(id)); err != nil {
+		return discountError(err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// @Summary Apply a discount or coupon to an order
+// @Description The order must be reserved and not paid yet.
+// @Tags orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param discount body ApplyDiscountRequest true "Discount ID or coupon code"
+// @Success 200 {object} OrderResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /orders/{id}/discounts [post]
+func (h *DiscountHandler) Apply(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	var req ApplyDiscountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if req.DiscountID == "" && req.Code == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "discountId or code is required")
+	}
+
+	order, err := h.discountService.ApplyDiscount(c.UserContext(), types.ID(id), types.ID(req.DiscountID), req.Code)
+	if err != nil {
+		return discountError(err)
+	}
+
+	return c.JSON(NewOrderResponse(order))
+}
+
+// @Summary Remove a discount from an order
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param discountId path string true "Order discount line ID"
+// @Success 200 {object} OrderResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /orders/{id}/discounts/{discountId} [delete]
+func (h *DiscountHandler) Remove(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	discountID, err := url.PathUnescape(c.Params("discountId"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	order, err := h.discountService.RemoveDiscount(c.UserContext(), types.ID(id), types.ID(discountID))
+	if err != nil {
+		return discountError(err)
+	}
+
+	return c.JSON(NewOrderResponse(order))
+}
+
+// @Summary Override the unit price of an order line
+// @Description Needs a manager, who is recorded as approving the override, and a reason.
+// @Tags orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param itemId path string true "Order item ID"
+// @Param override body PriceOverrideRequest true "New unit price and reason"
+// @Success 200 {object} OrderResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /orders/{id}/items/{itemId}/price [put]
+func (h *DiscountHandler) OverridePrice(c *fiber.Ctx) error {
+	id, itemID, err := orderItemParams(c)
+	if err != nil {
+		return err
+	}
+	var req PriceOverrideRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	order, err := h.discountService.OverridePrice(c.UserContext(), id, itemID, req.Price, req.Reason)
+	if err != nil {
+		return discountError(err)
+	}
+
+	return c.JSON(NewOrderResponse(order))
+}
+
+func discountError(err error) error {
+	var notFound *repositories.ErrNotFound
+	switch {
+	case errors.As(err, &notFound) && notFound.Entity == "Product":
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.As(err, &notFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrDiscountNotAllowed):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	var serviceErr *services.ServiceError
+	if errors.As(err, &serviceErr) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
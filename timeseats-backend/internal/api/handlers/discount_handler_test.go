@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockDiscountService struct {
+	code string
+}
+
+func (s *mockDiscountService) CreateDiscount(ctx context.Context, input services.DiscountInput) (*models.Discount, error) {
+	return nil, nil
+}
+
+func (s *mockDiscountService) GetDiscount(ctx context.Context, id types.ID) (*models.Discount, error) {
+	return nil, nil
+}
+
+func (s *mockDiscountService) GetAllDiscounts(ctx context.Context) ([]models.Discount, error) {
+	return nil, nil
+}
+
+func (s *mockDiscountService) UpdateDiscount(ctx context.Context, id types.ID, input services.DiscountInput) (*models.Discount, error) {
+	return nil, nil
+}
+
+func (s *mockDiscountService) DeleteDiscount(ctx context.Context, id types.ID) error {
+	return nil
+}
+
+func (s *mockDiscountService) RemoveDiscount(ctx context.Context, orderID, orderDiscountID types.ID) (*models.Order, error) {
+	return nil, nil
+}
+
+func (s *mockDiscountService) OverridePrice(ctx context.Context, orderID, itemID types.ID, price int, reason string) (*models.Order, error) {
+	return nil, nil
+}
+
+func (s *mockDiscountService) ApplyDiscount(ctx context.Context, orderID, discountID types.ID, code string) (*models.Order, error) {
+	if orderID == "confirmed" {
+		return nil, services.ErrDiscountNotAllowed
+	}
+	if code != "SEIKO100" {
+		return nil, services.ErrInvalidCoupon
+	}
+	s.code = code
+	discountID = "discount-1"
+	return &models.Order{
+		ID:             orderID,
+		Status:         types.RESERVED,
+		TotalAmount:    700,
+		DiscountAmount: 100,
+		Discounts: []models.OrderDiscount{
+			{ID: "line-1", DiscountID: &discountID, Type: types.FIXED_AMOUNT, Name: "生徒会クーポン", Code: &code, Value: 100, Amount: 100},
+		},
+	}, nil
+}
+
+func TestDiscountHandler_Apply(t *testing.T) {
+	app := fiber.New()
+	mockService := &mockDiscountService{}
+	handler := NewDiscountHandler(mockService)
+
+	app.Post("/orders/:id/discounts", handler.Apply)
+
+	apply := func(orderID string, req ApplyDiscountRequest) int {
+		body, _ := json.Marshal(req)
+		httpReq := httptest.NewRequest("POST", "/orders/"+orderID+"/discounts", bytes.NewReader(body))
+		httpReq.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(httpReq)
+		if err != nil {
+			t.Fatalf("Failed to test request: %v", err)
+		}
+		if resp.StatusCode == fiber.StatusOK {
+			var response OrderResponse
+			json.NewDecoder(resp.Body).Decode(&response)
+			if response.Subtotal != 800 || response.DiscountAmount != 100 || len(response.Discounts) != 1 {
+				t.Errorf("Unexpected order: %+v", response)
+			}
+		}
+		return resp.StatusCode
+	}
+
+	if status := apply("order-1", ApplyDiscountRequest{Code: "SEIKO100"}); status != fiber.StatusOK {
+		t.Errorf("Expected status code %d, got %d", fiber.StatusOK, status)
+	}
+	if status := apply("order-1", ApplyDiscountRequest{}); status != fiber.StatusBadRequest {
+		t.Errorf("Expected status code %d without a discount, got %d", fiber.StatusBadRequest, status)
+	}
+	if status := apply("order-1", ApplyDiscountRequest{Code: "NOPE"}); status != fiber.StatusBadRequest {
+		t.Errorf("Expected status code %d for an unknown coupon, got %d", fiber.StatusBadRequest, status)
+	}
+	if status := apply("confirmed", ApplyDiscountRequest{Code: "SEIKO100"}); status != fiber.StatusConflict {
+		t.Errorf("Expected status code %d, got %d", fiber.StatusConflict, status)
+	}
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"html/template"
+	"net/url"
+	"strconv"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:embed templates/drawer_report.html
+var drawerReportFS embed.FS
+
+var drawerReportTemplate = template.Must(template.New("drawer_report.html").Funcs(template.FuncMap{
+	"yen":   formatYen,
+	"mul":   func(a, b int) int { return a * b },
+	"deref": func(p *int) int { return *p },
+}).ParseFS(drawerReportFS, "templates/drawer_report.html"))
+
+type DrawerHandler struct {
+	drawerService services.DrawerService
+}
+
+func NewDrawerHandler(drawerService services.DrawerService) *DrawerHandler {
+	return &DrawerHandler{drawerService: drawerService}
+}
+
+// @Summary Open a cash drawer session
+// @Description Opens a session on the calling terminal for the logged-in cashier.
+// @Tags drawer-sessions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param session body OpenDrawerRequest true "Starting float"
+// @Success 201 {object} DrawerSessionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /drawer-sessions [post]
+func (h *DrawerHandler) Open(c *fiber.Ctx) error {
+	var req OpenDrawerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	session, err := h.drawerService.OpenSession(c.UserContext(), req.OpeningFloat)
+	if err != nil {
+		return drawerError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(NewDrawerSessionResponse(session))
+}
+
+// @Summary Get drawer sessions
+// @Tags drawer-sessions
+// @Security BearerAuth
+// @Produce json
+// @Param terminalId query string false "Terminal ID"
+// @Success 200 {array} DrawerSessionResponse
+// @Router /drawer-sessions [get]
+func (h *DrawerHandler) GetAll(c *fiber.Ctx) error {
+	sessions, err := h.drawerService.GetSessions(c.UserContext(), types.ID(c.Query("terminalId")))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(NewDrawerSessionResponseList(sessions))
+}
+
+// @Summary Get the open drawer session of the calling terminal
+// @Tags drawer-sessions
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} DrawerReportResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /drawer-sessions/current [get]
+func (h *DrawerHandler) GetCurrent(c *fiber.Ctx) error {
+	report, err := h.drawerService.GetCurrentSession(c.UserContext())
+	if err != nil {
+		return drawerError(err)
+	}
+
+	return c.JSON(NewDrawerReportResponse(report))
+}
+
+// @Summary Get a drawer session with its reconciliation
+// @Tags drawer-sessions
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Drawer session ID"
+// @Success 200 {object} DrawerReportResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /drawer-sessions/{id} [get]
+func (h *DrawerHandler) GetByID(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	report, err := h.drawerService.GetSession(c.UserContext(), types.ID(id))
+	if err != nil {
+		return drawerError(err)
+	}
+
+	return c.JSON(NewDrawerReportResponse(report))
+}
+
+// @Summary Record a pay-in or pay-out
+// @Tags drawer-sessions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Drawer session ID"
+// @Param movement body DrawerMovementRequest true "Cash movement"
+// @Success 201 {object} DrawerMovementResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /drawer-sessions/{id}/movements [post]
+func (h *DrawerHandler) RecordMovement(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	var req DrawerMovementRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	movementType, ok := types.ParseDrawerMovementType(req.Type)
+	if !ok {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid movement type")
+	}
+
+	movement, err := h.drawerService.RecordMovement(c.UserContext(), types.ID(id), movementType, req.Amount, req.Reason)
+	if err != nil {
+		return drawerError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(NewDrawerMovementResponse(movement))
+}
+
+// @Summary Close a drawer session
+// @Description Settles the session with the counted cash per yen denomination and reports any surplus or shortage.
+// @Tags drawer-sessions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Drawer session ID"
+// @Param count body CloseDrawerRequest true "Counted cash"
+// @Success 200 {object} DrawerReportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /drawer-sessions/{id}/close [put]
+func (h *DrawerHandler) Close(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	var req CloseDrawerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	counts := make(map[int]int, len(req.Counts))
+	for _, count := range req.Counts {
+		counts[count.Denomination] += count.Quantity
+	}
+
+	report, err := h.drawerService.CloseSession(c.UserContext(), types.ID(id), counts, req.Note)
+	if err != nil {
+		return drawerError(err)
+	}
+
+	return c.JSON(NewDrawerReportResponse(report))
+}
+
+// @Summary Get a printable drawer session summary
+// @Description Opened in a browser, the login token may be passed in the token query parameter.
+// @Tags drawer-sessions
+// @Security BearerAuth
+// @Produce html
+// @Param id path string true "Drawer session ID"
+// @Success 200 {string} string "HTML page"
+// @Failure 404 {object} ErrorResponse
+// @Router /drawer-sessions/{id}/report [get]
+func (h *DrawerHandler) Report(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	report, err := h.drawerService.GetSession(c.UserContext(), types.ID(id))
+	if err != nil {
+		return drawerError(err)
+	}
+
+	terminalName := string(report.Session.TerminalID)
+	if report.Session.Terminal != nil {
+		terminalName = report.Session.Terminal.Name
+	}
+
+	var buf bytes.Buffer
+	if err := drawerReportTemplate.Execute(&buf, struct {
+		*services.DrawerReport
+		TerminalName string
+	}{report, terminalName}); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Type("html", "utf-8")
+	return c.Send(buf.Bytes())
+}
+
+func drawerError(err error) error {
+	var notFound *repositories.ErrNotFound
+	switch {
+	case errors.As(err, &notFound):
+		return fiber.NewError(fiber.StatusNotFound, "Drawer session not found")
+	case errors.Is(err, services.ErrDrawerAlreadyOpen), errors.Is(err, services.ErrDrawerClosed):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrDrawerOtherTerminal):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	}
+	var serviceErr *services.ServiceError
+	if errors.As(err, &serviceErr) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
+
+// formatYen formats an amount as "¥1,234".
+func formatYen(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.Itoa(amount)
+	var buf bytes.Buffer
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteRune(d)
+	}
+	return sign + "¥" + buf.String()
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockDrawerService struct {
+	report *services.DrawerReport
+}
+
+func (s *mockDrawerService) OpenSession(ctx context.Context, openingFloat int) (*models.DrawerSession, error) {
+	return &models.DrawerSession{ID: "drawer-1", OpeningFloat: openingFloat}, nil
+}
+
+func (s *mockDrawerService) GetCurrentSession(ctx context.Context) (*services.DrawerReport, error) {
+	return s.report, nil
+}
+
+func (s *mockDrawerService) GetSession(ctx context.Context, id types.ID) (*services.DrawerReport, error) {
+	if s.report == nil || s.report.Session.ID != id {
+		return nil, repositories.NewErrNotFound("DrawerSession", id)
+	}
+	return s.report, nil
+}
+
+func (s *mockDrawerService) GetSessions(ctx context.Context, terminalID types.ID) ([]models.DrawerSession, error) {
+	return nil, nil
+}
+
+func (s *mockDrawerService) RecordMovement(ctx context.Context, sessionID types.ID, movementType types.DrawerMovementType, amount int, reason string) (*models.DrawerMovement, error) {
+	return &models.DrawerMovement{DrawerSessionID: sessionID, Type: movementType, Amount: amount, Reason: reason}, nil
+}
+
+func (s *mockDrawerService) CloseSession(ctx context.Context, sessionID types.ID, counts map[int]int, note string) (*services.DrawerReport, error) {
+	return nil, services.ErrDrawerClosed
+}
+
+func TestDrawerHandler_Report(t *testing.T) {
+	counted, difference := 12000, -1000
+	closedAt := time.Now()
+	service := &mockDrawerService{report: &services.DrawerReport{
+		Session: &models.DrawerSession{
+			ID:            "drawer-1",
+			Terminal:      &models.Terminal{Name: "レジ1"},
+			OpeningFloat:  10000,
+			OpenedAt:      closedAt.Add(-time.Hour),
+			ClosedAt:      &closedAt,
+			CountedAmount: &counted,
+			Counts:        []models.DrawerCount{{Denomination: 1000, Quantity: 2}},
+		},
+		CashSales:  3000,
+		Expected:   13000,
+		Counted:    &counted,
+		Difference: &difference,
+	}}
+	app := fiber.New()
+	app.Get("/drawer-sessions/:id/report", NewDrawerHandler(service).Report)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/drawer-sessions/drawer-1/report", nil))
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", fiber.StatusOK, resp.StatusCode)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	for _, want := range []string{"レジ1", "¥13,000", "¥12,000", "不足", "-¥1,000", "¥2,000"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected the report to contain %q", want)
+		}
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/drawer-sessions/unknown/report", nil))
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", fiber.StatusNotFound, resp.StatusCode)
+	}
+}
+
+func TestDrawerHandler_CloseClosedSession(t *testing.T) {
+	app := fiber.New()
+	app.Put("/drawer-sessions/:id/close", NewDrawerHandler(&mockDrawerService{}).Close)
+
+	req := httptest.NewRequest("PUT", "/drawer-sessions/drawer-1/close", strings.NewReader(`{"counts":[{"denomination":1000,"quantity":1}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusConflict {
+		t.Errorf("Expected status code %d, got %d", fiber.StatusConflict, resp.StatusCode)
+	}
+}
+
+func TestFormatYen(t *testing.T) {
+	for amount, want := range map[int]string{0: "¥0", 999: "¥999", 1000: "¥1,000", 1234567: "¥1,234,567", -500: "-¥500"} {
+		if got := formatYen(amount); got != want {
+			t.Errorf("formatYen(%d) = %q, want %q", amount, got, want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+const keepAliveInterval = 15 * time.Second
+
+type EventHandler struct {
+	bus *events.Bus
+}
+
+func NewEventHandler(bus *events.Bus) *EventHandler {
+	return &EventHandler{bus: bus}
+}
+
+// @Summary Stream order and inventory events (Server-Sent Events)
+// @Description Reconnecting clients resume with the Last-Event-ID header or the lastEventId query parameter.
+// @Tags events
+// @Security BearerAuth
+// @Produce text/event-stream
+// @Param salesSlotId query string false "Sales Slot ID"
+// @Param lastEventId query int false "Last received event ID"
+// @Success 200 {object} events.Event
+// @Failure 400 {object} ErrorResponse
+// @Router /events [get]
+func (h *EventHandler) Stream(c *fiber.Ctx) error {
+	lastEventID := c.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("lastEventId")
+	}
+	filter, after, err := parseSubscription(c.Query("salesSlotId"), lastEventID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	sub := h.bus.Subscribe(filter, after)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
+
+		ticker := time.NewTicker(keepAliveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case event, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				if err := writeServerSentEvent(w, event); err != nil {
+					return
+				}
+			case <-ticker.C:
+				if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+					return
+				}
+			}
+			// Flush fails once the client has gone away.
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// @Summary Upgrade to a WebSocket event stream
+// @Description Each message is a JSON encoded event. Use the lastEventId query parameter to resume.
+// @Tags events
+// @Security BearerAuth
+// @Param salesSlotId query string false "Sales Slot ID"
+// @Param lastEventId query int false "Last received event ID"
+// @Success 101 "Switching Protocols"
+// @Failure 426 {object} ErrorResponse
+// @Router /events/ws [get]
+func (h *EventHandler) Upgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+	if _, _, err := parseSubscription(c.Query("salesSlotId"), c.Query("lastEventId")); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return c.Next()
+}
+
+func (h *EventHandler) WebSocket(conn *websocket.Conn) {
+	filter, after, _ := parseSubscription(conn.Query("salesSlotId"), conn.Query("lastEventId"))
+	sub := h.bus.Subscribe(filter, after)
+	defer sub.Close()
+
+	// The stream is one-way; reading only detects the client closing it.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func parseSubscription(salesSlotID, lastEventID string) (events.Filter, uint64, error) {
+	filter := events.Filter{SalesSlotID: types.ID(salesSlotID)}
+	if lastEventID == "" {
+		return filter, 0, nil
+	}
+
+	after, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil {
+		return filter, 0, fmt.Errorf("Invalid last event ID")
+	}
+	return filter, after, nil
+}
+
+func writeServerSentEvent(w io.Writer, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	fasthttpws "github.com/fasthttp/websocket"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+func startEventServer(t *testing.T) (string, *events.Bus) {
+	t.Helper()
+	app := fiber.New()
+	bus := events.NewBus(10)
+	handler := NewEventHandler(bus)
+
+	app.Get("/events", handler.Stream)
+	app.Get("/events/ws", handler.Upgrade, websocket.New(handler.WebSocket))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go app.Listener(ln)
+	t.Cleanup(func() {
+		app.ShutdownWithTimeout(time.Second)
+	})
+
+	return ln.Addr().String(), bus
+}
+
+func TestEventHandler_Stream(t *testing.T) {
+	addr, bus := startEventServer(t)
+
+	bus.Publish(events.Event{Type: events.OrderCreated, SalesSlotID: types.ID("slot1")})
+	bus.Publish(events.Event{Type: events.OrderCreated, SalesSlotID: types.ID("slot2")})
+	bus.Publish(events.Event{Type: events.OrderPaid, SalesSlotID: types.ID("slot1")})
+
+	req, _ := http.NewRequest("GET", "http://"+addr+"/events?salesSlotId=slot1", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected content type text/event-stream, got %s", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read stream: %v", err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) != 3 {
+		t.Fatalf("Expected id, event and data lines, got %v", lines)
+	}
+	if lines[0] != "id: 3" {
+		t.Errorf("Expected replay to resume at event 3, got %q", lines[0])
+	}
+	if lines[1] != "event: order.paid" {
+		t.Errorf("Expected order.paid event, got %q", lines[1])
+	}
+	if !strings.Contains(lines[2], `"salesSlotId":"slot1"`) {
+		t.Errorf("Expected data for slot1, got %q", lines[2])
+	}
+}
+
+func TestEventHandler_WebSocket(t *testing.T) {
+	addr, bus := startEventServer(t)
+
+	bus.Publish(events.Event{Type: events.OrderCreated, SalesSlotID: types.ID("slot1")})
+	bus.Publish(events.Event{Type: events.OrderConfirmed, SalesSlotID: types.ID("slot1")})
+
+	conn, _, err := fasthttpws.DefaultDialer.Dial("ws://"+addr+"/events/ws?salesSlotId=slot1&lastEventId=1", nil)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var replayed events.Event
+	if err := conn.ReadJSON(&replayed); err != nil {
+		t.Fatalf("Failed to read replayed event: %v", err)
+	}
+	if replayed.ID != 2 || replayed.Type != events.OrderConfirmed {
+		t.Errorf("Expected replayed event 2 of type order.confirmed, got %d %s", replayed.ID, replayed.Type)
+	}
+
+	bus.Publish(events.Event{Type: events.OrderDelivered, SalesSlotID: types.ID("slot2")})
+	bus.Publish(events.Event{Type: events.OrderDelivered, SalesSlotID: types.ID("slot1")})
+
+	var live events.Event
+	if err := conn.ReadJSON(&live); err != nil {
+		t.Fatalf("Failed to read live event: %v", err)
+	}
+	if live.ID != 4 || live.SalesSlotID != types.ID("slot1") {
+		t.Errorf("Expected live event 4 for slot1, got %d for %s", live.ID, live.SalesSlotID)
+	}
+}
+
+func TestEventHandler_Upgrade(t *testing.T) {
+	app := fiber.New()
+	handler := NewEventHandler(events.NewBus(10))
+	app.Get("/events/ws", handler.Upgrade, websocket.New(handler.WebSocket))
+	app.Get("/events", handler.Stream)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/events/ws", nil))
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusUpgradeRequired {
+		t.Errorf("Expected status code %d, got %d", fiber.StatusUpgradeRequired, resp.StatusCode)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/events?lastEventId=abc", nil))
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", fiber.StatusBadRequest, resp.StatusCode)
+	}
+}
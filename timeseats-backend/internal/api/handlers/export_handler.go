@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"log"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExportHandler struct {
+	exportService services.ExportService
+}
+
+func NewExportHandler(exportService services.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// @Summary Export orders with their items
+// @Description Streams one row per order item as CSV (UTF-8 with BOM) or XLSX. Split tenders are broken down into a paid amount per payment method.
+// @Tags exports
+// @Security BearerAuth
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "File format" Enums(csv, xlsx) default(csv)
+// @Param salesSlotId query string false "Sales slot ID"
+// @Param status query string false "Order status" Enums(RESERVED, CONFIRMED, CANCELLED, PREPARING, READY, DELIVERED)
+// @Param from query string false "Start of the time range (RFC3339, inclusive)"
+// @Param to query string false "End of the time range (RFC3339, exclusive)"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Router /exports/orders [get]
+func (h *ExportHandler) Orders(c *fiber.Ctx) error {
+	filter := repositories.OrderFilter{
+		SalesSlotID: types.ID(c.Query("salesSlotId")),
+	}
+
+	if status := c.Query("status"); status != "" {
+		orderStatus, ok := types.ParseOrderStatus(status)
+		if !ok {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid order status")
+		}
+		filter.Status = orderStatus
+	}
+
+	var err error
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		return err
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		return err
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return fiber.NewError(fiber.StatusBadRequest, services.ErrInvalidTimeRange.Error())
+	}
+
+	return h.stream(c, "orders", func(ctx context.Context, w services.RowWriter) error {
+		return h.exportService.ExportOrders(ctx, filter, w)
+	})
+}
+
+// @Summary Export inventory snapshots
+// @Description Current inventory per sales slot and product as CSV (UTF-8 with BOM) or XLSX.
+// @Tags exports
+// @Security BearerAuth
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "File format" Enums(csv, xlsx) default(csv)
+// @Param salesSlotId query string false "Sales slot ID"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Router /exports/inventory [get]
+func (h *ExportHandler) Inventory(c *fiber.Ctx) error {
+	salesSlotID := types.ID(c.Query("salesSlotId"))
+
+	return h.stream(c, "inventory", func(ctx context.Context, w services.RowWriter) error {
+		return h.exportService.ExportInventory(ctx, salesSlotID, w)
+	})
+}
+
+// stream writes the export in the requested format as the response body is
+// sent. Once streaming has started the status can no longer change, so
+// failures are logged and the file is cut short.
+func (h *ExportHandler) stream(c *fiber.Ctx, name string, write func(ctx context.Context, w services.RowWriter) error) error {
+	formatName := c.Query("format", "csv")
+	format, err := h.exportService.Format(formatName)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx := c.UserContext()
+	c.Attachment(name + "." + formatName)
+	c.Set(fiber.HeaderContentType, format.ContentType)
+	c.Context().SetBodyStreamWriter(func(bw *bufio.Writer) {
+		defer bw.Flush()
+
+		w, err := format.NewWriter(bw)
+		if err != nil {
+			log.Printf("export %s failed: %v", name, err)
+			return
+		}
+
+		err = write(ctx, w)
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			log.Printf("export %s failed: %v", name, err)
+		}
+	})
+
+	return nil
+}
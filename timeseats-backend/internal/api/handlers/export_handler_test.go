@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockExportService struct {
+	filter repositories.OrderFilter
+}
+
+// lineWriter writes each row as its values separated by spaces.
+type lineWriter struct {
+	w io.Writer
+}
+
+func (l *lineWriter) WriteRow(values []any) error {
+	_, err := fmt.Fprintln(l.w, values...)
+	return err
+}
+
+func (l *lineWriter) Close() error {
+	return nil
+}
+
+func (s *mockExportService) Format(name string) (*services.ExportFormat, error) {
+	if name != "csv" {
+		return nil, services.ErrInvalidExportFormat
+	}
+	return &services.ExportFormat{
+		ContentType: "text/csv; charset=utf-8",
+		NewWriter: func(w io.Writer) (services.ExportWriter, error) {
+			return &lineWriter{w: w}, nil
+		},
+	}, nil
+}
+
+func (s *mockExportService) ExportOrders(ctx context.Context, filter repositories.OrderFilter, w services.RowWriter) error {
+	s.filter = filter
+	w.WriteRow([]any{"Ticket Number"})
+	return w.WriteRow([]any{"A001"})
+}
+
+func (s *mockExportService) ExportInventory(ctx context.Context, salesSlotID types.ID, w services.RowWriter) error {
+	return w.WriteRow([]any{"Sales Slot ID"})
+}
+
+func TestExportHandler_OrdersCSV(t *testing.T) {
+	app := fiber.New()
+	service := &mockExportService{}
+	handler := NewExportHandler(service)
+	app.Get("/exports/orders", handler.Orders)
+
+	req := httptest.NewRequest("GET", "/exports/orders?status=CONFIRMED&salesSlotId=slot-1", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", fiber.StatusOK, resp.StatusCode)
+	}
+	if disposition := resp.Header.Get(fiber.HeaderContentDisposition); !strings.Contains(disposition, "orders.csv") {
+		t.Errorf("Expected an orders.csv attachment, got %q", disposition)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "Ticket Number\nA001\n" {
+		t.Errorf("Unexpected body %q", body)
+	}
+	if service.filter.Status != types.CONFIRMED || service.filter.SalesSlotID != "slot-1" {
+		t.Errorf("Unexpected filter %+v", service.filter)
+	}
+}
+
+func TestExportHandler_InvalidRequests(t *testing.T) {
+	app := fiber.New()
+	handler := NewExportHandler(&mockExportService{})
+	app.Get("/exports/orders", handler.Orders)
+
+	for _, query := range []string{
+		"format=pdf",
+		"status=UNKNOWN",
+		"from=2025-11-03T12:00:00Z&to=2025-11-03T10:00:00Z",
+	} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/exports/orders?"+query, nil))
+		if err != nil {
+			t.Fatalf("Failed to test request: %v", err)
+		}
+		if resp.StatusCode != fiber.StatusBadRequest {
+			t.Errorf("Expected status code %d for %q, got %d", fiber.StatusBadRequest, query, resp.StatusCode)
+		}
+	}
+}
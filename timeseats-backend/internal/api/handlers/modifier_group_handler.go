@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ModifierGroupHandler struct {
+	modifierGroupService services.ModifierGroupService
+}
+
+func NewModifierGroupHandler(modifierGroupService services.ModifierGroupService) *ModifierGroupHandler {
+	return &ModifierGroupHandler{modifierGroupService: modifierGroupService}
+}
+
+// @Summary Add a modifier group to a product
+// @Description A group offers options such as sizes or toppings. minSelect > 0 makes a choice required; maxSelect of 0 allows any number.
+// @Tags products
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param group body ModifierGroupRequest true "Modifier group with its options"
+// @Success 201 {object} ModifierGroupResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /products/{id}/modifier-groups [post]
+func (h *ModifierGroupHandler) Create(c *fiber.Ctx) error {
+	productID, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	var req ModifierGroupRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	group, err := h.modifierGroupService.CreateModifierGroup(c.UserContext(), types.ID(productID), req.toInput())
+	if err != nil {
+		return modifierGroupError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(NewModifierGroupResponse(group))
+}
+
+// @Summary Get the modifier groups of a product
+// @Tags products
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {array} ModifierGroupResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /products/{id}/modifier-groups [get]
+func (h *ModifierGroupHandler) GetByProduct(c *fiber.Ctx) error {
+	productID, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	groups, err := h.modifierGroupService.GetProductModifierGroups(c.UserContext(), types.ID(productID))
+	if err != nil {
+		return modifierGroupError(err)
+	}
+
+	return c.JSON(NewModifierGroupResponseList(groups))
+}
+
+// @Summary Update a modifier group
+// @Description Replaces the group's settings and options. Options are matched by id; options without one are added and options left out are removed.
+// @Tags products
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Modifier group ID"
+// @Param group body ModifierGroupRequest true "Modifier group with its options"
+// @Success 200 {object} ModifierGroupResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /modifier-groups/{id} [put]
+func (h *ModifierGroupHandler) Update(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	var req ModifierGroupRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	group, err := h.modifierGroupService.UpdateModifierGroup(c.UserContext(), types.ID(id), req.toInput())
+	if err != nil {
+		return modifierGroupError(err)
+	}
+
+	return c.JSON(NewModifierGroupResponse(group))
+}
+
+// @Summary Delete a modifier group
+// @Tags products
+// @Security BearerAuth
+// @Param id path string true "Modifier group ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Router /modifier-groups/{id} [delete]
+func (h *ModifierGroupHandler) Delete(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	if err := h.modifierGroupService.DeleteModifierGroup(c.UserContext(), types.ID(id)); err != nil {
+		return modifierGroupError(err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func modifierGroupError(err error) error {
+	var notFound *repositories.ErrNotFound
+	if errors.As(err, &notFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	var serviceErr *services.ServiceError
+	if errors.As(err, &serviceErr) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"net/url"
 
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
 	"github.com/gofiber/fiber/v2"
@@ -17,12 +19,15 @@ func NewOrderHandler(orderService services.OrderService) *OrderHandler {
 }
 
 // @Summary Create a new order
+// @Description The ticket number is generated for the sales slot unless one is given, e.g. for a pre-printed paper ticket.
 // @Tags orders
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param order body CreateOrderRequest true "Order information"
 // @Success 201 {object} OrderResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Router /orders [post]
 func (h *OrderHandler) Create(c *fiber.Ctx) error {
 	var req CreateOrderRequest
@@ -30,16 +35,16 @@ func (h *OrderHandler) Create(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	var items []services.OrderItemInput
-	for _, item := range req.Items {
-		items = append(items, services.OrderItemInput{
-			ProductID: types.ID(item.ProductID),
-			Quantity:  item.Quantity,
-		})
-	}
-
-	order, err := h.orderService.CreateOrder(c.Context(), types.ID(req.SalesSlotID), items, req.TicketNumber, req.PaymentMethod)
+	items := newOrderItemInputs(req.Items)
+	order, err := h.orderService.CreateOrder(c.UserContext(), types.ID(req.SalesSlotID), items, req.TicketNumber, req.PaymentMethod)
 	if err != nil {
+		if errors.Is(err, services.ErrDuplicateTicketNumber) {
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
@@ -48,11 +53,17 @@ func (h *OrderHandler) Create(c *fiber.Ctx) error {
 
 // @Summary Get all orders
 // @Tags orders
+// @Security BearerAuth
 // @Produce json
+// @Param salesSlotId query string false "Sales Slot ID"
+// @Param terminalId query string false "Terminal that took or was paid for the order"
 // @Success 200 {array} OrderResponse
 // @Router /orders [get]
 func (h *OrderHandler) GetAll(c *fiber.Ctx) error {
-	orders, err := h.orderService.GetAllOrders(c.Context())
+	orders, err := h.orderService.GetAllOrders(c.UserContext(), repositories.OrderFilter{
+		SalesSlotID: types.ID(c.Query("salesSlotId")),
+		TerminalID:  types.ID(c.Query("terminalId")),
+	})
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -62,6 +73,7 @@ func (h *OrderHandler) GetAll(c *fiber.Ctx) error {
 
 // @Summary Get an order by ID
 // @Tags orders
+// @Security BearerAuth
 // @Produce json
 // @Param id path string true "Order ID"
 // @Success 200 {object} OrderResponse
@@ -72,7 +84,7 @@ func (h *OrderHandler) GetByID(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
 	}
-	order, err := h.orderService.GetOrder(c.Context(), types.ID(id))
+	order, err := h.orderService.GetOrder(c.UserContext(), types.ID(id))
 	if err != nil {
 		return fiber.NewError(fiber.StatusNotFound, "Order not found")
 	}
@@ -82,14 +94,17 @@ func (h *OrderHandler) GetByID(c *fiber.Ctx) error {
 
 // @Summary Get an order by ticket number
 // @Tags orders
+// @Security BearerAuth
 // @Produce json
+// @Description Ticket numbers are unique per sales slot; without salesSlotId the most recent order is returned.
 // @Param ticketNumber path string true "Ticket Number"
+// @Param salesSlotId query string false "Sales Slot ID"
 // @Success 200 {object} OrderResponse
 // @Failure 404 {object} ErrorResponse
 // @Router /orders/number/{ticketNumber} [get]
 func (h *OrderHandler) GetByTicketNumber(c *fiber.Ctx) error {
 	ticketNumber := c.Params("ticketNumber")
-	order, err := h.orderService.GetOrderByTicketNumber(c.Context(), ticketNumber)
+	order, err := h.orderService.GetOrderByTicketNumber(c.UserContext(), types.ID(c.Query("salesSlotId")), ticketNumber)
 	if err != nil {
 		return fiber.NewError(fiber.StatusNotFound, "Order not found")
 	}
@@ -98,13 +113,16 @@ func (h *OrderHandler) GetByTicketNumber(c *fiber.Ctx) error {
 }
 
 // @Summary Update payment status
+// @Description Pays the balance due in the order's payment method, recording it in the order's ledger. Square payments are recorded only when Square confirms them.
 // @Tags orders
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Order ID"
 // @Param payment body PaymentUpdateRequest true "Payment information"
 // @Success 200 {object} OrderResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Router /orders/{id}/payment [put]
 func (h *OrderHandler) UpdatePayment(c *fiber.Ctx) error {
 	id, err := url.PathUnescape(c.Params("id"))
@@ -117,20 +135,25 @@ func (h *OrderHandler) UpdatePayment(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	if err := h.orderService.UpdatePaymentStatus(c.Context(), types.ID(id), req.TransactionID); err != nil {
+	if err := h.orderService.UpdatePaymentStatus(c.UserContext(), types.ID(id), req.TransactionID); err != nil {
+		if errors.Is(err, services.ErrPaymentNotConfirmed) || errors.Is(err, services.ErrAlreadyPaid) {
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
-	order, _ := h.orderService.GetOrder(c.Context(), types.ID(id))
+	order, _ := h.orderService.GetOrder(c.UserContext(), types.ID(id))
 	return c.JSON(NewOrderResponse(order))
 }
 
 // @Summary Update delivery status
 // @Tags orders
+// @Security BearerAuth
 // @Produce json
 // @Param id path string true "Order ID"
 // @Success 200 {object} OrderResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Router /orders/{id}/delivery [put]
 func (h *OrderHandler) UpdateDelivery(c *fiber.Ctx) error {
 	id, err := url.PathUnescape(c.Params("id"))
@@ -138,36 +161,32 @@ func (h *OrderHandler) UpdateDelivery(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
 	}
 
-	if err := h.orderService.UpdateDeliveryStatus(c.Context(), types.ID(id)); err != nil {
+	if err := h.orderService.UpdateDeliveryStatus(c.UserContext(), types.ID(id)); err != nil {
+		if errors.Is(err, services.ErrDeliveryNotAllowed) {
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
-	order, _ := h.orderService.GetOrder(c.Context(), types.ID(id))
+	order, _ := h.orderService.GetOrder(c.UserContext(), types.ID(id))
 	return c.JSON(NewOrderResponse(order))
 }
 
 // @Summary Get orders by status
 // @Tags orders
+// @Security BearerAuth
 // @Produce json
-// @Param status query string true "Order Status" Enums(RESERVED, CONFIRMED, CANCELLED)
+// @Param status query string true "Order Status" Enums(RESERVED, CONFIRMED, CANCELLED, PREPARING, READY, DELIVERED)
 // @Success 200 {array} OrderResponse
 // @Failure 400 {object} ErrorResponse
 // @Router /orders/status [get]
 func (h *OrderHandler) GetByStatus(c *fiber.Ctx) error {
-	status := c.Query("status")
-	var orderStatus types.OrderStatus
-	switch status {
-	case "RESERVED":
-		orderStatus = types.RESERVED
-	case "CONFIRMED":
-		orderStatus = types.CONFIRMED
-	case "CANCELLED":
-		orderStatus = types.CANCELLED
-	default:
+	orderStatus, ok := types.ParseOrderStatus(c.Query("status"))
+	if !ok {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid order status")
 	}
 
-	orders, err := h.orderService.GetOrdersByStatus(c.Context(), orderStatus)
+	orders, err := h.orderService.GetOrdersByStatus(c.UserContext(), orderStatus)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -177,26 +196,40 @@ func (h *OrderHandler) GetByStatus(c *fiber.Ctx) error {
 
 // @Summary Cancel an order
 // @Tags orders
+// @Security BearerAuth
+// @Accept json
 // @Produce json
 // @Param id path string true "Order ID"
+// @Param cancel body CancelOrderRequest false "Cancellation reason"
 // @Success 200 {object} OrderResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Router /orders/{id}/cancel [put]
 func (h *OrderHandler) Cancel(c *fiber.Ctx) error {
 	id, err := url.PathUnescape(c.Params("id"))
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
 	}
-	if err := h.orderService.CancelOrder(c.Context(), types.ID(id)); err != nil {
+	var req CancelOrderRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+	if err := h.orderService.CancelOrder(c.UserContext(), types.ID(id), req.Reason); err != nil {
+		if errors.Is(err, services.ErrOrderPartiallyPaid) {
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
 		return fiber.NewError(fiber.StatusNotFound, "Order not found")
 	}
 
-	order, _ := h.orderService.GetOrder(c.Context(), types.ID(id))
+	order, _ := h.orderService.GetOrder(c.UserContext(), types.ID(id))
 	return c.JSON(NewOrderResponse(order))
 }
 
 // @Summary Confirm an order
 // @Tags orders
+// @Security BearerAuth
 // @Produce json
 // @Param id path string true "Order ID"
 // @Success 200 {object} OrderResponse
@@ -207,16 +240,17 @@ func (h *OrderHandler) Confirm(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
 	}
-	if err := h.orderService.UpdateOrderStatus(c.Context(), types.ID(id), types.CONFIRMED); err != nil {
+	if err := h.orderService.UpdateOrderStatus(c.UserContext(), types.ID(id), types.CONFIRMED); err != nil {
 		return fiber.NewError(fiber.StatusNotFound, "Order not found")
 	}
 
-	order, _ := h.orderService.GetOrder(c.Context(), types.ID(id))
+	order, _ := h.orderService.GetOrder(c.UserContext(), types.ID(id))
 	return c.JSON(NewOrderResponse(order))
 }
 
 // @Summary Add items to an order
 // @Tags orders
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Order ID"
@@ -235,18 +269,158 @@ func (h *OrderHandler) AddItems(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	var orderItems []services.OrderItemInput
-	for _, item := range items {
-		orderItems = append(orderItems, services.OrderItemInput{
-			ProductID: types.ID(item.ProductID),
-			Quantity:  item.Quantity,
-		})
+	if err := h.orderService.AddOrderItems(c.UserContext(), types.ID(id), newOrderItemInputs(items)); err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
-	if err := h.orderService.AddOrderItems(c.Context(), types.ID(id), orderItems); err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	order, _ := h.orderService.GetOrder(c.UserContext(), types.ID(id))
+	return c.JSON(NewOrderResponse(order))
+}
+
+// @Summary Change the quantity of an order item
+// @Description Only reserved orders can be changed. The reservation and the total follow the new quantity.
+// @Tags orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param itemId path string true "Order item ID"
+// @Param item body UpdateOrderItemRequest true "New quantity"
+// @Success 200 {object} OrderResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /orders/{id}/items/{itemId} [put]
+func (h *OrderHandler) UpdateItem(c *fiber.Ctx) error {
+	id, itemID, err := orderItemParams(c)
+	if err != nil {
+		return err
+	}
+	var req UpdateOrderItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := h.orderService.UpdateOrderItem(c.UserContext(), id, itemID, req.Quantity); err != nil {
+		return orderItemError(err)
+	}
+
+	order, _ := h.orderService.GetOrder(c.UserContext(), id)
+	return c.JSON(NewOrderResponse(order))
+}
+
+// @Summary Remove an item from an order
+// @Description Only reserved orders can be changed, and the last item cannot be removed.
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param itemId path string true "Order item ID"
+// @Success 200 {object} OrderResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /orders/{id}/items/{itemId} [delete]
+func (h *OrderHandler) RemoveItem(c *fiber.Ctx) error {
+	id, itemID, err := orderItemParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.orderService.RemoveOrderItem(c.UserContext(), id, itemID); err != nil {
+		return orderItemError(err)
+	}
+
+	order, _ := h.orderService.GetOrder(c.UserContext(), id)
+	return c.JSON(NewOrderResponse(order))
+}
+
+func orderItemParams(c *fiber.Ctx) (types.ID, types.ID, error) {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return "", "", fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	itemID, err := url.PathUnescape(c.Params("itemId"))
+	if err != nil {
+		return "", "", fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	return types.ID(id), types.ID(itemID), nil
+}
+
+func orderItemError(err error) error {
+	var notFound *repositories.ErrNotFound
+	switch {
+	case errors.As(err, &notFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrInvalidOrderStatus), errors.Is(err, services.ErrInsufficientInventory):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	var serviceErr *services.ServiceError
+	if errors.As(err, &serviceErr) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
+
+// @Summary Start preparing a confirmed order
+// @Tags kitchen
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} OrderResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /orders/{id}/prepare [put]
+func (h *OrderHandler) StartPreparing(c *fiber.Ctx) error {
+	return h.transition(c, types.PREPARING)
+}
 
-	order, _ := h.orderService.GetOrder(c.Context(), types.ID(id))
+// @Summary Mark an order as ready for pickup
+// @Tags kitchen
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} OrderResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /orders/{id}/ready [put]
+func (h *OrderHandler) MarkReady(c *fiber.Ctx) error {
+	return h.transition(c, types.READY)
+}
+
+func (h *OrderHandler) transition(c *fiber.Ctx, status types.OrderStatus) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	if err := h.orderService.UpdateOrderStatus(c.UserContext(), types.ID(id), status); err != nil {
+		if errors.Is(err, services.ErrInvalidOrderStatus) {
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		return fiber.NewError(fiber.StatusNotFound, "Order not found")
+	}
+
+	order, _ := h.orderService.GetOrder(c.UserContext(), types.ID(id))
 	return c.JSON(NewOrderResponse(order))
 }
+
+// @Summary Get the kitchen queue
+// @Description Confirmed and preparing orders, oldest confirmation first.
+// @Tags kitchen
+// @Security BearerAuth
+// @Produce json
+// @Param salesSlotId query string false "Sales Slot ID"
+// @Success 200 {array} OrderResponse
+// @Router /kitchen/queue [get]
+func (h *OrderHandler) KitchenQueue(c *fiber.Ctx) error {
+	orders, err := h.orderService.GetKitchenQueue(c.UserContext(), types.ID(c.Query("salesSlotId")))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(NewOrderResponseList(orders))
+}
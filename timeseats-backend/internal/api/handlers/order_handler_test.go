@@ -6,8 +6,10 @@ import (
 	"encoding/json"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
 	"github.com/gofiber/fiber/v2"
@@ -38,6 +40,7 @@ func (s *mockOrderService) CreateOrder(ctx context.Context, salesSlotID types.ID
 
 	for _, item := range items {
 		orderItem := models.OrderItem{
+			ID:        types.ID("item-" + string(item.ProductID)),
 			ProductID: item.ProductID,
 			Quantity:  item.Quantity,
 			Price:     1000,
@@ -57,9 +60,12 @@ func (s *mockOrderService) GetOrder(ctx context.Context, id types.ID) (*models.O
 	return nil, &services.ServiceError{Message: "Order not found"}
 }
 
-func (s *mockOrderService) GetAllOrders(ctx context.Context) ([]models.Order, error) {
+func (s *mockOrderService) GetAllOrders(ctx context.Context, filter repositories.OrderFilter) ([]models.Order, error) {
 	var orders []models.Order
 	for _, order := range s.orders {
+		if filter.TerminalID != "" && (order.TerminalID == nil || *order.TerminalID != filter.TerminalID) {
+			continue
+		}
 		orders = append(orders, *order)
 	}
 	return orders, nil
@@ -77,14 +83,35 @@ func (s *mockOrderService) GetOrdersByStatus(ctx context.Context, status types.O
 
 func (s *mockOrderService) UpdateOrderStatus(ctx context.Context, id types.ID, status types.OrderStatus) error {
 	if order, exists := s.orders[id]; exists {
+		if !order.Status.CanTransitionTo(status) {
+			return services.ErrInvalidOrderStatus
+		}
 		order.Status = status
 		return nil
 	}
 	return &services.ServiceError{Message: "Order not found"}
 }
 
-func (s *mockOrderService) CancelOrder(ctx context.Context, id types.ID) error {
-	return s.UpdateOrderStatus(ctx, id, types.CANCELLED)
+func (s *mockOrderService) CancelOrder(ctx context.Context, id types.ID, reason string) error {
+	if order, exists := s.orders[id]; exists {
+		order.Status = types.CANCELLED
+		if reason != "" {
+			order.CancelReason = &reason
+		}
+		return nil
+	}
+	return &services.ServiceError{Message: "Order not found"}
+}
+
+func (s *mockOrderService) CancelExpiredReservations(ctx context.Context, createdBefore time.Time) (int, error) {
+	cancelled := 0
+	for _, order := range s.orders {
+		if order.Status == types.RESERVED && !order.IsPaid && order.CreatedAt.Before(createdBefore) {
+			order.Status = types.CANCELLED
+			cancelled++
+		}
+	}
+	return cancelled, nil
 }
 
 func (s *mockOrderService) AddOrderItems(ctx context.Context, orderID types.ID, items []services.OrderItemInput) error {
@@ -105,7 +132,40 @@ func (s *mockOrderService) AddOrderItems(ctx context.Context, orderID types.ID,
 	return nil
 }
 
-func (s *mockOrderService) GetOrderByTicketNumber(ctx context.Context, ticketNumber string) (*models.Order, error) {
+func (s *mockOrderService) UpdateOrderItem(ctx context.Context, orderID, itemID types.ID, quantity int) error {
+	order, exists := s.orders[orderID]
+	if !exists {
+		return repositories.NewErrNotFound("Order", orderID)
+	}
+	if order.Status != types.RESERVED {
+		return services.ErrInvalidOrderStatus
+	}
+	for i := range order.Items {
+		if order.Items[i].ID == itemID {
+			order.TotalAmount += (quantity - order.Items[i].Quantity) * order.Items[i].Price
+			order.Items[i].Quantity = quantity
+			return nil
+		}
+	}
+	return repositories.NewErrNotFound("OrderItem", itemID)
+}
+
+func (s *mockOrderService) RemoveOrderItem(ctx context.Context, orderID, itemID types.ID) error {
+	order, exists := s.orders[orderID]
+	if !exists {
+		return repositories.NewErrNotFound("Order", orderID)
+	}
+	for i := range order.Items {
+		if order.Items[i].ID == itemID {
+			order.TotalAmount -= order.Items[i].GetSubtotal()
+			order.Items = append(order.Items[:i], order.Items[i+1:]...)
+			return nil
+		}
+	}
+	return repositories.NewErrNotFound("OrderItem", itemID)
+}
+
+func (s *mockOrderService) GetOrderByTicketNumber(ctx context.Context, salesSlotID types.ID, ticketNumber string) (*models.Order, error) {
 	for _, order := range s.orders {
 		if order.TicketNumber == ticketNumber {
 			return order, nil
@@ -125,12 +185,23 @@ func (s *mockOrderService) UpdatePaymentStatus(ctx context.Context, id types.ID,
 
 func (s *mockOrderService) UpdateDeliveryStatus(ctx context.Context, id types.ID) error {
 	if order, exists := s.orders[id]; exists {
+		order.Status = types.DELIVERED
 		order.IsDelivered = true
 		return nil
 	}
 	return &services.ServiceError{Message: "Order not found"}
 }
 
+func (s *mockOrderService) GetKitchenQueue(ctx context.Context, salesSlotID types.ID) ([]models.Order, error) {
+	var orders []models.Order
+	for _, order := range s.orders {
+		if order.Status == types.CONFIRMED || order.Status == types.PREPARING {
+			orders = append(orders, *order)
+		}
+	}
+	return orders, nil
+}
+
 func TestOrderHandler_Create(t *testing.T) {
 	app := fiber.New()
 	mockService := newMockOrderService()
@@ -252,3 +323,105 @@ func TestOrderHandler_AddItems(t *testing.T) {
 		t.Errorf("Expected 2 items, got %d", len(response.Items))
 	}
 }
+
+func TestOrderHandler_StartPreparing(t *testing.T) {
+	app := fiber.New()
+	mockService := newMockOrderService()
+	handler := NewOrderHandler(mockService)
+
+	ctx := context.Background()
+	items := []services.OrderItemInput{{ProductID: types.ID("test-product-id"), Quantity: 1}}
+	order, _ := mockService.CreateOrder(ctx, types.ID("test-slot-id"), items, "TEST-001", types.CASH)
+
+	app.Put("/orders/:id/prepare", handler.StartPreparing)
+
+	req := httptest.NewRequest("PUT", "/orders/"+string(order.ID)+"/prepare", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusConflict {
+		t.Errorf("Expected status code %d for a reserved order, got %d", fiber.StatusConflict, resp.StatusCode)
+	}
+
+	mockService.UpdateOrderStatus(ctx, order.ID, types.CONFIRMED)
+
+	req = httptest.NewRequest("PUT", "/orders/"+string(order.ID)+"/prepare", nil)
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Errorf("Expected status code %d, got %d", fiber.StatusOK, resp.StatusCode)
+	}
+
+	var response OrderResponse
+	json.NewDecoder(resp.Body).Decode(&response)
+
+	if response.Status != types.PREPARING.String() {
+		t.Errorf("Expected order status %s, got %s", types.PREPARING, response.Status)
+	}
+}
+
+func TestOrderHandler_UpdateAndRemoveItem(t *testing.T) {
+	app := fiber.New()
+	mockService := newMockOrderService()
+	handler := NewOrderHandler(mockService)
+
+	ctx := context.Background()
+	items := []services.OrderItemInput{
+		{ProductID: types.ID("yakisoba"), Quantity: 2},
+		{ProductID: types.ID("takoyaki"), Quantity: 1},
+	}
+	order, _ := mockService.CreateOrder(ctx, types.ID("test-slot-id"), items, "TEST-001", types.CASH)
+
+	app.Put("/orders/:id/items/:itemId", handler.UpdateItem)
+	app.Delete("/orders/:id/items/:itemId", handler.RemoveItem)
+
+	body, _ := json.Marshal(UpdateOrderItemRequest{Quantity: 3})
+	req := httptest.NewRequest("PUT", "/orders/"+string(order.ID)+"/items/item-yakisoba", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", fiber.StatusOK, resp.StatusCode)
+	}
+
+	var response OrderResponse
+	json.NewDecoder(resp.Body).Decode(&response)
+	if response.TotalAmount != 4000 || response.Items[0].Quantity != 3 {
+		t.Errorf("Expected 3 yakisoba and a total of 4000, got %+v", response)
+	}
+
+	req = httptest.NewRequest("DELETE", "/orders/"+string(order.ID)+"/items/item-takoyaki", nil)
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	json.NewDecoder(resp.Body).Decode(&response)
+	if resp.StatusCode != fiber.StatusOK || len(response.Items) != 1 || response.TotalAmount != 3000 {
+		t.Errorf("Expected takoyaki to be removed, got %d %+v", resp.StatusCode, response)
+	}
+
+	req = httptest.NewRequest("DELETE", "/orders/"+string(order.ID)+"/items/unknown", nil)
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", fiber.StatusNotFound, resp.StatusCode)
+	}
+
+	mockService.UpdateOrderStatus(ctx, order.ID, types.CONFIRMED)
+	req = httptest.NewRequest("PUT", "/orders/"+string(order.ID)+"/items/item-yakisoba", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusConflict {
+		t.Errorf("Expected status code %d for a confirmed order, got %d", fiber.StatusConflict, resp.StatusCode)
+	}
+}
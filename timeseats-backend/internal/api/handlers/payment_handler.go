@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+const squareSignatureHeader = "X-Square-Hmacsha256-Signature"
+
+type PaymentHandler struct {
+	paymentService services.PaymentService
+	orderService   services.OrderService
+}
+
+func NewPaymentHandler(paymentService services.PaymentService, orderService services.OrderService) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+		orderService:   orderService,
+	}
+}
+
+// @Summary Record a payment toward an order
+// @Description Adds a cash or PayPay payment to the order's ledger. An order can be paid with several payments in different methods and is paid once they cover its total. A zero amount pays the balance due. For cash, tendered is the amount the customer handed over; it may not be less than the amount paid nor more than 10,000 yen over it, and the change is stored and suggested as notes and coins from those the request lists as in the drawer. Square payments are recorded only when Square confirms them.
+// @Tags orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param payment body PaymentRequest true "Payment"
+// @Success 201 {object} PaymentResultResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /orders/{id}/payments [post]
+func (h *PaymentHandler) RecordPayment(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	var req PaymentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	payment, err := h.paymentService.RecordPayment(c.UserContext(), types.ID(id), services.PaymentInput{
+		Method:    req.Method,
+		Amount:    req.Amount,
+		Reference: req.Reference,
+		Tendered:  req.Tendered,
+	})
+	if err != nil {
+		return paymentError(err)
+	}
+
+	order, err := h.orderService.GetOrder(c.UserContext(), types.ID(id))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	available := make(map[int]int, len(req.Drawer))
+	for _, count := range req.Drawer {
+		available[count.Denomination] += count.Quantity
+	}
+	change, ok := services.ChangeDenominations(payment.ChangeAmount, available)
+
+	return c.Status(fiber.StatusCreated).JSON(PaymentResultResponse{
+		Payment:     NewPaymentResponse(payment),
+		Order:       NewOrderResponse(order),
+		Change:      newDenominationCounts(change),
+		ChangeShort: !ok,
+	})
+}
+
+// @Summary List an order's payments
+// @Description Includes pending and failed Square payments, which do not count toward the order.
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {array} PaymentResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /orders/{id}/payments [get]
+func (h *PaymentHandler) GetPayments(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	payments, err := h.paymentService.GetPayments(c.UserContext(), types.ID(id))
+	if err != nil {
+		return paymentError(err)
+	}
+	return c.JSON(NewPaymentResponseList(payments))
+}
+
+// @Summary Charge an order through Square
+// @Description Charges the order's balance due with the order ID as the payment's reference. The payment counts toward the order only when Square reports it completed.
+// @Tags orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param payment body CardPaymentRequest true "Card token"
+// @Success 200 {object} CardPaymentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 402 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /orders/{id}/payment/square [post]
+func (h *PaymentHandler) CreateSquarePayment(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	var req CardPaymentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	payment, err := h.paymentService.CreateCardPayment(c.UserContext(), types.ID(id), req.SourceID)
+	if err != nil {
+		return paymentError(err)
+	}
+
+	order, err := h.orderService.GetOrder(c.UserContext(), types.ID(id))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(CardPaymentResponse{
+		PaymentID: payment.ID,
+		Status:    payment.Status.String(),
+		Order:     NewOrderResponse(order),
+	})
+}
+
+// @Summary Receive a Square webhook notification
+// @Description Called by Square. Notifications must carry a valid x-square-hmacsha256-signature; payments they report are looked up with Square before they are recorded. A payment its order cannot take, such as one for an order cancelled meanwhile, is recorded as UNAPPLIED to be refunded.
+// @Tags webhooks
+// @Accept json
+// @Success 200 "OK"
+// @Failure 401 {object} ErrorResponse
+// @Router /webhooks/square [post]
+func (h *PaymentHandler) SquareWebhook(c *fiber.Ctx) error {
+	err := h.paymentService.HandleGatewayWebhook(c.UserContext(), c.Body(), c.Get(squareSignatureHeader))
+	if err == nil {
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	switch {
+	case errors.Is(err, services.ErrInvalidSignature):
+		return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+	case errors.Is(err, services.ErrGatewayUnavailable):
+		return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+	}
+	// Square retries notifications that fail, which cannot help when the
+	// payment does not fit the order. The payment is kept in the ledger as
+	// unapplied instead, to be refunded.
+	var serviceErr *services.ServiceError
+	if errors.As(err, &serviceErr) {
+		log.Printf("square payment kept unapplied: %v", err)
+		return c.SendStatus(fiber.StatusOK)
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
+
+// @Summary Create a PayPay QR code for an order
+// @Description Returns a dynamic PayPay code for the order's balance due. The order's active code is returned again while it is valid; a code for an old balance or past its expiry is cancelled and replaced.
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} QRCodeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /orders/{id}/payment/paypay [post]
+func (h *PaymentHandler) CreatePayPayCode(c *fiber.Ctx) error {
+	return h.qrCode(c, h.paymentService.CreateQRCode)
+}
+
+// @Summary Get the status of an order's PayPay QR code
+// @Description Asks PayPay about the order's latest code, recording the payment once the customer has paid.
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} QRCodeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /orders/{id}/payment/paypay [get]
+func (h *PaymentHandler) GetPayPayCode(c *fiber.Ctx) error {
+	return h.qrCode(c, h.paymentService.RefreshQRCode)
+}
+
+// @Summary Cancel an order's PayPay QR code
+// @Description Withdraws the order's active code. Fails with 409 if the customer has already paid with it.
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} QRCodeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /orders/{id}/payment/paypay [delete]
+func (h *PaymentHandler) CancelPayPayCode(c *fiber.Ctx) error {
+	return h.qrCode(c, h.paymentService.CancelQRCode)
+}
+
+func (h *PaymentHandler) qrCode(c *fiber.Ctx, action func(context.Context, types.ID) (*models.QRCode, error)) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	code, err := action(c.UserContext(), types.ID(id))
+	if err != nil {
+		return paymentError(err)
+	}
+
+	order, err := h.orderService.GetOrder(c.UserContext(), types.ID(id))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(NewQRCodeResponse(code, order))
+}
+
+// @Summary Receive a PayPay webhook notification
+// @Description Called by PayPay for transaction events. The notifications are not signed; the codes they name are looked up with PayPay before payments are recorded. A payment its order cannot take is recorded as UNAPPLIED to be refunded.
+// @Tags webhooks
+// @Accept json
+// @Success 200 "OK"
+// @Router /webhooks/paypay [post]
+func (h *PaymentHandler) PayPayWebhook(c *fiber.Ctx) error {
+	err := h.paymentService.HandleQRCodeWebhook(c.UserContext(), c.Body())
+	if err == nil {
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	if errors.Is(err, services.ErrGatewayUnavailable) {
+		return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+	}
+	// As with Square, a payment the order cannot take is kept as unapplied.
+	var serviceErr *services.ServiceError
+	if errors.As(err, &serviceErr) {
+		log.Printf("paypay payment kept unapplied: %v", err)
+		return c.SendStatus(fiber.StatusOK)
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
+
+func paymentError(err error) error {
+	var notFound *repositories.ErrNotFound
+	if errors.As(err, &notFound) {
+		return fiber.NewError(fiber.StatusNotFound, notFound.Entity+" not found")
+	}
+	var gatewayErr *services.GatewayError
+	if errors.As(err, &gatewayErr) {
+		if gatewayErr.Declined {
+			return fiber.NewError(fiber.StatusPaymentRequired, err.Error())
+		}
+		return fiber.NewError(fiber.StatusBadGateway, err.Error())
+	}
+	switch {
+	case errors.Is(err, services.ErrGatewayUnavailable):
+		return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+	case errors.Is(err, services.ErrAlreadyPaid), errors.Is(err, services.ErrInvalidOrderStatus),
+		errors.Is(err, services.ErrPaymentExceedsBalance), errors.Is(err, services.ErrPaymentNotConfirmed),
+		errors.Is(err, services.ErrQRCodeNotActive), errors.Is(err, services.ErrDuplicatePayment):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	var serviceErr *services.ServiceError
+	if errors.As(err, &serviceErr) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
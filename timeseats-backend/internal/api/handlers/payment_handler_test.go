@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockPaymentService struct {
+	signature string
+}
+
+func (s *mockPaymentService) RecordPayment(ctx context.Context, orderID types.ID, input services.PaymentInput) (*models.Payment, error) {
+	switch {
+	case orderID == "missing":
+		return nil, repositories.NewErrNotFound("Order", orderID)
+	case input.Method == types.SQUARE:
+		return nil, services.ErrPaymentNotConfirmed
+	case input.Amount > 800:
+		return nil, services.ErrPaymentExceedsBalance
+	case input.Amount < 0:
+		return nil, services.ErrInvalidPaymentAmount
+	case input.Tendered > 0 && input.Tendered < input.Amount:
+		return nil, services.ErrInsufficientTender
+	}
+	payment := &models.Payment{ID: "payment-1", OrderID: orderID, Amount: input.Amount, Method: input.Method, Status: types.PAYMENT_COMPLETED}
+	if input.Tendered > 0 {
+		payment.TenderedAmount = input.Tendered
+		payment.ChangeAmount = input.Tendered - input.Amount
+	}
+	return payment, nil
+}
+
+func (s *mockPaymentService) GetPayments(ctx context.Context, orderID types.ID) ([]models.Payment, error) {
+	if orderID == "missing" {
+		return nil, repositories.NewErrNotFound("Order", orderID)
+	}
+	return []models.Payment{{ID: "payment-1", OrderID: orderID, Amount: 300, Method: types.CASH, Status: types.PAYMENT_COMPLETED}}, nil
+}
+
+func (s *mockPaymentService) CreateCardPayment(ctx context.Context, orderID types.ID, sourceID string) (*services.GatewayPayment, error) {
+	switch sourceID {
+	case "":
+		return nil, services.ErrPaymentSourceRequired
+	case "square-down":
+		return nil, &services.GatewayError{Err: errors.New("square: HTTP 500")}
+	case "declined":
+		return nil, &services.GatewayError{Declined: true, Err: errors.New("square: CARD_DECLINED")}
+	}
+	if orderID == "paid" {
+		return nil, services.ErrAlreadyPaid
+	}
+	return &services.GatewayPayment{ID: "pay-1", OrderID: orderID, Amount: 800, Status: types.GATEWAY_PAYMENT_COMPLETED}, nil
+}
+
+func (s *mockPaymentService) HandleGatewayWebhook(ctx context.Context, body []byte, signature string) error {
+	s.signature = signature
+	switch signature {
+	case "valid":
+		return nil
+	case "mismatch":
+		return services.ErrPaymentExceedsBalance
+	}
+	return services.ErrInvalidSignature
+}
+
+func (s *mockPaymentService) CreateQRCode(ctx context.Context, orderID types.ID) (*models.QRCode, error) {
+	if orderID == "paid" {
+		return nil, services.ErrAlreadyPaid
+	}
+	return &models.QRCode{OrderID: orderID, MerchantPaymentID: "mp-1", Payload: "https://qr.paypay.ne.jp/mp-1", Amount: 800, Status: types.QR_CODE_ACTIVE}, nil
+}
+
+func (s *mockPaymentService) RefreshQRCode(ctx context.Context, orderID types.ID) (*models.QRCode, error) {
+	if orderID == "none" {
+		return nil, repositories.NewErrNotFound("QRCode", orderID)
+	}
+	paymentID := "paypay-1"
+	return &models.QRCode{OrderID: orderID, MerchantPaymentID: "mp-1", Amount: 800, Status: types.QR_CODE_COMPLETED, PaymentID: &paymentID}, nil
+}
+
+func (s *mockPaymentService) CancelQRCode(ctx context.Context, orderID types.ID) (*models.QRCode, error) {
+	return nil, services.ErrQRCodeNotActive
+}
+
+func (s *mockPaymentService) HandleQRCodeWebhook(ctx context.Context, body []byte) error {
+	return nil
+}
+
+func (s *mockPaymentService) RefreshActiveQRCodes(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func TestPaymentHandler_RecordPayment(t *testing.T) {
+	app := fiber.New()
+	orders := newMockOrderService()
+	orders.orders["order-1"] = &models.Order{ID: "order-1", TotalAmount: 800, PaidAmount: 300}
+	handler := NewPaymentHandler(&mockPaymentService{}, orders)
+	app.Post("/orders/:id/payments", handler.RecordPayment)
+	app.Get("/orders/:id/payments", handler.GetPayments)
+
+	tests := []struct {
+		orderID string
+		body    string
+		want    int
+	}{
+		{"order-1", `{"method":1,"amount":300}`, fiber.StatusCreated},
+		{"order-1", `{"method":3,"amount":300}`, fiber.StatusConflict},
+		{"order-1", `{"method":1,"amount":1000}`, fiber.StatusConflict},
+		{"order-1", `{"method":1,"amount":-1}`, fiber.StatusBadRequest},
+		{"order-1", `{"method":1,"amount":300,"tendered":100}`, fiber.StatusBadRequest},
+		{"missing", `{"method":1}`, fiber.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/orders/"+tt.orderID+"/payments", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Failed to test request: %v", err)
+		}
+		if resp.StatusCode != tt.want {
+			t.Errorf("%s with %s: expected %d, got %d", tt.orderID, tt.body, tt.want, resp.StatusCode)
+			continue
+		}
+		if resp.StatusCode == fiber.StatusCreated {
+			var response PaymentResultResponse
+			json.NewDecoder(resp.Body).Decode(&response)
+			if response.Payment.Method != "CASH" || response.Payment.Amount != 300 || response.Order.BalanceDue != 500 {
+				t.Errorf("Unexpected response: %+v", response)
+			}
+		}
+	}
+
+	req := httptest.NewRequest("POST", "/orders/order-1/payments", strings.NewReader(`{"method":1,"amount":300,"tendered":1000,"drawer":[{"denomination":500,"quantity":0},{"denomination":100,"quantity":10}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	var tendered PaymentResultResponse
+	json.NewDecoder(resp.Body).Decode(&tendered)
+	if tendered.Payment.TenderedAmount != 1000 || tendered.Payment.ChangeAmount != 700 ||
+		len(tendered.Change) != 1 || tendered.Change[0].Denomination != 100 || tendered.Change[0].Quantity != 7 || tendered.ChangeShort {
+		t.Errorf("Expected 700 change as 100 x7 with no 500 yen coins left, got %+v", tendered)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/orders/order-1/payments", nil))
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	var payments []PaymentResponse
+	json.NewDecoder(resp.Body).Decode(&payments)
+	if resp.StatusCode != fiber.StatusOK || len(payments) != 1 || payments[0].Status != "COMPLETED" {
+		t.Errorf("Unexpected payments: %d %+v", resp.StatusCode, payments)
+	}
+}
+
+func TestPaymentHandler_CreateSquarePayment(t *testing.T) {
+	app := fiber.New()
+	orders := newMockOrderService()
+	orders.orders["order-1"] = &models.Order{ID: "order-1", TotalAmount: 800, IsPaid: true, PaymentMethod: types.SQUARE}
+	app.Post("/orders/:id/payment/square", NewPaymentHandler(&mockPaymentService{}, orders).CreateSquarePayment)
+
+	tests := []struct {
+		orderID  string
+		sourceID string
+		want     int
+	}{
+		{"order-1", "cnon:card-ok", fiber.StatusOK},
+		{"order-1", "", fiber.StatusBadRequest},
+		{"paid", "cnon:card-ok", fiber.StatusConflict},
+		{"order-1", "square-down", fiber.StatusBadGateway},
+		{"order-1", "declined", fiber.StatusPaymentRequired},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/orders/"+tt.orderID+"/payment/square", strings.NewReader(`{"sourceId":"`+tt.sourceID+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Failed to test request: %v", err)
+		}
+		if resp.StatusCode != tt.want {
+			t.Errorf("%s with %q: expected %d, got %d", tt.orderID, tt.sourceID, tt.want, resp.StatusCode)
+			continue
+		}
+		if resp.StatusCode == fiber.StatusOK {
+			var response CardPaymentResponse
+			json.NewDecoder(resp.Body).Decode(&response)
+			if response.PaymentID != "pay-1" || response.Status != "COMPLETED" || !response.Order.IsPaid {
+				t.Errorf("Unexpected response: %+v", response)
+			}
+		}
+	}
+}
+
+func TestPaymentHandler_SquareWebhook(t *testing.T) {
+	app := fiber.New()
+	service := &mockPaymentService{}
+	app.Post("/webhooks/square", NewPaymentHandler(service, newMockOrderService()).SquareWebhook)
+
+	tests := []struct {
+		signature string
+		want      int
+	}{
+		{"valid", fiber.StatusOK},
+		{"forged", fiber.StatusUnauthorized},
+		// Retrying cannot fix a payment that does not fit its order.
+		{"mismatch", fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/webhooks/square", strings.NewReader(`{"type":"payment.updated"}`))
+		req.Header.Set("x-square-hmacsha256-signature", tt.signature)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Failed to test request: %v", err)
+		}
+		if resp.StatusCode != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.signature, tt.want, resp.StatusCode)
+		}
+		if service.signature != tt.signature {
+			t.Errorf("Expected signature %q to be passed on, got %q", tt.signature, service.signature)
+		}
+	}
+}
+
+func TestPaymentHandler_PayPayCode(t *testing.T) {
+	app := fiber.New()
+	orders := newMockOrderService()
+	orders.orders["order-1"] = &models.Order{ID: "order-1", TotalAmount: 800, PaymentMethod: types.PAYPAY}
+	handler := NewPaymentHandler(&mockPaymentService{}, orders)
+	app.Post("/orders/:id/payment/paypay", handler.CreatePayPayCode)
+	app.Get("/orders/:id/payment/paypay", handler.GetPayPayCode)
+	app.Delete("/orders/:id/payment/paypay", handler.CancelPayPayCode)
+
+	tests := []struct {
+		method     string
+		orderID    string
+		want       int
+		wantStatus string
+	}{
+		{"POST", "order-1", fiber.StatusOK, "ACTIVE"},
+		{"POST", "paid", fiber.StatusConflict, ""},
+		{"GET", "order-1", fiber.StatusOK, "COMPLETED"},
+		{"GET", "none", fiber.StatusNotFound, ""},
+		{"DELETE", "order-1", fiber.StatusConflict, ""},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(tt.method, "/orders/"+tt.orderID+"/payment/paypay", nil))
+		if err != nil {
+			t.Fatalf("Failed to test request: %v", err)
+		}
+		if resp.StatusCode != tt.want {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.orderID, tt.want, resp.StatusCode)
+			continue
+		}
+		if tt.orderID == "none" {
+			if body, _ := io.ReadAll(resp.Body); string(body) != "QRCode not found" {
+				t.Errorf("Expected the missing QR code to be reported, got %q", body)
+			}
+		}
+		if resp.StatusCode == fiber.StatusOK {
+			var response QRCodeResponse
+			json.NewDecoder(resp.Body).Decode(&response)
+			if response.Status != tt.wantStatus || response.MerchantPaymentID != "mp-1" || response.Order.ID != "order-1" {
+				t.Errorf("%s %s: unexpected response %+v", tt.method, tt.orderID, response)
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:embed templates/pickup_board.html
+var pickupBoardFS embed.FS
+
+var pickupBoardTemplate = template.Must(template.ParseFS(pickupBoardFS, "templates/pickup_board.html"))
+
+// PickupBoardHandler serves the public "now serving" board. Nothing but
+// ticket numbers may be exposed here, so the stream sends board snapshots
+// instead of forwarding order events.
+type PickupBoardHandler struct {
+	boardService services.PickupBoardService
+	bus          *events.Bus
+	streamURL    string
+}
+
+func NewPickupBoardHandler(boardService services.PickupBoardService, bus *events.Bus, streamURL string) *PickupBoardHandler {
+	return &PickupBoardHandler{
+		boardService: boardService,
+		bus:          bus,
+		streamURL:    streamURL,
+	}
+}
+
+// @Summary Get the pickup board
+// @Description Ticket numbers of the active sales slots that are being prepared or ready to collect.
+// @Tags pickup-board
+// @Produce json
+// @Success 200 {object} PickupBoardResponse
+// @Router /pickup-board [get]
+func (h *PickupBoardHandler) Get(c *fiber.Ctx) error {
+	board, err := h.boardService.GetPickupBoard(c.UserContext())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(NewPickupBoardResponse(board))
+}
+
+// @Summary Stream the pickup board (Server-Sent Events)
+// @Description Sends a "board" event with the full board on connect and whenever it changes.
+// @Tags pickup-board
+// @Produce text/event-stream
+// @Success 200 {object} PickupBoardResponse
+// @Router /pickup-board/stream [get]
+func (h *PickupBoardHandler) Stream(c *fiber.Ctx) error {
+	board, err := h.boardService.GetPickupBoard(c.UserContext())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	sub := h.bus.Subscribe(events.Filter{}, 0)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
+
+		ticker := time.NewTicker(keepAliveInterval)
+		defer ticker.Stop()
+
+		if err := writeBoardEvent(w, board); err != nil {
+			return
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case event, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				if !affectsPickupBoard(event.Type) {
+					continue
+				}
+				// The request context is gone once the handler has returned.
+				next, err := h.boardService.GetPickupBoard(context.Background())
+				if err != nil || sameBoard(board, next) {
+					continue
+				}
+				board = next
+				if err := writeBoardEvent(w, board); err != nil {
+					return
+				}
+			case <-ticker.C:
+				if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+					return
+				}
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// Page renders the board for a display at the pickup counter.
+func (h *PickupBoardHandler) Page(c *fiber.Ctx) error {
+	board, err := h.boardService.GetPickupBoard(c.UserContext())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	var buf bytes.Buffer
+	if err := pickupBoardTemplate.Execute(&buf, struct {
+		*services.PickupBoard
+		StreamURL string
+	}{board, h.streamURL}); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Type("html", "utf-8")
+	return c.Send(buf.Bytes())
+}
+
+func affectsPickupBoard(eventType events.Type) bool {
+	switch eventType {
+	case events.OrderConfirmed, events.OrderCancelled, events.OrderPreparing,
+		events.OrderReady, events.OrderDelivered,
+		events.SalesSlotActivated, events.SalesSlotDeactivated, events.Resync:
+		return true
+	}
+	return false
+}
+
+func sameBoard(a, b *services.PickupBoard) bool {
+	return slices.Equal(a.Preparing, b.Preparing) && slices.Equal(a.Ready, b.Ready)
+}
+
+func writeBoardEvent(w io.Writer, board *services.PickupBoard) error {
+	data, err := json.Marshal(NewPickupBoardResponse(board))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: board\ndata: %s\n\n", data)
+	return err
+}
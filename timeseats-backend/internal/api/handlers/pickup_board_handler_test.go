@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockPickupBoardService struct {
+	mu    sync.Mutex
+	board services.PickupBoard
+}
+
+func (s *mockPickupBoardService) GetPickupBoard(ctx context.Context) (*services.PickupBoard, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	board := s.board
+	return &board, nil
+}
+
+func (s *mockPickupBoardService) set(preparing, ready []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.board = services.PickupBoard{Preparing: preparing, Ready: ready, UpdatedAt: time.Now()}
+}
+
+func TestPickupBoardHandler_Page(t *testing.T) {
+	app := fiber.New()
+	mockService := &mockPickupBoardService{}
+	mockService.set([]string{"A003"}, []string{"A001", "A002"})
+	handler := NewPickupBoardHandler(mockService, events.NewBus(10), "/pickup-board/stream")
+
+	app.Get("/pickup", handler.Page)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/pickup", nil))
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Errorf("Expected status code %d, got %d", fiber.StatusOK, resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Expected an HTML page, got %s", ct)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	for _, number := range []string{"<li>A001</li>", "<li>A002</li>", "<li>A003</li>"} {
+		if !strings.Contains(string(body), number) {
+			t.Errorf("Expected page to contain %q", number)
+		}
+	}
+}
+
+func TestPickupBoardHandler_Stream(t *testing.T) {
+	app := fiber.New()
+	bus := events.NewBus(10)
+	mockService := &mockPickupBoardService{}
+	mockService.set([]string{}, []string{"A001"})
+	handler := NewPickupBoardHandler(mockService, bus, "/pickup-board/stream")
+
+	app.Get("/pickup-board/stream", handler.Stream)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go app.Listener(ln)
+	t.Cleanup(func() {
+		app.ShutdownWithTimeout(time.Second)
+	})
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get("http://" + ln.Addr().String() + "/pickup-board/stream")
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+
+	readBoard := func() PickupBoardResponse {
+		t.Helper()
+		var board PickupBoardResponse
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("Failed to read stream: %v", err)
+			}
+			if data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: "); ok {
+				json.Unmarshal([]byte(data), &board)
+				return board
+			}
+		}
+	}
+
+	if board := readBoard(); len(board.Ready) != 1 || board.Ready[0] != "A001" {
+		t.Fatalf("Expected A001 to be ready, got %+v", board)
+	}
+
+	// Events that do not change the board are not sent on.
+	bus.Publish(events.Event{Type: events.OrderPaid})
+	mockService.set([]string{}, []string{})
+	bus.Publish(events.Event{Type: events.OrderDelivered})
+
+	if board := readBoard(); len(board.Ready) != 0 {
+		t.Errorf("Expected A001 to drop off after delivery, got %+v", board)
+	}
+}
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PrinterHandler struct {
+	printerService services.PrinterService
+}
+
+func NewPrinterHandler(printerService services.PrinterService) *PrinterHandler {
+	return &PrinterHandler{printerService: printerService}
+}
+
+// @Summary Register a network printer
+// @Description Kitchen printers print the ticket of every confirmed order; receipt printers print receipts on demand. The port defaults to 9100.
+// @Tags printers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param printer body PrinterRequest true "Printer information"
+// @Success 201 {object} PrinterResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /printers [post]
+func (h *PrinterHandler) Create(c *fiber.Ctx) error {
+	var req PrinterRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	input, ok := req.toInput()
+	if !ok {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid printer kind")
+	}
+
+	printer, err := h.printerService.CreatePrinter(c.UserContext(), input)
+	if err != nil {
+		return printerError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(NewPrinterResponse(printer, h.printerService.PrinterStatus(printer.ID)))
+}
+
+// @Summary Get all printers with their queues
+// @Tags printers
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} PrinterResponse
+// @Router /printers [get]
+func (h *PrinterHandler) GetAll(c *fiber.Ctx) error {
+	printers, err := h.printerService.GetAllPrinters(c.UserContext())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	response := make([]PrinterResponse, len(printers))
+	for i, p := range printers {
+		response[i] = NewPrinterResponse(&p, h.printerService.PrinterStatus(p.ID))
+	}
+	return c.JSON(response)
+}
+
+// @Summary Get a printer by ID
+// @Tags printers
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Printer ID"
+// @Success 200 {object} PrinterResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /printers/{id} [get]
+func (h *PrinterHandler) GetByID(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	printer, err := h.printerService.GetPrinter(c.UserContext(), types.ID(id))
+	if err != nil {
+		return printerError(err)
+	}
+
+	return c.JSON(NewPrinterResponse(printer, h.printerService.PrinterStatus(printer.ID)))
+}
+
+// @Summary Update a printer
+// @Tags printers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Printer ID"
+// @Param printer body PrinterRequest true "Printer information"
+// @Success 200 {object} PrinterResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /printers/{id} [put]
+func (h *PrinterHandler) Update(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	var req PrinterRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	input, ok := req.toInput()
+	if !ok {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid printer kind")
+	}
+
+	printer, err := h.printerService.UpdatePrinter(c.UserContext(), types.ID(id), input)
+	if err != nil {
+		return printerError(err)
+	}
+
+	return c.JSON(NewPrinterResponse(printer, h.printerService.PrinterStatus(printer.ID)))
+}
+
+// @Summary Delete a printer
+// @Description Jobs already queued for the printer are still sent.
+// @Tags printers
+// @Security BearerAuth
+// @Param id path string true "Printer ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Router /printers/{id} [delete]
+func (h *PrinterHandler) Delete(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	if err := h.printerService.DeletePrinter(c.UserContext(), types.ID(id)); err != nil {
+		return printerError(err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// @Summary Print the receipt of a paid order
+// @Description Queues the receipt on the given receipt printer, or on the one at the calling terminal's counter. The first print issues the receipt; later prints are copies.
+// @Tags orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param printer body PrintReceiptRequest false "Receipt printer"
+// @Success 202 {object} PrintReceiptResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /orders/{id}/receipt/print [post]
+func (h *PrinterHandler) PrintReceipt(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	var req PrintReceiptRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	receipt, err := h.printerService.PrintReceipt(c.UserContext(), types.ID(id), optionalID(req.PrinterID))
+	if err != nil {
+		return printError(err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(PrintReceiptResponse{
+		SerialNumber: receipt.Receipt.SerialNumber,
+		IsCopy:       receipt.IsCopy,
+	})
+}
+
+// @Summary Print the kitchen tickets of an order again
+// @Description Tickets print by themselves when an order is confirmed; this prints them again, marked as reprints.
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 202 {object} PrintTicketResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /orders/{id}/ticket/print [post]
+func (h *PrinterHandler) PrintTicket(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	tickets, err := h.printerService.PrintKitchenTickets(c.UserContext(), types.ID(id), true)
+	if err != nil {
+		return printError(err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(PrintTicketResponse{Tickets: tickets})
+}
+
+// printerError maps errors of managing printers. A category or terminal
+// that does not exist is a bad request rather than a missing printer.
+func printerError(err error) error {
+	var notFound *repositories.ErrNotFound
+	if errors.As(err, &notFound) {
+		if notFound.Entity != "Printer" {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return fiber.NewError(fiber.StatusNotFound, "Printer not found")
+	}
+	var serviceErr *services.ServiceError
+	if errors.As(err, &serviceErr) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
+
+// printError maps errors of printing an order.
+func printError(err error) error {
+	var notFound *repositories.ErrNotFound
+	if errors.As(err, &notFound) {
+		return fiber.NewError(fiber.StatusNotFound, notFound.Entity+" not found")
+	}
+	switch {
+	case errors.Is(err, services.ErrReceiptNotPaid), errors.Is(err, services.ErrInvalidOrderStatus):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrPrintQueueFull):
+		return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+	}
+	var serviceErr *services.ServiceError
+	if errors.As(err, &serviceErr) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
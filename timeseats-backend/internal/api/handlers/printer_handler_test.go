@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockPrinterService struct {
+	input     services.PrinterInput
+	printerID *types.ID
+}
+
+func (s *mockPrinterService) CreatePrinter(ctx context.Context, input services.PrinterInput) (*models.Printer, error) {
+	s.input = input
+	return &models.Printer{ID: "printer-1", Name: input.Name, Kind: input.Kind, Host: input.Host, Port: 9100}, nil
+}
+
+func (s *mockPrinterService) GetPrinter(ctx context.Context, id types.ID) (*models.Printer, error) {
+	return nil, repositories.NewErrNotFound("Printer", id)
+}
+
+func (s *mockPrinterService) GetAllPrinters(ctx context.Context) ([]models.Printer, error) {
+	return nil, nil
+}
+
+func (s *mockPrinterService) UpdatePrinter(ctx context.Context, id types.ID, input services.PrinterInput) (*models.Printer, error) {
+	return nil, repositories.NewErrNotFound("Printer", id)
+}
+
+func (s *mockPrinterService) DeletePrinter(ctx context.Context, id types.ID) error {
+	return nil
+}
+
+func (s *mockPrinterService) PrinterStatus(id types.ID) services.PrinterStatus {
+	return services.PrinterStatus{Pending: 2, LastError: "connection refused"}
+}
+
+func (s *mockPrinterService) PrintKitchenTickets(ctx context.Context, orderID types.ID, reprint bool) (int, error) {
+	if orderID == "full" {
+		return 0, services.ErrPrintQueueFull
+	}
+	return 2, nil
+}
+
+func (s *mockPrinterService) PrintReceipt(ctx context.Context, orderID types.ID, printerID *types.ID) (*services.ReceiptDocument, error) {
+	if orderID == "unpaid" {
+		return nil, services.ErrReceiptNotPaid
+	}
+	s.printerID = printerID
+	return &services.ReceiptDocument{Receipt: models.Receipt{SerialNumber: 12}, IsCopy: true}, nil
+}
+
+func TestPrinterHandler_Create(t *testing.T) {
+	app := fiber.New()
+	service := &mockPrinterService{}
+	app.Post("/printers", NewPrinterHandler(service).Create)
+
+	post := func(body string) int {
+		req := httptest.NewRequest("POST", "/printers", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Failed to test request: %v", err)
+		}
+		if resp.StatusCode == fiber.StatusCreated {
+			var response PrinterResponse
+			json.NewDecoder(resp.Body).Decode(&response)
+			if response.Kind != "RECEIPT" || response.Pending != 2 || response.LastError == "" {
+				t.Errorf("Unexpected printer: %+v", response)
+			}
+		}
+		return resp.StatusCode
+	}
+
+	if status := post(`{"name":"レジ1","kind":"RECEIPT","host":"192.168.1.60","terminalId":"counter-1"}`); status != fiber.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d", fiber.StatusCreated, status)
+	}
+	if service.input.TerminalID == nil || *service.input.TerminalID != "counter-1" {
+		t.Errorf("Expected the terminal to be passed on, got %+v", service.input)
+	}
+	if status := post(`{"name":"レジ1","kind":"LABEL","host":"192.168.1.60"}`); status != fiber.StatusBadRequest {
+		t.Errorf("Expected status code %d for an unknown kind, got %d", fiber.StatusBadRequest, status)
+	}
+}
+
+func TestPrinterHandler_Print(t *testing.T) {
+	app := fiber.New()
+	service := &mockPrinterService{}
+	handler := NewPrinterHandler(service)
+	app.Post("/orders/:id/receipt/print", handler.PrintReceipt)
+	app.Post("/orders/:id/ticket/print", handler.PrintTicket)
+
+	tests := []struct {
+		path   string
+		body   string
+		status int
+	}{
+		{"/orders/order-1/receipt/print", "", fiber.StatusAccepted},
+		{"/orders/order-1/receipt/print", `{"printerId":"printer-1"}`, fiber.StatusAccepted},
+		{"/orders/unpaid/receipt/print", "", fiber.StatusConflict},
+		{"/orders/order-1/ticket/print", "", fiber.StatusAccepted},
+		{"/orders/full/ticket/print", "", fiber.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+		if tt.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Failed to test request: %v", err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Errorf("Expected status code %d for %s %s, got %d", tt.status, tt.path, tt.body, resp.StatusCode)
+		}
+	}
+	if service.printerID == nil || *service.printerID != "printer-1" {
+		t.Errorf("Expected the chosen printer to be passed on, got %v", service.printerID)
+	}
+}
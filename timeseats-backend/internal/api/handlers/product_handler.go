@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"net/url"
 
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
 	"github.com/gofiber/fiber/v2"
@@ -18,6 +20,7 @@ func NewProductHandler(productService services.ProductService) *ProductHandler {
 
 // @Summary Create a new product
 // @Tags products
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param product body CreateProductRequest true "Product information"
@@ -30,21 +33,23 @@ func (h *ProductHandler) Create(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	product, err := h.productService.CreateProduct(c.Context(), req.Name, req.Price)
+	product, err := h.productService.CreateProduct(c.UserContext(), req.Name, req.Price, req.taxRate(), optionalID(req.CategoryID), req.SortOrder)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return productError(err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(NewProductResponse(product))
 }
 
 // @Summary Get all products
+// @Description Products are in menu order: grouped by category, with uncategorized products last.
 // @Tags products
+// @Security BearerAuth
 // @Produce json
 // @Success 200 {array} ProductResponse
 // @Router /products [get]
 func (h *ProductHandler) GetAll(c *fiber.Ctx) error {
-	products, err := h.productService.GetAllProducts(c.Context())
+	products, err := h.productService.GetAllProducts(c.UserContext())
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -54,6 +59,7 @@ func (h *ProductHandler) GetAll(c *fiber.Ctx) error {
 
 // @Summary Get a product by ID
 // @Tags products
+// @Security BearerAuth
 // @Produce json
 // @Param id path string true "Product ID"
 // @Success 200 {object} ProductResponse
@@ -64,7 +70,7 @@ func (h *ProductHandler) GetByID(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
-	product, err := h.productService.GetProduct(c.Context(), types.ID(id))
+	product, err := h.productService.GetProduct(c.UserContext(), types.ID(id))
 	if err != nil {
 		return fiber.NewError(fiber.StatusNotFound, "Product not found")
 	}
@@ -74,6 +80,7 @@ func (h *ProductHandler) GetByID(c *fiber.Ctx) error {
 
 // @Summary Update a product
 // @Tags products
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID"
@@ -92,9 +99,39 @@ func (h *ProductHandler) Update(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	product, err := h.productService.UpdateProduct(c.Context(), types.ID(id), req.Name, req.Price)
+	product, err := h.productService.UpdateProduct(c.UserContext(), types.ID(id), req.Name, req.Price, req.taxRate(), optionalID(req.CategoryID), req.SortOrder)
 	if err != nil {
-		return fiber.NewError(fiber.StatusNotFound, "Product not found")
+		return productError(err)
+	}
+
+	return c.JSON(NewProductResponse(product))
+}
+
+// @Summary Set the components of a combo
+// @Description Makes the product a combo of the given products, or an ordinary product again when components is empty. Selling a combo draws on each component's inventory.
+// @Tags products
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param components body SetComboComponentsRequest true "Combo components"
+// @Success 200 {object} ProductResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /products/{id}/components [put]
+func (h *ProductHandler) SetComponents(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	var req SetComboComponentsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	product, err := h.productService.SetComboComponents(c.UserContext(), types.ID(id), req.toInputs())
+	if err != nil {
+		return productError(err)
 	}
 
 	return c.JSON(NewProductResponse(product))
@@ -102,8 +139,10 @@ func (h *ProductHandler) Update(c *fiber.Ctx) error {
 
 // @Summary Delete a product
 // @Tags products
+// @Security BearerAuth
 // @Param id path string true "Product ID"
 // @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Router /products/{id} [delete]
 func (h *ProductHandler) Delete(c *fiber.Ctx) error {
@@ -111,9 +150,24 @@ func (h *ProductHandler) Delete(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
 	}
-	if err := h.productService.DeleteProduct(c.Context(), types.ID(id)); err != nil {
-		return fiber.NewError(fiber.StatusNotFound, "Product not found")
+	if err := h.productService.DeleteProduct(c.UserContext(), types.ID(id)); err != nil {
+		return productError(err)
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+func productError(err error) error {
+	var notFound *repositories.ErrNotFound
+	if errors.As(err, &notFound) {
+		if notFound.Entity == "Category" {
+			return fiber.NewError(fiber.StatusBadRequest, "Category not found")
+		}
+		return fiber.NewError(fiber.StatusNotFound, "Product not found")
+	}
+	var serviceErr *services.ServiceError
+	if errors.As(err, &serviceErr) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
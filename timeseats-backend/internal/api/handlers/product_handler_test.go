@@ -24,11 +24,14 @@ func newMockProductService() *mockProductService {
 	}
 }
 
-func (s *mockProductService) CreateProduct(ctx context.Context, name string, price int) (*models.Product, error) {
+func (s *mockProductService) CreateProduct(ctx context.Context, name string, price int, taxRate types.TaxRate, categoryID *types.ID, sortOrder int) (*models.Product, error) {
 	product := &models.Product{
-		ID:    types.ID("test-id-" + name),
-		Name:  name,
-		Price: price,
+		ID:         types.ID("test-id-" + name),
+		Name:       name,
+		Price:      price,
+		TaxRate:    taxRate,
+		CategoryID: categoryID,
+		SortOrder:  sortOrder,
 	}
 	s.products[product.ID] = product
 	return product, nil
@@ -49,15 +52,35 @@ func (s *mockProductService) GetAllProducts(ctx context.Context) ([]models.Produ
 	return products, nil
 }
 
-func (s *mockProductService) UpdateProduct(ctx context.Context, id types.ID, name string, price int) (*models.Product, error) {
+func (s *mockProductService) UpdateProduct(ctx context.Context, id types.ID, name string, price int, taxRate types.TaxRate, categoryID *types.ID, sortOrder int) (*models.Product, error) {
 	if product, exists := s.products[id]; exists {
 		product.Name = name
 		product.Price = price
+		product.TaxRate = taxRate
+		product.CategoryID = categoryID
+		product.SortOrder = sortOrder
 		return product, nil
 	}
 	return nil, &services.ServiceError{Message: "Product not found"}
 }
 
+func (s *mockProductService) SetComboComponents(ctx context.Context, id types.ID, components []services.ComboComponentInput) (*models.Product, error) {
+	product, exists := s.products[id]
+	if !exists {
+		return nil, &services.ServiceError{Message: "Product not found"}
+	}
+	product.Components = nil
+	for _, component := range components {
+		product.Components = append(product.Components, models.ComboComponent{
+			ComboID:   id,
+			ProductID: component.ProductID,
+			Quantity:  component.Quantity,
+			Product:   s.products[component.ProductID],
+		})
+	}
+	return product, nil
+}
+
 func (s *mockProductService) DeleteProduct(ctx context.Context, id types.ID) error {
 	if _, exists := s.products[id]; !exists {
 		return &services.ServiceError{Message: "Product not found"}
@@ -109,8 +132,8 @@ func TestProductHandler_GetAll(t *testing.T) {
 	handler := NewProductHandler(mockService)
 
 	ctx := context.Background()
-	mockService.CreateProduct(ctx, "Product 1", 1000)
-	mockService.CreateProduct(ctx, "Product 2", 2000)
+	mockService.CreateProduct(ctx, "Product 1", 1000, types.REDUCED_TAX_RATE, nil, 0)
+	mockService.CreateProduct(ctx, "Product 2", 2000, types.REDUCED_TAX_RATE, nil, 0)
 
 	app.Get("/products", handler.GetAll)
 
@@ -139,7 +162,7 @@ func TestProductHandler_GetByID(t *testing.T) {
 	handler := NewProductHandler(mockService)
 
 	ctx := context.Background()
-	product, _ := mockService.CreateProduct(ctx, "Test Product", 1000)
+	product, _ := mockService.CreateProduct(ctx, "Test Product", 1000, types.REDUCED_TAX_RATE, nil, 0)
 
 	app.Get("/products/:id", handler.GetByID)
 
@@ -168,7 +191,7 @@ func TestProductHandler_Update(t *testing.T) {
 	handler := NewProductHandler(mockService)
 
 	ctx := context.Background()
-	product, _ := mockService.CreateProduct(ctx, "Test Product", 1000)
+	product, _ := mockService.CreateProduct(ctx, "Test Product", 1000, types.REDUCED_TAX_RATE, nil, 0)
 
 	app.Put("/products/:id", handler.Update)
 
@@ -208,7 +231,7 @@ func TestProductHandler_Delete(t *testing.T) {
 	handler := NewProductHandler(mockService)
 
 	ctx := context.Background()
-	product, _ := mockService.CreateProduct(ctx, "Test Product", 1000)
+	product, _ := mockService.CreateProduct(ctx, "Test Product", 1000, types.REDUCED_TAX_RATE, nil, 0)
 
 	app.Delete("/products/:id", handler.Delete)
 
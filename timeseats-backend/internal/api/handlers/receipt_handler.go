@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/url"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:embed templates/receipt.html
+var receiptFS embed.FS
+
+var receiptTemplate = template.Must(template.New("receipt.html").Funcs(template.FuncMap{
+	"yen":     formatYen,
+	"neg":     func(n int) int { return -n },
+	"serial":  func(n int) string { return fmt.Sprintf("%06d", n) },
+	"reduced": func(rate types.TaxRate) bool { return rate == types.REDUCED_TAX_RATE },
+}).ParseFS(receiptFS, "templates/receipt.html"))
+
+type ReceiptHandler struct {
+	receiptService services.ReceiptService
+}
+
+func NewReceiptHandler(receiptService services.ReceiptService) *ReceiptHandler {
+	return &ReceiptHandler{receiptService: receiptService}
+}
+
+// @Summary Issue the receipt of a paid order
+// @Description The first call issues the receipt with a new serial number; later calls reprint it as a copy. The receipt is then shown by GET /orders/{id}/receipt and /orders/{id}/receipt/pdf.
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} PrintReceiptResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /orders/{id}/receipt [post]
+func (h *ReceiptHandler) Issue(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	receipt, err := h.receiptService.PrintReceipt(c.UserContext(), types.ID(id))
+	if err != nil {
+		return receiptError(err)
+	}
+
+	return c.JSON(PrintReceiptResponse{
+		SerialNumber: receipt.Receipt.SerialNumber,
+		IsCopy:       receipt.IsCopy,
+	})
+}
+
+// @Summary Show the issued receipt of an order
+// @Description Shows the receipt as it was last issued or reprinted, marked as a copy after a reprint. Showing it does not count as a print. Opened in a browser, the login token may be passed in the token query parameter.
+// @Tags orders
+// @Security BearerAuth
+// @Produce html
+// @Param id path string true "Order ID"
+// @Success 200 {string} string "HTML page"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /orders/{id}/receipt [get]
+func (h *ReceiptHandler) HTML(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	receipt, err := h.receiptService.GetReceipt(c.UserContext(), types.ID(id))
+	if err != nil {
+		return receiptError(err)
+	}
+
+	var buf bytes.Buffer
+	if err := receiptTemplate.Execute(&buf, receipt); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Type("html", "utf-8")
+	return c.Send(buf.Bytes())
+}
+
+// @Summary Show the issued receipt of an order as a PDF
+// @Description Shows the receipt as it was last issued or reprinted, marked as a copy after a reprint. Showing it does not count as a print.
+// @Tags orders
+// @Security BearerAuth
+// @Produce application/pdf
+// @Param id path string true "Order ID"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /orders/{id}/receipt/pdf [get]
+func (h *ReceiptHandler) PDF(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	var buf bytes.Buffer
+	receipt, err := h.receiptService.WriteReceiptPDF(c.UserContext(), types.ID(id), &buf)
+	if err != nil {
+		return receiptError(err)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="receipt-%06d.pdf"`, receipt.Receipt.SerialNumber))
+	return c.Send(buf.Bytes())
+}
+
+func receiptError(err error) error {
+	var notFound *repositories.ErrNotFound
+	if errors.As(err, &notFound) {
+		return fiber.NewError(fiber.StatusNotFound, "Order not found")
+	}
+	if errors.Is(err, services.ErrReceiptNotPaid) || errors.Is(err, services.ErrReceiptNotIssued) || errors.Is(err, services.ErrInvalidOrderStatus) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	var serviceErr *services.ServiceError
+	if errors.As(err, &serviceErr) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockReceiptService struct {
+	prints int
+}
+
+func (s *mockReceiptService) PrintReceipt(ctx context.Context, orderID types.ID) (*services.ReceiptDocument, error) {
+	switch orderID {
+	case "unknown":
+		return nil, repositories.NewErrNotFound("Order", orderID)
+	case "unpaid":
+		return nil, services.ErrReceiptNotPaid
+	}
+	s.prints++
+	return s.GetReceipt(ctx, orderID)
+}
+
+func (s *mockReceiptService) GetReceipt(ctx context.Context, orderID types.ID) (*services.ReceiptDocument, error) {
+	switch {
+	case orderID == "unknown":
+		return nil, repositories.NewErrNotFound("Order", orderID)
+	case s.prints == 0:
+		return nil, services.ErrReceiptNotIssued
+	}
+	order := &models.Order{
+		ID:            orderID,
+		TicketNumber:  "A001",
+		TotalAmount:   540,
+		IsPaid:        true,
+		PaymentMethod: types.PAYPAY,
+		Items: []models.OrderItem{
+			{ProductID: "prod1", Product: &models.Product{Name: "焼きそば"}, Quantity: 2, Price: 270, TaxRate: types.REDUCED_TAX_RATE},
+		},
+	}
+	now := time.Now()
+	return &services.ReceiptDocument{
+		Issuer:  services.ReceiptIssuer{Name: "生徒会", RegistrationNumber: "T1234567890123"},
+		Receipt: models.Receipt{OrderID: orderID, SerialNumber: 7, IssuedAt: now, PrintCount: s.prints, LastPrintedAt: now},
+		Order:   order,
+		Taxes:   services.TaxBreakdown(order),
+		IsCopy:  s.prints > 1,
+	}, nil
+}
+
+func (s *mockReceiptService) WriteReceiptPDF(ctx context.Context, orderID types.ID, w io.Writer) (*services.ReceiptDocument, error) {
+	receipt, err := s.GetReceipt(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.WriteString(w, "%PDF-1.4\n")
+	return receipt, err
+}
+
+func TestReceiptHandler_HTML(t *testing.T) {
+	app := fiber.New()
+	service := &mockReceiptService{}
+	handler := NewReceiptHandler(service)
+	app.Post("/orders/:id/receipt", handler.Issue)
+	app.Get("/orders/:id/receipt", handler.HTML)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders/order-1/receipt", nil))
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusConflict {
+		t.Errorf("Expected status code %d before the receipt is issued, got %d", fiber.StatusConflict, resp.StatusCode)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("POST", "/orders/order-1/receipt", nil))
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	var issued PrintReceiptResponse
+	json.NewDecoder(resp.Body).Decode(&issued)
+	if resp.StatusCode != fiber.StatusOK || issued.SerialNumber != 7 || issued.IsCopy {
+		t.Errorf("Expected receipt No. 7 to be issued, got %d %+v", resp.StatusCode, issued)
+	}
+
+	// Showing the receipt again and again does not make it a copy.
+	var body []byte
+	for range 2 {
+		resp, err = app.Test(httptest.NewRequest("GET", "/orders/order-1/receipt", nil))
+		if err != nil {
+			t.Fatalf("Failed to test request: %v", err)
+		}
+		if resp.StatusCode != fiber.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", fiber.StatusOK, resp.StatusCode)
+		}
+		body, _ = io.ReadAll(resp.Body)
+	}
+	for _, want := range []string{"No. 000007", "T1234567890123", "焼きそば ※", "8%対象", "¥540", "¥40", "PayPay"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected the receipt to contain %q", want)
+		}
+	}
+	if strings.Contains(string(body), "再発行") {
+		t.Error("Expected the first print not to be marked as a copy")
+	}
+	if service.prints != 1 {
+		t.Errorf("Expected one print, got %d", service.prints)
+	}
+
+	app.Test(httptest.NewRequest("POST", "/orders/order-1/receipt", nil))
+	resp, _ = app.Test(httptest.NewRequest("GET", "/orders/order-1/receipt", nil))
+	body, _ = io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "【再発行（控え）】") {
+		t.Error("Expected the second print to be marked as a copy")
+	}
+
+	for id, status := range map[string]int{"unknown": fiber.StatusNotFound, "unpaid": fiber.StatusConflict} {
+		resp, err := app.Test(httptest.NewRequest("POST", "/orders/"+id+"/receipt", nil))
+		if err != nil {
+			t.Fatalf("Failed to test request: %v", err)
+		}
+		if resp.StatusCode != status {
+			t.Errorf("Expected status code %d for %s, got %d", status, id, resp.StatusCode)
+		}
+	}
+}
+
+func TestReceiptHandler_PDF(t *testing.T) {
+	app := fiber.New()
+	app.Get("/orders/:id/receipt/pdf", NewReceiptHandler(&mockReceiptService{prints: 1}).PDF)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders/order-1/receipt/pdf", nil))
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK || resp.Header.Get("Content-Type") != "application/pdf" {
+		t.Fatalf("Expected a PDF, got status %d and %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if disposition := resp.Header.Get("Content-Disposition"); !strings.Contains(disposition, "receipt-000007.pdf") {
+		t.Errorf("Expected the file to be named after the serial number, got %q", disposition)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.HasPrefix(string(body), "%PDF-") {
+		t.Errorf("Expected a PDF file")
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type RefundHandler struct {
+	refundService services.RefundService
+}
+
+func NewRefundHandler(refundService services.RefundService) *RefundHandler {
+	return &RefundHandler{refundService: refundService}
+}
+
+// @Summary Refund an order
+// @Description Refunds the listed items of a confirmed order, or all of it when no items are given. The logged-in admin is recorded as the approver. Restocked items go back into the slot's inventory; the others are written off.
+// @Tags orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param refund body RefundRequest true "Refund"
+// @Success 201 {object} RefundResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /orders/{id}/refunds [post]
+func (h *RefundHandler) Create(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	var req RefundRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	input := services.RefundInput{
+		Restock: req.Restock,
+		Reason:  req.Reason,
+		Method:  req.Method,
+	}
+	for _, item := range req.Items {
+		input.Items = append(input.Items, services.RefundItemInput{
+			OrderItemID: types.ID(item.OrderItemID),
+			Quantity:    item.Quantity,
+			Restock:     item.Restock,
+		})
+	}
+
+	refund, err := h.refundService.RefundOrder(c.UserContext(), types.ID(id), input)
+	if err != nil {
+		return refundError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(NewRefundResponse(refund))
+}
+
+// @Summary Get the refunds of an order
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {array} RefundResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /orders/{id}/refunds [get]
+func (h *RefundHandler) GetByOrder(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	refunds, err := h.refundService.GetRefunds(c.UserContext(), types.ID(id))
+	if err != nil {
+		return refundError(err)
+	}
+
+	return c.JSON(NewRefundResponseList(refunds))
+}
+
+func refundError(err error) error {
+	var notFound *repositories.ErrNotFound
+	switch {
+	case errors.As(err, &notFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrRefundNotAllowed), errors.Is(err, services.ErrRefundExceedsOrder):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	var serviceErr *services.ServiceError
+	if errors.As(err, &serviceErr) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockRefundService struct {
+	input services.RefundInput
+}
+
+func (s *mockRefundService) RefundOrder(ctx context.Context, orderID types.ID, input services.RefundInput) (*models.Refund, error) {
+	if orderID == "reserved" {
+		return nil, services.ErrRefundNotAllowed
+	}
+	s.input = input
+	refund := &models.Refund{ID: "refund-1", OrderID: orderID, Reason: input.Reason, Method: types.CASH}
+	for _, item := range input.Items {
+		refund.Amount += 400 * item.Quantity
+		refund.Items = append(refund.Items, models.RefundItem{OrderItemID: item.OrderItemID, Quantity: item.Quantity, Amount: 400 * item.Quantity, Restocked: item.Restock})
+	}
+	return refund, nil
+}
+
+func (s *mockRefundService) GetRefunds(ctx context.Context, orderID types.ID) ([]models.Refund, error) {
+	return nil, nil
+}
+
+func TestRefundHandler_Create(t *testing.T) {
+	app := fiber.New()
+	mockService := &mockRefundService{}
+	handler := NewRefundHandler(mockService)
+
+	app.Post("/orders/:id/refunds", handler.Create)
+
+	body, _ := json.Marshal(RefundRequest{
+		Reason: "誤注文",
+		Items:  []RefundItemInput{{OrderItemID: "item-1", Quantity: 2, Restock: true}},
+	})
+	req := httptest.NewRequest("POST", "/orders/order-1/refunds", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d", fiber.StatusCreated, resp.StatusCode)
+	}
+
+	var response RefundResponse
+	json.NewDecoder(resp.Body).Decode(&response)
+	if response.Amount != 800 || len(response.Items) != 1 || !response.Items[0].Restocked {
+		t.Errorf("Unexpected refund: %+v", response)
+	}
+	if len(mockService.input.Items) != 1 || mockService.input.Items[0].OrderItemID != "item-1" {
+		t.Errorf("Expected the item to be passed on, got %+v", mockService.input)
+	}
+
+	req = httptest.NewRequest("POST", "/orders/reserved/refunds", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusConflict {
+		t.Errorf("Expected status code %d, got %d", fiber.StatusConflict, resp.StatusCode)
+	}
+}
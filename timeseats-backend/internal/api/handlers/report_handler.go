@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReportHandler struct {
+	reportService services.ReportService
+}
+
+func NewReportHandler(reportService services.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+// @Summary Get the sales summary
+// @Description Totals and average order value of paid or confirmed orders, with the count and value of cancelled orders.
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start of the time range (RFC3339, inclusive)"
+// @Param to query string false "End of the time range (RFC3339, exclusive)"
+// @Param salesSlotId query string false "Sales slot ID"
+// @Param terminalId query string false "Terminal ID"
+// @Success 200 {object} SalesSummaryResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /reports/summary [get]
+func (h *ReportHandler) Summary(c *fiber.Ctx) error {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		return err
+	}
+
+	summary, err := h.reportService.GetSummary(c.UserContext(), filter)
+	if err != nil {
+		return reportError(err)
+	}
+
+	return c.JSON(NewSalesSummaryResponse(summary))
+}
+
+// @Summary Get revenue and units per product per sales slot
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start of the time range (RFC3339, inclusive)"
+// @Param to query string false "End of the time range (RFC3339, exclusive)"
+// @Param salesSlotId query string false "Sales slot ID"
+// @Param terminalId query string false "Terminal ID"
+// @Success 200 {array} ProductSalesResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /reports/products [get]
+func (h *ReportHandler) Products(c *fiber.Ctx) error {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		return err
+	}
+
+	rows, err := h.reportService.GetProductSales(c.UserContext(), filter)
+	if err != nil {
+		return reportError(err)
+	}
+
+	return c.JSON(NewProductSalesResponseList(rows))
+}
+
+// @Summary Get units per stocked product per sales slot
+// @Description Counts each product sold on its own or inside a combo; comboUnits is the part sold inside combos.
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start of the time range (RFC3339, inclusive)"
+// @Param to query string false "End of the time range (RFC3339, exclusive)"
+// @Param salesSlotId query string false "Sales slot ID"
+// @Param terminalId query string false "Terminal ID"
+// @Success 200 {array} ComponentSalesResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /reports/components [get]
+func (h *ReportHandler) Components(c *fiber.Ctx) error {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		return err
+	}
+
+	rows, err := h.reportService.GetComponentSales(c.UserContext(), filter)
+	if err != nil {
+		return reportError(err)
+	}
+
+	return c.JSON(NewComponentSalesResponseList(rows))
+}
+
+// @Summary Get totals per payment method
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start of the time range (RFC3339, inclusive)"
+// @Param to query string false "End of the time range (RFC3339, exclusive)"
+// @Param salesSlotId query string false "Sales slot ID"
+// @Param terminalId query string false "Terminal ID"
+// @Success 200 {array} PaymentMethodSalesResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /reports/payment-methods [get]
+func (h *ReportHandler) PaymentMethods(c *fiber.Ctx) error {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		return err
+	}
+
+	rows, err := h.reportService.GetPaymentMethodSales(c.UserContext(), filter)
+	if err != nil {
+		return reportError(err)
+	}
+
+	return c.JSON(NewPaymentMethodSalesResponseList(rows))
+}
+
+// @Summary Get sales per hour
+// @Tags reports
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start of the time range (RFC3339, inclusive)"
+// @Param to query string false "End of the time range (RFC3339, exclusive)"
+// @Param salesSlotId query string false "Sales slot ID"
+// @Param terminalId query string false "Terminal ID"
+// @Success 200 {array} HourlySalesResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /reports/hourly [get]
+func (h *ReportHandler) Hourly(c *fiber.Ctx) error {
+	filter, err := parseReportFilter(c)
+	if err != nil {
+		return err
+	}
+
+	rows, err := h.reportService.GetHourlySales(c.UserContext(), filter)
+	if err != nil {
+		return reportError(err)
+	}
+
+	return c.JSON(NewHourlySalesResponseList(rows))
+}
+
+func parseReportFilter(c *fiber.Ctx) (repositories.ReportFilter, error) {
+	filter := repositories.ReportFilter{
+		SalesSlotID: types.ID(c.Query("salesSlotId")),
+		TerminalID:  types.ID(c.Query("terminalId")),
+	}
+
+	var err error
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+func reportError(err error) error {
+	var serviceErr *services.ServiceError
+	if errors.As(err, &serviceErr) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
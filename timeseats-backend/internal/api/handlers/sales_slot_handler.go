@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/url"
 	"time"
 
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
 	"github.com/gofiber/fiber/v2"
@@ -19,6 +21,7 @@ func NewSalesSlotHandler(salesSlotService services.SalesSlotService) *SalesSlotH
 
 // @Summary Create a new sales slot
 // @Tags sales-slots
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param slot body CreateSalesSlotRequest true "Sales slot information"
@@ -41,7 +44,7 @@ func (h *SalesSlotHandler) Create(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid end time format")
 	}
 
-	slot, err := h.salesSlotService.CreateSalesSlot(c.Context(), startTime, endTime)
+	slot, err := h.salesSlotService.CreateSalesSlot(c.UserContext(), startTime, endTime, req.TicketPrefix)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -51,11 +54,12 @@ func (h *SalesSlotHandler) Create(c *fiber.Ctx) error {
 
 // @Summary Get all sales slots
 // @Tags sales-slots
+// @Security BearerAuth
 // @Produce json
 // @Success 200 {array} SalesSlotResponse
 // @Router /sales-slots [get]
 func (h *SalesSlotHandler) GetAll(c *fiber.Ctx) error {
-	slots, err := h.salesSlotService.GetAllSalesSlots(c.Context())
+	slots, err := h.salesSlotService.GetAllSalesSlots(c.UserContext())
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -65,6 +69,7 @@ func (h *SalesSlotHandler) GetAll(c *fiber.Ctx) error {
 
 // @Summary Get a sales slot by ID
 // @Tags sales-slots
+// @Security BearerAuth
 // @Produce json
 // @Param id path string true "Sales Slot ID"
 // @Success 200 {object} SalesSlotResponse
@@ -75,7 +80,37 @@ func (h *SalesSlotHandler) GetByID(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
 	}
-	slot, err := h.salesSlotService.GetSalesSlot(c.Context(), types.ID(id))
+	slot, err := h.salesSlotService.GetSalesSlot(c.UserContext(), types.ID(id))
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Sales slot not found")
+	}
+
+	return c.JSON(NewSalesSlotResponse(slot))
+}
+
+// @Summary Update the ticket number prefix of a sales slot
+// @Tags sales-slots
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Sales Slot ID"
+// @Param prefix body UpdateTicketPrefixRequest true "Ticket prefix"
+// @Success 200 {object} SalesSlotResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /sales-slots/{id}/ticket-prefix [put]
+func (h *SalesSlotHandler) UpdateTicketPrefix(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	var req UpdateTicketPrefixRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	slot, err := h.salesSlotService.UpdateTicketPrefix(c.UserContext(), types.ID(id), req.TicketPrefix)
 	if err != nil {
 		return fiber.NewError(fiber.StatusNotFound, "Sales slot not found")
 	}
@@ -85,6 +120,7 @@ func (h *SalesSlotHandler) GetByID(c *fiber.Ctx) error {
 
 // @Summary Activate a sales slot
 // @Tags sales-slots
+// @Security BearerAuth
 // @Produce json
 // @Param id path string true "Sales Slot ID"
 // @Success 200 {object} SalesSlotResponse
@@ -95,16 +131,17 @@ func (h *SalesSlotHandler) Activate(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
 	}
-	if err := h.salesSlotService.ActivateSalesSlot(c.Context(), types.ID(id)); err != nil {
+	if err := h.salesSlotService.ActivateSalesSlot(c.UserContext(), types.ID(id)); err != nil {
 		return fiber.NewError(fiber.StatusNotFound, "Sales slot not found")
 	}
 
-	slot, _ := h.salesSlotService.GetSalesSlot(c.Context(), types.ID(id)) // id is already unescaped
+	slot, _ := h.salesSlotService.GetSalesSlot(c.UserContext(), types.ID(id)) // id is already unescaped
 	return c.JSON(NewSalesSlotResponse(slot))
 }
 
 // @Summary Deactivate a sales slot
 // @Tags sales-slots
+// @Security BearerAuth
 // @Produce json
 // @Param id path string true "Sales Slot ID"
 // @Success 200 {object} SalesSlotResponse
@@ -115,16 +152,17 @@ func (h *SalesSlotHandler) Deactivate(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
 	}
-	if err := h.salesSlotService.DeactivateSalesSlot(c.Context(), types.ID(id)); err != nil {
+	if err := h.salesSlotService.DeactivateSalesSlot(c.UserContext(), types.ID(id)); err != nil {
 		return fiber.NewError(fiber.StatusNotFound, "Sales slot not found")
 	}
 
-	slot, _ := h.salesSlotService.GetSalesSlot(c.Context(), types.ID(id))
+	slot, _ := h.salesSlotService.GetSalesSlot(c.UserContext(), types.ID(id))
 	return c.JSON(NewSalesSlotResponse(slot))
 }
 
 // @Summary Add a product to a sales slot
 // @Tags sales-slots
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Sales Slot ID"
@@ -144,11 +182,14 @@ func (h *SalesSlotHandler) AddProduct(c *fiber.Ctx) error {
 	}
 
 	inventory, err := h.salesSlotService.AddProductToSlot(
-		c.Context(),
+		c.UserContext(),
 		types.ID(id),
 		types.ID(req.ProductID),
 		req.InitialQuantity,
 	)
+	if errors.Is(err, services.ErrComboInventory) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -157,7 +198,9 @@ func (h *SalesSlotHandler) AddProduct(c *fiber.Ctx) error {
 }
 
 // @Summary Get all products in a sales slot
+// @Description Products are in menu order, the same as GET /products.
 // @Tags sales-slots
+// @Security BearerAuth
 // @Produce json
 // @Param id path string true "Sales Slot ID"
 // @Success 200 {array} ProductInventoryResponse
@@ -168,10 +211,36 @@ func (h *SalesSlotHandler) GetProducts(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
 	}
-	inventories, err := h.salesSlotService.GetSlotInventories(c.Context(), types.ID(id))
+	inventories, err := h.salesSlotService.GetSlotInventories(c.UserContext(), types.ID(id))
 	if err != nil {
 		return fiber.NewError(fiber.StatusNotFound, "Sales slot not found")
 	}
 
 	return c.JSON(inventories)
 }
+
+// @Summary Get the menu of a sales slot
+// @Description Products on sale in the slot, in menu order, with how many can still be sold. A combo is listed when all its components are stocked in the slot, and is sold out as soon as any component runs out.
+// @Tags sales-slots
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Sales Slot ID"
+// @Success 200 {array} MenuItemResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /sales-slots/{id}/menu [get]
+func (h *SalesSlotHandler) GetMenu(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+	menu, err := h.salesSlotService.GetSlotMenu(c.UserContext(), types.ID(id))
+	if err != nil {
+		var notFound *repositories.ErrNotFound
+		if errors.As(err, &notFound) {
+			return fiber.NewError(fiber.StatusNotFound, "Sales slot not found")
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(NewMenuItemResponseList(menu))
+}
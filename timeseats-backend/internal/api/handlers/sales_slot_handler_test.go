@@ -27,17 +27,26 @@ func newMockSalesSlotService() *mockSalesSlotService {
 	}
 }
 
-func (s *mockSalesSlotService) CreateSalesSlot(ctx context.Context, startTime, endTime time.Time) (*models.SalesSlot, error) {
+func (s *mockSalesSlotService) CreateSalesSlot(ctx context.Context, startTime, endTime time.Time, ticketPrefix string) (*models.SalesSlot, error) {
 	slot := &models.SalesSlot{
-		ID:        types.ID("test-id"),
-		StartTime: startTime,
-		EndTime:   endTime,
-		IsActive:  false,
+		ID:           types.ID("test-id"),
+		StartTime:    startTime,
+		EndTime:      endTime,
+		IsActive:     false,
+		TicketPrefix: ticketPrefix,
 	}
 	s.slots[slot.ID] = slot
 	return slot, nil
 }
 
+func (s *mockSalesSlotService) UpdateTicketPrefix(ctx context.Context, id types.ID, ticketPrefix string) (*models.SalesSlot, error) {
+	if slot, exists := s.slots[id]; exists {
+		slot.TicketPrefix = ticketPrefix
+		return slot, nil
+	}
+	return nil, &services.ServiceError{Message: "Sales slot not found"}
+}
+
 func (s *mockSalesSlotService) GetSalesSlot(ctx context.Context, id types.ID) (*models.SalesSlot, error) {
 	if slot, exists := s.slots[id]; exists {
 		return slot, nil
@@ -95,6 +104,19 @@ func (s *mockSalesSlotService) UpdateInventory(ctx context.Context, slotID, prod
 	return nil
 }
 
+func (s *mockSalesSlotService) GetSlotMenu(ctx context.Context, slotID types.ID) ([]services.MenuItem, error) {
+	var menu []services.MenuItem
+	for _, inv := range s.inventories {
+		if inv.SalesSlotID == slotID {
+			menu = append(menu, services.MenuItem{
+				Product:           models.Product{ID: inv.ProductID},
+				AvailableQuantity: inv.GetAvailableQuantity(),
+			})
+		}
+	}
+	return menu, nil
+}
+
 func (s *mockSalesSlotService) GetSlotInventories(ctx context.Context, slotID types.ID) ([]models.ProductInventory, error) {
 	var inventories []models.ProductInventory
 	for _, inv := range s.inventories {
@@ -147,7 +169,7 @@ func TestSalesSlotHandler_Activate(t *testing.T) {
 	handler := NewSalesSlotHandler(mockService)
 
 	ctx := context.Background()
-	slot, _ := mockService.CreateSalesSlot(ctx, time.Now(), time.Now().Add(2*time.Hour))
+	slot, _ := mockService.CreateSalesSlot(ctx, time.Now(), time.Now().Add(2*time.Hour), "")
 
 	app.Put("/sales-slots/:id/activate", handler.Activate)
 
@@ -176,7 +198,7 @@ func TestSalesSlotHandler_AddProduct(t *testing.T) {
 	handler := NewSalesSlotHandler(mockService)
 
 	ctx := context.Background()
-	slot, _ := mockService.CreateSalesSlot(ctx, time.Now(), time.Now().Add(2*time.Hour))
+	slot, _ := mockService.CreateSalesSlot(ctx, time.Now(), time.Now().Add(2*time.Hour), "")
 
 	app.Post("/sales-slots/:id/products", handler.AddProduct)
 
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type StaffHandler struct {
+	staffService services.StaffService
+}
+
+func NewStaffHandler(staffService services.StaffService) *StaffHandler {
+	return &StaffHandler{staffService: staffService}
+}
+
+// @Summary Create a staff account
+// @Tags staff
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param staff body CreateStaffRequest true "Staff information"
+// @Success 201 {object} StaffResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /staff [post]
+func (h *StaffHandler) Create(c *fiber.Ctx) error {
+	var req CreateStaffRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	role, ok := types.ParseRole(req.Role)
+	if !ok {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid role")
+	}
+
+	staff, err := h.staffService.CreateStaff(c.UserContext(), req.Username, req.Password, role)
+	if err != nil {
+		return staffError(err, fiber.ErrInternalServerError)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(NewStaffResponse(staff))
+}
+
+// @Summary Get all staff accounts
+// @Tags staff
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} StaffResponse
+// @Router /staff [get]
+func (h *StaffHandler) GetAll(c *fiber.Ctx) error {
+	staff, err := h.staffService.GetAllStaff(c.UserContext())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(NewStaffResponseList(staff))
+}
+
+// @Summary Update the role or active flag of a staff account
+// @Tags staff
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Staff ID"
+// @Param staff body UpdateStaffRequest true "Staff information"
+// @Success 200 {object} StaffResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /staff/{id} [put]
+func (h *StaffHandler) Update(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	var req UpdateStaffRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	role, ok := types.ParseRole(req.Role)
+	if !ok {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid role")
+	}
+
+	staff, err := h.staffService.UpdateStaff(c.UserContext(), types.ID(id), role, req.IsActive)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Staff not found")
+	}
+
+	return c.JSON(NewStaffResponse(staff))
+}
+
+// @Summary Set the password of a staff account
+// @Description Ends all sessions of the account.
+// @Tags staff
+// @Security BearerAuth
+// @Accept json
+// @Param id path string true "Staff ID"
+// @Param password body ChangePasswordRequest true "New password"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /staff/{id}/password [put]
+func (h *StaffHandler) ChangePassword(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	var req ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := h.staffService.ChangePassword(c.UserContext(), types.ID(id), req.Password); err != nil {
+		return staffError(err, fiber.NewError(fiber.StatusNotFound, "Staff not found"))
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// @Summary Delete a staff account
+// @Tags staff
+// @Security BearerAuth
+// @Param id path string true "Staff ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /staff/{id} [delete]
+func (h *StaffHandler) Delete(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	if err := h.staffService.DeleteStaff(c.UserContext(), types.ID(id)); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Staff not found")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// staffError maps validation errors of the staff service to client errors
+// and anything else to fallback.
+func staffError(err error, fallback *fiber.Error) error {
+	if errors.Is(err, services.ErrDuplicateUsername) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	var serviceErr *services.ServiceError
+	if errors.As(err, &serviceErr) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return fallback
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TerminalHandler struct {
+	terminalService services.TerminalService
+}
+
+func NewTerminalHandler(terminalService services.TerminalService) *TerminalHandler {
+	return &TerminalHandler{terminalService: terminalService}
+}
+
+// @Summary Register a POS terminal
+// @Description The API key is returned only once and is sent in the X-Terminal-Key header.
+// @Tags terminals
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param terminal body CreateTerminalRequest true "Terminal information"
+// @Success 201 {object} TerminalKeyResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /terminals [post]
+func (h *TerminalHandler) Create(c *fiber.Ctx) error {
+	var req CreateTerminalRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	terminal, key, err := h.terminalService.RegisterTerminal(c.UserContext(), req.Name)
+	if err != nil {
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(TerminalKeyResponse{
+		Terminal: NewTerminalResponse(terminal),
+		APIKey:   key,
+	})
+}
+
+// @Summary Get all terminals
+// @Tags terminals
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} TerminalResponse
+// @Router /terminals [get]
+func (h *TerminalHandler) GetAll(c *fiber.Ctx) error {
+	terminals, err := h.terminalService.GetAllTerminals(c.UserContext())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(NewTerminalResponseList(terminals))
+}
+
+// @Summary Issue a new API key for a terminal
+// @Description The previous key stops working. A revoked terminal is reinstated.
+// @Tags terminals
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Terminal ID"
+// @Success 200 {object} TerminalKeyResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /terminals/{id}/rotate-key [post]
+func (h *TerminalHandler) RotateKey(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	terminal, key, err := h.terminalService.RotateKey(c.UserContext(), types.ID(id))
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Terminal not found")
+	}
+
+	return c.JSON(TerminalKeyResponse{
+		Terminal: NewTerminalResponse(terminal),
+		APIKey:   key,
+	})
+}
+
+// @Summary Revoke the API key of a terminal
+// @Tags terminals
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Terminal ID"
+// @Success 200 {object} TerminalResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /terminals/{id}/revoke [put]
+func (h *TerminalHandler) Revoke(c *fiber.Ctx) error {
+	id, err := url.PathUnescape(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID format")
+	}
+
+	terminal, err := h.terminalService.RevokeTerminal(c.UserContext(), types.ID(id))
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Terminal not found")
+	}
+
+	return c.JSON(NewTerminalResponse(terminal))
+}
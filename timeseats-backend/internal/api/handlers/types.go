@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
 )
 
@@ -11,32 +14,119 @@ type ErrorResponse struct {
 	Message string `json:"message"`
 }
 
+// CreateProductRequest.Price includes consumption tax at TaxRate, 8 or 10
+// percent; the reduced 8% is used when it is left out.
 type CreateProductRequest struct {
-	Name  string `json:"name"`
-	Price int    `json:"price"`
+	Name       string  `json:"name"`
+	Price      int     `json:"price"`
+	TaxRate    int     `json:"taxRate,omitempty" example:"8"`
+	CategoryID *string `json:"categoryId,omitempty"`
+	SortOrder  int     `json:"sortOrder"`
+}
+
+func (r CreateProductRequest) taxRate() types.TaxRate {
+	return taxRateOrDefault(r.TaxRate)
 }
 
 type UpdateProductRequest struct {
-	Name  string `json:"name"`
-	Price int    `json:"price"`
+	Name       string  `json:"name"`
+	Price      int     `json:"price"`
+	TaxRate    int     `json:"taxRate,omitempty" example:"8"`
+	CategoryID *string `json:"categoryId,omitempty"`
+	SortOrder  int     `json:"sortOrder"`
+}
+
+func (r UpdateProductRequest) taxRate() types.TaxRate {
+	return taxRateOrDefault(r.TaxRate)
+}
+
+func taxRateOrDefault(rate int) types.TaxRate {
+	if rate == 0 {
+		return types.REDUCED_TAX_RATE
+	}
+	return types.TaxRate(rate)
 }
 
 type ProductResponse struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Price     int       `json:"price"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID             string                  `json:"id"`
+	Name           string                  `json:"name"`
+	Price          int                     `json:"price"`
+	TaxRate        int                     `json:"taxRate"`
+	CategoryID     *string                 `json:"categoryId"`
+	CategoryName   string                  `json:"categoryName,omitempty"`
+	SortOrder      int                     `json:"sortOrder"`
+	ModifierGroups []ModifierGroupResponse `json:"modifierGroups"`
+	IsCombo        bool                    `json:"isCombo"`
+	Components     []ComponentResponse     `json:"components"`
+	CreatedAt      time.Time               `json:"createdAt"`
+	UpdatedAt      time.Time               `json:"updatedAt"`
+}
+
+// ComponentResponse is one product inside a combo, or inside a combo line
+// of an order.
+type ComponentResponse struct {
+	ProductID   string `json:"productId"`
+	ProductName string `json:"productName,omitempty"`
+	Quantity    int    `json:"quantity"`
+}
+
+// ComboComponentRequest is one product inside a combo and how many of it a
+// single combo contains.
+type ComboComponentRequest struct {
+	ProductID string `json:"productId"`
+	Quantity  int    `json:"quantity"`
+}
+
+type SetComboComponentsRequest struct {
+	Components []ComboComponentRequest `json:"components"`
+}
+
+func (r SetComboComponentsRequest) toInputs() []services.ComboComponentInput {
+	inputs := make([]services.ComboComponentInput, len(r.Components))
+	for i, component := range r.Components {
+		inputs[i] = services.ComboComponentInput{
+			ProductID: types.ID(component.ProductID),
+			Quantity:  component.Quantity,
+		}
+	}
+	return inputs
 }
 
 func NewProductResponse(p *models.Product) ProductResponse {
-	return ProductResponse{
-		ID:        string(p.ID),
-		Name:      p.Name,
-		Price:     p.Price,
-		CreatedAt: p.CreatedAt,
-		UpdatedAt: p.UpdatedAt,
+	response := ProductResponse{
+		ID:             string(p.ID),
+		Name:           p.Name,
+		Price:          p.Price,
+		TaxRate:        int(p.TaxRate),
+		SortOrder:      p.SortOrder,
+		ModifierGroups: NewModifierGroupResponseList(p.ModifierGroups),
+		IsCombo:        p.IsCombo(),
+		Components:     make([]ComponentResponse, len(p.Components)),
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+	if p.CategoryID != nil {
+		categoryID := string(*p.CategoryID)
+		response.CategoryID = &categoryID
+	}
+	if p.Category != nil {
+		response.CategoryName = p.Category.Name
+	}
+	for i, component := range p.Components {
+		response.Components[i] = newComponentResponse(component.ProductID, component.Product, component.Quantity)
 	}
+	return response
+}
+
+func newComponentResponse(productID types.ID, product *models.Product, quantity int) ComponentResponse {
+	response := ComponentResponse{
+		ProductID: string(productID),
+		Quantity:  quantity,
+	}
+	if product != nil {
+		response.ProductName = product.Name
+	}
+	return response
 }
 
 func NewProductResponseList(products []models.Product) []ProductResponse {
@@ -47,9 +137,139 @@ func NewProductResponseList(products []models.Product) []ProductResponse {
 	return result
 }
 
+// optionalID converts an optional request ID to a domain ID.
+func optionalID(id *string) *types.ID {
+	if id == nil || *id == "" {
+		return nil
+	}
+	domainID := types.ID(*id)
+	return &domainID
+}
+
+// ModifierGroupRequest describes a group and all of its options. On update,
+// options are matched by ID; options without an ID are added and options
+// left out are removed. A maxSelect of 0 allows any number of options.
+type ModifierGroupRequest struct {
+	Name      string                  `json:"name"`
+	MinSelect int                     `json:"minSelect"`
+	MaxSelect int                     `json:"maxSelect"`
+	SortOrder int                     `json:"sortOrder"`
+	Options   []ModifierOptionRequest `json:"options"`
+}
+
+type ModifierOptionRequest struct {
+	ID         string `json:"id,omitempty"`
+	Name       string `json:"name"`
+	PriceDelta int    `json:"priceDelta"`
+	SortOrder  int    `json:"sortOrder"`
+}
+
+func (r ModifierGroupRequest) toInput() services.ModifierGroupInput {
+	input := services.ModifierGroupInput{
+		Name:      r.Name,
+		MinSelect: r.MinSelect,
+		MaxSelect: r.MaxSelect,
+		SortOrder: r.SortOrder,
+		Options:   make([]services.ModifierOptionInput, len(r.Options)),
+	}
+	for i, option := range r.Options {
+		input.Options[i] = services.ModifierOptionInput{
+			ID:         types.ID(option.ID),
+			Name:       option.Name,
+			PriceDelta: option.PriceDelta,
+			SortOrder:  option.SortOrder,
+		}
+	}
+	return input
+}
+
+type ModifierGroupResponse struct {
+	ID        string                   `json:"id"`
+	ProductID string                   `json:"productId"`
+	Name      string                   `json:"name"`
+	Required  bool                     `json:"required"`
+	MinSelect int                      `json:"minSelect"`
+	MaxSelect int                      `json:"maxSelect"`
+	SortOrder int                      `json:"sortOrder"`
+	Options   []ModifierOptionResponse `json:"options"`
+}
+
+type ModifierOptionResponse struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	PriceDelta int    `json:"priceDelta"`
+	SortOrder  int    `json:"sortOrder"`
+}
+
+func NewModifierGroupResponse(g *models.ModifierGroup) ModifierGroupResponse {
+	options := make([]ModifierOptionResponse, len(g.Options))
+	for i, option := range g.Options {
+		options[i] = ModifierOptionResponse{
+			ID:         string(option.ID),
+			Name:       option.Name,
+			PriceDelta: option.PriceDelta,
+			SortOrder:  option.SortOrder,
+		}
+	}
+	return ModifierGroupResponse{
+		ID:        string(g.ID),
+		ProductID: string(g.ProductID),
+		Name:      g.Name,
+		Required:  g.IsRequired(),
+		MinSelect: g.MinSelect,
+		MaxSelect: g.MaxSelect,
+		SortOrder: g.SortOrder,
+		Options:   options,
+	}
+}
+
+func NewModifierGroupResponseList(groups []models.ModifierGroup) []ModifierGroupResponse {
+	result := make([]ModifierGroupResponse, len(groups))
+	for i, g := range groups {
+		result[i] = NewModifierGroupResponse(&g)
+	}
+	return result
+}
+
+type CategoryRequest struct {
+	Name      string `json:"name"`
+	SortOrder int    `json:"sortOrder"`
+}
+
+type CategoryResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	SortOrder int       `json:"sortOrder"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func NewCategoryResponse(c *models.Category) CategoryResponse {
+	return CategoryResponse{
+		ID:        string(c.ID),
+		Name:      c.Name,
+		SortOrder: c.SortOrder,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+func NewCategoryResponseList(categories []models.Category) []CategoryResponse {
+	result := make([]CategoryResponse, len(categories))
+	for i, c := range categories {
+		result[i] = NewCategoryResponse(&c)
+	}
+	return result
+}
+
 type CreateSalesSlotRequest struct {
-	StartTime string `json:"startTime"`
-	EndTime   string `json:"endTime"`
+	StartTime    string `json:"startTime"`
+	EndTime      string `json:"endTime"`
+	TicketPrefix string `json:"ticketPrefix"`
+}
+
+type UpdateTicketPrefixRequest struct {
+	TicketPrefix string `json:"ticketPrefix"`
 }
 
 type UpdateSalesSlotRequest struct {
@@ -58,22 +278,24 @@ type UpdateSalesSlotRequest struct {
 }
 
 type SalesSlotResponse struct {
-	ID        string    `json:"id"`
-	StartTime time.Time `json:"startTime"`
-	EndTime   time.Time `json:"endTime"`
-	IsActive  bool      `json:"isActive"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID           string    `json:"id"`
+	StartTime    time.Time `json:"startTime"`
+	EndTime      time.Time `json:"endTime"`
+	IsActive     bool      `json:"isActive"`
+	TicketPrefix string    `json:"ticketPrefix"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
 }
 
 func NewSalesSlotResponse(s *models.SalesSlot) SalesSlotResponse {
 	return SalesSlotResponse{
-		ID:        string(s.ID),
-		StartTime: s.StartTime,
-		EndTime:   s.EndTime,
-		IsActive:  s.IsActive,
-		CreatedAt: s.CreatedAt,
-		UpdatedAt: s.UpdatedAt,
+		ID:           string(s.ID),
+		StartTime:    s.StartTime,
+		EndTime:      s.EndTime,
+		IsActive:     s.IsActive,
+		TicketPrefix: s.TicketPrefix,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
 	}
 }
 
@@ -101,51 +323,235 @@ type ProductInventoryResponse struct {
 	UpdatedAt        time.Time `json:"updatedAt"`
 }
 
+type MenuItemResponse struct {
+	Product           ProductResponse `json:"product"`
+	AvailableQuantity int             `json:"availableQuantity"`
+	IsSoldOut         bool            `json:"isSoldOut"`
+}
+
+func NewMenuItemResponseList(menu []services.MenuItem) []MenuItemResponse {
+	result := make([]MenuItemResponse, len(menu))
+	for i, item := range menu {
+		result[i] = MenuItemResponse{
+			Product:           NewProductResponse(&item.Product),
+			AvailableQuantity: item.AvailableQuantity,
+			IsSoldOut:         item.IsSoldOut(),
+		}
+	}
+	return result
+}
+
 type CreateOrderRequest struct {
 	SalesSlotID   string                 `json:"salesSlotId"`
 	Items         []OrderItemCreateInput `json:"items"`
-	TicketNumber  string                 `json:"ticketNumber"`
+	TicketNumber  string                 `json:"ticketNumber,omitempty"`
 	PaymentMethod types.PaymentMethod    `json:"paymentMethod"`
 }
 
 type OrderItemCreateInput struct {
-	ProductID string `json:"productId"`
-	Quantity  int    `json:"quantity"`
+	ProductID string   `json:"productId"`
+	Quantity  int      `json:"quantity"`
+	OptionIDs []string `json:"optionIds,omitempty"`
+}
+
+func newOrderItemInputs(items []OrderItemCreateInput) []services.OrderItemInput {
+	var inputs []services.OrderItemInput
+	for _, item := range items {
+		optionIDs := make([]types.ID, len(item.OptionIDs))
+		for i, id := range item.OptionIDs {
+			optionIDs[i] = types.ID(id)
+		}
+		inputs = append(inputs, services.OrderItemInput{
+			ProductID: types.ID(item.ProductID),
+			Quantity:  item.Quantity,
+			OptionIDs: optionIDs,
+		})
+	}
+	return inputs
+}
+
+type UpdateOrderItemRequest struct {
+	Quantity int `json:"quantity"`
+}
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason"`
 }
 
 type PaymentUpdateRequest struct {
 	TransactionID string `json:"transactionId"`
 }
 
+// PaymentRequest records a cash or PayPay payment. A zero amount pays the
+// balance due; reference is the transaction ID of a payment taken on a
+// separate terminal. tendered is the cash the customer handed over, which
+// may not be less than the amount paid.
+type PaymentRequest struct {
+	Method    types.PaymentMethod `json:"method"`
+	Amount    int                 `json:"amount,omitempty"`
+	Reference string              `json:"reference,omitempty"`
+	Tendered  int                 `json:"tendered,omitempty"`
+	// Drawer lists the notes and coins on hand to make up the change from.
+	Drawer []DenominationCount `json:"drawer,omitempty"`
+}
+
+// RefundRequest refunds the listed items, or everything not refunded yet
+// when Items is empty. Method defaults to the order's payment method.
+type RefundRequest struct {
+	Items   []RefundItemInput   `json:"items,omitempty"`
+	Restock bool                `json:"restock"`
+	Reason  string              `json:"reason"`
+	Method  types.PaymentMethod `json:"method,omitempty"`
+}
+
+type RefundItemInput struct {
+	OrderItemID string `json:"orderItemId"`
+	Quantity    int    `json:"quantity"`
+	Restock     bool   `json:"restock"`
+}
+
 type OrderResponse struct {
-	ID            string              `json:"id"`
-	SalesSlotID   string              `json:"salesSlotId"`
-	Status        string              `json:"status"`
-	TotalAmount   int                 `json:"totalAmount"`
-	TicketNumber  string              `json:"ticketNumber"`
-	PaymentMethod string              `json:"paymentMethod"`
-	TransactionID *string             `json:"transactionId"`
-	IsPaid        bool                `json:"isPaid"`
-	IsDelivered   bool                `json:"isDelivered"`
-	Items         []OrderItemResponse `json:"items"`
-	CreatedAt     time.Time           `json:"createdAt"`
-	UpdatedAt     time.Time           `json:"updatedAt"`
+	ID             string              `json:"id"`
+	SalesSlotID    string              `json:"salesSlotId"`
+	Status         string              `json:"status"`
+	TotalAmount    int                 `json:"totalAmount"`
+	Subtotal       int                 `json:"subtotal"`
+	DiscountAmount int                 `json:"discountAmount"`
+	RefundedAmount int                 `json:"refundedAmount"`
+	IsRefunded     bool                `json:"isRefunded"`
+	PaidAmount     int                 `json:"paidAmount"`
+	BalanceDue     int                 `json:"balanceDue"`
+	TicketNumber   string              `json:"ticketNumber"`
+	PaymentMethod  string              `json:"paymentMethod"`
+	TransactionID  *string             `json:"transactionId"`
+	IsPaid         bool                `json:"isPaid"`
+	IsDelivered    bool                `json:"isDelivered"`
+	CancelReason   *string             `json:"cancelReason"`
+	ConfirmedAt    *time.Time          `json:"confirmedAt"`
+	PreparingAt    *time.Time          `json:"preparingAt"`
+	ReadyAt        *time.Time          `json:"readyAt"`
+	DeliveredAt    *time.Time          `json:"deliveredAt"`
+	CancelledAt    *time.Time          `json:"cancelledAt"`
+	TerminalID     *types.ID           `json:"terminalId"`
+	CreatedByID    *types.ID           `json:"createdById"`
+	PaidAt         *time.Time          `json:"paidAt"`
+	PaidTerminalID *types.ID           `json:"paidTerminalId"`
+	PaidByID       *types.ID           `json:"paidById"`
+	Items          []OrderItemResponse `json:"items"`
+	CreatedAt      time.Time           `json:"createdAt"`
+	UpdatedAt      time.Time           `json:"updatedAt"`
+	// Discounts add up to discountAmount, which is taken off subtotal to
+	// give totalAmount.
+	Discounts []OrderDiscountResponse `json:"discounts"`
+	// Taxes splits totalAmount by consumption tax rate.
+	Taxes []TaxAmountResponse `json:"taxes"`
+	// Payments is the order's ledger. Completed payments add up to
+	// paidAmount; the order is paid once balanceDue is zero.
+	Payments []PaymentResponse `json:"payments"`
+}
+
+// TaxAmountResponse is the part of an amount charged at one tax rate.
+// Amount includes tax; excludingTax is the same amount without it.
+type TaxAmountResponse struct {
+	Rate         int `json:"rate" example:"8"`
+	Amount       int `json:"amount"`
+	Tax          int `json:"tax"`
+	ExcludingTax int `json:"excludingTax"`
+}
+
+func NewTaxAmountResponseList(amounts []services.TaxAmount) []TaxAmountResponse {
+	result := make([]TaxAmountResponse, len(amounts))
+	for i, amount := range amounts {
+		result[i] = TaxAmountResponse{
+			Rate:         int(amount.Rate),
+			Amount:       amount.Amount,
+			Tax:          amount.Tax,
+			ExcludingTax: amount.ExcludingTax(),
+		}
+	}
+	return result
 }
 
+type OrderDiscountResponse struct {
+	ID           string    `json:"id"`
+	DiscountID   *types.ID `json:"discountId"`
+	OrderItemID  *types.ID `json:"orderItemId"`
+	Type         string    `json:"type"`
+	Name         string    `json:"name"`
+	Code         *string   `json:"code,omitempty"`
+	Value        int       `json:"value"`
+	Amount       int       `json:"amount"`
+	Reason       string    `json:"reason,omitempty"`
+	ApprovedByID *types.ID `json:"approvedById,omitempty"`
+}
+
+func newOrderDiscountResponse(d *models.OrderDiscount) OrderDiscountResponse {
+	return OrderDiscountResponse{
+		ID:           string(d.ID),
+		DiscountID:   d.DiscountID,
+		OrderItemID:  d.OrderItemID,
+		Type:         d.Type.String(),
+		Name:         d.Name,
+		Code:         d.Code,
+		Value:        d.Value,
+		Amount:       d.Amount,
+		Reason:       d.Reason,
+		ApprovedByID: d.ApprovedByID,
+	}
+}
+
+// OrderItemResponse gives the unit price including optionsAmount, the sum
+// of the price deltas of the chosen options.
 type OrderItemResponse struct {
-	ID        string `json:"id"`
-	ProductID string `json:"productId"`
-	Quantity  int    `json:"quantity"`
-	Price     int    `json:"price"`
+	ID               string                    `json:"id"`
+	ProductID        string                    `json:"productId"`
+	ProductName      string                    `json:"productName,omitempty"`
+	Quantity         int                       `json:"quantity"`
+	RefundedQuantity int                       `json:"refundedQuantity"`
+	Price            int                       `json:"price"`
+	OptionsAmount    int                       `json:"optionsAmount"`
+	TaxRate          int                       `json:"taxRate"`
+	DiscountAmount   int                       `json:"discountAmount"`
+	Options          []OrderItemOptionResponse `json:"options"`
+	Components       []ComponentResponse       `json:"components"`
+}
+
+type OrderItemOptionResponse struct {
+	OptionID   string `json:"optionId"`
+	GroupName  string `json:"groupName"`
+	Name       string `json:"name"`
+	PriceDelta int    `json:"priceDelta"`
 }
 
 func NewOrderItemResponse(item *models.OrderItem) OrderItemResponse {
-	return OrderItemResponse{
-		ID:        string(item.ID),
-		ProductID: string(item.ProductID),
-		Quantity:  item.Quantity,
-		Price:     item.Price,
+	options := make([]OrderItemOptionResponse, len(item.Options))
+	for i, option := range item.Options {
+		options[i] = OrderItemOptionResponse{
+			OptionID:   string(option.ModifierOptionID),
+			GroupName:  option.GroupName,
+			Name:       option.Name,
+			PriceDelta: option.PriceDelta,
+		}
+	}
+	response := OrderItemResponse{
+		ID:               string(item.ID),
+		ProductID:        string(item.ProductID),
+		Quantity:         item.Quantity,
+		RefundedQuantity: item.RefundedQuantity,
+		Price:            item.Price,
+		OptionsAmount:    item.OptionsAmount,
+		TaxRate:          int(item.TaxRate),
+		DiscountAmount:   item.DiscountAmount,
+		Options:          options,
+		Components:       make([]ComponentResponse, len(item.Components)),
+	}
+	for i, component := range item.Components {
+		response.Components[i] = newComponentResponse(component.ProductID, component.Product, component.Quantity)
+	}
+	if item.Product != nil {
+		response.ProductName = item.Product.Name
 	}
+	return response
 }
 
 func NewOrderResponse(o *models.Order) OrderResponse {
@@ -154,19 +560,44 @@ func NewOrderResponse(o *models.Order) OrderResponse {
 		items[i] = NewOrderItemResponse(&item)
 	}
 
+	discounts := make([]OrderDiscountResponse, len(o.Discounts))
+	for i, discount := range o.Discounts {
+		discounts[i] = newOrderDiscountResponse(&discount)
+	}
+
 	return OrderResponse{
-		ID:            string(o.ID),
-		SalesSlotID:   string(o.SalesSlotID),
-		Status:        o.Status.String(),
-		TotalAmount:   o.TotalAmount,
-		TicketNumber:  o.TicketNumber,
-		PaymentMethod: o.PaymentMethod.String(),
-		TransactionID: o.TransactionID,
-		IsPaid:        o.IsPaid,
-		IsDelivered:   o.IsDelivered,
-		Items:         items,
-		CreatedAt:     o.CreatedAt,
-		UpdatedAt:     o.UpdatedAt,
+		ID:             string(o.ID),
+		SalesSlotID:    string(o.SalesSlotID),
+		Status:         o.Status.String(),
+		TotalAmount:    o.TotalAmount,
+		Subtotal:       o.Subtotal(),
+		DiscountAmount: o.DiscountAmount,
+		RefundedAmount: o.RefundedAmount,
+		IsRefunded:     o.IsFullyRefunded(),
+		PaidAmount:     o.PaidAmount,
+		BalanceDue:     o.BalanceDue(),
+		TicketNumber:   o.TicketNumber,
+		PaymentMethod:  o.PaymentMethod.String(),
+		TransactionID:  o.TransactionID,
+		IsPaid:         o.IsPaid,
+		IsDelivered:    o.IsDelivered,
+		CancelReason:   o.CancelReason,
+		ConfirmedAt:    o.ConfirmedAt,
+		PreparingAt:    o.PreparingAt,
+		ReadyAt:        o.ReadyAt,
+		DeliveredAt:    o.DeliveredAt,
+		CancelledAt:    o.CancelledAt,
+		TerminalID:     o.TerminalID,
+		CreatedByID:    o.CreatedByID,
+		PaidAt:         o.PaidAt,
+		PaidTerminalID: o.PaidTerminalID,
+		PaidByID:       o.PaidByID,
+		Items:          items,
+		CreatedAt:      o.CreatedAt,
+		UpdatedAt:      o.UpdatedAt,
+		Discounts:      discounts,
+		Taxes:          NewTaxAmountResponseList(services.TaxBreakdown(o)),
+		Payments:       NewPaymentResponseList(o.Payments),
 	}
 }
 
@@ -177,3 +608,756 @@ func NewOrderResponseList(orders []models.Order) []OrderResponse {
 	}
 	return result
 }
+
+type RefundResponse struct {
+	ID           string               `json:"id"`
+	OrderID      string               `json:"orderId"`
+	Amount       int                  `json:"amount"`
+	Reason       string               `json:"reason"`
+	Method       string               `json:"method"`
+	ApprovedByID *types.ID            `json:"approvedById"`
+	TerminalID   *types.ID            `json:"terminalId"`
+	Items        []RefundItemResponse `json:"items"`
+	// Payments splits amount by the payments it was taken off; method is
+	// how the first part was paid back.
+	Payments  []RefundPaymentResponse `json:"payments"`
+	CreatedAt time.Time               `json:"createdAt"`
+}
+
+type RefundPaymentResponse struct {
+	PaymentID *types.ID `json:"paymentId"`
+	Method    string    `json:"method"`
+	Amount    int       `json:"amount"`
+}
+
+type RefundItemResponse struct {
+	OrderItemID string `json:"orderItemId"`
+	ProductID   string `json:"productId"`
+	Quantity    int    `json:"quantity"`
+	Amount      int    `json:"amount"`
+	Restocked   bool   `json:"restocked"`
+}
+
+func NewRefundResponse(r *models.Refund) RefundResponse {
+	items := make([]RefundItemResponse, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = RefundItemResponse{
+			OrderItemID: string(item.OrderItemID),
+			ProductID:   string(item.ProductID),
+			Quantity:    item.Quantity,
+			Amount:      item.Amount,
+			Restocked:   item.Restocked,
+		}
+	}
+	payments := make([]RefundPaymentResponse, len(r.Payments))
+	for i, line := range r.Payments {
+		payments[i] = RefundPaymentResponse{
+			PaymentID: line.PaymentID,
+			Method:    line.Method.String(),
+			Amount:    line.Amount,
+		}
+	}
+	return RefundResponse{
+		ID:           string(r.ID),
+		OrderID:      string(r.OrderID),
+		Amount:       r.Amount,
+		Reason:       r.Reason,
+		Method:       r.Method.String(),
+		ApprovedByID: r.ApprovedByID,
+		TerminalID:   r.TerminalID,
+		Items:        items,
+		Payments:     payments,
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+func NewRefundResponseList(refunds []models.Refund) []RefundResponse {
+	result := make([]RefundResponse, len(refunds))
+	for i, r := range refunds {
+		result[i] = NewRefundResponse(&r)
+	}
+	return result
+}
+
+type PickupBoardResponse struct {
+	Preparing []string  `json:"preparing"`
+	Ready     []string  `json:"ready"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func NewPickupBoardResponse(b *services.PickupBoard) PickupBoardResponse {
+	return PickupBoardResponse{
+		Preparing: b.Preparing,
+		Ready:     b.Ready,
+		UpdatedAt: b.UpdatedAt,
+	}
+}
+
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type LoginResponse struct {
+	Token     string        `json:"token"`
+	ExpiresAt time.Time     `json:"expiresAt"`
+	Staff     StaffResponse `json:"staff"`
+}
+
+type CreateStaffRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role" enums:"ADMIN,CASHIER,KITCHEN,PICKUP"`
+}
+
+type UpdateStaffRequest struct {
+	Role     string `json:"role" enums:"ADMIN,CASHIER,KITCHEN,PICKUP"`
+	IsActive bool   `json:"isActive"`
+}
+
+type ChangePasswordRequest struct {
+	Password string `json:"password"`
+}
+
+type StaffResponse struct {
+	ID        string    `json:"id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	IsActive  bool      `json:"isActive"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func NewStaffResponse(s *models.Staff) StaffResponse {
+	return StaffResponse{
+		ID:        string(s.ID),
+		Username:  s.Username,
+		Role:      s.Role.String(),
+		IsActive:  s.IsActive,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func NewStaffResponseList(staff []models.Staff) []StaffResponse {
+	result := make([]StaffResponse, len(staff))
+	for i, s := range staff {
+		result[i] = NewStaffResponse(&s)
+	}
+	return result
+}
+
+type CreateTerminalRequest struct {
+	Name string `json:"name"`
+}
+
+type TerminalResponse struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	KeyPrefix string     `json:"keyPrefix"`
+	RevokedAt *time.Time `json:"revokedAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+func NewTerminalResponse(t *models.Terminal) TerminalResponse {
+	return TerminalResponse{
+		ID:        string(t.ID),
+		Name:      t.Name,
+		KeyPrefix: t.KeyPrefix,
+		RevokedAt: t.RevokedAt,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+func NewTerminalResponseList(terminals []models.Terminal) []TerminalResponse {
+	result := make([]TerminalResponse, len(terminals))
+	for i, t := range terminals {
+		result[i] = NewTerminalResponse(&t)
+	}
+	return result
+}
+
+// TerminalKeyResponse carries a newly issued API key, which is shown only
+// once.
+type TerminalKeyResponse struct {
+	Terminal TerminalResponse `json:"terminal"`
+	APIKey   string           `json:"apiKey"`
+}
+
+type AuditLogResponse struct {
+	ID         string          `json:"id"`
+	ActorID    *types.ID       `json:"actorId"`
+	TerminalID *types.ID       `json:"terminalId"`
+	EntityType string          `json:"entityType"`
+	EntityID   string          `json:"entityId"`
+	Action     string          `json:"action"`
+	Before     json.RawMessage `json:"before" swaggertype:"object"`
+	After      json.RawMessage `json:"after" swaggertype:"object"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+func NewAuditLogResponse(a *models.AuditLog) AuditLogResponse {
+	return AuditLogResponse{
+		ID:         string(a.ID),
+		ActorID:    a.ActorID,
+		TerminalID: a.TerminalID,
+		EntityType: a.EntityType,
+		EntityID:   string(a.EntityID),
+		Action:     a.Action,
+		Before:     rawJSON(a.Before),
+		After:      rawJSON(a.After),
+		CreatedAt:  a.CreatedAt,
+	}
+}
+
+func NewAuditLogResponseList(entries []models.AuditLog) []AuditLogResponse {
+	result := make([]AuditLogResponse, len(entries))
+	for i, a := range entries {
+		result[i] = NewAuditLogResponse(&a)
+	}
+	return result
+}
+
+func rawJSON(s *string) json.RawMessage {
+	if s == nil {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(*s)
+}
+
+// SalesSummaryResponse gives grossSales before discounts and revenue, the
+// net sales after discounts and refunds.
+type SalesSummaryResponse struct {
+	Orders            int `json:"orders"`
+	GrossSales        int `json:"grossSales"`
+	Discounts         int `json:"discounts"`
+	Revenue           int `json:"revenue"`
+	AverageOrderValue int `json:"averageOrderValue"`
+	RefundedAmount    int `json:"refundedAmount"`
+	CancelledOrders   int `json:"cancelledOrders"`
+	CancelledValue    int `json:"cancelledValue"`
+	// Taxes splits revenue by consumption tax rate.
+	Taxes []TaxAmountResponse `json:"taxes"`
+}
+
+func NewSalesSummaryResponse(s *services.SalesSummary) SalesSummaryResponse {
+	return SalesSummaryResponse{
+		Orders:            s.Orders,
+		GrossSales:        s.GrossSales,
+		Discounts:         s.DiscountAmount,
+		Revenue:           s.Revenue,
+		AverageOrderValue: s.AverageOrderValue,
+		RefundedAmount:    s.RefundedAmount,
+		CancelledOrders:   s.CancelledOrders,
+		CancelledValue:    s.CancelledValue,
+		Taxes:             NewTaxAmountResponseList(s.Taxes),
+	}
+}
+
+type ProductSalesResponse struct {
+	SalesSlotID   string `json:"salesSlotId"`
+	ProductID     string `json:"productId"`
+	ProductName   string `json:"productName"`
+	Units         int    `json:"units"`
+	Revenue       int    `json:"revenue"`
+	RefundedUnits int    `json:"refundedUnits"`
+}
+
+func NewProductSalesResponseList(rows []repositories.ProductSales) []ProductSalesResponse {
+	result := make([]ProductSalesResponse, len(rows))
+	for i, r := range rows {
+		result[i] = ProductSalesResponse{
+			SalesSlotID:   string(r.SalesSlotID),
+			ProductID:     string(r.ProductID),
+			ProductName:   r.ProductName,
+			Units:         r.Units,
+			Revenue:       r.Revenue,
+			RefundedUnits: r.RefundedUnits,
+		}
+	}
+	return result
+}
+
+type ComponentSalesResponse struct {
+	SalesSlotID   string `json:"salesSlotId"`
+	ProductID     string `json:"productId"`
+	ProductName   string `json:"productName"`
+	Units         int    `json:"units"`
+	ComboUnits    int    `json:"comboUnits"`
+	RefundedUnits int    `json:"refundedUnits"`
+}
+
+func NewComponentSalesResponseList(rows []repositories.ComponentSales) []ComponentSalesResponse {
+	result := make([]ComponentSalesResponse, len(rows))
+	for i, r := range rows {
+		result[i] = ComponentSalesResponse{
+			SalesSlotID:   string(r.SalesSlotID),
+			ProductID:     string(r.ProductID),
+			ProductName:   r.ProductName,
+			Units:         r.Units,
+			ComboUnits:    r.ComboUnits,
+			RefundedUnits: r.RefundedUnits,
+		}
+	}
+	return result
+}
+
+type PaymentMethodSalesResponse struct {
+	PaymentMethod string `json:"paymentMethod"`
+	Orders        int    `json:"orders"`
+	Revenue       int    `json:"revenue"`
+}
+
+func NewPaymentMethodSalesResponseList(rows []repositories.PaymentMethodSales) []PaymentMethodSalesResponse {
+	result := make([]PaymentMethodSalesResponse, len(rows))
+	for i, r := range rows {
+		result[i] = PaymentMethodSalesResponse{
+			PaymentMethod: r.PaymentMethod.String(),
+			Orders:        r.Orders,
+			Revenue:       r.Revenue,
+		}
+	}
+	return result
+}
+
+type HourlySalesResponse struct {
+	Hour    time.Time `json:"hour"`
+	Orders  int       `json:"orders"`
+	Revenue int       `json:"revenue"`
+}
+
+func NewHourlySalesResponseList(rows []repositories.HourlySales) []HourlySalesResponse {
+	result := make([]HourlySalesResponse, len(rows))
+	for i, r := range rows {
+		result[i] = HourlySalesResponse{
+			Hour:    r.Hour,
+			Orders:  r.Orders,
+			Revenue: r.Revenue,
+		}
+	}
+	return result
+}
+
+type OpenDrawerRequest struct {
+	OpeningFloat int `json:"openingFloat"`
+}
+
+type DrawerMovementRequest struct {
+	Type   string `json:"type" enums:"PAY_IN,PAY_OUT"`
+	Amount int    `json:"amount"`
+	Reason string `json:"reason"`
+}
+
+type DenominationCount struct {
+	Denomination int `json:"denomination" example:"1000"`
+	Quantity     int `json:"quantity"`
+}
+
+type CloseDrawerRequest struct {
+	Counts []DenominationCount `json:"counts"`
+	Note   string              `json:"note"`
+}
+
+type DrawerMovementResponse struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Amount    int       `json:"amount"`
+	Reason    string    `json:"reason"`
+	StaffID   *types.ID `json:"staffId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type DrawerSessionResponse struct {
+	ID             string                   `json:"id"`
+	TerminalID     string                   `json:"terminalId"`
+	TerminalName   string                   `json:"terminalName"`
+	OpenedByID     string                   `json:"openedById"`
+	OpeningFloat   int                      `json:"openingFloat"`
+	OpenedAt       time.Time                `json:"openedAt"`
+	ClosedByID     *types.ID                `json:"closedById"`
+	ClosedAt       *time.Time               `json:"closedAt"`
+	CashSales      *int                     `json:"cashSales"`
+	CashTendered   *int                     `json:"cashTendered"`
+	ChangeGiven    *int                     `json:"changeGiven"`
+	CashRefunds    *int                     `json:"cashRefunds"`
+	ExpectedAmount *int                     `json:"expectedAmount"`
+	CountedAmount  *int                     `json:"countedAmount"`
+	Note           string                   `json:"note"`
+	Movements      []DrawerMovementResponse `json:"movements"`
+	Counts         []DenominationCount      `json:"counts"`
+}
+
+func NewDrawerSessionResponse(d *models.DrawerSession) DrawerSessionResponse {
+	response := DrawerSessionResponse{
+		ID:             string(d.ID),
+		TerminalID:     string(d.TerminalID),
+		OpenedByID:     string(d.OpenedByID),
+		OpeningFloat:   d.OpeningFloat,
+		OpenedAt:       d.OpenedAt,
+		ClosedByID:     d.ClosedByID,
+		ClosedAt:       d.ClosedAt,
+		CashSales:      d.CashSales,
+		CashTendered:   d.CashTendered,
+		ChangeGiven:    d.ChangeGiven,
+		CashRefunds:    d.CashRefunds,
+		ExpectedAmount: d.ExpectedAmount,
+		CountedAmount:  d.CountedAmount,
+		Note:           d.Note,
+		Movements:      make([]DrawerMovementResponse, len(d.Movements)),
+		Counts:         newDenominationCounts(d.Counts),
+	}
+	if d.Terminal != nil {
+		response.TerminalName = d.Terminal.Name
+	}
+	for i, m := range d.Movements {
+		response.Movements[i] = NewDrawerMovementResponse(&m)
+	}
+	return response
+}
+
+func newDenominationCounts(counts []models.DrawerCount) []DenominationCount {
+	result := make([]DenominationCount, len(counts))
+	for i, c := range counts {
+		result[i] = DenominationCount{Denomination: c.Denomination, Quantity: c.Quantity}
+	}
+	return result
+}
+
+func NewDrawerSessionResponseList(sessions []models.DrawerSession) []DrawerSessionResponse {
+	result := make([]DrawerSessionResponse, len(sessions))
+	for i, d := range sessions {
+		result[i] = NewDrawerSessionResponse(&d)
+	}
+	return result
+}
+
+func NewDrawerMovementResponse(m *models.DrawerMovement) DrawerMovementResponse {
+	return DrawerMovementResponse{
+		ID:        string(m.ID),
+		Type:      m.Type.String(),
+		Amount:    m.Amount,
+		Reason:    m.Reason,
+		StaffID:   m.StaffID,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// DrawerReportResponse reconciles a drawer session. A positive difference is
+// a surplus, a negative one a shortage.
+type DrawerReportResponse struct {
+	Session      DrawerSessionResponse `json:"session"`
+	CashSales    int                   `json:"cashSales"`
+	CashTendered int                   `json:"cashTendered"`
+	ChangeGiven  int                   `json:"changeGiven"`
+	CashRefunds  int                   `json:"cashRefunds"`
+	PayIns       int                   `json:"payIns"`
+	PayOuts      int                   `json:"payOuts"`
+	Expected     int                   `json:"expected"`
+	Counted      *int                  `json:"counted"`
+	Difference   *int                  `json:"difference"`
+}
+
+func NewDrawerReportResponse(r *services.DrawerReport) DrawerReportResponse {
+	return DrawerReportResponse{
+		Session:      NewDrawerSessionResponse(r.Session),
+		CashSales:    r.CashSales,
+		CashTendered: r.CashTendered,
+		ChangeGiven:  r.ChangeGiven,
+		CashRefunds:  r.CashRefunds,
+		PayIns:       r.PayIns,
+		PayOuts:      r.PayOuts,
+		Expected:     r.Expected,
+		Counted:      r.Counted,
+		Difference:   r.Difference,
+	}
+}
+
+// DiscountRequest describes a discount. value is yen off for FIXED_AMOUNT
+// and percent off for PERCENTAGE; with a productId the discount applies to
+// each unit of that product instead of the whole order. BUY_X_GET_Y needs a
+// productId, buyQuantity and freeQuantity. A usageLimit of 0 allows any
+// number of uses.
+type DiscountRequest struct {
+	Name         string     `json:"name"`
+	Code         string     `json:"code,omitempty"`
+	Type         string     `json:"type" enums:"FIXED_AMOUNT,PERCENTAGE,BUY_X_GET_Y"`
+	Value        int        `json:"value"`
+	ProductID    *string    `json:"productId,omitempty"`
+	BuyQuantity  int        `json:"buyQuantity,omitempty"`
+	FreeQuantity int        `json:"freeQuantity,omitempty"`
+	UsageLimit   int        `json:"usageLimit"`
+	ValidFrom    *time.Time `json:"validFrom,omitempty"`
+	ValidUntil   *time.Time `json:"validUntil,omitempty"`
+	IsActive     bool       `json:"isActive"`
+}
+
+// toInput reports false when the type is not one a discount can have.
+func (r DiscountRequest) toInput() (services.DiscountInput, bool) {
+	discountType, ok := types.ParseDiscountType(r.Type)
+	if !ok || discountType == types.PRICE_OVERRIDE {
+		return services.DiscountInput{}, false
+	}
+	return services.DiscountInput{
+		Name:         r.Name,
+		Code:         r.Code,
+		Type:         discountType,
+		Value:        r.Value,
+		ProductID:    optionalID(r.ProductID),
+		BuyQuantity:  r.BuyQuantity,
+		FreeQuantity: r.FreeQuantity,
+		UsageLimit:   r.UsageLimit,
+		ValidFrom:    r.ValidFrom,
+		ValidUntil:   r.ValidUntil,
+		IsActive:     r.IsActive,
+	}, true
+}
+
+type DiscountResponse struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Code         *string    `json:"code"`
+	Type         string     `json:"type"`
+	Value        int        `json:"value"`
+	ProductID    *types.ID  `json:"productId"`
+	ProductName  string     `json:"productName,omitempty"`
+	BuyQuantity  int        `json:"buyQuantity"`
+	FreeQuantity int        `json:"freeQuantity"`
+	UsageLimit   int        `json:"usageLimit"`
+	UsageCount   int        `json:"usageCount"`
+	ValidFrom    *time.Time `json:"validFrom"`
+	ValidUntil   *time.Time `json:"validUntil"`
+	IsActive     bool       `json:"isActive"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+func NewDiscountResponse(d *models.Discount) DiscountResponse {
+	response := DiscountResponse{
+		ID:           string(d.ID),
+		Name:         d.Name,
+		Code:         d.Code,
+		Type:         d.Type.String(),
+		Value:        d.Value,
+		ProductID:    d.ProductID,
+		BuyQuantity:  d.BuyQuantity,
+		FreeQuantity: d.FreeQuantity,
+		UsageLimit:   d.UsageLimit,
+		UsageCount:   d.UsageCount,
+		ValidFrom:    d.ValidFrom,
+		ValidUntil:   d.ValidUntil,
+		IsActive:     d.IsActive,
+		CreatedAt:    d.CreatedAt,
+		UpdatedAt:    d.UpdatedAt,
+	}
+	if d.Product != nil {
+		response.ProductName = d.Product.Name
+	}
+	return response
+}
+
+func NewDiscountResponseList(discounts []models.Discount) []DiscountResponse {
+	result := make([]DiscountResponse, len(discounts))
+	for i, d := range discounts {
+		result[i] = NewDiscountResponse(&d)
+	}
+	return result
+}
+
+// ApplyDiscountRequest names the discount by discountId, or a coupon by its
+// code.
+type ApplyDiscountRequest struct {
+	DiscountID string `json:"discountId,omitempty"`
+	Code       string `json:"code,omitempty"`
+}
+
+// PriceOverrideRequest sets the unit price of an order line below its price.
+type PriceOverrideRequest struct {
+	Price  int    `json:"price"`
+	Reason string `json:"reason"`
+}
+
+// PrinterRequest describes a printer. categoryId limits a kitchen printer to
+// one station; terminalId ties a receipt printer to one counter.
+type PrinterRequest struct {
+	Name       string  `json:"name"`
+	Kind       string  `json:"kind" enums:"KITCHEN,RECEIPT"`
+	Host       string  `json:"host"`
+	Port       int     `json:"port,omitempty"`
+	CategoryID *string `json:"categoryId,omitempty"`
+	TerminalID *string `json:"terminalId,omitempty"`
+}
+
+// toInput reports false when the kind is not a printer kind.
+func (r PrinterRequest) toInput() (services.PrinterInput, bool) {
+	kind, ok := types.ParsePrinterKind(r.Kind)
+	if !ok {
+		return services.PrinterInput{}, false
+	}
+	return services.PrinterInput{
+		Name:       r.Name,
+		Kind:       kind,
+		Host:       r.Host,
+		Port:       r.Port,
+		CategoryID: optionalID(r.CategoryID),
+		TerminalID: optionalID(r.TerminalID),
+	}, true
+}
+
+// PrinterResponse includes the printer's queue: pending jobs and, while it
+// is offline, the last error.
+type PrinterResponse struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Kind         string     `json:"kind"`
+	Host         string     `json:"host"`
+	Port         int        `json:"port"`
+	CategoryID   *types.ID  `json:"categoryId"`
+	CategoryName string     `json:"categoryName,omitempty"`
+	TerminalID   *types.ID  `json:"terminalId"`
+	TerminalName string     `json:"terminalName,omitempty"`
+	Pending      int        `json:"pending"`
+	LastError    string     `json:"lastError,omitempty"`
+	LastErrorAt  *time.Time `json:"lastErrorAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+func NewPrinterResponse(p *models.Printer, status services.PrinterStatus) PrinterResponse {
+	response := PrinterResponse{
+		ID:          string(p.ID),
+		Name:        p.Name,
+		Kind:        p.Kind.String(),
+		Host:        p.Host,
+		Port:        p.Port,
+		CategoryID:  p.CategoryID,
+		TerminalID:  p.TerminalID,
+		Pending:     status.Pending,
+		LastError:   status.LastError,
+		LastErrorAt: status.LastErrorAt,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+	if p.Category != nil {
+		response.CategoryName = p.Category.Name
+	}
+	if p.Terminal != nil {
+		response.TerminalName = p.Terminal.Name
+	}
+	return response
+}
+
+// PrintReceiptRequest picks the receipt printer. Without one the receipt
+// goes to the printer of the calling terminal's counter.
+type PrintReceiptRequest struct {
+	PrinterID *string `json:"printerId,omitempty"`
+}
+
+type PrintReceiptResponse struct {
+	SerialNumber int  `json:"serialNumber"`
+	IsCopy       bool `json:"isCopy"`
+}
+
+type PrintTicketResponse struct {
+	// Tickets is the number of kitchen printers the ticket was sent to.
+	Tickets int `json:"tickets"`
+}
+
+// CardPaymentRequest carries the card or device token the POS got from the
+// Square SDK.
+type CardPaymentRequest struct {
+	SourceID string `json:"sourceId"`
+}
+
+// PaymentResponse is one entry of an order's ledger. Only COMPLETED
+// payments count toward the order. tenderedAmount and changeAmount are zero
+// unless the cashier entered the cash handed over.
+type PaymentResponse struct {
+	ID             string     `json:"id"`
+	Method         string     `json:"method"`
+	Amount         int        `json:"amount"`
+	TenderedAmount int        `json:"tenderedAmount"`
+	ChangeAmount   int        `json:"changeAmount"`
+	RefundedAmount int        `json:"refundedAmount"`
+	Status         string     `json:"status" enums:"PENDING,COMPLETED,FAILED,UNAPPLIED"`
+	Reference      *string    `json:"reference"`
+	TakenByID      *types.ID  `json:"takenById"`
+	TerminalID     *types.ID  `json:"terminalId"`
+	CompletedAt    *time.Time `json:"completedAt"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+func NewPaymentResponse(p *models.Payment) PaymentResponse {
+	return PaymentResponse{
+		ID:             string(p.ID),
+		Method:         p.Method.String(),
+		Amount:         p.Amount,
+		TenderedAmount: p.TenderedAmount,
+		ChangeAmount:   p.ChangeAmount,
+		RefundedAmount: p.RefundedAmount,
+		Status:         p.Status.String(),
+		Reference:      p.Reference,
+		TakenByID:      p.TakenByID,
+		TerminalID:     p.TerminalID,
+		CompletedAt:    p.CompletedAt,
+		CreatedAt:      p.CreatedAt,
+	}
+}
+
+func NewPaymentResponseList(payments []models.Payment) []PaymentResponse {
+	result := make([]PaymentResponse, len(payments))
+	for i, p := range payments {
+		result[i] = NewPaymentResponse(&p)
+	}
+	return result
+}
+
+// PaymentResultResponse is a recorded payment with the order it was
+// recorded toward. change suggests the notes and coins to hand back, largest
+// first.
+// PaymentResultResponse suggests the change as notes and coins from the
+// drawer listed in the request. ChangeShort is set when the drawer cannot
+// make up the change.
+type PaymentResultResponse struct {
+	Payment     PaymentResponse     `json:"payment"`
+	Order       OrderResponse       `json:"order"`
+	Change      []DenominationCount `json:"change"`
+	ChangeShort bool                `json:"changeShort"`
+}
+
+// CardPaymentResponse reports the payment as Square answered it. The order
+// is paid only when status is COMPLETED; a PENDING payment is settled later
+// by Square's webhook.
+type CardPaymentResponse struct {
+	PaymentID string        `json:"paymentId"`
+	Status    string        `json:"status" enums:"PENDING,COMPLETED,FAILED,UNAPPLIED"`
+	Order     OrderResponse `json:"order"`
+}
+
+// QRCodeResponse is a PayPay code for the POS to show. Payload is the text
+// to render as the QR code; deepLink opens the PayPay app on the same
+// device. The order is paid once status is COMPLETED.
+type QRCodeResponse struct {
+	MerchantPaymentID string        `json:"merchantPaymentId"`
+	Payload           string        `json:"payload"`
+	DeepLink          string        `json:"deepLink"`
+	Amount            int           `json:"amount"`
+	Status            string        `json:"status" enums:"ACTIVE,COMPLETED,EXPIRED,CANCELLED,FAILED"`
+	PaymentID         *string       `json:"paymentId,omitempty"`
+	ExpiresAt         time.Time     `json:"expiresAt"`
+	Order             OrderResponse `json:"order"`
+}
+
+func NewQRCodeResponse(code *models.QRCode, order *models.Order) QRCodeResponse {
+	return QRCodeResponse{
+		MerchantPaymentID: code.MerchantPaymentID,
+		Payload:           code.Payload,
+		DeepLink:          code.DeepLink,
+		Amount:            code.Amount,
+		Status:            code.Status.String(),
+		PaymentID:         code.PaymentID,
+		ExpiresAt:         code.ExpiresAt,
+		Order:             NewOrderResponse(order),
+	}
+}
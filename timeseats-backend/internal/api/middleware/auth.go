@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Authenticate resolves the session token to a staff member and puts it on
+// the request's user context, where services pick it up as the actor.
+func Authenticate(authService services.AuthService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		staff, err := authService.Authenticate(c.UserContext(), BearerToken(c))
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidSession) {
+				return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+
+		c.SetUserContext(services.WithActor(c.UserContext(), staff))
+		return c.Next()
+	}
+}
+
+// RequireRole lets the request through when the authenticated staff member
+// has one of the roles. Admins pass every check.
+func RequireRole(roles ...types.Role) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		staff, ok := services.ActorFromContext(c.UserContext())
+		if !ok {
+			return fiber.NewError(fiber.StatusUnauthorized, "Authentication required")
+		}
+		if !staff.HasRole(roles...) {
+			return fiber.NewError(fiber.StatusForbidden, "Insufficient permissions")
+		}
+		return c.Next()
+	}
+}
+
+// BearerToken returns the token of the Authorization header. Browsers cannot
+// set headers on EventSource and WebSocket connections, so the token query
+// parameter is accepted as well.
+func BearerToken(c *fiber.Ctx) string {
+	if header := c.Get(fiber.HeaderAuthorization); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+	return c.Query("token")
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockAuthService struct {
+	sessions map[string]*models.Staff
+}
+
+func (s *mockAuthService) Login(ctx context.Context, username, password string) (*services.Session, error) {
+	return nil, services.ErrInvalidCredentials
+}
+
+func (s *mockAuthService) Logout(ctx context.Context, token string) error {
+	delete(s.sessions, token)
+	return nil
+}
+
+func (s *mockAuthService) Authenticate(ctx context.Context, token string) (*models.Staff, error) {
+	if staff, exists := s.sessions[token]; exists {
+		return staff, nil
+	}
+	return nil, services.ErrInvalidSession
+}
+
+func (s *mockAuthService) EnsureAdmin(ctx context.Context, username, password string) (bool, error) {
+	return false, nil
+}
+
+func TestRequireRole(t *testing.T) {
+	authService := &mockAuthService{sessions: map[string]*models.Staff{
+		"admin-token":   {ID: types.ID("admin"), Role: types.ADMIN},
+		"cashier-token": {ID: types.ID("cashier"), Role: types.CASHIER},
+		"kitchen-token": {ID: types.ID("kitchen"), Role: types.KITCHEN},
+	}}
+
+	app := fiber.New()
+	app.Post("/orders", Authenticate(authService), RequireRole(types.CASHIER), func(c *fiber.Ctx) error {
+		staff, _ := services.ActorFromContext(c.UserContext())
+		return c.SendString(string(staff.ID))
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		query  string
+		want   int
+	}{
+		{name: "no token", want: fiber.StatusUnauthorized},
+		{name: "unknown token", header: "Bearer nope", want: fiber.StatusUnauthorized},
+		{name: "wrong scheme", header: "Basic cashier-token", want: fiber.StatusUnauthorized},
+		{name: "wrong role", header: "Bearer kitchen-token", want: fiber.StatusForbidden},
+		{name: "cashier", header: "Bearer cashier-token", want: fiber.StatusOK},
+		{name: "admin", header: "Bearer admin-token", want: fiber.StatusOK},
+		{name: "query token", query: "?token=cashier-token", want: fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/orders"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Failed to test request: %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("Expected status code %d, got %d", tt.want, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+const TerminalKeyHeader = "X-Terminal-Key"
+
+// IdentifyTerminal puts the POS terminal named by the X-Terminal-Key header
+// on the request's user context. Requests without the header pass through;
+// a revoked or unknown key is rejected.
+func IdentifyTerminal(terminalService services.TerminalService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(TerminalKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+
+		terminal, err := terminalService.Authenticate(c.UserContext(), key)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidTerminalKey) {
+				return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+
+		c.SetUserContext(services.WithTerminal(c.UserContext(), terminal))
+		return c.Next()
+	}
+}
+
+// RequireTerminal rejects requests that were not made from a registered
+// terminal. It must run after IdentifyTerminal.
+func RequireTerminal() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := services.TerminalFromContext(c.UserContext()); !ok {
+			return fiber.NewError(fiber.StatusUnauthorized, "A registered terminal is required")
+		}
+		return c.Next()
+	}
+}
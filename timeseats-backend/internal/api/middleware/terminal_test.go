@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockTerminalService struct {
+	services.TerminalService
+	keys map[string]*models.Terminal
+}
+
+func (s *mockTerminalService) Authenticate(ctx context.Context, key string) (*models.Terminal, error) {
+	if terminal, exists := s.keys[key]; exists {
+		return terminal, nil
+	}
+	return nil, services.ErrInvalidTerminalKey
+}
+
+func TestIdentifyTerminal(t *testing.T) {
+	terminalService := &mockTerminalService{keys: map[string]*models.Terminal{
+		"tsk_register1": {ID: types.ID("register1")},
+	}}
+
+	app := fiber.New()
+	app.Use(IdentifyTerminal(terminalService))
+	app.Get("/orders", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Post("/orders", RequireTerminal(), func(c *fiber.Ctx) error {
+		terminal, _ := services.TerminalFromContext(c.UserContext())
+		return c.SendString(string(terminal.ID))
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		key    string
+		want   int
+	}{
+		{name: "read without terminal", method: "GET", want: fiber.StatusOK},
+		{name: "unknown key", method: "GET", key: "tsk_unknown", want: fiber.StatusUnauthorized},
+		{name: "create without terminal", method: "POST", want: fiber.StatusUnauthorized},
+		{name: "create from terminal", method: "POST", key: "tsk_register1", want: fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/orders", nil)
+			if tt.key != "" {
+				req.Header.Set(TerminalKeyHeader, tt.key)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Failed to test request: %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("Expected status code %d, got %d", tt.want, resp.StatusCode)
+			}
+		})
+	}
+}
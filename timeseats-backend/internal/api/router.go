@@ -2,8 +2,12 @@ package api
 
 import (
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/api/handlers"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/api/middleware"
 	_ "github.com/SeikoStudentCouncil/timeseats-backend/internal/docs"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/swagger"
@@ -17,50 +21,224 @@ import (
 func SetupRouter(
 	app *fiber.App,
 	productService services.ProductService,
+	categoryService services.CategoryService,
+	modifierGroupService services.ModifierGroupService,
 	salesSlotService services.SalesSlotService,
 	orderService services.OrderService,
+	pickupBoardService services.PickupBoardService,
+	authService services.AuthService,
+	staffService services.StaffService,
+	terminalService services.TerminalService,
+	auditLogService services.AuditLogService,
+	reportService services.ReportService,
+	exportService services.ExportService,
+	drawerService services.DrawerService,
+	refundService services.RefundService,
+	discountService services.DiscountService,
+	receiptService services.ReceiptService,
+	printerService services.PrinterService,
+	paymentService services.PaymentService,
+	eventBus *events.Bus,
 ) {
 	app.Use(cors.New())
 
 	api := app.Group("/api/v1")
 
 	productHandler := handlers.NewProductHandler(productService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	modifierGroupHandler := handlers.NewModifierGroupHandler(modifierGroupService)
 	salesSlotHandler := handlers.NewSalesSlotHandler(salesSlotService)
 	orderHandler := handlers.NewOrderHandler(orderService)
+	eventHandler := handlers.NewEventHandler(eventBus)
+	pickupBoardHandler := handlers.NewPickupBoardHandler(pickupBoardService, eventBus, "/api/v1/pickup-board/stream")
+	authHandler := handlers.NewAuthHandler(authService)
+	staffHandler := handlers.NewStaffHandler(staffService)
+	terminalHandler := handlers.NewTerminalHandler(terminalService)
+	auditLogHandler := handlers.NewAuditLogHandler(auditLogService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	drawerHandler := handlers.NewDrawerHandler(drawerService)
+	refundHandler := handlers.NewRefundHandler(refundService)
+	discountHandler := handlers.NewDiscountHandler(discountService)
+	receiptHandler := handlers.NewReceiptHandler(receiptService)
+	printerHandler := handlers.NewPrinterHandler(printerService)
+	paymentHandler := handlers.NewPaymentHandler(paymentService, orderService)
+
+	authenticated := middleware.Authenticate(authService)
+	admin := middleware.RequireRole(types.ADMIN)
+	cashier := middleware.RequireRole(types.CASHIER)
+	kitchenStaff := middleware.RequireRole(types.KITCHEN)
+	pickupStaff := middleware.RequireRole(types.PICKUP)
+	cashierOrKitchen := middleware.RequireRole(types.CASHIER, types.KITCHEN)
+	terminal := middleware.IdentifyTerminal(terminalService)
+	fromTerminal := middleware.RequireTerminal()
 
 	app.Get("/swagger/*", swagger.HandlerDefault)
 
-	products := api.Group("/products")
+	// The pickup board is public and shows ticket numbers only.
+	app.Get("/pickup", pickupBoardHandler.Page)
+	pickupBoard := api.Group("/pickup-board")
+	{
+		pickupBoard.Get("/", pickupBoardHandler.Get)
+		pickupBoard.Get("/stream", pickupBoardHandler.Stream)
+	}
+
+	// Webhooks are authenticated by their signatures.
+	api.Post("/webhooks/square", paymentHandler.SquareWebhook)
+	api.Post("/webhooks/paypay", paymentHandler.PayPayWebhook)
+
+	api.Post("/auth/login", authHandler.Login)
+	auth := api.Group("/auth", authenticated)
+	{
+		auth.Post("/logout", authHandler.Logout)
+		auth.Get("/me", authHandler.Me)
+	}
+
+	staff := api.Group("/staff", authenticated, admin)
+	{
+		staff.Post("/", staffHandler.Create)
+		staff.Get("/", staffHandler.GetAll)
+		staff.Put("/:id", staffHandler.Update)
+		staff.Put("/:id/password", staffHandler.ChangePassword)
+		staff.Delete("/:id", staffHandler.Delete)
+	}
+
+	terminals := api.Group("/terminals", authenticated, admin)
+	{
+		terminals.Post("/", terminalHandler.Create)
+		terminals.Get("/", terminalHandler.GetAll)
+		terminals.Post("/:id/rotate-key", terminalHandler.RotateKey)
+		terminals.Put("/:id/revoke", terminalHandler.Revoke)
+	}
+
+	printers := api.Group("/printers", authenticated, admin)
+	{
+		printers.Post("/", printerHandler.Create)
+		printers.Get("/", printerHandler.GetAll)
+		printers.Get("/:id", printerHandler.GetByID)
+		printers.Put("/:id", printerHandler.Update)
+		printers.Delete("/:id", printerHandler.Delete)
+	}
+
+	api.Get("/audit-logs", authenticated, admin, auditLogHandler.Search)
+
+	reports := api.Group("/reports", authenticated, admin)
+	{
+		reports.Get("/summary", reportHandler.Summary)
+		reports.Get("/products", reportHandler.Products)
+		reports.Get("/components", reportHandler.Components)
+		reports.Get("/payment-methods", reportHandler.PaymentMethods)
+		reports.Get("/hourly", reportHandler.Hourly)
+	}
+
+	exports := api.Group("/exports", authenticated, admin)
+	{
+		exports.Get("/orders", exportHandler.Orders)
+		exports.Get("/inventory", exportHandler.Inventory)
+	}
+
+	categories := api.Group("/categories", authenticated)
+	{
+		categories.Post("/", admin, categoryHandler.Create)
+		categories.Get("/", categoryHandler.GetAll)
+		categories.Get("/:id", categoryHandler.GetByID)
+		categories.Put("/:id", admin, categoryHandler.Update)
+		categories.Delete("/:id", admin, categoryHandler.Delete)
+	}
+
+	discounts := api.Group("/discounts", authenticated)
 	{
-		products.Post("/", productHandler.Create)
+		discounts.Post("/", admin, discountHandler.Create)
+		discounts.Get("/", discountHandler.GetAll)
+		discounts.Get("/:id", discountHandler.GetByID)
+		discounts.Put("/:id", admin, discountHandler.Update)
+		discounts.Delete("/:id", admin, discountHandler.Delete)
+	}
+
+	products := api.Group("/products", authenticated)
+	{
+		products.Post("/", admin, productHandler.Create)
 		products.Get("/", productHandler.GetAll)
 		products.Get("/:id", productHandler.GetByID)
-		products.Put("/:id", productHandler.Update)
-		products.Delete("/:id", productHandler.Delete)
+		products.Put("/:id", admin, productHandler.Update)
+		products.Delete("/:id", admin, productHandler.Delete)
+		products.Put("/:id/components", admin, productHandler.SetComponents)
+		products.Post("/:id/modifier-groups", admin, modifierGroupHandler.Create)
+		products.Get("/:id/modifier-groups", modifierGroupHandler.GetByProduct)
+	}
+
+	modifierGroups := api.Group("/modifier-groups", authenticated, admin)
+	{
+		modifierGroups.Put("/:id", modifierGroupHandler.Update)
+		modifierGroups.Delete("/:id", modifierGroupHandler.Delete)
 	}
 
-	salesSlots := api.Group("/sales-slots")
+	salesSlots := api.Group("/sales-slots", authenticated)
 	{
-		salesSlots.Post("/", salesSlotHandler.Create)
+		salesSlots.Post("/", admin, salesSlotHandler.Create)
 		salesSlots.Get("/", salesSlotHandler.GetAll)
 		salesSlots.Get("/:id", salesSlotHandler.GetByID)
-		salesSlots.Put("/:id/activate", salesSlotHandler.Activate)
-		salesSlots.Put("/:id/deactivate", salesSlotHandler.Deactivate)
-		salesSlots.Post("/:id/products", salesSlotHandler.AddProduct)
+		salesSlots.Put("/:id/ticket-prefix", admin, salesSlotHandler.UpdateTicketPrefix)
+		salesSlots.Put("/:id/activate", admin, salesSlotHandler.Activate)
+		salesSlots.Put("/:id/deactivate", admin, salesSlotHandler.Deactivate)
+		salesSlots.Post("/:id/products", admin, salesSlotHandler.AddProduct)
 		salesSlots.Get("/:id/products", salesSlotHandler.GetProducts)
+		salesSlots.Get("/:id/menu", salesSlotHandler.GetMenu)
 	}
 
-	orders := api.Group("/orders")
+	orders := api.Group("/orders", authenticated, terminal)
 	{
-		orders.Post("/", orderHandler.Create)
+		orders.Post("/", cashier, fromTerminal, orderHandler.Create)
 		orders.Get("/", orderHandler.GetAll)
-		orders.Get("/:id", orderHandler.GetByID)
 		orders.Get("/status", orderHandler.GetByStatus)
-		orders.Put("/:id/cancel", orderHandler.Cancel)
-		orders.Put("/:id/confirm", orderHandler.Confirm)
-		orders.Post("/:id/items", orderHandler.AddItems)
+		orders.Get("/:id", orderHandler.GetByID)
+		orders.Put("/:id/cancel", cashier, orderHandler.Cancel)
+		orders.Put("/:id/confirm", cashier, orderHandler.Confirm)
+		orders.Post("/:id/items", cashier, orderHandler.AddItems)
+		orders.Put("/:id/items/:itemId", cashier, orderHandler.UpdateItem)
+		orders.Delete("/:id/items/:itemId", cashier, orderHandler.RemoveItem)
 		orders.Get("/number/:ticketNumber", orderHandler.GetByTicketNumber)
-		orders.Put("/:id/payment", orderHandler.UpdatePayment)
-		orders.Put("/:id/delivery", orderHandler.UpdateDelivery)
+		orders.Put("/:id/payment", cashier, fromTerminal, orderHandler.UpdatePayment)
+		orders.Post("/:id/payments", cashier, fromTerminal, paymentHandler.RecordPayment)
+		orders.Get("/:id/payments", cashier, paymentHandler.GetPayments)
+		orders.Post("/:id/payment/square", cashier, fromTerminal, paymentHandler.CreateSquarePayment)
+		orders.Post("/:id/payment/paypay", cashier, fromTerminal, paymentHandler.CreatePayPayCode)
+		orders.Get("/:id/payment/paypay", cashier, paymentHandler.GetPayPayCode)
+		orders.Delete("/:id/payment/paypay", cashier, paymentHandler.CancelPayPayCode)
+		orders.Put("/:id/prepare", kitchenStaff, orderHandler.StartPreparing)
+		orders.Put("/:id/ready", kitchenStaff, orderHandler.MarkReady)
+		orders.Put("/:id/delivery", pickupStaff, orderHandler.UpdateDelivery)
+		orders.Post("/:id/receipt", cashier, receiptHandler.Issue)
+		orders.Get("/:id/receipt", cashier, receiptHandler.HTML)
+		orders.Get("/:id/receipt/pdf", cashier, receiptHandler.PDF)
+		orders.Post("/:id/receipt/print", cashier, printerHandler.PrintReceipt)
+		orders.Post("/:id/ticket/print", cashierOrKitchen, printerHandler.PrintTicket)
+		orders.Post("/:id/discounts", cashier, discountHandler.Apply)
+		orders.Delete("/:id/discounts/:discountId", cashier, discountHandler.Remove)
+		orders.Put("/:id/items/:itemId/price", admin, discountHandler.OverridePrice)
+		orders.Post("/:id/refunds", admin, refundHandler.Create)
+		orders.Get("/:id/refunds", refundHandler.GetByOrder)
+	}
+
+	drawerSessions := api.Group("/drawer-sessions", authenticated, terminal, cashier)
+	{
+		drawerSessions.Post("/", fromTerminal, drawerHandler.Open)
+		drawerSessions.Get("/", drawerHandler.GetAll)
+		drawerSessions.Get("/current", fromTerminal, drawerHandler.GetCurrent)
+		drawerSessions.Get("/:id", drawerHandler.GetByID)
+		drawerSessions.Get("/:id/report", drawerHandler.Report)
+		drawerSessions.Post("/:id/movements", drawerHandler.RecordMovement)
+		drawerSessions.Put("/:id/close", drawerHandler.Close)
+	}
+
+	kitchen := api.Group("/kitchen", authenticated, kitchenStaff)
+	{
+		kitchen.Get("/queue", orderHandler.KitchenQueue)
+	}
+
+	eventStream := api.Group("/events", authenticated)
+	{
+		eventStream.Get("/", eventHandler.Stream)
+		eventStream.Get("/ws", eventHandler.Upgrade, websocket.New(eventHandler.WebSocket))
 	}
 }
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	Port string
+
+	// ReservationTTL is how long an unpaid RESERVED order may hold inventory
+	// before the sweeper cancels it. Zero disables the sweeper.
+	ReservationTTL           time.Duration
+	ReservationSweepInterval time.Duration
+
+	// EventHistorySize is how many events are kept for clients resuming an
+	// event stream with a last event ID.
+	EventHistorySize int
+
+	// TicketNumberDigits is the zero-padded width of generated ticket
+	// numbers, which follow the sales slot's ticket prefix.
+	TicketNumberDigits int
+
+	// SessionTTL is how long a staff login stays valid.
+	SessionTTL time.Duration
+
+	// AdminUsername and AdminPassword create the first admin account when
+	// no staff account exists yet.
+	AdminUsername string
+	AdminPassword string
+
+	// ReceiptIssuerName and ReceiptRegistrationNumber are printed on
+	// receipts. The registration number is the qualified invoice issuer
+	// number, "T" followed by 13 digits, and may be left empty.
+	ReceiptIssuerName         string
+	ReceiptRegistrationNumber string
+
+	// PrintRetryInterval is how long to wait before trying an offline
+	// printer again.
+	PrintRetryInterval time.Duration
+
+	// Square card payments are enabled when SquareAccessToken is set.
+	// SquareWebhookURL must be the notification URL exactly as registered
+	// with Square, since webhook signatures cover it.
+	SquareBaseURL             string
+	SquareAccessToken         string
+	SquareLocationID          string
+	SquareWebhookSignatureKey string
+	SquareWebhookURL          string
+
+	// PayPay QR code payments are enabled when PayPayAPIKey is set. Active
+	// codes are checked every PayPayPollInterval besides on webhook
+	// notifications.
+	PayPayBaseURL      string
+	PayPayAPIKey       string
+	PayPayAPISecret    string
+	PayPayMerchantID   string
+	PayPayPollInterval time.Duration
+}
+
+func Load() (*Config, error) {
+	godotenv.Load()
+
+	cfg := &Config{
+		Port:          getEnv("PORT", "8080"),
+		AdminUsername: os.Getenv("ADMIN_USERNAME"),
+		AdminPassword: os.Getenv("ADMIN_PASSWORD"),
+
+		ReceiptIssuerName:         os.Getenv("RECEIPT_ISSUER_NAME"),
+		ReceiptRegistrationNumber: os.Getenv("RECEIPT_REGISTRATION_NUMBER"),
+
+		SquareBaseURL:             getEnv("SQUARE_BASE_URL", "https://connect.squareup.com"),
+		SquareAccessToken:         os.Getenv("SQUARE_ACCESS_TOKEN"),
+		SquareLocationID:          os.Getenv("SQUARE_LOCATION_ID"),
+		SquareWebhookSignatureKey: os.Getenv("SQUARE_WEBHOOK_SIGNATURE_KEY"),
+		SquareWebhookURL:          os.Getenv("SQUARE_WEBHOOK_URL"),
+
+		PayPayBaseURL:    getEnv("PAYPAY_BASE_URL", "https://api.paypay.ne.jp"),
+		PayPayAPIKey:     os.Getenv("PAYPAY_API_KEY"),
+		PayPayAPISecret:  os.Getenv("PAYPAY_API_SECRET"),
+		PayPayMerchantID: os.Getenv("PAYPAY_MERCHANT_ID"),
+	}
+
+	var err error
+	if cfg.ReservationTTL, err = getDuration("RESERVATION_TTL", 15*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.ReservationSweepInterval, err = getDuration("RESERVATION_SWEEP_INTERVAL", time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.ReservationSweepInterval <= 0 {
+		return nil, fmt.Errorf("RESERVATION_SWEEP_INTERVAL must be positive")
+	}
+	if cfg.EventHistorySize, err = getInt("EVENT_HISTORY_SIZE", 1000); err != nil {
+		return nil, err
+	}
+	if cfg.TicketNumberDigits, err = getInt("TICKET_NUMBER_DIGITS", 3); err != nil {
+		return nil, err
+	}
+	if cfg.SessionTTL, err = getDuration("SESSION_TTL", 12*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.SessionTTL <= 0 {
+		return nil, fmt.Errorf("SESSION_TTL must be positive")
+	}
+	if cfg.PrintRetryInterval, err = getDuration("PRINT_RETRY_INTERVAL", 5*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.PrintRetryInterval <= 0 {
+		return nil, fmt.Errorf("PRINT_RETRY_INTERVAL must be positive")
+	}
+	if cfg.PayPayPollInterval, err = getDuration("PAYPAY_POLL_INTERVAL", 5*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.PayPayPollInterval <= 0 {
+		return nil, fmt.Errorf("PAYPAY_POLL_INTERVAL must be positive")
+	}
+	if cfg.ReceiptRegistrationNumber != "" && !registrationNumberPattern.MatchString(cfg.ReceiptRegistrationNumber) {
+		return nil, fmt.Errorf("RECEIPT_REGISTRATION_NUMBER must be T followed by 13 digits")
+	}
+
+	return cfg, nil
+}
+
+var registrationNumberPattern = regexp.MustCompile(`^T[0-9]{13}$`)
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return d, nil
+}
+
+func getInt(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,135 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+const subscriberBuffer = 64
+
+// Bus is an in-process publish/subscribe hub. It keeps the most recent events
+// so that a reconnecting subscriber can resume from the last event it saw.
+type Bus struct {
+	mu          sync.Mutex
+	lastID      uint64
+	history     []Event
+	historySize int
+	subscribers map[*Subscription]struct{}
+}
+
+type Filter struct {
+	SalesSlotID types.ID
+}
+
+func (f Filter) matches(event Event) bool {
+	return f.SalesSlotID == "" || event.SalesSlotID == "" || f.SalesSlotID == event.SalesSlotID
+}
+
+type Subscription struct {
+	C <-chan Event
+
+	ch     chan Event
+	filter Filter
+	bus    *Bus
+	once   sync.Once
+}
+
+func NewBus(historySize int) *Bus {
+	return &Bus{
+		historySize: historySize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+func (b *Bus) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	if b.historySize > 0 {
+		if len(b.history) == b.historySize {
+			b.history = append(b.history[:0], b.history[1:]...)
+		}
+		b.history = append(b.history, event)
+	}
+
+	for sub := range b.subscribers {
+		if !sub.filter.matches(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			// A subscriber that cannot keep up is dropped. It can reconnect
+			// with its last event ID and replay what it missed.
+			b.remove(sub)
+		}
+	}
+}
+
+// Subscribe registers a subscriber. When lastEventID is non-zero, retained
+// events published after it are replayed first; if they are no longer
+// retained a Resync event is delivered instead.
+func (b *Bus) Subscribe(filter Filter, lastEventID uint64) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []Event
+	if lastEventID > 0 {
+		replay = b.replay(filter, lastEventID)
+	}
+
+	ch := make(chan Event, subscriberBuffer+len(replay))
+	for _, event := range replay {
+		ch <- event
+	}
+
+	sub := &Subscription{
+		C:      ch,
+		ch:     ch,
+		filter: filter,
+		bus:    b,
+	}
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+func (b *Bus) replay(filter Filter, lastEventID uint64) []Event {
+	if lastEventID == b.lastID {
+		return nil
+	}
+	if lastEventID > b.lastID || len(b.history) == 0 || b.history[0].ID > lastEventID+1 {
+		return []Event{{ID: b.lastID, Type: Resync, OccurredAt: time.Now()}}
+	}
+
+	var events []Event
+	for _, event := range b.history {
+		if event.ID > lastEventID && filter.matches(event) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func (b *Bus) remove(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.ch)
+	}
+}
+
+// Close unregisters the subscription and closes C.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		defer s.bus.mu.Unlock()
+		s.bus.remove(s)
+	})
+}
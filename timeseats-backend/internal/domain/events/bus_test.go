@@ -0,0 +1,97 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+func receive(t *testing.T, sub *Subscription) Event {
+	t.Helper()
+	select {
+	case event := <-sub.C:
+		return event
+	default:
+		t.Fatal("Expected an event to be delivered")
+		return Event{}
+	}
+}
+
+func TestBus_FilterBySalesSlot(t *testing.T) {
+	bus := NewBus(10)
+	sub := bus.Subscribe(Filter{SalesSlotID: types.ID("slot1")}, 0)
+	defer sub.Close()
+
+	bus.Publish(Event{Type: OrderCreated, SalesSlotID: types.ID("slot2")})
+	bus.Publish(Event{Type: OrderCreated, SalesSlotID: types.ID("slot1")})
+
+	event := receive(t, sub)
+	if event.SalesSlotID != types.ID("slot1") {
+		t.Errorf("Expected event for slot1, got %s", event.SalesSlotID)
+	}
+	if event.ID != 2 {
+		t.Errorf("Expected event ID 2, got %d", event.ID)
+	}
+	if len(sub.C) != 0 {
+		t.Errorf("Expected no further events, got %d", len(sub.C))
+	}
+}
+
+func TestBus_ResumeFromLastEventID(t *testing.T) {
+	bus := NewBus(10)
+	for i := 0; i < 5; i++ {
+		bus.Publish(Event{Type: InventoryChanged, SalesSlotID: types.ID("slot1")})
+	}
+
+	sub := bus.Subscribe(Filter{}, 3)
+	defer sub.Close()
+
+	if first := receive(t, sub); first.ID != 4 {
+		t.Errorf("Expected replay to start at event 4, got %d", first.ID)
+	}
+	if second := receive(t, sub); second.ID != 5 {
+		t.Errorf("Expected replay to continue with event 5, got %d", second.ID)
+	}
+
+	bus.Publish(Event{Type: OrderPaid})
+	if live := receive(t, sub); live.ID != 6 {
+		t.Errorf("Expected live event 6, got %d", live.ID)
+	}
+}
+
+func TestBus_ResyncWhenHistoryIsGone(t *testing.T) {
+	bus := NewBus(2)
+	for i := 0; i < 5; i++ {
+		bus.Publish(Event{Type: OrderCreated})
+	}
+
+	sub := bus.Subscribe(Filter{}, 1)
+	defer sub.Close()
+
+	event := receive(t, sub)
+	if event.Type != Resync {
+		t.Errorf("Expected %s, got %s", Resync, event.Type)
+	}
+	if event.ID != 5 {
+		t.Errorf("Expected resync to point at event 5, got %d", event.ID)
+	}
+}
+
+func TestBus_DropsSlowSubscriber(t *testing.T) {
+	bus := NewBus(0)
+	sub := bus.Subscribe(Filter{}, 0)
+
+	for i := 0; i < subscriberBuffer+1; i++ {
+		bus.Publish(Event{Type: OrderCreated})
+	}
+
+	count := 0
+	for range sub.C {
+		count++
+	}
+	if count != subscriberBuffer {
+		t.Errorf("Expected %d buffered events before the channel closed, got %d", subscriberBuffer, count)
+	}
+
+	sub.Close()
+}
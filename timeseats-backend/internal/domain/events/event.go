@@ -0,0 +1,113 @@
+package events
+
+import (
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type Type string
+
+const (
+	OrderCreated         Type = "order.created"
+	OrderItemsAdded      Type = "order.items_added"
+	OrderItemsUpdated    Type = "order.items_updated"
+	OrderPaymentAdded    Type = "order.payment_added"
+	OrderPaid            Type = "order.paid"
+	OrderConfirmed       Type = "order.confirmed"
+	OrderCancelled       Type = "order.cancelled"
+	OrderPreparing       Type = "order.preparing"
+	OrderReady           Type = "order.ready"
+	OrderDelivered       Type = "order.delivered"
+	OrderRefunded        Type = "order.refunded"
+	InventoryChanged     Type = "inventory.changed"
+	SalesSlotActivated   Type = "sales_slot.activated"
+	SalesSlotDeactivated Type = "sales_slot.deactivated"
+
+	// Resync is sent to a resuming subscriber whose last event is no longer
+	// retained. The client should reload its state from the REST API.
+	Resync Type = "stream.resync"
+)
+
+type Event struct {
+	ID          uint64    `json:"id"`
+	Type        Type      `json:"type"`
+	SalesSlotID types.ID  `json:"salesSlotId,omitempty"`
+	OccurredAt  time.Time `json:"occurredAt"`
+	Data        any       `json:"data,omitempty"`
+}
+
+type Publisher interface {
+	Publish(event Event)
+}
+
+type OrderPayload struct {
+	OrderID        types.ID `json:"orderId"`
+	TicketNumber   string   `json:"ticketNumber"`
+	Status         string   `json:"status"`
+	TotalAmount    int      `json:"totalAmount"`
+	RefundedAmount int      `json:"refundedAmount"`
+	PaidAmount     int      `json:"paidAmount"`
+	PaymentMethod  string   `json:"paymentMethod"`
+	IsPaid         bool     `json:"isPaid"`
+	IsDelivered    bool     `json:"isDelivered"`
+}
+
+type InventoryPayload struct {
+	InventoryID       types.ID `json:"inventoryId"`
+	ProductID         types.ID `json:"productId"`
+	InitialQuantity   int      `json:"initialQuantity"`
+	ReservedQuantity  int      `json:"reservedQuantity"`
+	SoldQuantity      int      `json:"soldQuantity"`
+	AvailableQuantity int      `json:"availableQuantity"`
+}
+
+type SalesSlotPayload struct {
+	IsActive bool `json:"isActive"`
+}
+
+func NewOrderEvent(eventType Type, o *models.Order) Event {
+	return Event{
+		Type:        eventType,
+		SalesSlotID: o.SalesSlotID,
+		Data: OrderPayload{
+			OrderID:        o.ID,
+			TicketNumber:   o.TicketNumber,
+			Status:         o.Status.String(),
+			TotalAmount:    o.TotalAmount,
+			RefundedAmount: o.RefundedAmount,
+			PaidAmount:     o.PaidAmount,
+			PaymentMethod:  o.PaymentMethod.String(),
+			IsPaid:         o.IsPaid,
+			IsDelivered:    o.IsDelivered,
+		},
+	}
+}
+
+func NewInventoryEvent(inv *models.ProductInventory) Event {
+	return Event{
+		Type:        InventoryChanged,
+		SalesSlotID: inv.SalesSlotID,
+		Data: InventoryPayload{
+			InventoryID:       inv.ID,
+			ProductID:         inv.ProductID,
+			InitialQuantity:   inv.InitialQuantity,
+			ReservedQuantity:  inv.ReservedQuantity,
+			SoldQuantity:      inv.SoldQuantity,
+			AvailableQuantity: inv.GetAvailableQuantity(),
+		},
+	}
+}
+
+func NewSalesSlotEvent(slot *models.SalesSlot) Event {
+	eventType := SalesSlotDeactivated
+	if slot.IsActive {
+		eventType = SalesSlotActivated
+	}
+	return Event{
+		Type:        eventType,
+		SalesSlotID: slot.ID,
+		Data:        SalesSlotPayload{IsActive: slot.IsActive},
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditLog records one change to an entity. Rows are never updated or
+// deleted; the database rejects both.
+type AuditLog struct {
+	ID         types.ID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ActorID    *types.ID `gorm:"type:uuid;index"`
+	TerminalID *types.ID `gorm:"type:uuid"`
+	EntityType string    `gorm:"index:idx_audit_logs_entity"`
+	EntityID   types.ID  `gorm:"index:idx_audit_logs_entity"`
+	Action     string
+	// Before and After are JSON snapshots of the entity; empty for creations
+	// and deletions respectively.
+	Before    *string   `gorm:"type:jsonb"`
+	After     *string   `gorm:"type:jsonb"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Category groups products on the menu, e.g. mains, drinks and desserts.
+// Categories are shown by SortOrder, then by name.
+type Category struct {
+	ID        types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name      string
+	SortOrder int `gorm:"default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (c *Category) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
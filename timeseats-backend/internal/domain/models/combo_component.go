@@ -0,0 +1,32 @@
+package models
+
+import (
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ComboComponent is a product included in a combo, Quantity units per combo.
+// A combo has no inventory of its own; selling it draws on its components.
+type ComboComponent struct {
+	ID        types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ComboID   types.ID `gorm:"type:uuid;index"`
+	ProductID types.ID `gorm:"type:uuid;index"`
+	Quantity  int
+
+	Product *Product `gorm:"foreignKey:ProductID"`
+}
+
+func (c *ComboComponent) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
+
+// StockUnit is a product an order line draws from inventory, Quantity units
+// per unit of the line.
+type StockUnit struct {
+	ProductID types.ID
+	Quantity  int
+}
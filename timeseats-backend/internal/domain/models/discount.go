@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Discount is a promotion that can be applied to orders: a coupon when it
+// has a Code, otherwise one the cashier picks, such as a staff discount.
+//
+// Value is yen off for FIXED_AMOUNT and percent off for PERCENTAGE. With a
+// ProductID the discount applies to each unit of that product, otherwise to
+// the whole order. BUY_X_GET_Y always needs a ProductID and gives
+// FreeQuantity units free for every BuyQuantity bought.
+//
+// A UsageLimit of 0 allows any number of uses; a cancelled order gives its
+// use back. ValidFrom is inclusive and ValidUntil exclusive; nil leaves that
+// end open.
+type Discount struct {
+	ID           types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name         string
+	Code         *string
+	Type         types.DiscountType
+	Value        int
+	ProductID    *types.ID `gorm:"type:uuid"`
+	BuyQuantity  int       `gorm:"default:0"`
+	FreeQuantity int       `gorm:"default:0"`
+	UsageLimit   int       `gorm:"default:0"`
+	// UsageCount is the number of orders, other than cancelled ones, the
+	// discount is on. It is loaded by the repository, not stored.
+	UsageCount int `gorm:"->;-:migration"`
+	ValidFrom  *time.Time
+	ValidUntil *time.Time
+	IsActive   bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
+
+	Product *Product `gorm:"foreignKey:ProductID"`
+}
+
+func (d *Discount) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
+
+// IsValidAt reports whether the discount may be applied at the given time.
+func (d *Discount) IsValidAt(at time.Time) bool {
+	if !d.IsActive {
+		return false
+	}
+	if d.ValidFrom != nil && at.Before(*d.ValidFrom) {
+		return false
+	}
+	if d.ValidUntil != nil && !at.Before(*d.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// OrderDiscount is a discount line on an order. It copies the terms of the
+// Discount it came from, so later edits to the promotion do not change
+// orders already discounted, and Amount is recalculated whenever the items
+// of a reserved order change.
+//
+// A PRICE_OVERRIDE line sets the unit price of OrderItemID to Value; its
+// Reason and ApprovedByID record why and by whom.
+type OrderDiscount struct {
+	ID           types.ID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID      types.ID  `gorm:"type:uuid;index"`
+	DiscountID   *types.ID `gorm:"type:uuid;index"`
+	OrderItemID  *types.ID `gorm:"type:uuid;index"`
+	Type         types.DiscountType
+	Name         string
+	Code         *string
+	Value        int
+	ProductID    *types.ID `gorm:"type:uuid"`
+	BuyQuantity  int
+	FreeQuantity int
+	Amount       int
+	Reason       string
+	ApprovedByID *types.ID `gorm:"type:uuid"`
+	CreatedAt    time.Time
+}
+
+func (od *OrderDiscount) BeforeCreate(tx *gorm.DB) error {
+	if od.ID == "" {
+		od.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
+
+// IsItemLevel reports whether the line discounts particular items rather
+// than the whole order.
+func (od *OrderDiscount) IsItemLevel() bool {
+	return od.OrderItemID != nil || od.ProductID != nil
+}
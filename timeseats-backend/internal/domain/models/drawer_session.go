@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DrawerSession is a cashier shift on one terminal's cash drawer, from the
+// starting float until the cash is counted. At most one session per terminal
+// is open at a time.
+type DrawerSession struct {
+	ID           types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TerminalID   types.ID `gorm:"type:uuid;index"`
+	OpenedByID   types.ID `gorm:"type:uuid"`
+	OpeningFloat int
+	OpenedAt     time.Time
+	ClosedByID   *types.ID `gorm:"type:uuid"`
+	ClosedAt     *time.Time
+	// The amounts below are settled when the session is closed.
+	CashSales      *int
+	CashTendered   *int
+	ChangeGiven    *int
+	CashRefunds    *int
+	ExpectedAmount *int
+	CountedAmount  *int
+	Note           string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	Terminal  *Terminal        `gorm:"foreignKey:TerminalID"`
+	Movements []DrawerMovement `gorm:"foreignKey:DrawerSessionID"`
+	Counts    []DrawerCount    `gorm:"foreignKey:DrawerSessionID"`
+}
+
+func (d *DrawerSession) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
+
+func (d *DrawerSession) IsClosed() bool {
+	return d.ClosedAt != nil
+}
+
+// MovementTotals returns the cash paid into and out of the drawer.
+func (d *DrawerSession) MovementTotals() (payIns, payOuts int) {
+	for _, m := range d.Movements {
+		switch m.Type {
+		case types.PAY_IN:
+			payIns += m.Amount
+		case types.PAY_OUT:
+			payOuts += m.Amount
+		}
+	}
+	return payIns, payOuts
+}
+
+type DrawerMovement struct {
+	ID              types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	DrawerSessionID types.ID `gorm:"type:uuid;index"`
+	Type            types.DrawerMovementType
+	Amount          int
+	Reason          string
+	StaffID         *types.ID `gorm:"type:uuid"`
+	CreatedAt       time.Time
+}
+
+func (m *DrawerMovement) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
+
+// DrawerCount is how many notes or coins of one yen denomination were
+// counted when the session was closed.
+type DrawerCount struct {
+	ID              types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	DrawerSessionID types.ID `gorm:"type:uuid;index"`
+	Denomination    int
+	Quantity        int
+}
+
+func (c *DrawerCount) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ModifierGroup is a set of options offered with a product, e.g. sizes or
+// toppings. At least MinSelect and at most MaxSelect options of the group
+// are picked per order line; a MaxSelect of 0 means no limit.
+type ModifierGroup struct {
+	ID        types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ProductID types.ID `gorm:"type:uuid;index"`
+	Name      string
+	MinSelect int `gorm:"default:0"`
+	MaxSelect int `gorm:"default:0"`
+	SortOrder int `gorm:"default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	Options []ModifierOption `gorm:"foreignKey:GroupID"`
+}
+
+func (g *ModifierGroup) BeforeCreate(tx *gorm.DB) error {
+	if g.ID == "" {
+		g.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
+
+// IsRequired reports whether an order line must pick from the group.
+func (g *ModifierGroup) IsRequired() bool {
+	return g.MinSelect > 0
+}
+
+// ModifierOption is one choice in a group. PriceDelta is added to the unit
+// price of the product and may be negative, e.g. for a small size.
+type ModifierOption struct {
+	ID         types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	GroupID    types.ID `gorm:"type:uuid;index"`
+	Name       string
+	PriceDelta int `gorm:"default:0"`
+	SortOrder  int `gorm:"default:0"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
+}
+
+func (o *ModifierOption) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == "" {
+		o.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
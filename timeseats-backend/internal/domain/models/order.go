@@ -10,20 +10,43 @@ import (
 
 type Order struct {
 	ID            types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	SalesSlotID   types.ID `gorm:"type:uuid"`
+	SalesSlotID   types.ID `gorm:"type:uuid;uniqueIndex:idx_orders_sales_slot_ticket_number"`
 	Status        types.OrderStatus
 	TotalAmount   int
-	TicketNumber  string `gorm:"unique"`
+	TicketNumber  string `gorm:"uniqueIndex:idx_orders_sales_slot_ticket_number"`
 	PaymentMethod types.PaymentMethod
 	TransactionID *string
 	IsPaid        bool `gorm:"default:false"`
 	IsDelivered   bool `gorm:"default:false"`
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	DeletedAt     gorm.DeletedAt `gorm:"index"`
+	CancelReason  *string
+	// DiscountAmount is taken off the sum of the items to give TotalAmount,
+	// what the customer pays.
+	DiscountAmount int `gorm:"default:0"`
+	// RefundedAmount is the part of TotalAmount paid back to the customer.
+	RefundedAmount int `gorm:"default:0"`
+	// PaidAmount sums the completed payments in the order's ledger. IsPaid is
+	// set once it covers TotalAmount.
+	PaidAmount int `gorm:"default:0"`
+	// TerminalID and CreatedByID record the POS terminal and cashier that
+	// took the order; the Paid fields those that took the payment.
+	TerminalID     *types.ID `gorm:"type:uuid;index"`
+	CreatedByID    *types.ID `gorm:"type:uuid"`
+	PaidAt         *time.Time
+	PaidTerminalID *types.ID `gorm:"type:uuid;index"`
+	PaidByID       *types.ID `gorm:"type:uuid"`
+	ConfirmedAt    *time.Time
+	PreparingAt    *time.Time
+	ReadyAt        *time.Time
+	DeliveredAt    *time.Time
+	CancelledAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
 
-	SalesSlot *SalesSlot  `gorm:"foreignKey:SalesSlotID"`
-	Items     []OrderItem `gorm:"foreignKey:OrderID"`
+	SalesSlot *SalesSlot      `gorm:"foreignKey:SalesSlotID"`
+	Items     []OrderItem     `gorm:"foreignKey:OrderID"`
+	Discounts []OrderDiscount `gorm:"foreignKey:OrderID"`
+	Payments  []Payment       `gorm:"foreignKey:OrderID"`
 }
 
 func (o *Order) BeforeCreate(tx *gorm.DB) error {
@@ -39,6 +62,68 @@ func (o *Order) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// SetStatus changes the status and stamps the time of the transition.
+func (o *Order) SetStatus(status types.OrderStatus, at time.Time) {
+	o.Status = status
+	switch status {
+	case types.CONFIRMED:
+		o.ConfirmedAt = &at
+	case types.PREPARING:
+		o.PreparingAt = &at
+	case types.READY:
+		o.ReadyAt = &at
+	case types.DELIVERED:
+		o.DeliveredAt = &at
+		o.IsDelivered = true
+	case types.CANCELLED:
+		o.CancelledAt = &at
+	}
+}
+
+// MarkPaid records the payment and where it was taken.
+func (o *Order) MarkPaid(transactionID string, at time.Time, staffID, terminalID *types.ID) {
+	o.IsPaid = true
+	o.TransactionID = &transactionID
+	o.PaidAt = &at
+	o.PaidByID = staffID
+	o.PaidTerminalID = terminalID
+}
+
+// AddPayment counts a completed payment toward the order, which is paid by
+// that payment once nothing is left to pay.
+func (o *Order) AddPayment(payment *Payment) {
+	o.PaidAmount += payment.Amount
+	payments := make([]Payment, 0, len(o.Payments)+1)
+	for _, p := range o.Payments {
+		if p.ID != payment.ID {
+			payments = append(payments, p)
+		}
+	}
+	o.Payments = append(payments, *payment)
+	if o.IsPaid || o.BalanceDue() > 0 {
+		return
+	}
+	reference := ""
+	if payment.Reference != nil {
+		reference = *payment.Reference
+	}
+	o.MarkPaid(reference, *payment.CompletedAt, payment.TakenByID, payment.TerminalID)
+}
+
+// BalanceDue is what is left to pay.
+func (o *Order) BalanceDue() int {
+	return max(o.TotalAmount-o.PaidAmount, 0)
+}
+
+func (o *Order) IsFullyRefunded() bool {
+	return o.RefundedAmount > 0 && o.RefundedAmount >= o.TotalAmount
+}
+
+// Subtotal is the sum of the items before discounts.
+func (o *Order) Subtotal() int {
+	return o.TotalAmount + o.DiscountAmount
+}
+
 func (o *Order) CalculateTotalAmount() {
 	total := 0
 	for _, item := range o.Items {
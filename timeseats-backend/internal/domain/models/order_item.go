@@ -11,10 +11,23 @@ type OrderItem struct {
 	OrderID   types.ID `gorm:"type:uuid"`
 	ProductID types.ID `gorm:"type:uuid"`
 	Quantity  int
-	Price     int
+	// Price is the unit price including OptionsAmount, the sum of the
+	// price deltas of the chosen options.
+	Price         int
+	OptionsAmount int `gorm:"default:0"`
+	// RefundedQuantity counts the units of Quantity that were refunded.
+	RefundedQuantity int `gorm:"default:0"`
+	// TaxRate is copied from the product. DiscountAmount is the share of the
+	// order's discounts taken off the line, so that the tax of each rate is
+	// worked out on what was actually charged.
+	TaxRate        types.TaxRate `gorm:"default:8"`
+	DiscountAmount int           `gorm:"default:0"`
 
-	Order   *Order   `gorm:"foreignKey:OrderID"`
-	Product *Product `gorm:"foreignKey:ProductID"`
+	Order   *Order            `gorm:"foreignKey:OrderID"`
+	Product *Product          `gorm:"foreignKey:ProductID"`
+	Options []OrderItemOption `gorm:"foreignKey:OrderItemID"`
+	// Components is set when the product is a combo.
+	Components []OrderItemComponent `gorm:"foreignKey:OrderItemID"`
 }
 
 func (oi *OrderItem) BeforeCreate(tx *gorm.DB) error {
@@ -27,3 +40,66 @@ func (oi *OrderItem) BeforeCreate(tx *gorm.DB) error {
 func (oi *OrderItem) GetSubtotal() int {
 	return oi.Price * oi.Quantity
 }
+
+// NetAmount is the subtotal less the line's share of the discounts.
+func (oi *OrderItem) NetAmount() int {
+	return oi.GetSubtotal() - oi.DiscountAmount
+}
+
+// StockUnits returns the products the line draws from inventory: the
+// components of a combo, or else the product itself.
+func (oi *OrderItem) StockUnits() []StockUnit {
+	if len(oi.Components) == 0 {
+		return []StockUnit{{ProductID: oi.ProductID, Quantity: 1}}
+	}
+	units := make([]StockUnit, len(oi.Components))
+	for i, component := range oi.Components {
+		units[i] = StockUnit{ProductID: component.ProductID, Quantity: component.Quantity}
+	}
+	return units
+}
+
+// OptionIDs returns the IDs of the chosen options.
+func (oi *OrderItem) OptionIDs() []types.ID {
+	ids := make([]types.ID, len(oi.Options))
+	for i, option := range oi.Options {
+		ids[i] = option.ModifierOptionID
+	}
+	return ids
+}
+
+// OrderItemOption records an option chosen for an order line. The names and
+// price delta are copied so later menu changes do not alter past orders.
+type OrderItemOption struct {
+	ID               types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderItemID      types.ID `gorm:"type:uuid;index"`
+	ModifierOptionID types.ID `gorm:"type:uuid"`
+	GroupName        string
+	Name             string
+	PriceDelta       int
+}
+
+func (o *OrderItemOption) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == "" {
+		o.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
+
+// OrderItemComponent records a component of a combo sold on an order line,
+// Quantity units per unit of the line, as the combo was made up at the time.
+type OrderItemComponent struct {
+	ID          types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderItemID types.ID `gorm:"type:uuid;index"`
+	ProductID   types.ID `gorm:"type:uuid"`
+	Quantity    int
+
+	Product *Product `gorm:"foreignKey:ProductID"`
+}
+
+func (c *OrderItemComponent) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Payment is one tender toward an order: cash taken at the counter, a card
+// payment or a PayPay payment. An order may be paid with several, and only
+// COMPLETED payments count toward it. Reference is the provider's ID for the
+// payment, or the transaction ID entered for a manual one, and is recorded
+// once per method. An UNAPPLIED
+// payment was taken by the provider but could not be counted toward its
+// order, as when the order was cancelled meanwhile, and is to be refunded.
+type Payment struct {
+	ID        types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID   types.ID `gorm:"type:uuid;index"`
+	Amount    int
+	Method    types.PaymentMethod `gorm:"uniqueIndex:idx_payments_reference_method,priority:2"`
+	Reference *string             `gorm:"uniqueIndex:idx_payments_reference_method,priority:1"`
+	Status    types.PaymentStatus
+	// RefundedAmount is the part of Amount paid back.
+	RefundedAmount int `gorm:"default:0"`
+	// TenderedAmount is the cash the customer handed over and ChangeAmount
+	// what they got back, so Amount is the difference. Both are zero for
+	// cash payments taken without entering the amount tendered.
+	TenderedAmount int       `gorm:"default:0"`
+	ChangeAmount   int       `gorm:"default:0"`
+	TakenByID      *types.ID `gorm:"type:uuid"`
+	TerminalID     *types.ID `gorm:"type:uuid;index"`
+	CompletedAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (p *Payment) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = types.ID(uuid.New().String())
+	}
+	if p.Status == 0 {
+		p.Status = types.PAYMENT_PENDING
+	}
+	return nil
+}
+
+func (p *Payment) IsCompleted() bool {
+	return p.Status == types.PAYMENT_COMPLETED
+}
+
+// Refundable is what is left of the payment to pay back.
+func (p *Payment) Refundable() int {
+	if !p.IsCompleted() {
+		return 0
+	}
+	return p.Amount - p.RefundedAmount
+}
+
+// Complete records that the payment was taken and where.
+func (p *Payment) Complete(at time.Time, staffID, terminalID *types.ID) {
+	p.Status = types.PAYMENT_COMPLETED
+	p.CompletedAt = &at
+	p.TakenByID = staffID
+	p.TerminalID = terminalID
+}
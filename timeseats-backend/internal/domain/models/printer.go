@@ -0,0 +1,61 @@
+package models
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Printer is a network thermal printer that takes ESC/POS over raw TCP.
+// A kitchen printer prints the ticket of every confirmed order, limited to
+// the items of CategoryID when it serves one station. A receipt printer
+// prints receipts on demand for the counter at TerminalID, or for any
+// counter when TerminalID is nil.
+type Printer struct {
+	ID         types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name       string
+	Kind       types.PrinterKind
+	Host       string
+	Port       int       `gorm:"default:9100"`
+	CategoryID *types.ID `gorm:"type:uuid;index"`
+	TerminalID *types.ID `gorm:"type:uuid;index"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
+
+	Category *Category `gorm:"foreignKey:CategoryID"`
+	Terminal *Terminal `gorm:"foreignKey:TerminalID"`
+}
+
+func (p *Printer) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
+
+// Address is the host and port to connect to.
+func (p *Printer) Address() string {
+	return net.JoinHostPort(p.Host, strconv.Itoa(p.Port))
+}
+
+// Prints reports whether a kitchen printer's station makes item. Items of a
+// combo go to every station that makes one of its components.
+func (p *Printer) Prints(item *OrderItem) bool {
+	if p.CategoryID == nil {
+		return true
+	}
+	if item.Product != nil && item.Product.CategoryID != nil && *item.Product.CategoryID == *p.CategoryID {
+		return true
+	}
+	for _, component := range item.Components {
+		if component.Product != nil && component.Product.CategoryID != nil && *component.Product.CategoryID == *p.CategoryID {
+			return true
+		}
+	}
+	return false
+}
@@ -12,9 +12,20 @@ type Product struct {
 	ID        types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	Name      string
 	Price     int
+	TaxRate   types.TaxRate `gorm:"default:8"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	// Products without a category are listed after all categories. Within
+	// a category they are shown by SortOrder, then by name.
+	CategoryID *types.ID `gorm:"type:uuid;index"`
+	SortOrder  int       `gorm:"default:0"`
+	Category   *Category `gorm:"foreignKey:CategoryID"`
+
+	ModifierGroups []ModifierGroup `gorm:"foreignKey:ProductID"`
+	// Components make the product a combo, e.g. a yakisoba and drink set.
+	Components []ComboComponent `gorm:"foreignKey:ComboID"`
 }
 
 func (p *Product) BeforeCreate(tx *gorm.DB) error {
@@ -23,3 +34,20 @@ func (p *Product) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+func (p *Product) IsCombo() bool {
+	return len(p.Components) > 0
+}
+
+// FindOption returns the option with the ID and the group it belongs to.
+func (p *Product) FindOption(id types.ID) (*ModifierGroup, *ModifierOption) {
+	for i := range p.ModifierGroups {
+		group := &p.ModifierGroups[i]
+		for j := range group.Options {
+			if group.Options[j].ID == id {
+				return group, &group.Options[j]
+			}
+		}
+	}
+	return nil, nil
+}
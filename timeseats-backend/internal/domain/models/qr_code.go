@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// QRCode is a dynamic PayPay QR code shown to the customer for an order. An
+// order gets a new code when its last one has expired or been cancelled, and
+// PayPay requires every code to have its own MerchantPaymentID.
+type QRCode struct {
+	ID                types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID           types.ID `gorm:"type:uuid;index"`
+	MerchantPaymentID string   `gorm:"uniqueIndex"`
+	CodeID            string
+	// Payload is the URL encoded in the QR code; DeepLink opens the PayPay
+	// app on the same device.
+	Payload   string
+	DeepLink  string
+	Amount    int
+	Status    types.QRCodeStatus `gorm:"index"`
+	PaymentID *string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (q *QRCode) BeforeCreate(tx *gorm.DB) error {
+	if q.ID == "" {
+		q.ID = types.ID(uuid.New().String())
+	}
+	if q.Status == 0 {
+		q.Status = types.QR_CODE_ACTIVE
+	}
+	return nil
+}
+
+func (q *QRCode) IsActive() bool {
+	return q.Status == types.QR_CODE_ACTIVE
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Receipt records the receipt issued for an order. An order gets at most
+// one SerialNumber; every print after the first is a copy, and PrintCount
+// counts them all.
+type Receipt struct {
+	ID            types.ID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID       types.ID  `gorm:"type:uuid;uniqueIndex"`
+	SerialNumber  int       `gorm:"uniqueIndex"`
+	IssuedByID    *types.ID `gorm:"type:uuid"`
+	IssuedAt      time.Time
+	PrintCount    int
+	LastPrintedAt time.Time
+}
+
+func (r *Receipt) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Refund returns money for a whole order or some of its items. ApprovedByID
+// is the staff member who carried it out. Payments breaks Amount down by the
+// tenders it is paid back against; Method is how the first part was paid
+// back.
+type Refund struct {
+	ID           types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrderID      types.ID `gorm:"type:uuid;index"`
+	Amount       int
+	Reason       string
+	Method       types.PaymentMethod
+	ApprovedByID *types.ID `gorm:"type:uuid"`
+	TerminalID   *types.ID `gorm:"type:uuid;index"`
+	CreatedAt    time.Time
+
+	Items    []RefundItem    `gorm:"foreignKey:RefundID"`
+	Payments []RefundPayment `gorm:"foreignKey:RefundID"`
+}
+
+func (r *Refund) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
+
+// RefundItem is a refunded quantity of an order item. Restocked items go back
+// into the slot's inventory; the others are written off as waste.
+type RefundItem struct {
+	ID          types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	RefundID    types.ID `gorm:"type:uuid;index"`
+	OrderItemID types.ID `gorm:"type:uuid"`
+	ProductID   types.ID `gorm:"type:uuid"`
+	Quantity    int
+	Amount      int
+	Restocked   bool
+}
+
+func (ri *RefundItem) BeforeCreate(tx *gorm.DB) error {
+	if ri.ID == "" {
+		ri.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
+
+// RefundPayment is the part of a refund paid back against one payment of the
+// order, in Method. PaymentID is nil for the part of orders sold without a
+// recorded payment.
+type RefundPayment struct {
+	ID        types.ID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	RefundID  types.ID  `gorm:"type:uuid;index"`
+	PaymentID *types.ID `gorm:"type:uuid;index"`
+	Method    types.PaymentMethod
+	Amount    int
+}
+
+func (rp *RefundPayment) BeforeCreate(tx *gorm.DB) error {
+	if rp.ID == "" {
+		rp.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
@@ -13,9 +13,11 @@ type SalesSlot struct {
 	StartTime time.Time
 	EndTime   time.Time
 	IsActive  bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	// TicketPrefix is prepended to ticket numbers generated for this slot.
+	TicketPrefix string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
 }
 
 func (s *SalesSlot) BeforeCreate(tx *gorm.DB) error {
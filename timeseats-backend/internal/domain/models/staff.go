@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Staff struct {
+	ID           types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Username     string   `gorm:"uniqueIndex"`
+	PasswordHash string   `json:"-"`
+	Role         types.Role
+	IsActive     bool `gorm:"default:true"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
+
+func (s *Staff) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
+
+// HasRole reports whether the staff member may act in one of the roles.
+func (s *Staff) HasRole(roles ...types.Role) bool {
+	if s.Role == types.ADMIN {
+		return true
+	}
+	for _, role := range roles {
+		if s.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// StaffSession is a login session. Only the SHA-256 hash of the bearer token
+// is stored.
+type StaffSession struct {
+	ID        types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	StaffID   types.ID `gorm:"type:uuid;index"`
+	TokenHash string   `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	CreatedAt time.Time
+
+	Staff *Staff `gorm:"foreignKey:StaffID"`
+}
+
+func (s *StaffSession) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Terminal is a registered POS device. It identifies itself with an API key
+// of which only the SHA-256 hash is stored.
+type Terminal struct {
+	ID        types.ID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	Name      string
+	KeyHash   string `gorm:"uniqueIndex" json:"-"`
+	KeyPrefix string
+	RevokedAt *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (t *Terminal) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = types.ID(uuid.New().String())
+	}
+	return nil
+}
+
+func (t *Terminal) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
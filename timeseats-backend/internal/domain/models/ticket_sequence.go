@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// TicketSequence holds the last ticket number handed out in a sales slot.
+type TicketSequence struct {
+	SalesSlotID types.ID `gorm:"type:uuid;primary_key"`
+	LastNumber  int
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// AuditLogFilter narrows an audit log search. Empty fields match any entry.
+type AuditLogFilter struct {
+	EntityType string
+	EntityID   types.ID
+	ActorID    types.ID
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
+
+// AuditLogRepository is append-only.
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *models.AuditLog) error
+	// Find returns matching entries, newest first.
+	Find(ctx context.Context, filter AuditLogFilter) ([]models.AuditLog, error)
+}
@@ -0,0 +1,9 @@
+package repositories
+
+import (
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+)
+
+type CategoryRepository interface {
+	Repository[models.Category]
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// DiscountRepository loads discounts with their UsageCount.
+type DiscountRepository interface {
+	Repository[models.Discount]
+	// FindByIDForUpdate locks the discount until the transaction ends, so
+	// its uses can be counted and added to without racing other orders.
+	FindByIDForUpdate(ctx context.Context, id types.ID) (*models.Discount, error)
+	FindByCode(ctx context.Context, code string) (*models.Discount, error)
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type DrawerSessionRepository interface {
+	Create(ctx context.Context, session *models.DrawerSession) error
+	FindByID(ctx context.Context, id types.ID) (*models.DrawerSession, error)
+	// FindByIDForUpdate also locks the session until the transaction ends.
+	FindByIDForUpdate(ctx context.Context, id types.ID) (*models.DrawerSession, error)
+	FindOpenByTerminal(ctx context.Context, terminalID types.ID) (*models.DrawerSession, error)
+	// FindByTerminal returns the sessions of a terminal, or of all terminals
+	// when terminalID is empty, newest first.
+	FindByTerminal(ctx context.Context, terminalID types.ID) ([]models.DrawerSession, error)
+	AddMovement(ctx context.Context, movement *models.DrawerMovement) error
+	// Close stores the closing fields of the session and its counts.
+	Close(ctx context.Context, session *models.DrawerSession) error
+}
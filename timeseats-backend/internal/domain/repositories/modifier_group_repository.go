@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// ModifierGroupRepository loads and saves groups together with their
+// options. Update replaces the options: listed options without an ID are
+// created and options no longer listed are deleted.
+type ModifierGroupRepository interface {
+	Repository[models.ModifierGroup]
+	FindByProductID(ctx context.Context, productID types.ID) ([]models.ModifierGroup, error)
+}
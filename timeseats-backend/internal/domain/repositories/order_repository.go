@@ -2,17 +2,61 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
 )
 
+// OrderFilter narrows an order search. Empty fields match any order.
+type OrderFilter struct {
+	SalesSlotID types.ID
+	// TerminalID matches orders taken or paid at the terminal.
+	TerminalID types.ID
+	Status     types.OrderStatus
+	// From and To bound the creation time; From is inclusive, To exclusive.
+	From time.Time
+	To   time.Time
+}
+
 type OrderRepository interface {
 	Repository[models.Order]
+	// FindByIDForUpdate locks the order until the transaction ends.
+	FindByIDForUpdate(ctx context.Context, id types.ID) (*models.Order, error)
+	FindByFilter(ctx context.Context, filter OrderFilter) ([]models.Order, error)
+	// StreamByFilter calls fn for each matching order with its items, oldest
+	// first, loading the orders in batches rather than all at once.
+	StreamByFilter(ctx context.Context, filter OrderFilter, fn func(order *models.Order) error) error
+	// AddRefund adds to the refunded amount of an order in one of the
+	// statuses, returning ErrConflict if the order is in another status or
+	// the refunds would exceed its total.
+	AddRefund(ctx context.Context, id types.ID, statuses []types.OrderStatus, amount int) error
+	// AddRefundedQuantity returns ErrConflict if more units would be
+	// refunded than were ordered.
+	AddRefundedQuantity(ctx context.Context, itemID types.ID, quantity int) error
 	FindBySalesSlotID(ctx context.Context, salesSlotID types.ID) ([]models.Order, error)
 	FindByStatus(ctx context.Context, status types.OrderStatus) ([]models.Order, error)
+	FindBySalesSlotAndStatuses(ctx context.Context, salesSlotID types.ID, statuses []types.OrderStatus) ([]models.Order, error)
 	UpdateStatus(ctx context.Context, id types.ID, status types.OrderStatus) error
+	TransitionStatus(ctx context.Context, id types.ID, from, to types.OrderStatus, at time.Time) error
+	AddTotalAmount(ctx context.Context, id types.ID, status types.OrderStatus, amount int) error
 	AddItems(ctx context.Context, orderID types.ID, items []models.OrderItem) error
+	AddItemQuantity(ctx context.Context, itemID types.ID, delta int) error
+	DeleteItem(ctx context.Context, itemID types.ID) error
 	CreateWithItems(ctx context.Context, order *models.Order, items []models.OrderItem) error
-	FindByTicketNumber(ctx context.Context, ticketNumber string) (*models.Order, error)
+	// FindByTicketNumber returns the most recent order with the ticket number,
+	// limited to the sales slot unless salesSlotID is empty.
+	FindByTicketNumber(ctx context.Context, salesSlotID types.ID, ticketNumber string) (*models.Order, error)
+	TicketNumberExists(ctx context.Context, salesSlotID types.ID, ticketNumber string) (bool, error)
+	FindExpiredReservations(ctx context.Context, createdBefore time.Time) ([]models.Order, error)
+	SetCancelReason(ctx context.Context, id types.ID, reason string) error
+	// UpdatePayment saves the paid amount and the payment fields set by
+	// models.Order.MarkPaid.
+	UpdatePayment(ctx context.Context, order *models.Order) error
+	AddDiscount(ctx context.Context, discount *models.OrderDiscount) error
+	DeleteDiscount(ctx context.Context, discountID types.ID) error
+	// UpdatePricing saves the totals, discount line amounts and the items'
+	// shares of the discounts of an order in the status, returning
+	// ErrConflict if the order is in another status.
+	UpdatePricing(ctx context.Context, order *models.Order, status types.OrderStatus) error
 }
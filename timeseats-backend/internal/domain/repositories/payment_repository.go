@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// CashTotals sums the cash customers handed over for payments taken with the
+// amount tendered, and the change they got back.
+type CashTotals struct {
+	Tendered int
+	Change   int
+}
+
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *models.Payment) error
+	// FindByOrderID returns the payments of the order in the order they were
+	// taken.
+	FindByOrderID(ctx context.Context, orderID types.ID) ([]models.Payment, error)
+	// FindByReference returns the payment with the provider reference.
+	FindByReference(ctx context.Context, reference string) (*models.Payment, error)
+	// Update saves the status and the completion fields of the payment.
+	Update(ctx context.Context, payment *models.Payment) error
+	// AddRefund adds to the refunded amount of a completed payment, returning
+	// ErrConflict if the refunds would exceed the payment.
+	AddRefund(ctx context.Context, id types.ID, amount int) error
+	// SumPayments totals the completed payments of a method taken at the
+	// terminal in [from, to), leaving out those of cancelled orders. A zero
+	// to leaves the range open.
+	SumPayments(ctx context.Context, terminalID types.ID, method types.PaymentMethod, from, to time.Time) (int, error)
+	// SumCashTendered totals the amounts tendered and the change given for
+	// the payments SumPayments counts as cash.
+	SumCashTendered(ctx context.Context, terminalID types.ID, from, to time.Time) (*CashTotals, error)
+}
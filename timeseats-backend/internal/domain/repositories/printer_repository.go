@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type PrinterRepository interface {
+	Repository[models.Printer]
+	FindByKind(ctx context.Context, kind types.PrinterKind) ([]models.Printer, error)
+}
@@ -13,4 +13,5 @@ type ProductInventoryRepository interface {
 	FindByProductID(ctx context.Context, productID types.ID) ([]models.ProductInventory, error)
 	FindBySalesSlotAndProduct(ctx context.Context, salesSlotID, productID types.ID) (*models.ProductInventory, error)
 	UpdateQuantities(ctx context.Context, id types.ID, reserved, sold int) error
+	AdjustQuantities(ctx context.Context, id types.ID, reservedDelta, soldDelta int) error
 }
@@ -4,9 +4,17 @@ import (
 	"context"
 
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
 )
 
 type ProductRepository interface {
 	Repository[models.Product]
 	FindByName(ctx context.Context, name string) (*models.Product, error)
+	// ClearCategory moves every product in the category to uncategorized.
+	ClearCategory(ctx context.Context, categoryID types.ID) error
+	// ReplaceComponents sets the components of a combo. No components make
+	// the product an ordinary one again.
+	ReplaceComponents(ctx context.Context, comboID types.ID, components []models.ComboComponent) error
+	// IsComponent reports whether the product is part of any combo.
+	IsComponent(ctx context.Context, productID types.ID) (bool, error)
 }
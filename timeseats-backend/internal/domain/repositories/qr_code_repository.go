@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type QRCodeRepository interface {
+	Create(ctx context.Context, code *models.QRCode) error
+	// FindLatestByOrderID returns the code created last for the order.
+	FindLatestByOrderID(ctx context.Context, orderID types.ID) (*models.QRCode, error)
+	FindByMerchantPaymentID(ctx context.Context, merchantPaymentID string) (*models.QRCode, error)
+	// FindActive returns the codes still waiting to be paid.
+	FindActive(ctx context.Context) ([]models.QRCode, error)
+	// UpdateStatus saves Status and PaymentID.
+	UpdateStatus(ctx context.Context, code *models.QRCode) error
+}
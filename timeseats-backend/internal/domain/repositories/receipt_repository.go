@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type ReceiptRepository interface {
+	Create(ctx context.Context, receipt *models.Receipt) error
+	FindByOrderID(ctx context.Context, orderID types.ID) (*models.Receipt, error)
+	// FindByOrderIDForUpdate locks the order's receipt until the transaction
+	// ends.
+	FindByOrderIDForUpdate(ctx context.Context, orderID types.ID) (*models.Receipt, error)
+	// UpdatePrints saves PrintCount and LastPrintedAt.
+	UpdatePrints(ctx context.Context, receipt *models.Receipt) error
+	// NextSerialNumber hands out receipt serial numbers, starting at 1. A
+	// number taken by a transaction that rolls back is not reused.
+	NextSerialNumber(ctx context.Context) (int, error)
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type RefundRepository interface {
+	// Create stores the refund with its items and payment lines.
+	Create(ctx context.Context, refund *models.Refund) error
+	FindByOrderID(ctx context.Context, orderID types.ID) ([]models.Refund, error)
+	// SumRefunds totals what the refunds made at the terminal in [from, to)
+	// paid back in the method. A zero to leaves the range open.
+	SumRefunds(ctx context.Context, terminalID types.ID, method types.PaymentMethod, from, to time.Time) (int, error)
+}
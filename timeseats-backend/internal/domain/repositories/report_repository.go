@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// ReportFilter narrows the orders a report covers. From is inclusive and To
+// exclusive, both against the order's creation time; zero values are open.
+// TerminalID matches the terminal that took the payment, or the one that
+// took the order when it was paid elsewhere.
+type ReportFilter struct {
+	From        time.Time
+	To          time.Time
+	SalesSlotID types.ID
+	TerminalID  types.ID
+}
+
+// SalesTotals.Gross is the sum of the items before Discounts; Revenue is
+// what was charged, net of Refunded, the amount paid back.
+type SalesTotals struct {
+	Orders    int
+	Gross     int
+	Discounts int
+	Revenue   int
+	Refunded  int
+}
+
+// ProductSales counts the units sold net of RefundedUnits. Revenue is net
+// of each line's share of the discounts and of what was refunded for it, so
+// the products add up to the revenue of SalesTotals.
+type ProductSales struct {
+	SalesSlotID   types.ID
+	ProductID     types.ID
+	ProductName   string
+	Units         int
+	Revenue       int
+	RefundedUnits int
+}
+
+// ComponentSales counts the units of a stocked product that left the
+// counter, whether sold on its own or inside a combo. ComboUnits is the part
+// sold inside combos; ProductSales counts the combos themselves.
+type ComponentSales struct {
+	SalesSlotID   types.ID
+	ProductID     types.ID
+	ProductName   string
+	Units         int
+	ComboUnits    int
+	RefundedUnits int
+}
+
+// TaxSales totals the items charged at one tax rate after discounts, and
+// what was refunded of them. Both include tax.
+type TaxSales struct {
+	Rate     types.TaxRate
+	Sales    int
+	Refunded int
+}
+
+// PaymentMethodSales totals what was paid in a method, less refunds. An
+// order paid with several methods counts under each. The part of a sold
+// order its payments do not cover counts under the order's payment method.
+type PaymentMethodSales struct {
+	PaymentMethod types.PaymentMethod
+	Orders        int
+	Revenue       int
+}
+
+type HourlySales struct {
+	Hour    time.Time
+	Orders  int
+	Revenue int
+}
+
+// ReportRepository aggregates orders. Sales figures count only orders that
+// are paid or confirmed and not cancelled, less what has been refunded.
+type ReportRepository interface {
+	SalesTotals(ctx context.Context, filter ReportFilter) (*SalesTotals, error)
+	CancelledTotals(ctx context.Context, filter ReportFilter) (*SalesTotals, error)
+	ProductSales(ctx context.Context, filter ReportFilter) ([]ProductSales, error)
+	ComponentSales(ctx context.Context, filter ReportFilter) ([]ComponentSales, error)
+	TaxSales(ctx context.Context, filter ReportFilter) ([]TaxSales, error)
+	PaymentMethodSales(ctx context.Context, filter ReportFilter) ([]PaymentMethodSales, error)
+	HourlySales(ctx context.Context, filter ReportFilter) ([]HourlySales, error)
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
 )
@@ -38,3 +39,9 @@ func NewErrNotFound(entity string, id types.ID) error {
 		ID:     id,
 	}
 }
+
+var (
+	ErrInsufficientQuantity = errors.New("insufficient quantity")
+	ErrConflict             = errors.New("entity was modified concurrently")
+	ErrDuplicate            = errors.New("entity already exists")
+)
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type StaffRepository interface {
+	Repository[models.Staff]
+	FindByUsername(ctx context.Context, username string) (*models.Staff, error)
+	Count(ctx context.Context) (int64, error)
+}
+
+type StaffSessionRepository interface {
+	Create(ctx context.Context, session *models.StaffSession) error
+	// FindByTokenHash returns the session with its staff member loaded.
+	FindByTokenHash(ctx context.Context, tokenHash string) (*models.StaffSession, error)
+	DeleteByTokenHash(ctx context.Context, tokenHash string) error
+	DeleteByStaffID(ctx context.Context, staffID types.ID) error
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+)
+
+type TerminalRepository interface {
+	Repository[models.Terminal]
+	FindByKeyHash(ctx context.Context, keyHash string) (*models.Terminal, error)
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type TicketSequenceRepository interface {
+	// Next atomically increments and returns the sequence of the sales slot,
+	// starting at 1.
+	Next(ctx context.Context, salesSlotID types.ID) (int, error)
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type (
+	actorKey    struct{}
+	terminalKey struct{}
+)
+
+// WithActor returns a context carrying the staff member making the request.
+func WithActor(ctx context.Context, staff *models.Staff) context.Context {
+	return context.WithValue(ctx, actorKey{}, staff)
+}
+
+// ActorFromContext returns the staff member making the request, if any.
+func ActorFromContext(ctx context.Context) (*models.Staff, bool) {
+	staff, ok := ctx.Value(actorKey{}).(*models.Staff)
+	return staff, ok && staff != nil
+}
+
+// WithTerminal returns a context carrying the POS terminal the request came
+// from.
+func WithTerminal(ctx context.Context, terminal *models.Terminal) context.Context {
+	return context.WithValue(ctx, terminalKey{}, terminal)
+}
+
+// TerminalFromContext returns the POS terminal the request came from, if any.
+func TerminalFromContext(ctx context.Context) (*models.Terminal, bool) {
+	terminal, ok := ctx.Value(terminalKey{}).(*models.Terminal)
+	return terminal, ok && terminal != nil
+}
+
+// actorIDs returns the IDs of the staff member and terminal in the context.
+func actorIDs(ctx context.Context) (staffID, terminalID *types.ID) {
+	if staff, ok := ActorFromContext(ctx); ok {
+		staffID = &staff.ID
+	}
+	if terminal, ok := TerminalFromContext(ctx); ok {
+		terminalID = &terminal.ID
+	}
+	return staffID, terminalID
+}
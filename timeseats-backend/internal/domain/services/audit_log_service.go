@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// Audited entity types.
+const (
+	AuditEntityProduct       = "product"
+	AuditEntityCategory      = "category"
+	AuditEntityModifierGroup = "modifier_group"
+	AuditEntitySalesSlot     = "sales_slot"
+	AuditEntityInventory     = "inventory"
+	AuditEntityOrder         = "order"
+	AuditEntityStaff         = "staff"
+	AuditEntityTerminal      = "terminal"
+	AuditEntityDrawerSession = "drawer_session"
+	AuditEntityDiscount      = "discount"
+	AuditEntityReceipt       = "receipt"
+	AuditEntityPrinter       = "printer"
+)
+
+const defaultAuditLogLimit = 500
+
+type AuditLogService interface {
+	// Record appends an entry for a change made by the actor and terminal in
+	// ctx. Call it inside the transaction making the change so both commit
+	// or roll back together. before or after is nil when the entity did not
+	// exist on that side of the change.
+	Record(ctx context.Context, entityType string, entityID types.ID, action string, before, after any) error
+	Search(ctx context.Context, filter repositories.AuditLogFilter) ([]models.AuditLog, error)
+}
+
+type auditLogService struct {
+	auditRepo repositories.AuditLogRepository
+	clock     Clock
+}
+
+func NewAuditLogService(auditRepo repositories.AuditLogRepository, clock Clock) AuditLogService {
+	return &auditLogService{
+		auditRepo: auditRepo,
+		clock:     clock,
+	}
+}
+
+func (s *auditLogService) Record(ctx context.Context, entityType string, entityID types.ID, action string, before, after any) error {
+	beforeJSON, err := auditSnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := auditSnapshot(after)
+	if err != nil {
+		return err
+	}
+
+	actorID, terminalID := actorIDs(ctx)
+	return s.auditRepo.Create(ctx, &models.AuditLog{
+		ActorID:    actorID,
+		TerminalID: terminalID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		CreatedAt:  s.clock.Now(),
+	})
+}
+
+func (s *auditLogService) Search(ctx context.Context, filter repositories.AuditLogFilter) ([]models.AuditLog, error) {
+	if filter.Limit <= 0 || filter.Limit > defaultAuditLogLimit {
+		filter.Limit = defaultAuditLogLimit
+	}
+	return s.auditRepo.Find(ctx, filter)
+}
+
+func auditSnapshot(v any) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := string(data)
+	return &snapshot, nil
+}
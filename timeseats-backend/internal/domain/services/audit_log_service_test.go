@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type mockAuditLogRepository struct {
+	mu      sync.Mutex
+	entries []models.AuditLog
+}
+
+func newMockAuditLogRepository() *mockAuditLogRepository {
+	return &mockAuditLogRepository{}
+}
+
+func (r *mockAuditLogRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *mockAuditLogRepository) Find(ctx context.Context, filter repositories.AuditLogFilter) ([]models.AuditLog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []models.AuditLog
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		entry := r.entries[i]
+		if filter.EntityType != "" && entry.EntityType != filter.EntityType {
+			continue
+		}
+		if filter.EntityID != "" && entry.EntityID != filter.EntityID {
+			continue
+		}
+		if filter.ActorID != "" && (entry.ActorID == nil || *entry.ActorID != filter.ActorID) {
+			continue
+		}
+		if !filter.From.IsZero() && entry.CreatedAt.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !entry.CreatedAt.Before(filter.To) {
+			continue
+		}
+		result = append(result, entry)
+		if filter.Limit > 0 && len(result) == filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func newTestAuditLogService() AuditLogService {
+	return NewAuditLogService(newMockAuditLogRepository(), NewSystemClock())
+}
+
+func (r *mockAuditLogRepository) actions(entityID types.ID) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var actions []string
+	for _, entry := range r.entries {
+		if entry.EntityID == entityID {
+			actions = append(actions, entry.Action)
+		}
+	}
+	return actions
+}
+
+func TestAuditLogService_Record(t *testing.T) {
+	repo := newMockAuditLogRepository()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	service := NewAuditLogService(repo, &fakeClock{now: now})
+
+	ctx := WithActor(context.Background(), &models.Staff{ID: "staff-1", Role: types.ADMIN})
+	ctx = WithTerminal(ctx, &models.Terminal{ID: "terminal-1"})
+
+	before := &models.Product{ID: "product-1", Name: "焼きそば", Price: 300}
+	after := &models.Product{ID: "product-1", Name: "焼きそば", Price: 350}
+	if err := service.Record(ctx, AuditEntityProduct, before.ID, "update", before, after); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	entries, err := service.Search(context.Background(), repositories.AuditLogFilter{ActorID: "staff-1"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+
+	entry := entries[0]
+	if entry.TerminalID == nil || *entry.TerminalID != "terminal-1" {
+		t.Errorf("Expected terminal terminal-1, got %v", entry.TerminalID)
+	}
+	if !entry.CreatedAt.Equal(now) {
+		t.Errorf("Expected timestamp %v, got %v", now, entry.CreatedAt)
+	}
+
+	var snapshot models.Product
+	if entry.After == nil || json.Unmarshal([]byte(*entry.After), &snapshot) != nil {
+		t.Fatalf("Expected an after snapshot, got %v", entry.After)
+	}
+	if snapshot.Price != 350 {
+		t.Errorf("Expected snapshot price 350, got %d", snapshot.Price)
+	}
+}
+
+func TestAuditLogService_RecordWithoutSnapshot(t *testing.T) {
+	repo := newMockAuditLogRepository()
+	service := NewAuditLogService(repo, NewSystemClock())
+
+	product := &models.Product{ID: "product-1"}
+	if err := service.Record(context.Background(), AuditEntityProduct, product.ID, "create", nil, product); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	entry := repo.entries[0]
+	if entry.Before != nil {
+		t.Errorf("Expected no before snapshot, got %s", *entry.Before)
+	}
+	if entry.ActorID != nil || entry.TerminalID != nil {
+		t.Errorf("Expected no actor or terminal, got %v, %v", entry.ActorID, entry.TerminalID)
+	}
+}
+
+func TestProductService_AuditLog(t *testing.T) {
+	repo := newMockProductRepository()
+	auditRepo := newMockAuditLogRepository()
+	service := NewProductService(repo, newMockCategoryRepository(), &mockTransactor{}, NewAuditLogService(auditRepo, NewSystemClock()))
+
+	product, err := service.CreateProduct(context.Background(), "たこ焼き", 400, types.REDUCED_TAX_RATE, nil, 0)
+	if err != nil {
+		t.Fatalf("CreateProduct failed: %v", err)
+	}
+	if _, err := service.UpdateProduct(context.Background(), product.ID, "たこ焼き", 450, types.REDUCED_TAX_RATE, nil, 0); err != nil {
+		t.Fatalf("UpdateProduct failed: %v", err)
+	}
+	if _, err := service.UpdateProduct(context.Background(), "missing", "x", 1, types.REDUCED_TAX_RATE, nil, 0); err == nil {
+		t.Fatal("Expected an error updating a missing product")
+	}
+
+	actions := auditRepo.actions(product.ID)
+	if len(actions) != 2 || actions[0] != "create" || actions[1] != "update" {
+		t.Errorf("Expected [create update], got %v", actions)
+	}
+	if len(auditRepo.entries) != 2 {
+		t.Errorf("Expected no entry for the failed update, got %d entries", len(auditRepo.entries))
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type AuthService interface {
+	Login(ctx context.Context, username, password string) (*Session, error)
+	Logout(ctx context.Context, token string) error
+	// Authenticate returns the active staff member the token belongs to.
+	Authenticate(ctx context.Context, token string) (*models.Staff, error)
+	// EnsureAdmin creates an admin account when no staff account exists yet.
+	EnsureAdmin(ctx context.Context, username, password string) (bool, error)
+}
+
+type Session struct {
+	Token     string
+	Staff     *models.Staff
+	ExpiresAt time.Time
+}
+
+type authService struct {
+	staffRepo    repositories.StaffRepository
+	sessionRepo  repositories.StaffSessionRepository
+	staffService StaffService
+	clock        Clock
+	sessionTTL   time.Duration
+}
+
+func NewAuthService(
+	staffRepo repositories.StaffRepository,
+	sessionRepo repositories.StaffSessionRepository,
+	staffService StaffService,
+	clock Clock,
+	sessionTTL time.Duration,
+) AuthService {
+	return &authService{
+		staffRepo:    staffRepo,
+		sessionRepo:  sessionRepo,
+		staffService: staffService,
+		clock:        clock,
+		sessionTTL:   sessionTTL,
+	}
+}
+
+// dummyPasswordHash is compared against when the username does not exist so
+// that a failed login takes as long either way.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("timeseats-dummy-password"), bcrypt.DefaultCost)
+
+func (s *authService) Login(ctx context.Context, username, password string) (*Session, error) {
+	staff, err := s.staffRepo.FindByUsername(ctx, username)
+	if err != nil {
+		var notFound *repositories.ErrNotFound
+		if !errors.As(err, &notFound) {
+			return nil, err
+		}
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+	if bcrypt.CompareHashAndPassword([]byte(staff.PasswordHash), []byte(password)) != nil || !staff.IsActive {
+		return nil, ErrInvalidCredentials
+	}
+
+	token, err := newSecretToken("")
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	session := &models.StaffSession{
+		StaffID:   staff.ID,
+		TokenHash: hashSecretToken(token),
+		ExpiresAt: now.Add(s.sessionTTL),
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	// Opportunistic cleanup; a failure here must not block the login.
+	s.sessionRepo.DeleteExpired(ctx, now)
+
+	return &Session{Token: token, Staff: staff, ExpiresAt: session.ExpiresAt}, nil
+}
+
+func (s *authService) Logout(ctx context.Context, token string) error {
+	return s.sessionRepo.DeleteByTokenHash(ctx, hashSecretToken(token))
+}
+
+func (s *authService) Authenticate(ctx context.Context, token string) (*models.Staff, error) {
+	if token == "" {
+		return nil, ErrInvalidSession
+	}
+
+	session, err := s.sessionRepo.FindByTokenHash(ctx, hashSecretToken(token))
+	if err != nil {
+		var notFound *repositories.ErrNotFound
+		if errors.As(err, &notFound) {
+			return nil, ErrInvalidSession
+		}
+		return nil, err
+	}
+	if !s.clock.Now().Before(session.ExpiresAt) || session.Staff == nil || !session.Staff.IsActive {
+		return nil, ErrInvalidSession
+	}
+
+	return session.Staff, nil
+}
+
+func (s *authService) EnsureAdmin(ctx context.Context, username, password string) (bool, error) {
+	count, err := s.staffRepo.Count(ctx)
+	if err != nil {
+		return false, err
+	}
+	if count > 0 || username == "" {
+		return false, nil
+	}
+
+	if _, err := s.staffService.CreateStaff(ctx, username, password, types.ADMIN); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type mockStaffRepository struct {
+	mu    sync.Mutex
+	staff map[types.ID]*models.Staff
+}
+
+func newMockStaffRepository() *mockStaffRepository {
+	return &mockStaffRepository{staff: make(map[types.ID]*models.Staff)}
+}
+
+func (r *mockStaffRepository) Create(ctx context.Context, staff *models.Staff) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if staff.ID == "" {
+		staff.ID = types.ID("staff-" + staff.Username)
+	}
+	r.staff[staff.ID] = staff
+	return nil
+}
+
+func (r *mockStaffRepository) FindByID(ctx context.Context, id types.ID) (*models.Staff, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if staff, exists := r.staff[id]; exists {
+		copied := *staff
+		return &copied, nil
+	}
+	return nil, repositories.NewErrNotFound("Staff", id)
+}
+
+func (r *mockStaffRepository) FindAll(ctx context.Context) ([]models.Staff, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var staff []models.Staff
+	for _, s := range r.staff {
+		staff = append(staff, *s)
+	}
+	return staff, nil
+}
+
+func (r *mockStaffRepository) Update(ctx context.Context, staff *models.Staff) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *staff
+	r.staff[staff.ID] = &copied
+	return nil
+}
+
+func (r *mockStaffRepository) Delete(ctx context.Context, id types.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.staff, id)
+	return nil
+}
+
+func (r *mockStaffRepository) FindByUsername(ctx context.Context, username string) (*models.Staff, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.staff {
+		if s.Username == username {
+			copied := *s
+			return &copied, nil
+		}
+	}
+	return nil, repositories.NewErrNotFound("Staff", types.ID(username))
+}
+
+func (r *mockStaffRepository) Count(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return int64(len(r.staff)), nil
+}
+
+type mockStaffSessionRepository struct {
+	mu        sync.Mutex
+	staffRepo *mockStaffRepository
+	sessions  map[string]*models.StaffSession
+}
+
+func newMockStaffSessionRepository(staffRepo *mockStaffRepository) *mockStaffSessionRepository {
+	return &mockStaffSessionRepository{
+		staffRepo: staffRepo,
+		sessions:  make(map[string]*models.StaffSession),
+	}
+}
+
+func (r *mockStaffSessionRepository) Create(ctx context.Context, session *models.StaffSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[session.TokenHash] = session
+	return nil
+}
+
+func (r *mockStaffSessionRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*models.StaffSession, error) {
+	r.mu.Lock()
+	session, exists := r.sessions[tokenHash]
+	r.mu.Unlock()
+	if !exists {
+		return nil, repositories.NewErrNotFound("StaffSession", "")
+	}
+	copied := *session
+	copied.Staff, _ = r.staffRepo.FindByID(ctx, session.StaffID)
+	return &copied, nil
+}
+
+func (r *mockStaffSessionRepository) DeleteByTokenHash(ctx context.Context, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, tokenHash)
+	return nil
+}
+
+func (r *mockStaffSessionRepository) DeleteByStaffID(ctx context.Context, staffID types.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for hash, session := range r.sessions {
+		if session.StaffID == staffID {
+			delete(r.sessions, hash)
+		}
+	}
+	return nil
+}
+
+func (r *mockStaffSessionRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for hash, session := range r.sessions {
+		if session.ExpiresAt.Before(now) {
+			delete(r.sessions, hash)
+		}
+	}
+	return nil
+}
+
+func setupAuthTest(t *testing.T) (AuthService, StaffService, *fakeClock) {
+	t.Helper()
+	staffRepo := newMockStaffRepository()
+	sessionRepo := newMockStaffSessionRepository(staffRepo)
+	clock := &fakeClock{now: time.Date(2026, 9, 12, 9, 0, 0, 0, time.Local)}
+	staffService := NewStaffService(staffRepo, sessionRepo, &mockTransactor{}, newTestAuditLogService())
+	authService := NewAuthService(staffRepo, sessionRepo, staffService, clock, time.Hour)
+	return authService, staffService, clock
+}
+
+func TestAuthService_LoginAndAuthenticate(t *testing.T) {
+	authService, staffService, clock := setupAuthTest(t)
+	ctx := context.Background()
+
+	staff, err := staffService.CreateStaff(ctx, "cashier1", "correct-horse", types.CASHIER)
+	if err != nil {
+		t.Fatalf("CreateStaff failed: %v", err)
+	}
+	if staff.PasswordHash == "correct-horse" {
+		t.Error("Expected the password to be hashed")
+	}
+
+	if _, err := authService.Login(ctx, "cashier1", "wrong-password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials for a wrong password, got %v", err)
+	}
+	if _, err := authService.Login(ctx, "nobody", "correct-horse"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials for an unknown user, got %v", err)
+	}
+
+	session, err := authService.Login(ctx, "cashier1", "correct-horse")
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	authenticated, err := authService.Authenticate(ctx, session.Token)
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if authenticated.ID != staff.ID || authenticated.Role != types.CASHIER {
+		t.Errorf("Expected cashier1, got %+v", authenticated)
+	}
+
+	clock.now = clock.now.Add(2 * time.Hour)
+	if _, err := authService.Authenticate(ctx, session.Token); !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("Expected ErrInvalidSession for an expired session, got %v", err)
+	}
+}
+
+func TestAuthService_LogoutAndDeactivate(t *testing.T) {
+	authService, staffService, _ := setupAuthTest(t)
+	ctx := context.Background()
+
+	staff, _ := staffService.CreateStaff(ctx, "kitchen1", "correct-horse", types.KITCHEN)
+
+	first, _ := authService.Login(ctx, "kitchen1", "correct-horse")
+	authService.Logout(ctx, first.Token)
+	if _, err := authService.Authenticate(ctx, first.Token); !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("Expected ErrInvalidSession after logout, got %v", err)
+	}
+
+	second, _ := authService.Login(ctx, "kitchen1", "correct-horse")
+	if _, err := staffService.UpdateStaff(ctx, staff.ID, types.KITCHEN, false); err != nil {
+		t.Fatalf("UpdateStaff failed: %v", err)
+	}
+	if _, err := authService.Authenticate(ctx, second.Token); !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("Expected ErrInvalidSession after deactivation, got %v", err)
+	}
+	if _, err := authService.Login(ctx, "kitchen1", "correct-horse"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected a deactivated account to be unable to log in, got %v", err)
+	}
+}
+
+func TestAuthService_EnsureAdmin(t *testing.T) {
+	authService, staffService, _ := setupAuthTest(t)
+	ctx := context.Background()
+
+	if _, err := authService.EnsureAdmin(ctx, "admin", "short"); !errors.Is(err, ErrPasswordTooShort) {
+		t.Errorf("Expected ErrPasswordTooShort, got %v", err)
+	}
+
+	created, err := authService.EnsureAdmin(ctx, "admin", "correct-horse")
+	if err != nil || !created {
+		t.Fatalf("Expected the admin to be created, got %v, %v", created, err)
+	}
+	created, _ = authService.EnsureAdmin(ctx, "admin2", "correct-horse")
+	if created {
+		t.Error("Expected no admin to be created once staff accounts exist")
+	}
+
+	staff, _ := staffService.GetAllStaff(ctx)
+	if len(staff) != 1 || staff[0].Role != types.ADMIN {
+		t.Errorf("Expected a single admin account, got %+v", staff)
+	}
+}
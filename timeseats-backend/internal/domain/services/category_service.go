@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+)
+
+type CategoryService interface {
+	CreateCategory(ctx context.Context, name string, sortOrder int) (*models.Category, error)
+	GetCategory(ctx context.Context, id types.ID) (*models.Category, error)
+	GetAllCategories(ctx context.Context) ([]models.Category, error)
+	UpdateCategory(ctx context.Context, id types.ID, name string, sortOrder int) (*models.Category, error)
+	// DeleteCategory removes the category and moves its products to
+	// uncategorized.
+	DeleteCategory(ctx context.Context, id types.ID) error
+}
+
+type categoryService struct {
+	categoryRepo repositories.CategoryRepository
+	productRepo  repositories.ProductRepository
+	transactor   repositories.Transactor
+	audit        AuditLogService
+}
+
+func NewCategoryService(
+	categoryRepo repositories.CategoryRepository,
+	productRepo repositories.ProductRepository,
+	transactor repositories.Transactor,
+	audit AuditLogService,
+) CategoryService {
+	return &categoryService{
+		categoryRepo: categoryRepo,
+		productRepo:  productRepo,
+		transactor:   transactor,
+		audit:        audit,
+	}
+}
+
+func (s *categoryService) CreateCategory(ctx context.Context, name string, sortOrder int) (*models.Category, error) {
+	if name == "" {
+		return nil, ErrCategoryNameRequired
+	}
+
+	category := &models.Category{
+		ID:        types.ID(uuid.New().String()),
+		Name:      name,
+		SortOrder: sortOrder,
+	}
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.Create(ctx, category); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityCategory, category.ID, "create", nil, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *categoryService) GetCategory(ctx context.Context, id types.ID) (*models.Category, error) {
+	return s.categoryRepo.FindByID(ctx, id)
+}
+
+func (s *categoryService) GetAllCategories(ctx context.Context) ([]models.Category, error) {
+	return s.categoryRepo.FindAll(ctx)
+}
+
+func (s *categoryService) UpdateCategory(ctx context.Context, id types.ID, name string, sortOrder int) (*models.Category, error) {
+	if name == "" {
+		return nil, ErrCategoryNameRequired
+	}
+
+	var category *models.Category
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.categoryRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		updated := *before
+		updated.Name = name
+		updated.SortOrder = sortOrder
+
+		if err := s.categoryRepo.Update(ctx, &updated); err != nil {
+			return err
+		}
+		category = &updated
+
+		return s.audit.Record(ctx, AuditEntityCategory, id, "update", before, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *categoryService) DeleteCategory(ctx context.Context, id types.ID) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.categoryRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.productRepo.ClearCategory(ctx, id); err != nil {
+			return err
+		}
+		if err := s.categoryRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityCategory, id, "delete", before, nil)
+	})
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type mockCategoryRepository struct {
+	categories map[types.ID]*models.Category
+}
+
+func newMockCategoryRepository() *mockCategoryRepository {
+	return &mockCategoryRepository{
+		categories: make(map[types.ID]*models.Category),
+	}
+}
+
+func (r *mockCategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	r.categories[category.ID] = category
+	return nil
+}
+
+func (r *mockCategoryRepository) FindByID(ctx context.Context, id types.ID) (*models.Category, error) {
+	if category, exists := r.categories[id]; exists {
+		copied := *category
+		return &copied, nil
+	}
+	return nil, repositories.NewErrNotFound("Category", id)
+}
+
+func (r *mockCategoryRepository) FindAll(ctx context.Context) ([]models.Category, error) {
+	var categories []models.Category
+	for _, c := range r.categories {
+		categories = append(categories, *c)
+	}
+	return categories, nil
+}
+
+func (r *mockCategoryRepository) Update(ctx context.Context, category *models.Category) error {
+	if _, exists := r.categories[category.ID]; !exists {
+		return repositories.NewErrNotFound("Category", category.ID)
+	}
+	r.categories[category.ID] = category
+	return nil
+}
+
+func (r *mockCategoryRepository) Delete(ctx context.Context, id types.ID) error {
+	if _, exists := r.categories[id]; !exists {
+		return repositories.NewErrNotFound("Category", id)
+	}
+	delete(r.categories, id)
+	return nil
+}
+
+func TestCategoryService_CreateAndUpdate(t *testing.T) {
+	service := NewCategoryService(newMockCategoryRepository(), newMockProductRepository(), &mockTransactor{}, newTestAuditLogService())
+	ctx := context.Background()
+
+	if _, err := service.CreateCategory(ctx, "", 0); !errors.Is(err, ErrCategoryNameRequired) {
+		t.Errorf("Expected ErrCategoryNameRequired, got %v", err)
+	}
+
+	category, err := service.CreateCategory(ctx, "ドリンク", 2)
+	if err != nil {
+		t.Fatalf("CreateCategory failed: %v", err)
+	}
+
+	updated, err := service.UpdateCategory(ctx, category.ID, "飲み物", 1)
+	if err != nil {
+		t.Fatalf("UpdateCategory failed: %v", err)
+	}
+	if updated.Name != "飲み物" || updated.SortOrder != 1 {
+		t.Errorf("Expected 飲み物 with sort order 1, got %s with %d", updated.Name, updated.SortOrder)
+	}
+}
+
+func TestCategoryService_DeleteCategoryUncategorizesProducts(t *testing.T) {
+	categoryRepo := newMockCategoryRepository()
+	productRepo := newMockProductRepository()
+	categoryService := NewCategoryService(categoryRepo, productRepo, &mockTransactor{}, newTestAuditLogService())
+	productService := NewProductService(productRepo, categoryRepo, &mockTransactor{}, newTestAuditLogService())
+	ctx := context.Background()
+
+	category, _ := categoryService.CreateCategory(ctx, "主食", 0)
+	product, err := productService.CreateProduct(ctx, "焼きそば", 500, types.REDUCED_TAX_RATE, &category.ID, 1)
+	if err != nil {
+		t.Fatalf("CreateProduct failed: %v", err)
+	}
+	if product.Category == nil || product.Category.Name != "主食" {
+		t.Errorf("Expected product to carry its category, got %+v", product.Category)
+	}
+
+	if err := categoryService.DeleteCategory(ctx, category.ID); err != nil {
+		t.Fatalf("DeleteCategory failed: %v", err)
+	}
+
+	stored, _ := productService.GetProduct(ctx, product.ID)
+	if stored.CategoryID != nil {
+		t.Errorf("Expected product to be uncategorized, got category %s", *stored.CategoryID)
+	}
+}
+
+func TestProductService_CreateProductUnknownCategory(t *testing.T) {
+	service := NewProductService(newMockProductRepository(), newMockCategoryRepository(), &mockTransactor{}, newTestAuditLogService())
+
+	missing := types.ID("missing")
+	_, err := service.CreateProduct(context.Background(), "焼きそば", 500, types.REDUCED_TAX_RATE, &missing, 0)
+
+	var notFound *repositories.ErrNotFound
+	if !errors.As(err, &notFound) || notFound.Entity != "Category" {
+		t.Errorf("Expected category not found, got %v", err)
+	}
+}
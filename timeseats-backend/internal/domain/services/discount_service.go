@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+)
+
+// DiscountService manages promotions and applies them to orders. Discounts
+// can only be added to or removed from orders that are reserved and not
+// paid yet.
+type DiscountService interface {
+	CreateDiscount(ctx context.Context, input DiscountInput) (*models.Discount, error)
+	GetDiscount(ctx context.Context, id types.ID) (*models.Discount, error)
+	GetAllDiscounts(ctx context.Context) ([]models.Discount, error)
+	UpdateDiscount(ctx context.Context, id types.ID, input DiscountInput) (*models.Discount, error)
+	DeleteDiscount(ctx context.Context, id types.ID) error
+	// ApplyDiscount adds the discount with the ID, or the coupon with the
+	// code when discountID is empty, to the order.
+	ApplyDiscount(ctx context.Context, orderID, discountID types.ID, code string) (*models.Order, error)
+	RemoveDiscount(ctx context.Context, orderID, orderDiscountID types.ID) (*models.Order, error)
+	// OverridePrice sets the unit price of an order line, replacing any
+	// earlier override of the line. The staff member in the context is
+	// recorded as the one who approved it.
+	OverridePrice(ctx context.Context, orderID, itemID types.ID, price int, reason string) (*models.Order, error)
+}
+
+// DiscountInput describes a discount; see models.Discount for the meaning of
+// the fields. Codes are matched case-insensitively.
+type DiscountInput struct {
+	Name         string
+	Code         string
+	Type         types.DiscountType
+	Value        int
+	ProductID    *types.ID
+	BuyQuantity  int
+	FreeQuantity int
+	UsageLimit   int
+	ValidFrom    *time.Time
+	ValidUntil   *time.Time
+	IsActive     bool
+}
+
+type discountService struct {
+	discountRepo repositories.DiscountRepository
+	orderRepo    repositories.OrderRepository
+	productRepo  repositories.ProductRepository
+	transactor   repositories.Transactor
+	publisher    events.Publisher
+	clock        Clock
+	audit        AuditLogService
+}
+
+func NewDiscountService(
+	discountRepo repositories.DiscountRepository,
+	orderRepo repositories.OrderRepository,
+	productRepo repositories.ProductRepository,
+	transactor repositories.Transactor,
+	publisher events.Publisher,
+	clock Clock,
+	audit AuditLogService,
+) DiscountService {
+	return &discountService{
+		discountRepo: discountRepo,
+		orderRepo:    orderRepo,
+		productRepo:  productRepo,
+		transactor:   transactor,
+		publisher:    publisher,
+		clock:        clock,
+		audit:        audit,
+	}
+}
+
+func (s *discountService) CreateDiscount(ctx context.Context, input DiscountInput) (*models.Discount, error) {
+	discount := &models.Discount{ID: types.ID(uuid.New().String())}
+	applyDiscountInput(discount, input)
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.validateDiscount(ctx, discount); err != nil {
+			return err
+		}
+		if err := s.discountRepo.Create(ctx, discount); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityDiscount, discount.ID, "create", nil, discount)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return discount, nil
+}
+
+func (s *discountService) GetDiscount(ctx context.Context, id types.ID) (*models.Discount, error) {
+	return s.discountRepo.FindByID(ctx, id)
+}
+
+func (s *discountService) GetAllDiscounts(ctx context.Context) ([]models.Discount, error) {
+	return s.discountRepo.FindAll(ctx)
+}
+
+func (s *discountService) UpdateDiscount(ctx context.Context, id types.ID, input DiscountInput) (*models.Discount, error) {
+	var discount *models.Discount
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.discountRepo.FindByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		after := *before
+		after.Product = nil
+		applyDiscountInput(&after, input)
+		if err := s.validateDiscount(ctx, &after); err != nil {
+			return err
+		}
+		if err := s.discountRepo.Update(ctx, &after); err != nil {
+			return err
+		}
+		discount = &after
+		return s.audit.Record(ctx, AuditEntityDiscount, id, "update", before, discount)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return discount, nil
+}
+
+// DeleteDiscount keeps the discount lines already on orders.
+func (s *discountService) DeleteDiscount(ctx context.Context, id types.ID) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.discountRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.discountRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityDiscount, id, "delete", before, nil)
+	})
+}
+
+func applyDiscountInput(discount *models.Discount, input DiscountInput) {
+	discount.Name = input.Name
+	discount.Code = nil
+	if code := normalizeCouponCode(input.Code); code != "" {
+		discount.Code = &code
+	}
+	discount.Type = input.Type
+	discount.Value = input.Value
+	discount.ProductID = input.ProductID
+	discount.BuyQuantity = input.BuyQuantity
+	discount.FreeQuantity = input.FreeQuantity
+	discount.UsageLimit = input.UsageLimit
+	discount.ValidFrom = input.ValidFrom
+	discount.ValidUntil = input.ValidUntil
+	discount.IsActive = input.IsActive
+}
+
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (s *discountService) validateDiscount(ctx context.Context, discount *models.Discount) error {
+	if discount.Name == "" || discount.UsageLimit < 0 {
+		return ErrInvalidDiscount
+	}
+	if discount.ValidFrom != nil && discount.ValidUntil != nil && !discount.ValidFrom.Before(*discount.ValidUntil) {
+		return ErrInvalidTimeRange
+	}
+
+	switch discount.Type {
+	case types.FIXED_AMOUNT:
+		if discount.Value <= 0 {
+			return ErrInvalidDiscount
+		}
+	case types.PERCENTAGE:
+		if discount.Value <= 0 || discount.Value > 100 {
+			return ErrInvalidDiscount
+		}
+	case types.BUY_X_GET_Y:
+		if discount.ProductID == nil || discount.BuyQuantity <= 0 || discount.FreeQuantity <= 0 {
+			return ErrInvalidDiscount
+		}
+	default:
+		return ErrInvalidDiscount
+	}
+
+	if discount.ProductID != nil {
+		if _, err := s.productRepo.FindByID(ctx, *discount.ProductID); err != nil {
+			return err
+		}
+	}
+
+	if discount.Code != nil {
+		existing, err := s.discountRepo.FindByCode(ctx, *discount.Code)
+		var notFound *repositories.ErrNotFound
+		if err != nil && !errors.As(err, &notFound) {
+			return err
+		}
+		if existing != nil && existing.ID != discount.ID {
+			return ErrDuplicateDiscountCode
+		}
+	}
+	return nil
+}
+
+func (s *discountService) ApplyDiscount(ctx context.Context, orderID, discountID types.ID, code string) (*models.Order, error) {
+	return s.changeDiscounts(ctx, orderID, "apply_discount", func(ctx context.Context, order *models.Order) error {
+		if discountID == "" {
+			found, err := s.discountRepo.FindByCode(ctx, normalizeCouponCode(code))
+			var notFound *repositories.ErrNotFound
+			if errors.As(err, &notFound) {
+				return ErrInvalidCoupon
+			}
+			if err != nil {
+				return err
+			}
+			discountID = found.ID
+		}
+
+		// Locked so that two orders cannot both take the last use.
+		discount, err := s.discountRepo.FindByIDForUpdate(ctx, discountID)
+		if err != nil {
+			return err
+		}
+		if !discount.IsValidAt(s.clock.Now()) {
+			return ErrDiscountUnavailable
+		}
+		for _, line := range order.Discounts {
+			if line.DiscountID != nil && *line.DiscountID == discount.ID {
+				return ErrDuplicateDiscount
+			}
+		}
+		if discount.UsageLimit > 0 && discount.UsageCount >= discount.UsageLimit {
+			return ErrDiscountUsedUp
+		}
+
+		line := models.OrderDiscount{
+			ID:           types.ID(uuid.New().String()),
+			OrderID:      order.ID,
+			DiscountID:   &discount.ID,
+			Type:         discount.Type,
+			Name:         discount.Name,
+			Code:         discount.Code,
+			Value:        discount.Value,
+			ProductID:    discount.ProductID,
+			BuyQuantity:  discount.BuyQuantity,
+			FreeQuantity: discount.FreeQuantity,
+			CreatedAt:    s.clock.Now(),
+		}
+		trial := *order
+		trial.Items = slices.Clone(order.Items)
+		trial.Discounts = append(slices.Clone(order.Discounts), line)
+		priceOrder(&trial)
+		if trial.Discounts[len(trial.Discounts)-1].Amount == 0 {
+			return ErrDiscountNotApplicable
+		}
+		return s.orderRepo.AddDiscount(ctx, &line)
+	})
+}
+
+func (s *discountService) RemoveDiscount(ctx context.Context, orderID, orderDiscountID types.ID) (*models.Order, error) {
+	return s.changeDiscounts(ctx, orderID, "remove_discount", func(ctx context.Context, order *models.Order) error {
+		for _, line := range order.Discounts {
+			if line.ID == orderDiscountID {
+				return s.orderRepo.DeleteDiscount(ctx, line.ID)
+			}
+		}
+		return repositories.NewErrNotFound("OrderDiscount", orderDiscountID)
+	})
+}
+
+func (s *discountService) OverridePrice(ctx context.Context, orderID, itemID types.ID, price int, reason string) (*models.Order, error) {
+	if reason == "" {
+		return nil, ErrPriceReasonRequired
+	}
+	return s.changeDiscounts(ctx, orderID, "override_price", func(ctx context.Context, order *models.Order) error {
+		item := findOrderItem(order.Items, itemID)
+		if item == nil {
+			return repositories.NewErrNotFound("OrderItem", itemID)
+		}
+		if price < 0 || price >= item.Price {
+			return ErrInvalidAmount
+		}
+
+		for _, line := range order.Discounts {
+			if line.Type == types.PRICE_OVERRIDE && line.OrderItemID != nil && *line.OrderItemID == itemID {
+				if err := s.orderRepo.DeleteDiscount(ctx, line.ID); err != nil {
+					return err
+				}
+			}
+		}
+
+		approvedByID, _ := actorIDs(ctx)
+		return s.orderRepo.AddDiscount(ctx, &models.OrderDiscount{
+			ID:           types.ID(uuid.New().String()),
+			OrderID:      order.ID,
+			OrderItemID:  &item.ID,
+			Type:         types.PRICE_OVERRIDE,
+			Name:         "価格変更",
+			Value:        price,
+			Reason:       reason,
+			ApprovedByID: approvedByID,
+			CreatedAt:    s.clock.Now(),
+		})
+	})
+}
+
+// changeDiscounts locks a reserved, unpaid order, lets change add or remove
+// discount lines and then recalculates and saves the totals.
+func (s *discountService) changeDiscounts(ctx context.Context, orderID types.ID, action string, change func(ctx context.Context, order *models.Order) error) (*models.Order, error) {
+	var order *models.Order
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.orderRepo.FindByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if before.Status != types.RESERVED || before.PaidAmount > 0 {
+			return ErrDiscountNotAllowed
+		}
+
+		if err := change(ctx, before); err != nil {
+			return err
+		}
+
+		after, err := s.orderRepo.FindByID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		priceOrder(after)
+		err = s.orderRepo.UpdatePricing(ctx, after, types.RESERVED)
+		if errors.Is(err, repositories.ErrConflict) {
+			return ErrDiscountNotAllowed
+		}
+		if err != nil {
+			return err
+		}
+		order = after
+		return s.audit.Record(ctx, AuditEntityOrder, orderID, action, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publisher.Publish(events.NewOrderEvent(events.OrderItemsUpdated, order))
+
+	return order, nil
+}
@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+)
+
+// mockDiscountRepository counts uses from the orders of orderRepo like the
+// real repository does from the database.
+type mockDiscountRepository struct {
+	discounts map[types.ID]*models.Discount
+	orderRepo *mockOrderRepository
+}
+
+func newMockDiscountRepository(orderRepo *mockOrderRepository) *mockDiscountRepository {
+	return &mockDiscountRepository{
+		discounts: make(map[types.ID]*models.Discount),
+		orderRepo: orderRepo,
+	}
+}
+
+func (r *mockDiscountRepository) withUsageCount(discount *models.Discount) *models.Discount {
+	found := *discount
+	found.UsageCount = 0
+	r.orderRepo.mu.Lock()
+	defer r.orderRepo.mu.Unlock()
+	for _, order := range r.orderRepo.orders {
+		if order.Status == types.CANCELLED {
+			continue
+		}
+		for _, line := range order.Discounts {
+			if line.DiscountID != nil && *line.DiscountID == discount.ID {
+				found.UsageCount++
+				break
+			}
+		}
+	}
+	return &found
+}
+
+func (r *mockDiscountRepository) Create(ctx context.Context, discount *models.Discount) error {
+	if discount.ID == "" {
+		discount.ID = types.ID(uuid.New().String())
+	}
+	r.discounts[discount.ID] = discount
+	return nil
+}
+
+func (r *mockDiscountRepository) FindByID(ctx context.Context, id types.ID) (*models.Discount, error) {
+	if discount, exists := r.discounts[id]; exists {
+		return r.withUsageCount(discount), nil
+	}
+	return nil, repositories.NewErrNotFound("Discount", id)
+}
+
+func (r *mockDiscountRepository) FindByIDForUpdate(ctx context.Context, id types.ID) (*models.Discount, error) {
+	return r.FindByID(ctx, id)
+}
+
+func (r *mockDiscountRepository) FindByCode(ctx context.Context, code string) (*models.Discount, error) {
+	for _, discount := range r.discounts {
+		if discount.Code != nil && *discount.Code == code {
+			return r.withUsageCount(discount), nil
+		}
+	}
+	return nil, repositories.NewErrNotFound("Discount", types.ID(code))
+}
+
+func (r *mockDiscountRepository) FindAll(ctx context.Context) ([]models.Discount, error) {
+	var discounts []models.Discount
+	for _, discount := range r.discounts {
+		discounts = append(discounts, *r.withUsageCount(discount))
+	}
+	return discounts, nil
+}
+
+func (r *mockDiscountRepository) Update(ctx context.Context, discount *models.Discount) error {
+	if _, exists := r.discounts[discount.ID]; !exists {
+		return repositories.NewErrNotFound("Discount", discount.ID)
+	}
+	r.discounts[discount.ID] = discount
+	return nil
+}
+
+func (r *mockDiscountRepository) Delete(ctx context.Context, id types.ID) error {
+	if _, exists := r.discounts[id]; !exists {
+		return repositories.NewErrNotFound("Discount", id)
+	}
+	delete(r.discounts, id)
+	return nil
+}
+
+func TestPriceOrder(t *testing.T) {
+	yakisoba := types.ID("prod1")
+	drink := types.ID("prod2")
+	itemID := types.ID("item1")
+	items := []models.OrderItem{
+		{ID: itemID, ProductID: yakisoba, Quantity: 3, Price: 400},
+		{ID: "item2", ProductID: drink, Quantity: 1, Price: 150},
+	}
+
+	tests := []struct {
+		name      string
+		discounts []models.OrderDiscount
+		total     int
+		amounts   []int
+	}{
+		{
+			name:  "no discounts",
+			total: 1350,
+		},
+		{
+			name:      "fixed amount off the order",
+			discounts: []models.OrderDiscount{{Type: types.FIXED_AMOUNT, Value: 100}},
+			total:     1250,
+			amounts:   []int{100},
+		},
+		{
+			name:      "fixed amount off each unit of a product",
+			discounts: []models.OrderDiscount{{Type: types.FIXED_AMOUNT, Value: 50, ProductID: &yakisoba}},
+			total:     1200,
+			amounts:   []int{150},
+		},
+		{
+			name:      "percentage rounds down",
+			discounts: []models.OrderDiscount{{Type: types.PERCENTAGE, Value: 15}},
+			total:     1148,
+			amounts:   []int{202},
+		},
+		{
+			name:      "buy two get one free",
+			discounts: []models.OrderDiscount{{Type: types.BUY_X_GET_Y, ProductID: &yakisoba, BuyQuantity: 2, FreeQuantity: 1}},
+			total:     950,
+			amounts:   []int{400},
+		},
+		{
+			name:      "price override",
+			discounts: []models.OrderDiscount{{Type: types.PRICE_OVERRIDE, OrderItemID: &itemID, Value: 300}},
+			total:     1050,
+			amounts:   []int{300},
+		},
+		{
+			name: "order-level lines apply after item-level ones",
+			discounts: []models.OrderDiscount{
+				{Type: types.PERCENTAGE, Value: 10},
+				{Type: types.PRICE_OVERRIDE, OrderItemID: &itemID, Value: 300},
+			},
+			total:   945,
+			amounts: []int{105, 300},
+		},
+		{
+			name: "total never goes below zero",
+			discounts: []models.OrderDiscount{
+				{Type: types.FIXED_AMOUNT, Value: 1000},
+				{Type: types.FIXED_AMOUNT, Value: 1000},
+			},
+			total:   0,
+			amounts: []int{1000, 350},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &models.Order{Items: items, Discounts: tt.discounts}
+			priceOrder(order)
+
+			if order.TotalAmount != tt.total {
+				t.Errorf("Expected total %d, got %d", tt.total, order.TotalAmount)
+			}
+			if order.Subtotal() != 1350 {
+				t.Errorf("Expected subtotal 1350, got %d", order.Subtotal())
+			}
+			for i, amount := range tt.amounts {
+				if order.Discounts[i].Amount != amount {
+					t.Errorf("Expected line %d to take off %d, got %d", i, amount, order.Discounts[i].Amount)
+				}
+			}
+		})
+	}
+}
+
+func setupDiscountTest(t *testing.T) (DiscountService, OrderService, *models.SalesSlot, *models.Product, *fakeClock) {
+	t.Helper()
+	orders, _, slot, product := setupConcurrencyTest(t, 10)
+	orderRepo := orders.(*orderService).orderRepo
+	productRepo := orders.(*orderService).productRepo
+	discountRepo := newMockDiscountRepository(orderRepo.(*mockOrderRepository))
+	clock := &fakeClock{now: time.Date(2026, 9, 12, 9, 0, 0, 0, time.Local)}
+	service := NewDiscountService(discountRepo, orderRepo, productRepo, &mockTransactor{}, &mockPublisher{}, clock, newTestAuditLogService())
+	return service, orders, slot, product, clock
+}
+
+func TestDiscountService_ApplyCoupon(t *testing.T) {
+	service, orders, slot, product, clock := setupDiscountTest(t)
+	ctx := context.Background()
+
+	until := clock.now.Add(time.Hour)
+	coupon, err := service.CreateDiscount(ctx, DiscountInput{
+		Name:       "生徒会クーポン",
+		Code:       " seiko100 ",
+		Type:       types.FIXED_AMOUNT,
+		Value:      100,
+		UsageLimit: 1,
+		ValidUntil: &until,
+		IsActive:   true,
+	})
+	if err != nil {
+		t.Fatalf("CreateDiscount failed: %v", err)
+	}
+	if _, err := service.CreateDiscount(ctx, DiscountInput{Name: "重複", Code: "SEIKO100", Type: types.FIXED_AMOUNT, Value: 50, IsActive: true}); !errors.Is(err, ErrDuplicateDiscountCode) {
+		t.Errorf("Expected ErrDuplicateDiscountCode, got %v", err)
+	}
+
+	first, _ := orders.CreateOrder(ctx, slot.ID, []OrderItemInput{{ProductID: product.ID, Quantity: 2}}, "", types.CASH)
+	second, _ := orders.CreateOrder(ctx, slot.ID, []OrderItemInput{{ProductID: product.ID, Quantity: 1}}, "", types.CASH)
+
+	if _, err := service.ApplyDiscount(ctx, first.ID, "", "NOPE"); !errors.Is(err, ErrInvalidCoupon) {
+		t.Errorf("Expected ErrInvalidCoupon, got %v", err)
+	}
+	order, err := service.ApplyDiscount(ctx, first.ID, "", "seiko100")
+	if err != nil {
+		t.Fatalf("ApplyDiscount failed: %v", err)
+	}
+	if order.TotalAmount != 700 || order.DiscountAmount != 100 {
+		t.Errorf("Expected total 700 with 100 off, got %d with %d off", order.TotalAmount, order.DiscountAmount)
+	}
+	if _, err := service.ApplyDiscount(ctx, first.ID, coupon.ID, ""); !errors.Is(err, ErrDuplicateDiscount) {
+		t.Errorf("Expected ErrDuplicateDiscount, got %v", err)
+	}
+	if _, err := service.ApplyDiscount(ctx, second.ID, "", "SEIKO100"); !errors.Is(err, ErrDiscountUsedUp) {
+		t.Errorf("Expected ErrDiscountUsedUp, got %v", err)
+	}
+
+	// Cancelling the order gives the use back.
+	if err := orders.CancelOrder(ctx, first.ID, ""); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+	clock.now = until
+	if _, err := service.ApplyDiscount(ctx, second.ID, "", "SEIKO100"); !errors.Is(err, ErrDiscountUnavailable) {
+		t.Errorf("Expected ErrDiscountUnavailable after the coupon expired, got %v", err)
+	}
+	clock.now = until.Add(-time.Minute)
+	if _, err := service.ApplyDiscount(ctx, second.ID, "", "SEIKO100"); err != nil {
+		t.Errorf("Expected the use of a cancelled order to be given back, got %v", err)
+	}
+}
+
+func TestDiscountService_ApplyDiscount_NotApplicable(t *testing.T) {
+	service, orders, slot, product, _ := setupDiscountTest(t)
+	ctx := context.Background()
+
+	bogo, err := service.CreateDiscount(ctx, DiscountInput{
+		Name:         "2つ買うと1つ無料",
+		Type:         types.BUY_X_GET_Y,
+		ProductID:    &product.ID,
+		BuyQuantity:  2,
+		FreeQuantity: 1,
+		IsActive:     true,
+	})
+	if err != nil {
+		t.Fatalf("CreateDiscount failed: %v", err)
+	}
+
+	order, _ := orders.CreateOrder(ctx, slot.ID, []OrderItemInput{{ProductID: product.ID, Quantity: 2}}, "", types.CASH)
+	if _, err := service.ApplyDiscount(ctx, order.ID, bogo.ID, ""); !errors.Is(err, ErrDiscountNotApplicable) {
+		t.Errorf("Expected ErrDiscountNotApplicable with two units, got %v", err)
+	}
+
+	if err := orders.UpdateOrderItem(ctx, order.ID, order.Items[0].ID, 3); err != nil {
+		t.Fatalf("UpdateOrderItem failed: %v", err)
+	}
+	order, err = service.ApplyDiscount(ctx, order.ID, bogo.ID, "")
+	if err != nil {
+		t.Fatalf("ApplyDiscount failed: %v", err)
+	}
+	if order.TotalAmount != 800 {
+		t.Errorf("Expected total 800, got %d", order.TotalAmount)
+	}
+
+	// The discount follows the items when they change.
+	if err := orders.UpdateOrderItem(ctx, order.ID, order.Items[0].ID, 6); err != nil {
+		t.Fatalf("UpdateOrderItem failed: %v", err)
+	}
+	order, _ = orders.GetOrder(ctx, order.ID)
+	if order.TotalAmount != 1600 || order.DiscountAmount != 800 {
+		t.Errorf("Expected total 1600 with 800 off, got %d with %d off", order.TotalAmount, order.DiscountAmount)
+	}
+
+	order, err = service.RemoveDiscount(ctx, order.ID, order.Discounts[0].ID)
+	if err != nil {
+		t.Fatalf("RemoveDiscount failed: %v", err)
+	}
+	if order.TotalAmount != 2400 || len(order.Discounts) != 0 {
+		t.Errorf("Expected total 2400 without discounts, got %d with %d lines", order.TotalAmount, len(order.Discounts))
+	}
+}
+
+func TestDiscountService_OverridePrice(t *testing.T) {
+	service, orders, slot, product, _ := setupDiscountTest(t)
+	ctx := WithActor(context.Background(), &models.Staff{ID: "admin-1", Role: types.ADMIN})
+
+	order, _ := orders.CreateOrder(ctx, slot.ID, []OrderItemInput{{ProductID: product.ID, Quantity: 2}}, "", types.CASH)
+	itemID := order.Items[0].ID
+
+	if _, err := service.OverridePrice(ctx, order.ID, itemID, 300, ""); !errors.Is(err, ErrPriceReasonRequired) {
+		t.Errorf("Expected ErrPriceReasonRequired, got %v", err)
+	}
+	if _, err := service.OverridePrice(ctx, order.ID, itemID, 500, "値引き"); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Expected ErrInvalidAmount for a higher price, got %v", err)
+	}
+
+	service.OverridePrice(ctx, order.ID, itemID, 300, "売れ残り")
+	order, err := service.OverridePrice(ctx, order.ID, itemID, 250, "売れ残り")
+	if err != nil {
+		t.Fatalf("OverridePrice failed: %v", err)
+	}
+	if len(order.Discounts) != 1 {
+		t.Fatalf("Expected the second override to replace the first, got %d lines", len(order.Discounts))
+	}
+	line := order.Discounts[0]
+	if line.Amount != 300 || order.TotalAmount != 500 {
+		t.Errorf("Expected 300 off and a total of 500, got %d off and %d", line.Amount, order.TotalAmount)
+	}
+	if line.ApprovedByID == nil || *line.ApprovedByID != "admin-1" || line.Reason != "売れ残り" {
+		t.Errorf("Expected the override to record who approved it and why, got %+v", line)
+	}
+
+	orders.UpdateOrderStatus(ctx, order.ID, types.CONFIRMED)
+	if _, err := service.RemoveDiscount(ctx, order.ID, line.ID); !errors.Is(err, ErrDiscountNotAllowed) {
+		t.Errorf("Expected ErrDiscountNotAllowed on a confirmed order, got %v", err)
+	}
+}
@@ -0,0 +1,370 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// YenDenominations are the notes and coins a drawer count may list.
+var YenDenominations = []int{10000, 5000, 2000, 1000, 500, 100, 50, 10, 5, 1}
+
+// ChangeDenominations suggests notes and coins that make up the change from
+// what the drawer holds, available being the quantity on hand of each
+// denomination. The largest are taken first, and when a denomination runs
+// out the change is made up from smaller ones. It reports false when the
+// drawer cannot make up the change.
+func ChangeDenominations(change int, available map[int]int) ([]models.DrawerCount, bool) {
+	if change < 0 {
+		return nil, false
+	}
+
+	// Taking the largest first always works out from 2,000 yen down, as each
+	// of those denominations divides the one above it. 10,000 and 5,000 yen
+	// notes may have to be held back for 2,000 yen notes to make up the
+	// rest, such as 6,000 yen as three of them.
+	most10000 := min(available[10000], change/10000)
+	for n10000 := most10000; n10000 >= max(most10000-4, 0); n10000-- {
+		rest := change - n10000*10000
+		most5000 := min(available[5000], rest/5000)
+		for n5000 := most5000; n5000 >= max(most5000-4, 0); n5000-- {
+			smaller, ok := largestFirst(rest-n5000*5000, YenDenominations[2:], available)
+			if !ok {
+				continue
+			}
+			var counts []models.DrawerCount
+			if n10000 > 0 {
+				counts = append(counts, models.DrawerCount{Denomination: 10000, Quantity: n10000})
+			}
+			if n5000 > 0 {
+				counts = append(counts, models.DrawerCount{Denomination: 5000, Quantity: n5000})
+			}
+			return append(counts, smaller...), true
+		}
+	}
+	return nil, false
+}
+
+// largestFirst makes up the amount from the denominations, largest first,
+// as far as the available quantities go.
+func largestFirst(amount int, denominations []int, available map[int]int) ([]models.DrawerCount, bool) {
+	var counts []models.DrawerCount
+	for _, denomination := range denominations {
+		if quantity := min(available[denomination], amount/denomination); quantity > 0 {
+			counts = append(counts, models.DrawerCount{Denomination: denomination, Quantity: quantity})
+			amount -= denomination * quantity
+		}
+	}
+	return counts, amount == 0
+}
+
+// DrawerReport reconciles a drawer session. Expected is the opening float
+// plus cash sales and pay-ins, minus cash refunds and pay-outs. Counted and
+// Difference are set once the session is closed; a positive Difference is a
+// surplus and a negative one a shortage. CashTendered and ChangeGiven break
+// down the cash sales taken with the amount tendered: customers handed over
+// CashTendered and got ChangeGiven back.
+type DrawerReport struct {
+	Session      *models.DrawerSession
+	CashSales    int
+	CashTendered int
+	ChangeGiven  int
+	CashRefunds  int
+	PayIns       int
+	PayOuts      int
+	Expected     int
+	Counted      *int
+	Difference   *int
+}
+
+type DrawerService interface {
+	// OpenSession opens a session on the terminal in ctx for the cashier in
+	// ctx.
+	OpenSession(ctx context.Context, openingFloat int) (*models.DrawerSession, error)
+	GetCurrentSession(ctx context.Context) (*DrawerReport, error)
+	GetSession(ctx context.Context, id types.ID) (*DrawerReport, error)
+	GetSessions(ctx context.Context, terminalID types.ID) ([]models.DrawerSession, error)
+	RecordMovement(ctx context.Context, sessionID types.ID, movementType types.DrawerMovementType, amount int, reason string) (*models.DrawerMovement, error)
+	// CloseSession settles the session with the number of notes and coins
+	// counted per denomination.
+	CloseSession(ctx context.Context, sessionID types.ID, counts map[int]int, note string) (*DrawerReport, error)
+}
+
+type drawerService struct {
+	drawerRepo  repositories.DrawerSessionRepository
+	paymentRepo repositories.PaymentRepository
+	refundRepo  repositories.RefundRepository
+	transactor  repositories.Transactor
+	clock       Clock
+	audit       AuditLogService
+}
+
+func NewDrawerService(
+	drawerRepo repositories.DrawerSessionRepository,
+	paymentRepo repositories.PaymentRepository,
+	refundRepo repositories.RefundRepository,
+	transactor repositories.Transactor,
+	clock Clock,
+	audit AuditLogService,
+) DrawerService {
+	return &drawerService{
+		drawerRepo:  drawerRepo,
+		paymentRepo: paymentRepo,
+		refundRepo:  refundRepo,
+		transactor:  transactor,
+		clock:       clock,
+		audit:       audit,
+	}
+}
+
+func (s *drawerService) OpenSession(ctx context.Context, openingFloat int) (*models.DrawerSession, error) {
+	if openingFloat < 0 {
+		return nil, ErrInvalidAmount
+	}
+	staffID, terminalID := actorIDs(ctx)
+	if terminalID == nil || staffID == nil {
+		return nil, ErrTerminalRequired
+	}
+
+	session := &models.DrawerSession{
+		TerminalID:   *terminalID,
+		OpenedByID:   *staffID,
+		OpeningFloat: openingFloat,
+		OpenedAt:     s.clock.Now(),
+	}
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.drawerRepo.FindOpenByTerminal(ctx, *terminalID)
+		if err == nil {
+			return ErrDrawerAlreadyOpen
+		}
+		var notFound *repositories.ErrNotFound
+		if !errors.As(err, &notFound) {
+			return err
+		}
+
+		if err := s.drawerRepo.Create(ctx, session); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityDrawerSession, session.ID, "open", nil, session)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (s *drawerService) GetCurrentSession(ctx context.Context) (*DrawerReport, error) {
+	terminal, ok := TerminalFromContext(ctx)
+	if !ok {
+		return nil, ErrTerminalRequired
+	}
+
+	session, err := s.drawerRepo.FindOpenByTerminal(ctx, terminal.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.report(ctx, session)
+}
+
+func (s *drawerService) GetSession(ctx context.Context, id types.ID) (*DrawerReport, error) {
+	session, err := s.drawerRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.report(ctx, session)
+}
+
+func (s *drawerService) GetSessions(ctx context.Context, terminalID types.ID) ([]models.DrawerSession, error) {
+	return s.drawerRepo.FindByTerminal(ctx, terminalID)
+}
+
+func (s *drawerService) RecordMovement(ctx context.Context, sessionID types.ID, movementType types.DrawerMovementType, amount int, reason string) (*models.DrawerMovement, error) {
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	staffID, _ := actorIDs(ctx)
+	movement := &models.DrawerMovement{
+		DrawerSessionID: sessionID,
+		Type:            movementType,
+		Amount:          amount,
+		Reason:          reason,
+		StaffID:         staffID,
+		CreatedAt:       s.clock.Now(),
+	}
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// The lock keeps the session from being closed meanwhile.
+		session, err := s.drawerRepo.FindByIDForUpdate(ctx, sessionID)
+		if err != nil {
+			return err
+		}
+		if err := checkDrawerOpen(ctx, session); err != nil {
+			return err
+		}
+
+		if err := s.drawerRepo.AddMovement(ctx, movement); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityDrawerSession, sessionID, "record_"+movementType.String(), nil, movement)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return movement, nil
+}
+
+func (s *drawerService) CloseSession(ctx context.Context, sessionID types.ID, counts map[int]int, note string) (*DrawerReport, error) {
+	drawerCounts, counted, err := drawerCounts(counts)
+	if err != nil {
+		return nil, err
+	}
+
+	var report *DrawerReport
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		session, err := s.drawerRepo.FindByIDForUpdate(ctx, sessionID)
+		if err != nil {
+			return err
+		}
+		if err := checkDrawerOpen(ctx, session); err != nil {
+			return err
+		}
+
+		before := *session
+		closedAt := s.clock.Now()
+		closedByID, _ := actorIDs(ctx)
+		session.ClosedAt = &closedAt
+		session.ClosedByID = closedByID
+
+		// Computed after ClosedAt is set so sales are counted up to closing.
+		if report, err = s.report(ctx, session); err != nil {
+			return err
+		}
+		session.CashSales = &report.CashSales
+		session.CashTendered = &report.CashTendered
+		session.ChangeGiven = &report.ChangeGiven
+		session.CashRefunds = &report.CashRefunds
+		session.ExpectedAmount = &report.Expected
+		session.CountedAmount = &counted
+		session.Counts = drawerCounts
+		session.Note = note
+
+		err = s.drawerRepo.Close(ctx, session)
+		if errors.Is(err, repositories.ErrConflict) {
+			return ErrDrawerClosed
+		}
+		if err != nil {
+			return err
+		}
+
+		report.Counted = session.CountedAmount
+		difference := counted - report.Expected
+		report.Difference = &difference
+
+		return s.audit.Record(ctx, AuditEntityDrawerSession, sessionID, "close", &before, session)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// report reconciles the session, adding up cash sales and refunds until it
+// was closed or until now.
+func (s *drawerService) report(ctx context.Context, session *models.DrawerSession) (*DrawerReport, error) {
+	payIns, payOuts := session.MovementTotals()
+	report := &DrawerReport{
+		Session: session,
+		PayIns:  payIns,
+		PayOuts: payOuts,
+		Counted: session.CountedAmount,
+	}
+
+	var until time.Time
+	if session.ClosedAt != nil {
+		until = *session.ClosedAt
+	}
+	if session.CashSales != nil {
+		report.CashSales = *session.CashSales
+	} else {
+		cashSales, err := s.paymentRepo.SumPayments(ctx, session.TerminalID, types.CASH, session.OpenedAt, until)
+		if err != nil {
+			return nil, err
+		}
+		report.CashSales = cashSales
+	}
+	if session.CashTendered != nil && session.ChangeGiven != nil {
+		report.CashTendered = *session.CashTendered
+		report.ChangeGiven = *session.ChangeGiven
+	} else {
+		cash, err := s.paymentRepo.SumCashTendered(ctx, session.TerminalID, session.OpenedAt, until)
+		if err != nil {
+			return nil, err
+		}
+		report.CashTendered = cash.Tendered
+		report.ChangeGiven = cash.Change
+	}
+	if session.CashRefunds != nil {
+		report.CashRefunds = *session.CashRefunds
+	} else {
+		cashRefunds, err := s.refundRepo.SumRefunds(ctx, session.TerminalID, types.CASH, session.OpenedAt, until)
+		if err != nil {
+			return nil, err
+		}
+		report.CashRefunds = cashRefunds
+	}
+
+	report.Expected = session.OpeningFloat + report.CashSales - report.CashRefunds + payIns - payOuts
+	if session.ExpectedAmount != nil {
+		report.Expected = *session.ExpectedAmount
+	}
+	if report.Counted != nil {
+		difference := *report.Counted - report.Expected
+		report.Difference = &difference
+	}
+
+	return report, nil
+}
+
+// checkDrawerOpen allows changes to an open session from its own terminal;
+// admins may change it from anywhere.
+func checkDrawerOpen(ctx context.Context, session *models.DrawerSession) error {
+	if session.IsClosed() {
+		return ErrDrawerClosed
+	}
+	if staff, ok := ActorFromContext(ctx); ok && staff.Role == types.ADMIN {
+		return nil
+	}
+	if terminal, ok := TerminalFromContext(ctx); !ok || terminal.ID != session.TerminalID {
+		return ErrDrawerOtherTerminal
+	}
+	return nil
+}
+
+func drawerCounts(counts map[int]int) ([]models.DrawerCount, int, error) {
+	for denomination, quantity := range counts {
+		if !slices.Contains(YenDenominations, denomination) {
+			return nil, 0, ErrInvalidDenomination
+		}
+		if quantity < 0 {
+			return nil, 0, ErrInvalidAmount
+		}
+	}
+
+	var result []models.DrawerCount
+	total := 0
+	for _, denomination := range YenDenominations {
+		if quantity := counts[denomination]; quantity > 0 {
+			result = append(result, models.DrawerCount{Denomination: denomination, Quantity: quantity})
+			total += denomination * quantity
+		}
+	}
+	return result, total, nil
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type mockDrawerSessionRepository struct {
+	mu       sync.Mutex
+	sessions map[types.ID]*models.DrawerSession
+}
+
+func newMockDrawerSessionRepository() *mockDrawerSessionRepository {
+	return &mockDrawerSessionRepository{
+		sessions: make(map[types.ID]*models.DrawerSession),
+	}
+}
+
+func (r *mockDrawerSessionRepository) Create(ctx context.Context, session *models.DrawerSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if session.ID == "" {
+		session.ID = types.ID("drawer-" + string(rune('a'+len(r.sessions))))
+	}
+	stored := *session
+	r.sessions[session.ID] = &stored
+	return nil
+}
+
+func (r *mockDrawerSessionRepository) FindByID(ctx context.Context, id types.ID) (*models.DrawerSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, repositories.NewErrNotFound("DrawerSession", id)
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (r *mockDrawerSessionRepository) FindByIDForUpdate(ctx context.Context, id types.ID) (*models.DrawerSession, error) {
+	return r.FindByID(ctx, id)
+}
+
+func (r *mockDrawerSessionRepository) FindOpenByTerminal(ctx context.Context, terminalID types.ID) (*models.DrawerSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, session := range r.sessions {
+		if session.TerminalID == terminalID && !session.IsClosed() {
+			copied := *session
+			return &copied, nil
+		}
+	}
+	return nil, repositories.NewErrNotFound("DrawerSession", terminalID)
+}
+
+func (r *mockDrawerSessionRepository) FindByTerminal(ctx context.Context, terminalID types.ID) ([]models.DrawerSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sessions []models.DrawerSession
+	for _, session := range r.sessions {
+		if terminalID == "" || session.TerminalID == terminalID {
+			sessions = append(sessions, *session)
+		}
+	}
+	return sessions, nil
+}
+
+func (r *mockDrawerSessionRepository) AddMovement(ctx context.Context, movement *models.DrawerMovement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session := r.sessions[movement.DrawerSessionID]
+	session.Movements = append(session.Movements, *movement)
+	return nil
+}
+
+func (r *mockDrawerSessionRepository) Close(ctx context.Context, session *models.DrawerSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sessions[session.ID].IsClosed() {
+		return repositories.ErrConflict
+	}
+	stored := *session
+	r.sessions[session.ID] = &stored
+	return nil
+}
+
+type drawerTest struct {
+	service    DrawerService
+	orderRepo  *mockOrderRepository
+	refundRepo *mockRefundRepository
+	clock      *fakeClock
+	ctx        context.Context
+	terminal   *models.Terminal
+}
+
+func setupDrawerTest(t *testing.T) *drawerTest {
+	t.Helper()
+	orderRepo := newMockOrderRepository()
+	refundRepo := newMockRefundRepository()
+	clock := &fakeClock{now: time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)}
+	terminal := &models.Terminal{ID: "terminal-1"}
+	ctx := WithTerminal(WithActor(context.Background(), &models.Staff{ID: "cashier-1", Role: types.CASHIER}), terminal)
+
+	return &drawerTest{
+		service:    NewDrawerService(newMockDrawerSessionRepository(), orderRepo.payments, refundRepo, &mockTransactor{}, clock, newTestAuditLogService()),
+		orderRepo:  orderRepo,
+		refundRepo: refundRepo,
+		clock:      clock,
+		ctx:        ctx,
+		terminal:   terminal,
+	}
+}
+
+func (d *drawerTest) paidOrder(id types.ID, method types.PaymentMethod, amount int) {
+	paidAt := d.clock.Now()
+	d.orderRepo.Create(context.Background(), &models.Order{
+		ID:             id,
+		TicketNumber:   string(id),
+		Status:         types.CONFIRMED,
+		PaymentMethod:  method,
+		TotalAmount:    amount,
+		PaidAmount:     amount,
+		IsPaid:         true,
+		PaidAt:         &paidAt,
+		PaidTerminalID: &d.terminal.ID,
+	})
+	d.orderRepo.payments.Create(context.Background(), &models.Payment{
+		OrderID:     id,
+		Amount:      amount,
+		Method:      method,
+		Status:      types.PAYMENT_COMPLETED,
+		TerminalID:  &d.terminal.ID,
+		CompletedAt: &paidAt,
+	})
+}
+
+func TestDrawerService_Reconcile(t *testing.T) {
+	d := setupDrawerTest(t)
+
+	d.paidOrder("before-open", types.CASH, 700)
+	d.clock.now = d.clock.now.Add(time.Minute)
+
+	session, err := d.service.OpenSession(d.ctx, 10000)
+	if err != nil {
+		t.Fatalf("OpenSession failed: %v", err)
+	}
+
+	d.clock.now = d.clock.now.Add(time.Hour)
+	d.paidOrder("cash", types.CASH, 1500)
+	cash := d.orderRepo.payments.payments[len(d.orderRepo.payments.payments)-1]
+	cash.TenderedAmount, cash.ChangeAmount = 2000, 500
+	d.paidOrder("paypay", types.PAYPAY, 800)
+
+	if _, err := d.service.RecordMovement(d.ctx, session.ID, types.PAY_IN, 2000, "両替"); err != nil {
+		t.Fatalf("RecordMovement failed: %v", err)
+	}
+	if _, err := d.service.RecordMovement(d.ctx, session.ID, types.PAY_OUT, 500, "備品購入"); err != nil {
+		t.Fatalf("RecordMovement failed: %v", err)
+	}
+
+	current, err := d.service.GetCurrentSession(d.ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentSession failed: %v", err)
+	}
+	if current.Expected != 13000 || current.Counted != nil {
+		t.Errorf("Expected 13000 expected and nothing counted, got %d and %v", current.Expected, current.Counted)
+	}
+
+	d.clock.now = d.clock.now.Add(time.Hour)
+
+	// One 1000 yen note short.
+	report, err := d.service.CloseSession(d.ctx, session.ID, map[int]int{10000: 1, 1000: 2}, "")
+	if err != nil {
+		t.Fatalf("CloseSession failed: %v", err)
+	}
+	if report.CashSales != 1500 || report.PayIns != 2000 || report.PayOuts != 500 {
+		t.Errorf("Unexpected totals: sales %d, pay-ins %d, pay-outs %d", report.CashSales, report.PayIns, report.PayOuts)
+	}
+	if report.CashTendered != 2000 || report.ChangeGiven != 500 {
+		t.Errorf("Expected 2000 tendered and 500 change, got %d and %d", report.CashTendered, report.ChangeGiven)
+	}
+	if *report.Counted != 12000 || *report.Difference != -1000 {
+		t.Errorf("Expected 12000 counted and -1000 difference, got %d and %d", *report.Counted, *report.Difference)
+	}
+
+	// Payments after closing do not change the closed session, nor does a
+	// later correction to a payment it settled.
+	d.paidOrder("after-close", types.CASH, 300)
+	cash.TenderedAmount, cash.ChangeAmount = 5000, 3500
+	closed, err := d.service.GetSession(d.ctx, session.ID)
+	if err != nil {
+		t.Fatalf("GetSession failed: %v", err)
+	}
+	if closed.Expected != 13000 || *closed.Difference != -1000 || len(closed.Session.Counts) != 2 {
+		t.Errorf("Unexpected closed report: expected %d, difference %d, %d counts", closed.Expected, *closed.Difference, len(closed.Session.Counts))
+	}
+	if closed.CashTendered != 2000 || closed.ChangeGiven != 500 {
+		t.Errorf("Expected the settled 2000 tendered and 500 change, got %d and %d", closed.CashTendered, closed.ChangeGiven)
+	}
+}
+
+func TestDrawerService_CashRefunds(t *testing.T) {
+	d := setupDrawerTest(t)
+
+	session, err := d.service.OpenSession(d.ctx, 10000)
+	if err != nil {
+		t.Fatalf("OpenSession failed: %v", err)
+	}
+
+	d.clock.now = d.clock.now.Add(time.Hour)
+	d.paidOrder("cash", types.CASH, 1500)
+	d.refundRepo.Create(d.ctx, &models.Refund{OrderID: "cash", Amount: 500, Method: types.CASH, TerminalID: &d.terminal.ID, CreatedAt: d.clock.Now(),
+		Payments: []models.RefundPayment{{Method: types.CASH, Amount: 500}}})
+	d.refundRepo.Create(d.ctx, &models.Refund{OrderID: "paypay", Amount: 800, Method: types.PAYPAY, TerminalID: &d.terminal.ID, CreatedAt: d.clock.Now(),
+		Payments: []models.RefundPayment{{Method: types.PAYPAY, Amount: 800}}})
+
+	d.clock.now = d.clock.now.Add(time.Hour)
+	report, err := d.service.CloseSession(d.ctx, session.ID, map[int]int{10000: 1, 1000: 1}, "")
+	if err != nil {
+		t.Fatalf("CloseSession failed: %v", err)
+	}
+	if report.CashRefunds != 500 || report.Expected != 11000 || *report.Difference != 0 {
+		t.Errorf("Expected 500 refunded, 11000 expected and no difference, got %d, %d and %d", report.CashRefunds, report.Expected, *report.Difference)
+	}
+	if report.Session.CashRefunds == nil || *report.Session.CashRefunds != 500 {
+		t.Errorf("Expected the refunds to be settled on the session, got %v", report.Session.CashRefunds)
+	}
+}
+
+func TestDrawerService_OpenSession(t *testing.T) {
+	d := setupDrawerTest(t)
+
+	if _, err := d.service.OpenSession(d.ctx, 5000); err != nil {
+		t.Fatalf("OpenSession failed: %v", err)
+	}
+	if _, err := d.service.OpenSession(d.ctx, 5000); !errors.Is(err, ErrDrawerAlreadyOpen) {
+		t.Errorf("Expected ErrDrawerAlreadyOpen, got %v", err)
+	}
+
+	noTerminal := WithActor(context.Background(), &models.Staff{ID: "cashier-1", Role: types.CASHIER})
+	if _, err := d.service.OpenSession(noTerminal, 5000); !errors.Is(err, ErrTerminalRequired) {
+		t.Errorf("Expected ErrTerminalRequired, got %v", err)
+	}
+}
+
+func TestDrawerService_ClosedOrForeignSession(t *testing.T) {
+	d := setupDrawerTest(t)
+
+	session, err := d.service.OpenSession(d.ctx, 5000)
+	if err != nil {
+		t.Fatalf("OpenSession failed: %v", err)
+	}
+
+	other := WithTerminal(d.ctx, &models.Terminal{ID: "terminal-2"})
+	if _, err := d.service.RecordMovement(other, session.ID, types.PAY_IN, 100, ""); !errors.Is(err, ErrDrawerOtherTerminal) {
+		t.Errorf("Expected ErrDrawerOtherTerminal, got %v", err)
+	}
+	if _, err := d.service.CloseSession(d.ctx, session.ID, map[int]int{3000: 1}, ""); !errors.Is(err, ErrInvalidDenomination) {
+		t.Errorf("Expected ErrInvalidDenomination, got %v", err)
+	}
+
+	if _, err := d.service.CloseSession(d.ctx, session.ID, map[int]int{5000: 1}, ""); err != nil {
+		t.Fatalf("CloseSession failed: %v", err)
+	}
+	if _, err := d.service.CloseSession(d.ctx, session.ID, map[int]int{5000: 1}, ""); !errors.Is(err, ErrDrawerClosed) {
+		t.Errorf("Expected ErrDrawerClosed, got %v", err)
+	}
+	if _, err := d.service.RecordMovement(d.ctx, session.ID, types.PAY_OUT, 100, ""); !errors.Is(err, ErrDrawerClosed) {
+		t.Errorf("Expected ErrDrawerClosed, got %v", err)
+	}
+}
@@ -15,4 +15,59 @@ var (
 	ErrDeliveryNotAllowed    = &ServiceError{Message: "商品の受け渡しができません"}
 	ErrDuplicateInventory    = &ServiceError{Message: "指定された販売枠に既に商品が登録されています"}
 	ErrInvalidTimeRange      = &ServiceError{Message: "無効な時間範囲です"}
+	ErrInvalidExportFormat   = &ServiceError{Message: "無効な出力形式です"}
+	ErrDuplicateTicketNumber = &ServiceError{Message: "この整理券番号は既に使用されています"}
+	ErrInvalidCredentials    = &ServiceError{Message: "ユーザー名またはパスワードが正しくありません"}
+	ErrInvalidSession        = &ServiceError{Message: "ログインの有効期限が切れているか無効です"}
+	ErrDuplicateUsername     = &ServiceError{Message: "このユーザー名は既に使用されています"}
+	ErrPasswordTooShort      = &ServiceError{Message: "パスワードは8文字以上にしてください"}
+	ErrInvalidTerminalKey    = &ServiceError{Message: "端末のAPIキーが無効です"}
+	ErrTerminalRequired      = &ServiceError{Message: "この操作は登録済みの端末から行ってください"}
+	ErrDrawerAlreadyOpen     = &ServiceError{Message: "この端末のレジは既に開いています"}
+	ErrDrawerClosed          = &ServiceError{Message: "このレジは既に締められています"}
+	ErrDrawerOtherTerminal   = &ServiceError{Message: "他の端末のレジは操作できません"}
+	ErrInvalidAmount         = &ServiceError{Message: "金額が無効です"}
+	ErrInvalidDenomination   = &ServiceError{Message: "無効な金種です"}
+	ErrRefundNotAllowed      = &ServiceError{Message: "この注文は返金できません"}
+	ErrRefundExceedsOrder    = &ServiceError{Message: "返金する数量が注文の残りの数量を超えています"}
+	ErrRefundReasonRequired  = &ServiceError{Message: "返金理由を入力してください"}
+	ErrInvalidQuantity       = &ServiceError{Message: "数量が無効です"}
+	ErrCategoryNameRequired  = &ServiceError{Message: "カテゴリ名を入力してください"}
+	ErrInvalidOption         = &ServiceError{Message: "この商品では選択できないオプションです"}
+	ErrInvalidModifierGroup  = &ServiceError{Message: "オプションの設定が正しくありません"}
+	ErrInvalidCombo          = &ServiceError{Message: "セットの構成が正しくありません"}
+	ErrProductInCombo        = &ServiceError{Message: "セットに含まれている商品は削除できません"}
+	ErrComboInventory        = &ServiceError{Message: "セット商品の在庫は構成商品の在庫で管理されます"}
+	ErrInvalidTaxRate        = &ServiceError{Message: "税率は8%または10%を指定してください"}
+	ErrReceiptNotPaid        = &ServiceError{Message: "未払いの注文には領収書を発行できません"}
+	ErrReceiptIssuerMissing  = &ServiceError{Message: "領収書の発行者が設定されていません"}
+	ErrReceiptNotIssued      = &ServiceError{Message: "この注文の領収書はまだ発行されていません"}
+	ErrInvalidPrinter        = &ServiceError{Message: "プリンターの設定が正しくありません"}
+	ErrNoReceiptPrinter      = &ServiceError{Message: "使用できるレシートプリンターがありません"}
+	ErrPrintQueueFull        = &ServiceError{Message: "プリンターの印刷待ちが上限に達しています"}
+	ErrGatewayUnavailable    = &ServiceError{Message: "オンライン決済が設定されていません"}
+	ErrPaymentNotConfirmed   = &ServiceError{Message: "この支払い方法は決済サービスの確認後に支払い済みになります"}
+	ErrPaymentSourceRequired = &ServiceError{Message: "決済情報がありません"}
+	ErrAlreadyPaid           = &ServiceError{Message: "この注文は既に支払い済みです"}
+	ErrInvalidPaymentMethod  = &ServiceError{Message: "支払い方法が正しくありません"}
+	ErrInvalidPaymentAmount  = &ServiceError{Message: "支払額が正しくありません"}
+	ErrPaymentExceedsBalance = &ServiceError{Message: "支払額が注文の残額を超えています"}
+	ErrDuplicatePayment      = &ServiceError{Message: "この取引は既に記録されています"}
+	ErrOrderPartiallyPaid    = &ServiceError{Message: "支払いを受けた注文は商品の変更も取消もできません"}
+	ErrTenderNotCash         = &ServiceError{Message: "お預かり金額は現金払いにのみ指定できます"}
+	ErrInsufficientTender    = &ServiceError{Message: "お預かり金額が支払額に足りません"}
+	ErrInvalidSignature      = &ServiceError{Message: "Webhookの署名が正しくありません"}
+	ErrQRCodeNotActive       = &ServiceError{Message: "このQRコードは既に無効です"}
+	ErrInvalidDiscount       = &ServiceError{Message: "割引の設定が正しくありません"}
+	ErrDuplicateDiscountCode = &ServiceError{Message: "このクーポンコードは既に使用されています"}
+	ErrInvalidCoupon         = &ServiceError{Message: "クーポンコードが無効です"}
+	ErrDiscountUnavailable   = &ServiceError{Message: "この割引は現在利用できません"}
+	ErrDiscountUsedUp        = &ServiceError{Message: "この割引は利用上限に達しています"}
+	ErrDuplicateDiscount     = &ServiceError{Message: "この割引は既に適用されています"}
+	ErrDiscountNotApplicable = &ServiceError{Message: "この注文には適用できない割引です"}
+	ErrDiscountNotAllowed    = &ServiceError{Message: "支払いを受けた注文または確定済みの注文の割引は変更できません"}
+	ErrPriceReasonRequired   = &ServiceError{Message: "価格変更の理由を入力してください"}
+	ErrLastOrderItem         = &ServiceError{Message: "注文の最後の商品は削除できません。注文を取り消してください"}
 )
+
+const ReservationExpiredReason = "予約の有効期限が切れたため自動的にキャンセルされました"
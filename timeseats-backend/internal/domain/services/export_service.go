@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// RowWriter receives the rows of an export. Values are strings, ints, bools,
+// time.Time or nil for an empty cell.
+type RowWriter interface {
+	WriteRow(values []any) error
+}
+
+// ExportWriter writes the rows of an export as a file. Close finishes the
+// file without closing the underlying writer.
+type ExportWriter interface {
+	RowWriter
+	Close() error
+}
+
+// ExportFormat is a file format exports can be written in, such as CSV.
+type ExportFormat struct {
+	ContentType string
+	NewWriter   func(w io.Writer) (ExportWriter, error)
+}
+
+type ExportService interface {
+	// Format returns the export format with the name, such as "csv", or
+	// ErrInvalidExportFormat.
+	Format(name string) (*ExportFormat, error)
+	// ExportOrders writes one row per order item, repeating the order
+	// columns; an order without items gets a single row. Split tenders are
+	// broken down into a column per payment method.
+	ExportOrders(ctx context.Context, filter repositories.OrderFilter, w RowWriter) error
+	// ExportInventory writes the current inventory of a sales slot, or of all
+	// slots when salesSlotID is empty.
+	ExportInventory(ctx context.Context, salesSlotID types.ID, w RowWriter) error
+}
+
+type exportService struct {
+	orderRepo repositories.OrderRepository
+	invRepo   repositories.ProductInventoryRepository
+	formats   map[string]ExportFormat
+}
+
+func NewExportService(
+	orderRepo repositories.OrderRepository,
+	invRepo repositories.ProductInventoryRepository,
+	formats map[string]ExportFormat,
+) ExportService {
+	return &exportService{
+		orderRepo: orderRepo,
+		invRepo:   invRepo,
+		formats:   formats,
+	}
+}
+
+func (s *exportService) Format(name string) (*ExportFormat, error) {
+	format, ok := s.formats[name]
+	if !ok {
+		return nil, ErrInvalidExportFormat
+	}
+	return &format, nil
+}
+
+var orderExportHeader = []any{
+	"Order ID", "Sales Slot ID", "Ticket Number", "Status", "Payment Method", "Transaction ID", "Paid",
+	"Paid Amount", "Cash Paid", "PayPay Paid", "Square Paid", "Payment References", "Total Amount", "Discount Amount", "Refunded Amount", "Created At", "Confirmed At", "Paid At", "Delivered At", "Cancelled At",
+	"Cancel Reason", "Product ID", "Product Name", "Quantity", "Refunded Quantity", "Unit Price", "Subtotal",
+	"Tax Rate", "Item Discount", "Net Amount",
+}
+
+func (s *exportService) ExportOrders(ctx context.Context, filter repositories.OrderFilter, w RowWriter) error {
+	if err := w.WriteRow(orderExportHeader); err != nil {
+		return err
+	}
+
+	return s.orderRepo.StreamByFilter(ctx, filter, func(order *models.Order) error {
+		paid, references := tenders(order)
+		columns := []any{
+			string(order.ID),
+			string(order.SalesSlotID),
+			order.TicketNumber,
+			order.Status.String(),
+			order.PaymentMethod.String(),
+			optional(order.TransactionID),
+			order.IsPaid,
+			order.PaidAmount,
+			paid[types.CASH],
+			paid[types.PAYPAY],
+			paid[types.SQUARE],
+			references,
+			order.TotalAmount,
+			order.DiscountAmount,
+			order.RefundedAmount,
+			order.CreatedAt,
+			optional(order.ConfirmedAt),
+			optional(order.PaidAt),
+			optional(order.DeliveredAt),
+			optional(order.CancelledAt),
+			optional(order.CancelReason),
+		}
+
+		if len(order.Items) == 0 {
+			return w.WriteRow(columns)
+		}
+		for _, item := range order.Items {
+			productName := ""
+			if item.Product != nil {
+				productName = item.Product.Name
+			}
+			row := append(columns[:len(columns):len(columns)],
+				string(item.ProductID), productName, item.Quantity, item.RefundedQuantity, item.Price, item.GetSubtotal(),
+				int(item.TaxRate), item.DiscountAmount, item.NetAmount())
+			if err := w.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+var inventoryExportHeader = []any{
+	"Sales Slot ID", "Slot Start", "Slot End", "Product ID", "Product Name",
+	"Initial Quantity", "Reserved Quantity", "Sold Quantity", "Available Quantity",
+}
+
+func (s *exportService) ExportInventory(ctx context.Context, salesSlotID types.ID, w RowWriter) error {
+	var inventories []models.ProductInventory
+	var err error
+	if salesSlotID != "" {
+		inventories, err = s.invRepo.FindBySalesSlotID(ctx, salesSlotID)
+	} else {
+		inventories, err = s.invRepo.FindAll(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := w.WriteRow(inventoryExportHeader); err != nil {
+		return err
+	}
+	for _, inv := range inventories {
+		var slotStart, slotEnd any
+		if inv.SalesSlot != nil {
+			slotStart, slotEnd = inv.SalesSlot.StartTime, inv.SalesSlot.EndTime
+		}
+		productName := ""
+		if inv.Product != nil {
+			productName = inv.Product.Name
+		}
+		row := []any{
+			string(inv.SalesSlotID), slotStart, slotEnd, string(inv.ProductID), productName,
+			inv.InitialQuantity, inv.ReservedQuantity, inv.SoldQuantity, inv.GetAvailableQuantity(),
+		}
+		if err := w.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tenders adds up the order's completed payments by method and lists their
+// references, separated by spaces.
+func tenders(order *models.Order) (map[types.PaymentMethod]int, string) {
+	paid := make(map[types.PaymentMethod]int)
+	var references []string
+	for _, p := range order.Payments {
+		if !p.IsCompleted() {
+			continue
+		}
+		paid[p.Method] += p.Amount
+		if p.Reference != nil && *p.Reference != "" {
+			references = append(references, *p.Reference)
+		}
+	}
+	return paid, strings.Join(references, " ")
+}
+
+// optional returns the pointed-to value, or nil for an empty cell.
+func optional[T any](v *T) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type recordingRowWriter struct {
+	rows [][]any
+}
+
+func (w *recordingRowWriter) WriteRow(values []any) error {
+	w.rows = append(w.rows, append([]any(nil), values...))
+	return nil
+}
+
+func TestExportService_ExportOrders(t *testing.T) {
+	orderRepo := newMockOrderRepository()
+	transactionID := "tx-1"
+	orderRepo.Create(context.Background(), &models.Order{
+		ID:            "order-1",
+		SalesSlotID:   "slot-1",
+		TicketNumber:  "A001",
+		Status:        types.CONFIRMED,
+		PaymentMethod: types.PAYPAY,
+		TransactionID: &transactionID,
+		IsPaid:        true,
+		TotalAmount:   1100,
+		PaidAmount:    1100,
+		CreatedAt:     time.Now(),
+		Payments: []models.Payment{
+			{Method: types.CASH, Amount: 600, Status: types.PAYMENT_COMPLETED},
+			{Method: types.SQUARE, Amount: 500, Status: types.PAYMENT_FAILED},
+			{Method: types.PAYPAY, Amount: 500, Reference: &transactionID, Status: types.PAYMENT_COMPLETED},
+		},
+		Items: []models.OrderItem{
+			{ProductID: "product-1", Quantity: 2, Price: 300, Product: &models.Product{Name: "焼きそば"}},
+			{ProductID: "product-2", Quantity: 1, Price: 500, Product: &models.Product{Name: "たこ焼き"}},
+		},
+	})
+	orderRepo.Create(context.Background(), &models.Order{
+		ID:           "order-2",
+		SalesSlotID:  "slot-1",
+		TicketNumber: "A002",
+		Status:       types.CANCELLED,
+		CreatedAt:    time.Now(),
+	})
+
+	service := NewExportService(orderRepo, newMockInventoryRepository(), nil)
+	w := &recordingRowWriter{}
+	if err := service.ExportOrders(context.Background(), repositories.OrderFilter{}, w); err != nil {
+		t.Fatalf("ExportOrders failed: %v", err)
+	}
+
+	if len(w.rows) != 4 {
+		t.Fatalf("Expected a header and 3 rows, got %d rows", len(w.rows))
+	}
+	if len(w.rows[0]) != len(w.rows[1]) || len(w.rows[1]) != len(w.rows[3])+9 {
+		t.Errorf("Unexpected column counts: header %d, item row %d, order row %d", len(w.rows[0]), len(w.rows[1]), len(w.rows[3]))
+	}
+	if w.rows[1][5] != transactionID || w.rows[2][22] != "たこ焼き" || w.rows[2][26] != 500 {
+		t.Errorf("Unexpected item row: %v", w.rows[2])
+	}
+	// Split between cash and PayPay; the failed card payment is left out.
+	if w.rows[1][7] != 1100 || w.rows[1][8] != 600 || w.rows[1][9] != 500 || w.rows[1][10] != 0 || w.rows[1][11] != transactionID {
+		t.Errorf("Unexpected tender columns: %v", w.rows[1][7:12])
+	}
+	if w.rows[3][2] != "A002" || w.rows[3][5] != nil {
+		t.Errorf("Unexpected row for an order without items: %v", w.rows[3])
+	}
+}
+
+func TestExportService_ExportOrdersByStatus(t *testing.T) {
+	orderRepo := newMockOrderRepository()
+	orderRepo.Create(context.Background(), &models.Order{ID: "order-1", TicketNumber: "A001", Status: types.CONFIRMED})
+	orderRepo.Create(context.Background(), &models.Order{ID: "order-2", TicketNumber: "A002", Status: types.CANCELLED})
+
+	service := NewExportService(orderRepo, newMockInventoryRepository(), nil)
+	w := &recordingRowWriter{}
+	filter := repositories.OrderFilter{Status: types.CANCELLED}
+	if err := service.ExportOrders(context.Background(), filter, w); err != nil {
+		t.Fatalf("ExportOrders failed: %v", err)
+	}
+
+	if len(w.rows) != 2 || w.rows[1][0] != "order-2" {
+		t.Errorf("Expected only the cancelled order, got %v", w.rows)
+	}
+}
+
+func TestExportService_ExportInventory(t *testing.T) {
+	invRepo := newMockInventoryRepository()
+	invRepo.Create(context.Background(), &models.ProductInventory{
+		ID:               "inv-1",
+		SalesSlotID:      "slot-1",
+		ProductID:        "product-1",
+		InitialQuantity:  10,
+		ReservedQuantity: 2,
+		SoldQuantity:     5,
+	})
+
+	service := NewExportService(newMockOrderRepository(), invRepo, nil)
+	w := &recordingRowWriter{}
+	if err := service.ExportInventory(context.Background(), "slot-1", w); err != nil {
+		t.Fatalf("ExportInventory failed: %v", err)
+	}
+
+	if len(w.rows) != 2 {
+		t.Fatalf("Expected a header and 1 row, got %d rows", len(w.rows))
+	}
+	if available := w.rows[1][8]; available != 3 {
+		t.Errorf("Expected 3 available, got %v", available)
+	}
+}
+
+func TestExportService_Format(t *testing.T) {
+	service := NewExportService(newMockOrderRepository(), newMockInventoryRepository(), map[string]ExportFormat{
+		"csv": {ContentType: "text/csv; charset=utf-8"},
+	})
+
+	if format, err := service.Format("csv"); err != nil || format.ContentType != "text/csv; charset=utf-8" {
+		t.Errorf("Expected the CSV format, got %+v, %v", format, err)
+	}
+	if _, err := service.Format("pdf"); !errors.Is(err, ErrInvalidExportFormat) {
+		t.Errorf("Expected ErrInvalidExportFormat, got %v", err)
+	}
+}
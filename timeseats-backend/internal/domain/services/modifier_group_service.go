@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+)
+
+type ModifierGroupService interface {
+	CreateModifierGroup(ctx context.Context, productID types.ID, input ModifierGroupInput) (*models.ModifierGroup, error)
+	GetProductModifierGroups(ctx context.Context, productID types.ID) ([]models.ModifierGroup, error)
+	// UpdateModifierGroup replaces the group's settings and options. Options
+	// are matched by ID; those without one are added and those left out are
+	// removed.
+	UpdateModifierGroup(ctx context.Context, id types.ID, input ModifierGroupInput) (*models.ModifierGroup, error)
+	DeleteModifierGroup(ctx context.Context, id types.ID) error
+}
+
+// ModifierGroupInput describes a group and its options. A MaxSelect of 0
+// allows any number of options.
+type ModifierGroupInput struct {
+	Name      string
+	MinSelect int
+	MaxSelect int
+	SortOrder int
+	Options   []ModifierOptionInput
+}
+
+type ModifierOptionInput struct {
+	ID         types.ID
+	Name       string
+	PriceDelta int
+	SortOrder  int
+}
+
+type modifierGroupService struct {
+	groupRepo   repositories.ModifierGroupRepository
+	productRepo repositories.ProductRepository
+	transactor  repositories.Transactor
+	audit       AuditLogService
+}
+
+func NewModifierGroupService(
+	groupRepo repositories.ModifierGroupRepository,
+	productRepo repositories.ProductRepository,
+	transactor repositories.Transactor,
+	audit AuditLogService,
+) ModifierGroupService {
+	return &modifierGroupService{
+		groupRepo:   groupRepo,
+		productRepo: productRepo,
+		transactor:  transactor,
+		audit:       audit,
+	}
+}
+
+func (s *modifierGroupService) CreateModifierGroup(ctx context.Context, productID types.ID, input ModifierGroupInput) (*models.ModifierGroup, error) {
+	if err := validateModifierGroup(input); err != nil {
+		return nil, err
+	}
+
+	group := &models.ModifierGroup{
+		ID:        types.ID(uuid.New().String()),
+		ProductID: productID,
+	}
+	applyModifierGroupInput(group, input)
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.productRepo.FindByID(ctx, productID); err != nil {
+			return err
+		}
+		if err := s.groupRepo.Create(ctx, group); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityModifierGroup, group.ID, "create", nil, group)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (s *modifierGroupService) GetProductModifierGroups(ctx context.Context, productID types.ID) ([]models.ModifierGroup, error) {
+	if _, err := s.productRepo.FindByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.groupRepo.FindByProductID(ctx, productID)
+}
+
+func (s *modifierGroupService) UpdateModifierGroup(ctx context.Context, id types.ID, input ModifierGroupInput) (*models.ModifierGroup, error) {
+	if err := validateModifierGroup(input); err != nil {
+		return nil, err
+	}
+
+	var group *models.ModifierGroup
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.groupRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		for _, option := range input.Options {
+			if option.ID != "" && findModifierOption(before.Options, option.ID) == nil {
+				return ErrInvalidModifierGroup
+			}
+		}
+
+		updated := *before
+		applyModifierGroupInput(&updated, input)
+		if err := s.groupRepo.Update(ctx, &updated); err != nil {
+			return err
+		}
+		group = &updated
+
+		return s.audit.Record(ctx, AuditEntityModifierGroup, id, "update", before, group)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (s *modifierGroupService) DeleteModifierGroup(ctx context.Context, id types.ID) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.groupRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.groupRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityModifierGroup, id, "delete", before, nil)
+	})
+}
+
+// validateModifierGroup checks that the group has named options and that a
+// choice within its limits is possible.
+func validateModifierGroup(input ModifierGroupInput) error {
+	if input.Name == "" || len(input.Options) == 0 {
+		return ErrInvalidModifierGroup
+	}
+	if input.MinSelect < 0 || input.MaxSelect < 0 || input.MinSelect > len(input.Options) {
+		return ErrInvalidModifierGroup
+	}
+	if input.MaxSelect > 0 && input.MaxSelect < input.MinSelect {
+		return ErrInvalidModifierGroup
+	}
+	for _, option := range input.Options {
+		if option.Name == "" {
+			return ErrInvalidModifierGroup
+		}
+	}
+	return nil
+}
+
+// applyModifierGroupInput sets the group's fields and options from input,
+// keeping the stored fields of options that already exist.
+func applyModifierGroupInput(group *models.ModifierGroup, input ModifierGroupInput) {
+	options := make([]models.ModifierOption, len(input.Options))
+	for i, in := range input.Options {
+		option := models.ModifierOption{GroupID: group.ID}
+		if existing := findModifierOption(group.Options, in.ID); existing != nil {
+			option = *existing
+		}
+		option.Name = in.Name
+		option.PriceDelta = in.PriceDelta
+		option.SortOrder = in.SortOrder
+		options[i] = option
+	}
+
+	group.Name = input.Name
+	group.MinSelect = input.MinSelect
+	group.MaxSelect = input.MaxSelect
+	group.SortOrder = input.SortOrder
+	group.Options = options
+}
+
+func findModifierOption(options []models.ModifierOption, id types.ID) *models.ModifierOption {
+	if id == "" {
+		return nil
+	}
+	for i := range options {
+		if options[i].ID == id {
+			return &options[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+)
+
+type mockModifierGroupRepository struct {
+	groups map[types.ID]*models.ModifierGroup
+}
+
+func newMockModifierGroupRepository() *mockModifierGroupRepository {
+	return &mockModifierGroupRepository{
+		groups: make(map[types.ID]*models.ModifierGroup),
+	}
+}
+
+func (r *mockModifierGroupRepository) Create(ctx context.Context, group *models.ModifierGroup) error {
+	return r.Update(ctx, group)
+}
+
+func (r *mockModifierGroupRepository) FindByID(ctx context.Context, id types.ID) (*models.ModifierGroup, error) {
+	if group, exists := r.groups[id]; exists {
+		copied := *group
+		return &copied, nil
+	}
+	return nil, repositories.NewErrNotFound("ModifierGroup", id)
+}
+
+func (r *mockModifierGroupRepository) FindAll(ctx context.Context) ([]models.ModifierGroup, error) {
+	var groups []models.ModifierGroup
+	for _, g := range r.groups {
+		groups = append(groups, *g)
+	}
+	return groups, nil
+}
+
+func (r *mockModifierGroupRepository) FindByProductID(ctx context.Context, productID types.ID) ([]models.ModifierGroup, error) {
+	var groups []models.ModifierGroup
+	for _, g := range r.groups {
+		if g.ProductID == productID {
+			groups = append(groups, *g)
+		}
+	}
+	return groups, nil
+}
+
+func (r *mockModifierGroupRepository) Update(ctx context.Context, group *models.ModifierGroup) error {
+	for i := range group.Options {
+		if group.Options[i].ID == "" {
+			group.Options[i].ID = types.ID(uuid.New().String())
+		}
+	}
+	r.groups[group.ID] = group
+	return nil
+}
+
+func (r *mockModifierGroupRepository) Delete(ctx context.Context, id types.ID) error {
+	if _, exists := r.groups[id]; !exists {
+		return repositories.NewErrNotFound("ModifierGroup", id)
+	}
+	delete(r.groups, id)
+	return nil
+}
+
+func TestModifierGroupService_CreateAndUpdate(t *testing.T) {
+	productRepo := newMockProductRepository()
+	service := NewModifierGroupService(newMockModifierGroupRepository(), productRepo, &mockTransactor{}, newTestAuditLogService())
+	ctx := context.Background()
+
+	product := &models.Product{ID: "prod1", Name: "ドリンク", Price: 200}
+	productRepo.Create(ctx, product)
+
+	input := ModifierGroupInput{
+		Name:      "サイズ",
+		MinSelect: 1,
+		MaxSelect: 1,
+		Options: []ModifierOptionInput{
+			{Name: "M"},
+			{Name: "L", PriceDelta: 50},
+		},
+	}
+	if _, err := service.CreateModifierGroup(ctx, "missing", input); err == nil {
+		t.Error("Expected an error for an unknown product")
+	}
+	group, err := service.CreateModifierGroup(ctx, product.ID, input)
+	if err != nil {
+		t.Fatalf("CreateModifierGroup failed: %v", err)
+	}
+	if len(group.Options) != 2 || group.Options[0].ID == "" {
+		t.Fatalf("Expected 2 options with IDs, got %+v", group.Options)
+	}
+
+	large := group.Options[1]
+	updated, err := service.UpdateModifierGroup(ctx, group.ID, ModifierGroupInput{
+		Name:      "サイズ",
+		MinSelect: 1,
+		MaxSelect: 1,
+		Options: []ModifierOptionInput{
+			{ID: large.ID, Name: "L", PriceDelta: 80},
+			{Name: "S", PriceDelta: -30},
+		},
+	})
+	if err != nil {
+		t.Fatalf("UpdateModifierGroup failed: %v", err)
+	}
+	if len(updated.Options) != 2 || updated.Options[0].ID != large.ID || updated.Options[0].PriceDelta != 80 {
+		t.Errorf("Expected L to be kept at 80 and M to be replaced by S, got %+v", updated.Options)
+	}
+
+	_, err = service.UpdateModifierGroup(ctx, group.ID, ModifierGroupInput{
+		Name:    "サイズ",
+		Options: []ModifierOptionInput{{ID: "other", Name: "XL"}},
+	})
+	if !errors.Is(err, ErrInvalidModifierGroup) {
+		t.Errorf("Expected ErrInvalidModifierGroup for an option of another group, got %v", err)
+	}
+}
+
+func TestModifierGroupService_Validation(t *testing.T) {
+	productRepo := newMockProductRepository()
+	service := NewModifierGroupService(newMockModifierGroupRepository(), productRepo, &mockTransactor{}, newTestAuditLogService())
+	ctx := context.Background()
+	productRepo.Create(ctx, &models.Product{ID: "prod1", Name: "焼きそば", Price: 400})
+
+	option := []ModifierOptionInput{{Name: "チーズ", PriceDelta: 50}}
+	invalid := []ModifierGroupInput{
+		{Name: "", Options: option},
+		{Name: "トッピング"},
+		{Name: "トッピング", MinSelect: 2, Options: option},
+		{Name: "トッピング", MinSelect: 1, MaxSelect: -1, Options: option},
+		{Name: "トッピング", Options: []ModifierOptionInput{{Name: ""}}},
+	}
+	for _, input := range invalid {
+		if _, err := service.CreateModifierGroup(ctx, "prod1", input); !errors.Is(err, ErrInvalidModifierGroup) {
+			t.Errorf("Expected ErrInvalidModifierGroup for %+v, got %v", input, err)
+		}
+	}
+}
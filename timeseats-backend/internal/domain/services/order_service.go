@@ -2,7 +2,13 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
@@ -11,244 +17,671 @@ import (
 type OrderService interface {
 	CreateOrder(ctx context.Context, salesSlotID types.ID, items []OrderItemInput, ticketNumber string, paymentMethod types.PaymentMethod) (*models.Order, error)
 	GetOrder(ctx context.Context, id types.ID) (*models.Order, error)
-	GetAllOrders(ctx context.Context) ([]models.Order, error)
+	GetAllOrders(ctx context.Context, filter repositories.OrderFilter) ([]models.Order, error)
 	GetOrdersByStatus(ctx context.Context, status types.OrderStatus) ([]models.Order, error)
 	UpdateOrderStatus(ctx context.Context, id types.ID, status types.OrderStatus) error
-	CancelOrder(ctx context.Context, id types.ID) error
+	CancelOrder(ctx context.Context, id types.ID, reason string) error
+	CancelExpiredReservations(ctx context.Context, createdBefore time.Time) (int, error)
 	AddOrderItems(ctx context.Context, orderID types.ID, items []OrderItemInput) error
-	GetOrderByTicketNumber(ctx context.Context, ticketNumber string) (*models.Order, error)
+	UpdateOrderItem(ctx context.Context, orderID, itemID types.ID, quantity int) error
+	RemoveOrderItem(ctx context.Context, orderID, itemID types.ID) error
+	GetOrderByTicketNumber(ctx context.Context, salesSlotID types.ID, ticketNumber string) (*models.Order, error)
+	// UpdatePaymentStatus pays the order's balance due in its payment
+	// method.
 	UpdatePaymentStatus(ctx context.Context, id types.ID, transactionID string) error
 	UpdateDeliveryStatus(ctx context.Context, id types.ID) error
+	GetKitchenQueue(ctx context.Context, salesSlotID types.ID) ([]models.Order, error)
 }
 
 type OrderItemInput struct {
 	ProductID types.ID
 	Quantity  int
+	// OptionIDs are the modifier options chosen for every unit of the line.
+	OptionIDs []types.ID
 }
 
 type orderService struct {
 	orderRepo   repositories.OrderRepository
+	paymentRepo repositories.PaymentRepository
 	slotRepo    repositories.SalesSlotRepository
 	invRepo     repositories.ProductInventoryRepository
 	productRepo repositories.ProductRepository
+	transactor  repositories.Transactor
+	publisher   events.Publisher
+	clock       Clock
+	tickets     TicketNumberGenerator
+	audit       AuditLogService
 }
 
 func NewOrderService(
 	orderRepo repositories.OrderRepository,
+	paymentRepo repositories.PaymentRepository,
 	slotRepo repositories.SalesSlotRepository,
 	invRepo repositories.ProductInventoryRepository,
 	productRepo repositories.ProductRepository,
+	transactor repositories.Transactor,
+	publisher events.Publisher,
+	clock Clock,
+	tickets TicketNumberGenerator,
+	audit AuditLogService,
 ) OrderService {
 	return &orderService{
 		orderRepo:   orderRepo,
+		paymentRepo: paymentRepo,
 		slotRepo:    slotRepo,
 		invRepo:     invRepo,
 		productRepo: productRepo,
+		transactor:  transactor,
+		publisher:   publisher,
+		clock:       clock,
+		tickets:     tickets,
+		audit:       audit,
 	}
 }
 
+// CreateOrder generates a ticket number for the slot when ticketNumber is
+// empty; otherwise the given number, e.g. from a pre-printed paper ticket,
+// must not be in use in the slot yet.
 func (s *orderService) CreateOrder(ctx context.Context, salesSlotID types.ID, items []OrderItemInput, ticketNumber string, paymentMethod types.PaymentMethod) (*models.Order, error) {
-	slot, err := s.slotRepo.FindByID(ctx, salesSlotID)
+	var order *models.Order
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		slot, err := s.slotRepo.FindByID(ctx, salesSlotID)
+		if err != nil {
+			return err
+		}
+		if !slot.IsActive {
+			return &ServiceError{Message: "販売枠がアクティブではありません"}
+		}
+
+		if ticketNumber != "" {
+			exists, err := s.orderRepo.TicketNumberExists(ctx, salesSlotID, ticketNumber)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return ErrDuplicateTicketNumber
+			}
+		}
+
+		orderItems, totalAmount, err := s.reserveItems(ctx, salesSlotID, items)
+		if err != nil {
+			return err
+		}
+
+		// Generated last so the sequence row is locked only briefly.
+		if ticketNumber == "" {
+			if ticketNumber, err = s.tickets.Next(ctx, slot); err != nil {
+				return err
+			}
+		}
+
+		createdByID, terminalID := actorIDs(ctx)
+		order = &models.Order{
+			SalesSlotID:   salesSlotID,
+			Status:        types.RESERVED,
+			TotalAmount:   totalAmount,
+			TicketNumber:  ticketNumber,
+			PaymentMethod: paymentMethod,
+			IsPaid:        false,
+			IsDelivered:   false,
+			TerminalID:    terminalID,
+			CreatedByID:   createdByID,
+		}
+
+		// An order taking the same number concurrently gets past the check
+		// above but not the unique index.
+		err = s.orderRepo.CreateWithItems(ctx, order, orderItems)
+		if errors.Is(err, repositories.ErrDuplicate) {
+			return ErrDuplicateTicketNumber
+		}
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityOrder, order.ID, "create", nil, order)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if !slot.IsActive {
-		return nil, &ServiceError{Message: "販売枠がアクティブではありません"}
-	}
 
+	s.publisher.Publish(events.NewOrderEvent(events.OrderCreated, order))
+	s.publishInventories(ctx, order.SalesSlotID, order.Items)
+
+	return order, nil
+}
+
+// reserveItems prices items and reserves their inventory in the given slot,
+// merging repeated products with the same options into one line. It must run
+// inside a transaction so a failure releases earlier reservations.
+func (s *orderService) reserveItems(ctx context.Context, salesSlotID types.ID, items []OrderItemInput) ([]models.OrderItem, int, error) {
 	var orderItems []models.OrderItem
 	totalAmount := 0
 
-	for _, item := range items {
+	for _, item := range mergeItemInputs(items) {
+		if item.Quantity <= 0 {
+			return nil, 0, ErrInvalidQuantity
+		}
+
 		product, err := s.productRepo.FindByID(ctx, item.ProductID)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
-		inventory, err := s.invRepo.FindBySalesSlotAndProduct(ctx, salesSlotID, item.ProductID)
+		options, optionsAmount, err := chooseOptions(product, item.OptionIDs)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
-		if inventory.GetAvailableQuantity() < item.Quantity {
-			return nil, ErrInsufficientInventory
+		price := product.Price + optionsAmount
+		orderItem := models.OrderItem{
+			ProductID:     item.ProductID,
+			Quantity:      item.Quantity,
+			Price:         price,
+			OptionsAmount: optionsAmount,
+			TaxRate:       product.TaxRate,
+			Options:       options,
+		}
+		for _, component := range product.Components {
+			orderItem.Components = append(orderItem.Components, models.OrderItemComponent{
+				ProductID: component.ProductID,
+				Quantity:  component.Quantity,
+			})
 		}
 
-		orderItems = append(orderItems, models.OrderItem{
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     product.Price,
+		if err := s.adjustStock(ctx, salesSlotID, &orderItem, item.Quantity, 0); err != nil {
+			return nil, 0, err
+		}
+
+		orderItems = append(orderItems, orderItem)
+		totalAmount += price * item.Quantity
+	}
+
+	return orderItems, totalAmount, nil
+}
+
+// chooseOptions checks the chosen options against the product's modifier
+// groups and returns them as order item options with the sum of their price
+// deltas.
+func chooseOptions(product *models.Product, optionIDs []types.ID) ([]models.OrderItemOption, int, error) {
+	var options []models.OrderItemOption
+	amount := 0
+	picked := make(map[types.ID]int, len(product.ModifierGroups))
+	seen := make(map[types.ID]bool, len(optionIDs))
+
+	for _, id := range optionIDs {
+		group, option := product.FindOption(id)
+		if option == nil || seen[id] {
+			return nil, 0, ErrInvalidOption
+		}
+		seen[id] = true
+		picked[group.ID]++
+
+		options = append(options, models.OrderItemOption{
+			ModifierOptionID: option.ID,
+			GroupName:        group.Name,
+			Name:             option.Name,
+			PriceDelta:       option.PriceDelta,
 		})
+		amount += option.PriceDelta
+	}
 
-		totalAmount += product.Price * item.Quantity
+	for _, group := range product.ModifierGroups {
+		count := picked[group.ID]
+		if count < group.MinSelect || (group.MaxSelect > 0 && count > group.MaxSelect) {
+			return nil, 0, &ServiceError{Message: fmt.Sprintf("「%s」の%sの選択数が正しくありません", product.Name, group.Name)}
+		}
 	}
 
-	order := &models.Order{
-		SalesSlotID:   salesSlotID,
-		Status:        types.RESERVED,
-		TotalAmount:   totalAmount,
-		TicketNumber:  ticketNumber,
-		PaymentMethod: paymentMethod,
-		IsPaid:        false,
-		IsDelivered:   false,
+	return options, amount, nil
+}
+
+// mergeItemInputs adds up the quantities of repeated products with the same
+// options, keeping the order in which they first appear.
+func mergeItemInputs(items []OrderItemInput) []OrderItemInput {
+	merged := make([]OrderItemInput, 0, len(items))
+	index := make(map[string]int, len(items))
+	for _, item := range items {
+		key := lineKey(item.ProductID, item.OptionIDs)
+		if i, ok := index[key]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, item)
 	}
+	return merged
+}
 
-	err = s.orderRepo.CreateWithItems(ctx, order, orderItems)
-	if err != nil {
-		return nil, err
+// lineKey identifies a product with a set of options regardless of the order
+// in which the options were chosen.
+func lineKey(productID types.ID, optionIDs []types.ID) string {
+	ids := make([]string, len(optionIDs))
+	for i, id := range optionIDs {
+		ids[i] = string(id)
 	}
+	sort.Strings(ids)
+	return string(productID) + "|" + strings.Join(ids, ",")
+}
 
-	for _, item := range orderItems {
-		inventory, _ := s.invRepo.FindBySalesSlotAndProduct(ctx, salesSlotID, item.ProductID)
-		err = s.invRepo.UpdateQuantities(ctx, inventory.ID, inventory.ReservedQuantity+item.Quantity, inventory.SoldQuantity)
+// adjustStock moves the inventory of every product the line draws from by
+// the given numbers of line units, so a combo moves each of its components.
+func (s *orderService) adjustStock(ctx context.Context, salesSlotID types.ID, item *models.OrderItem, reservedUnits, soldUnits int) error {
+	for _, unit := range item.StockUnits() {
+		inventory, err := s.invRepo.FindBySalesSlotAndProduct(ctx, salesSlotID, unit.ProductID)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if err := s.adjustInventory(ctx, inventory.ID, reservedUnits*unit.Quantity, soldUnits*unit.Quantity); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	return order, nil
+func (s *orderService) adjustInventory(ctx context.Context, inventoryID types.ID, reservedDelta, soldDelta int) error {
+	err := s.invRepo.AdjustQuantities(ctx, inventoryID, reservedDelta, soldDelta)
+	if errors.Is(err, repositories.ErrInsufficientQuantity) {
+		return ErrInsufficientInventory
+	}
+	return err
+}
+
+// publishOrder announces the committed state of an order, and optionally of
+// the inventory rows its items draw from.
+func (s *orderService) publishOrder(ctx context.Context, eventType events.Type, id types.ID, withInventory bool) {
+	order, err := s.orderRepo.FindByID(ctx, id)
+	if err != nil {
+		return
+	}
+
+	s.publisher.Publish(events.NewOrderEvent(eventType, order))
+	if withInventory {
+		s.publishInventories(ctx, order.SalesSlotID, order.Items)
+	}
+}
+
+func (s *orderService) publishInventories(ctx context.Context, salesSlotID types.ID, items []models.OrderItem) {
+	publishInventories(ctx, s.invRepo, s.publisher, salesSlotID, items)
+}
+
+// publishInventories announces the inventory rows the items draw from, each
+// once.
+func publishInventories(ctx context.Context, invRepo repositories.ProductInventoryRepository, publisher events.Publisher, salesSlotID types.ID, items []models.OrderItem) {
+	published := make(map[types.ID]bool)
+	for _, item := range items {
+		for _, unit := range item.StockUnits() {
+			if published[unit.ProductID] {
+				continue
+			}
+			published[unit.ProductID] = true
+
+			inventory, err := invRepo.FindBySalesSlotAndProduct(ctx, salesSlotID, unit.ProductID)
+			if err != nil {
+				continue
+			}
+			publisher.Publish(events.NewInventoryEvent(inventory))
+		}
+	}
 }
 
 func (s *orderService) GetOrder(ctx context.Context, id types.ID) (*models.Order, error) {
 	return s.orderRepo.FindByID(ctx, id)
 }
 
-func (s *orderService) GetAllOrders(ctx context.Context) ([]models.Order, error) {
-	return s.orderRepo.FindAll(ctx)
+func (s *orderService) GetAllOrders(ctx context.Context, filter repositories.OrderFilter) ([]models.Order, error) {
+	return s.orderRepo.FindByFilter(ctx, filter)
 }
 
 func (s *orderService) GetOrdersByStatus(ctx context.Context, status types.OrderStatus) ([]models.Order, error) {
 	return s.orderRepo.FindByStatus(ctx, status)
 }
 
+var orderStatusEvents = map[types.OrderStatus]events.Type{
+	types.CONFIRMED: events.OrderConfirmed,
+	types.CANCELLED: events.OrderCancelled,
+	types.PREPARING: events.OrderPreparing,
+	types.READY:     events.OrderReady,
+	types.DELIVERED: events.OrderDelivered,
+}
+
+var orderStatusActions = map[types.OrderStatus]string{
+	types.CONFIRMED: "confirm",
+	types.CANCELLED: "cancel",
+	types.PREPARING: "prepare",
+	types.READY:     "ready",
+	types.DELIVERED: "deliver",
+}
+
 func (s *orderService) UpdateOrderStatus(ctx context.Context, id types.ID, status types.OrderStatus) error {
-	order, err := s.orderRepo.FindByID(ctx, id)
-	if err != nil {
-		return err
-	}
+	return s.updateOrderStatus(ctx, id, status, nil)
+}
 
-	switch status {
-	case types.CONFIRMED:
-		if order.Status != types.RESERVED {
+// updateOrderStatus moves an order to status if the transition is allowed and
+// settles the inventory of a reservation that is confirmed or cancelled. check
+// runs on the locked order and may abort the transaction by returning an error.
+func (s *orderService) updateOrderStatus(ctx context.Context, id types.ID, status types.OrderStatus, check func(ctx context.Context, order *models.Order) error) error {
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.orderRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !current.Status.CanTransitionTo(status) {
 			return ErrInvalidOrderStatus
 		}
-	case types.CANCELLED:
-		if order.Status != types.RESERVED {
+
+		// The conditional status update locks the order row, so the items
+		// read below cannot change until the transaction ends.
+		err = s.orderRepo.TransitionStatus(ctx, id, current.Status, status, s.clock.Now())
+		if errors.Is(err, repositories.ErrConflict) {
 			return ErrInvalidOrderStatus
 		}
-	default:
-		return ErrInvalidOrderStatus
-	}
+		if err != nil {
+			return err
+		}
 
-	if status == types.CONFIRMED {
-		for _, item := range order.Items {
-			inventory, _ := s.invRepo.FindBySalesSlotAndProduct(ctx, order.SalesSlotID, item.ProductID)
-			err = s.invRepo.UpdateQuantities(ctx, inventory.ID,
-				inventory.ReservedQuantity-item.Quantity,
-				inventory.SoldQuantity+item.Quantity)
-			if err != nil {
+		order, err := s.orderRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if check != nil {
+			if err := check(ctx, order); err != nil {
 				return err
 			}
 		}
-	} else if status == types.CANCELLED {
+
+		if err := s.audit.Record(ctx, AuditEntityOrder, id, orderStatusActions[status], current, order); err != nil {
+			return err
+		}
+
+		if current.Status != types.RESERVED {
+			return nil
+		}
+
 		for _, item := range order.Items {
-			inventory, _ := s.invRepo.FindBySalesSlotAndProduct(ctx, order.SalesSlotID, item.ProductID)
-			err = s.invRepo.UpdateQuantities(ctx, inventory.ID,
-				inventory.ReservedQuantity-item.Quantity,
-				inventory.SoldQuantity)
-			if err != nil {
+			soldUnits := 0
+			if status == types.CONFIRMED {
+				soldUnits = item.Quantity
+			}
+			if err := s.adjustStock(ctx, order.SalesSlotID, &item, -item.Quantity, soldUnits); err != nil {
 				return err
 			}
 		}
+
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	return s.orderRepo.UpdateStatus(ctx, id, status)
+	settled := status == types.CONFIRMED || status == types.CANCELLED
+	s.publishOrder(ctx, orderStatusEvents[status], id, settled)
+
+	return nil
 }
 
-func (s *orderService) CancelOrder(ctx context.Context, id types.ID) error {
-	return s.UpdateOrderStatus(ctx, id, types.CANCELLED)
+// CancelOrder refuses an order with payments in its ledger, which has to be
+// refunded instead so the money taken stays on record.
+func (s *orderService) CancelOrder(ctx context.Context, id types.ID, reason string) error {
+	return s.updateOrderStatus(ctx, id, types.CANCELLED, func(ctx context.Context, order *models.Order) error {
+		if order.PaidAmount > 0 {
+			return ErrOrderPartiallyPaid
+		}
+		if reason == "" {
+			return nil
+		}
+		order.CancelReason = &reason
+		return s.orderRepo.SetCancelReason(ctx, id, reason)
+	})
 }
 
-func (s *orderService) AddOrderItems(ctx context.Context, orderID types.ID, items []OrderItemInput) error {
-	order, err := s.orderRepo.FindByID(ctx, orderID)
+func (s *orderService) CancelExpiredReservations(ctx context.Context, createdBefore time.Time) (int, error) {
+	orders, err := s.orderRepo.FindExpiredReservations(ctx, createdBefore)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	if order.Status != types.RESERVED {
-		return ErrInvalidOrderStatus
+	cancelled := 0
+	for _, order := range orders {
+		err := s.updateOrderStatus(ctx, order.ID, types.CANCELLED, func(ctx context.Context, locked *models.Order) error {
+			// The order may have been paid after it was listed.
+			if locked.PaidAmount > 0 {
+				return ErrInvalidOrderStatus
+			}
+			reason := ReservationExpiredReason
+			locked.CancelReason = &reason
+			return s.orderRepo.SetCancelReason(ctx, locked.ID, ReservationExpiredReason)
+		})
+		if errors.Is(err, ErrInvalidOrderStatus) {
+			continue
+		}
+		if err != nil {
+			return cancelled, err
+		}
+		cancelled++
 	}
 
-	var orderItems []models.OrderItem
-	additionalAmount := 0
+	return cancelled, nil
+}
 
-	for _, item := range items {
-		product, err := s.productRepo.FindByID(ctx, item.ProductID)
+// AddOrderItems adds to the quantity of a line that already has the product
+// with the same options at the same price instead of adding another line.
+func (s *orderService) AddOrderItems(ctx context.Context, orderID types.ID, items []OrderItemInput) error {
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.FindByIDForUpdate(ctx, orderID)
 		if err != nil {
 			return err
 		}
 
-		inventory, err := s.invRepo.FindBySalesSlotAndProduct(ctx, order.SalesSlotID, item.ProductID)
+		if order.Status != types.RESERVED {
+			return ErrInvalidOrderStatus
+		}
+		if order.PaidAmount > 0 {
+			return ErrOrderPartiallyPaid
+		}
+
+		orderItems, additionalAmount, err := s.reserveItems(ctx, order.SalesSlotID, items)
 		if err != nil {
 			return err
 		}
 
-		if inventory.GetAvailableQuantity() < item.Quantity {
-			return ErrInsufficientInventory
+		err = s.orderRepo.AddTotalAmount(ctx, orderID, types.RESERVED, additionalAmount)
+		if errors.Is(err, repositories.ErrConflict) {
+			return ErrInvalidOrderStatus
+		}
+		if err != nil {
+			return err
 		}
 
-		orderItems = append(orderItems, models.OrderItem{
-			OrderID:   orderID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     product.Price,
-		})
-
-		additionalAmount += product.Price * item.Quantity
-	}
+		var newItems []models.OrderItem
+		for _, item := range orderItems {
+			line := findOrderLine(order.Items, &item)
+			if line == nil {
+				newItems = append(newItems, item)
+				continue
+			}
+			if err := s.orderRepo.AddItemQuantity(ctx, line.ID, item.Quantity); err != nil {
+				return err
+			}
+		}
+		if len(newItems) > 0 {
+			if err := s.orderRepo.AddItems(ctx, orderID, newItems); err != nil {
+				return err
+			}
+		}
 
-	err = s.orderRepo.AddItems(ctx, orderID, orderItems)
+		after, err := repriceOrder(ctx, s.orderRepo, orderID)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityOrder, orderID, "add_items", order, after)
+	})
 	if err != nil {
 		return err
 	}
 
-	for _, item := range orderItems {
-		inventory, _ := s.invRepo.FindBySalesSlotAndProduct(ctx, order.SalesSlotID, item.ProductID)
-		err = s.invRepo.UpdateQuantities(ctx, inventory.ID,
-			inventory.ReservedQuantity+item.Quantity,
-			inventory.SoldQuantity)
+	s.publishOrder(ctx, events.OrderItemsAdded, orderID, true)
+
+	return nil
+}
+
+// UpdateOrderItem sets the quantity of a line of a reserved order and
+// reserves or releases the difference.
+func (s *orderService) UpdateOrderItem(ctx context.Context, orderID, itemID types.ID, quantity int) error {
+	if quantity <= 0 {
+		return ErrInvalidQuantity
+	}
+	return s.changeOrderItem(ctx, orderID, itemID, "update_item", func(order *models.Order) (int, error) {
+		return quantity, nil
+	})
+}
+
+// RemoveOrderItem removes a line from a reserved order and releases its
+// reservation. The last line cannot be removed; the order is cancelled
+// instead.
+func (s *orderService) RemoveOrderItem(ctx context.Context, orderID, itemID types.ID) error {
+	return s.changeOrderItem(ctx, orderID, itemID, "remove_item", func(order *models.Order) (int, error) {
+		if len(order.Items) == 1 {
+			return 0, ErrLastOrderItem
+		}
+		return 0, nil
+	})
+}
+
+// changeOrderItem locks a reserved order and sets one of its lines to the
+// quantity returned by newQuantity, removing the line at zero. The
+// reservation and the total move by the difference, and the discounts are
+// recalculated.
+func (s *orderService) changeOrderItem(ctx context.Context, orderID, itemID types.ID, action string, newQuantity func(order *models.Order) (int, error)) error {
+	var salesSlotID types.ID
+	var changed models.OrderItem
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.FindByIDForUpdate(ctx, orderID)
 		if err != nil {
 			return err
 		}
+		if order.Status != types.RESERVED {
+			return ErrInvalidOrderStatus
+		}
+		if order.PaidAmount > 0 {
+			return ErrOrderPartiallyPaid
+		}
+
+		var item *models.OrderItem
+		for i := range order.Items {
+			if order.Items[i].ID == itemID {
+				item = &order.Items[i]
+				break
+			}
+		}
+		if item == nil {
+			return repositories.NewErrNotFound("OrderItem", itemID)
+		}
+		salesSlotID = order.SalesSlotID
+		changed = *item
+
+		quantity, err := newQuantity(order)
+		if err != nil {
+			return err
+		}
+		delta := quantity - item.Quantity
+		if delta == 0 {
+			return nil
+		}
+
+		if err := s.adjustStock(ctx, order.SalesSlotID, item, delta, 0); err != nil {
+			return err
+		}
+
+		err = s.orderRepo.AddTotalAmount(ctx, orderID, types.RESERVED, delta*item.Price)
+		if errors.Is(err, repositories.ErrConflict) {
+			return ErrInvalidOrderStatus
+		}
+		if err != nil {
+			return err
+		}
+
+		if quantity == 0 {
+			err = s.orderRepo.DeleteItem(ctx, item.ID)
+		} else {
+			err = s.orderRepo.AddItemQuantity(ctx, item.ID, delta)
+		}
+		if err != nil {
+			return err
+		}
+
+		after, err := repriceOrder(ctx, s.orderRepo, orderID)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityOrder, orderID, action, order, after)
+	})
+	if err != nil {
+		return err
 	}
 
-	order.TotalAmount += additionalAmount
-	return s.orderRepo.Update(ctx, order)
+	s.publishOrder(ctx, events.OrderItemsUpdated, orderID, false)
+	s.publishInventories(ctx, salesSlotID, []models.OrderItem{changed})
+
+	return nil
 }
 
-func (s *orderService) GetOrderByTicketNumber(ctx context.Context, ticketNumber string) (*models.Order, error) {
-	return s.orderRepo.FindByTicketNumber(ctx, ticketNumber)
+func findOrderLine(items []models.OrderItem, item *models.OrderItem) *models.OrderItem {
+	key := lineKey(item.ProductID, item.OptionIDs())
+	for i := range items {
+		if items[i].Price == item.Price && lineKey(items[i].ProductID, items[i].OptionIDs()) == key {
+			return &items[i]
+		}
+	}
+	return nil
+}
+
+func (s *orderService) GetOrderByTicketNumber(ctx context.Context, salesSlotID types.ID, ticketNumber string) (*models.Order, error) {
+	return s.orderRepo.FindByTicketNumber(ctx, salesSlotID, ticketNumber)
 }
 
 func (s *orderService) UpdatePaymentStatus(ctx context.Context, id types.ID, transactionID string) error {
-	order, err := s.orderRepo.FindByID(ctx, id)
+	var order models.Order
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.orderRepo.FindByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		// Card payments are recorded by the payment service once Square has
+		// confirmed them.
+		if before.PaymentMethod == types.SQUARE {
+			return ErrPaymentNotConfirmed
+		}
+
+		order = *before
+		payment := &models.Payment{
+			Amount: before.BalanceDue(),
+			Method: before.PaymentMethod,
+		}
+		if transactionID != "" {
+			payment.Reference = &transactionID
+		}
+		if err := recordPayment(ctx, s.orderRepo, s.paymentRepo, &order, payment, s.clock.Now()); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityOrder, id, "pay", before, &order)
+	})
 	if err != nil {
 		return err
 	}
 
-	order.IsPaid = true
-	order.TransactionID = &transactionID
+	s.publisher.Publish(events.NewOrderEvent(events.OrderPaid, &order))
 
-	return s.orderRepo.Update(ctx, order)
+	return nil
 }
 
 func (s *orderService) UpdateDeliveryStatus(ctx context.Context, id types.ID) error {
-	order, err := s.orderRepo.FindByID(ctx, id)
-	if err != nil {
-		return err
+	err := s.UpdateOrderStatus(ctx, id, types.DELIVERED)
+	if errors.Is(err, ErrInvalidOrderStatus) {
+		return ErrDeliveryNotAllowed
 	}
+	return err
+}
 
-	order.IsDelivered = true
-
-	return s.orderRepo.Update(ctx, order)
+func (s *orderService) GetKitchenQueue(ctx context.Context, salesSlotID types.ID) ([]models.Order, error) {
+	return s.orderRepo.FindBySalesSlotAndStatuses(ctx, salesSlotID, []types.OrderStatus{types.CONFIRMED, types.PREPARING})
 }
@@ -2,45 +2,130 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
 )
 
+// mockOrderRepository loads the payments of an order from payments, the way
+// the real repository preloads them.
 type mockOrderRepository struct {
-	orders map[types.ID]*models.Order
+	mu       sync.Mutex
+	orders   map[types.ID]*models.Order
+	payments *mockPaymentRepository
 }
 
 func newMockOrderRepository() *mockOrderRepository {
 	return &mockOrderRepository{
-		orders: make(map[types.ID]*models.Order),
+		orders:   make(map[types.ID]*models.Order),
+		payments: newMockPaymentRepository(),
+	}
+}
+
+func (r *mockOrderRepository) snapshot() func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := make(map[types.ID]models.Order, len(r.orders))
+	for id, order := range r.orders {
+		copied := *order
+		copied.Items = append([]models.OrderItem(nil), order.Items...)
+		copied.Payments = append([]models.Payment(nil), order.Payments...)
+		saved[id] = copied
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.orders = make(map[types.ID]*models.Order, len(saved))
+		for id, order := range saved {
+			r.orders[id] = &order
+		}
 	}
 }
 
 func (r *mockOrderRepository) Create(ctx context.Context, order *models.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.orders[order.ID] = order
 	return nil
 }
 
 func (r *mockOrderRepository) FindByID(ctx context.Context, id types.ID) (*models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	if order, exists := r.orders[id]; exists {
-		return order, nil
+		found := *order
+		found.Items = append([]models.OrderItem(nil), order.Items...)
+		found.Discounts = append([]models.OrderDiscount(nil), order.Discounts...)
+		found.Payments, _ = r.payments.FindByOrderID(ctx, id)
+		return &found, nil
 	}
 	return nil, repositories.NewErrNotFound("Order", id)
 }
 
+func (r *mockOrderRepository) FindByIDForUpdate(ctx context.Context, id types.ID) (*models.Order, error) {
+	return r.FindByID(ctx, id)
+}
+
 func (r *mockOrderRepository) FindAll(ctx context.Context) ([]models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var orders []models.Order
+	for _, o := range r.orders {
+		orders = append(orders, *o)
+	}
+	return orders, nil
+}
+
+func (r *mockOrderRepository) FindByFilter(ctx context.Context, filter repositories.OrderFilter) ([]models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	var orders []models.Order
 	for _, o := range r.orders {
+		if filter.SalesSlotID != "" && o.SalesSlotID != filter.SalesSlotID {
+			continue
+		}
+		if filter.TerminalID != "" && !matchesID(o.TerminalID, filter.TerminalID) && !matchesID(o.PaidTerminalID, filter.TerminalID) {
+			continue
+		}
+		if filter.Status != 0 && o.Status != filter.Status {
+			continue
+		}
 		orders = append(orders, *o)
 	}
 	return orders, nil
 }
 
+func (r *mockOrderRepository) StreamByFilter(ctx context.Context, filter repositories.OrderFilter, fn func(order *models.Order) error) error {
+	orders, err := r.FindByFilter(ctx, filter)
+	if err != nil {
+		return err
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].TicketNumber < orders[j].TicketNumber })
+	for i := range orders {
+		if err := fn(&orders[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func matchesID(id *types.ID, want types.ID) bool {
+	return id != nil && *id == want
+}
+
 func (r *mockOrderRepository) Update(ctx context.Context, order *models.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	if _, exists := r.orders[order.ID]; !exists {
 		return repositories.NewErrNotFound("Order", order.ID)
 	}
@@ -49,6 +134,8 @@ func (r *mockOrderRepository) Update(ctx context.Context, order *models.Order) e
 }
 
 func (r *mockOrderRepository) Delete(ctx context.Context, id types.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	if _, exists := r.orders[id]; !exists {
 		return repositories.NewErrNotFound("Order", id)
 	}
@@ -57,6 +144,8 @@ func (r *mockOrderRepository) Delete(ctx context.Context, id types.ID) error {
 }
 
 func (r *mockOrderRepository) FindBySalesSlotID(ctx context.Context, salesSlotID types.ID) ([]models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	var orders []models.Order
 	for _, o := range r.orders {
 		if o.SalesSlotID == salesSlotID {
@@ -67,6 +156,8 @@ func (r *mockOrderRepository) FindBySalesSlotID(ctx context.Context, salesSlotID
 }
 
 func (r *mockOrderRepository) FindByStatus(ctx context.Context, status types.OrderStatus) ([]models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	var orders []models.Order
 	for _, o := range r.orders {
 		if o.Status == status {
@@ -77,6 +168,8 @@ func (r *mockOrderRepository) FindByStatus(ctx context.Context, status types.Ord
 }
 
 func (r *mockOrderRepository) UpdateStatus(ctx context.Context, id types.ID, status types.OrderStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	order, exists := r.orders[id]
 	if !exists {
 		return repositories.NewErrNotFound("Order", id)
@@ -85,24 +178,188 @@ func (r *mockOrderRepository) UpdateStatus(ctx context.Context, id types.ID, sta
 	return nil
 }
 
+func (r *mockOrderRepository) TransitionStatus(ctx context.Context, id types.ID, from, to types.OrderStatus, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order, exists := r.orders[id]
+	if !exists {
+		return repositories.NewErrNotFound("Order", id)
+	}
+	if order.Status != from {
+		return repositories.ErrConflict
+	}
+	order.SetStatus(to, at)
+	return nil
+}
+
+func (r *mockOrderRepository) AddTotalAmount(ctx context.Context, id types.ID, status types.OrderStatus, amount int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order, exists := r.orders[id]
+	if !exists {
+		return repositories.NewErrNotFound("Order", id)
+	}
+	if order.Status != status {
+		return repositories.ErrConflict
+	}
+	order.TotalAmount += amount
+	return nil
+}
+
+func (r *mockOrderRepository) AddRefund(ctx context.Context, id types.ID, statuses []types.OrderStatus, amount int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order, exists := r.orders[id]
+	if !exists {
+		return repositories.NewErrNotFound("Order", id)
+	}
+	if !slices.Contains(statuses, order.Status) || order.RefundedAmount+amount > order.TotalAmount {
+		return repositories.ErrConflict
+	}
+	order.RefundedAmount += amount
+	return nil
+}
+
+func (r *mockOrderRepository) AddRefundedQuantity(ctx context.Context, itemID types.ID, quantity int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, order := range r.orders {
+		for i := range order.Items {
+			item := &order.Items[i]
+			if item.ID != itemID {
+				continue
+			}
+			if item.RefundedQuantity+quantity > item.Quantity {
+				return repositories.ErrConflict
+			}
+			item.RefundedQuantity += quantity
+			return nil
+		}
+	}
+	return repositories.NewErrNotFound("OrderItem", itemID)
+}
+
 func (r *mockOrderRepository) AddItems(ctx context.Context, orderID types.ID, items []models.OrderItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	order, exists := r.orders[orderID]
 	if !exists {
 		return repositories.NewErrNotFound("Order", orderID)
 	}
+	for i := range items {
+		if items[i].ID == "" {
+			items[i].ID = types.ID(uuid.New().String())
+		}
+	}
 	order.Items = append(order.Items, items...)
 	return nil
 }
 
+func (r *mockOrderRepository) AddItemQuantity(ctx context.Context, itemID types.ID, delta int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, order := range r.orders {
+		for i := range order.Items {
+			if order.Items[i].ID == itemID {
+				order.Items[i].Quantity += delta
+				return nil
+			}
+		}
+	}
+	return repositories.NewErrNotFound("OrderItem", itemID)
+}
+
+func (r *mockOrderRepository) DeleteItem(ctx context.Context, itemID types.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, order := range r.orders {
+		for i := range order.Items {
+			if order.Items[i].ID == itemID {
+				order.Items = slices.Delete(order.Items, i, i+1)
+				order.Discounts = slices.DeleteFunc(order.Discounts, func(d models.OrderDiscount) bool {
+					return d.OrderItemID != nil && *d.OrderItemID == itemID
+				})
+				return nil
+			}
+		}
+	}
+	return repositories.NewErrNotFound("OrderItem", itemID)
+}
+
+func (r *mockOrderRepository) AddDiscount(ctx context.Context, discount *models.OrderDiscount) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order, exists := r.orders[discount.OrderID]
+	if !exists {
+		return repositories.NewErrNotFound("Order", discount.OrderID)
+	}
+	order.Discounts = append(order.Discounts, *discount)
+	return nil
+}
+
+func (r *mockOrderRepository) DeleteDiscount(ctx context.Context, discountID types.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, order := range r.orders {
+		for i := range order.Discounts {
+			if order.Discounts[i].ID == discountID {
+				order.Discounts = slices.Delete(order.Discounts, i, i+1)
+				return nil
+			}
+		}
+	}
+	return repositories.NewErrNotFound("OrderDiscount", discountID)
+}
+
+func (r *mockOrderRepository) UpdatePricing(ctx context.Context, order *models.Order, status types.OrderStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, exists := r.orders[order.ID]
+	if !exists {
+		return repositories.NewErrNotFound("Order", order.ID)
+	}
+	if stored.Status != status {
+		return repositories.ErrConflict
+	}
+	stored.TotalAmount = order.TotalAmount
+	stored.DiscountAmount = order.DiscountAmount
+	stored.Discounts = append([]models.OrderDiscount(nil), order.Discounts...)
+	for _, item := range order.Items {
+		for i := range stored.Items {
+			if stored.Items[i].ID == item.ID {
+				stored.Items[i].DiscountAmount = item.DiscountAmount
+			}
+		}
+	}
+	return nil
+}
+
 func (r *mockOrderRepository) CreateWithItems(ctx context.Context, order *models.Order, items []models.OrderItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.orders {
+		if existing.SalesSlotID == order.SalesSlotID && existing.TicketNumber == order.TicketNumber {
+			return repositories.ErrDuplicate
+		}
+	}
+	if order.ID == "" {
+		order.ID = types.ID(uuid.New().String())
+	}
+	for i := range items {
+		if items[i].ID == "" {
+			items[i].ID = types.ID(uuid.New().String())
+		}
+	}
 	order.Items = items
 	r.orders[order.ID] = order
 	return nil
 }
 
-func (r *mockOrderRepository) FindByTicketNumber(ctx context.Context, ticketNumber string) (*models.Order, error) {
+func (r *mockOrderRepository) FindByTicketNumber(ctx context.Context, salesSlotID types.ID, ticketNumber string) (*models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	for _, order := range r.orders {
-		if order.TicketNumber == ticketNumber {
+		if order.TicketNumber == ticketNumber && (salesSlotID == "" || order.SalesSlotID == salesSlotID) {
 			return order, nil
 		}
 	}
@@ -112,12 +369,206 @@ func (r *mockOrderRepository) FindByTicketNumber(ctx context.Context, ticketNumb
 	}
 }
 
+func (r *mockOrderRepository) TicketNumberExists(ctx context.Context, salesSlotID types.ID, ticketNumber string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, order := range r.orders {
+		if order.SalesSlotID == salesSlotID && order.TicketNumber == ticketNumber {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *mockOrderRepository) UpdatePayment(ctx context.Context, order *models.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, exists := r.orders[order.ID]
+	if !exists {
+		return repositories.NewErrNotFound("Order", order.ID)
+	}
+	stored.PaidAmount = order.PaidAmount
+	stored.IsPaid = order.IsPaid
+	stored.TransactionID = order.TransactionID
+	stored.PaidAt = order.PaidAt
+	stored.PaidTerminalID = order.PaidTerminalID
+	stored.PaidByID = order.PaidByID
+	return nil
+}
+
+func (r *mockOrderRepository) FindExpiredReservations(ctx context.Context, createdBefore time.Time) ([]models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var orders []models.Order
+	for _, o := range r.orders {
+		if o.Status == types.RESERVED && o.PaidAmount == 0 && o.CreatedAt.Before(createdBefore) {
+			orders = append(orders, *o)
+		}
+	}
+	return orders, nil
+}
+
+func (r *mockOrderRepository) FindBySalesSlotAndStatuses(ctx context.Context, salesSlotID types.ID, statuses []types.OrderStatus) ([]models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var orders []models.Order
+	for _, o := range r.orders {
+		if salesSlotID != "" && o.SalesSlotID != salesSlotID {
+			continue
+		}
+		for _, status := range statuses {
+			if o.Status == status {
+				orders = append(orders, *o)
+				break
+			}
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if orders[i].ConfirmedAt == nil || orders[j].ConfirmedAt == nil {
+			return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+		}
+		return orders[i].ConfirmedAt.Before(*orders[j].ConfirmedAt)
+	})
+	return orders, nil
+}
+
+func (r *mockOrderRepository) SetCancelReason(ctx context.Context, id types.ID, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order, exists := r.orders[id]
+	if !exists {
+		return repositories.NewErrNotFound("Order", id)
+	}
+	order.CancelReason = &reason
+	return nil
+}
+
+type mockPublisher struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (p *mockPublisher) Publish(event events.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+}
+
+func (p *mockPublisher) types() []events.Type {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var result []events.Type
+	for _, e := range p.events {
+		result = append(result, e.Type)
+	}
+	return result
+}
+
+type mockTransactor struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (t *mockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.mu.Lock()
+	t.calls++
+	t.mu.Unlock()
+	return fn(ctx)
+}
+
+// snapshotter is a mock repository whose state can be saved and put back.
+type snapshotter interface {
+	snapshot() (restore func())
+}
+
+type rollbackTxKey struct{}
+
+// rollbackTransactor runs one transaction at a time, as if it held every
+// row lock, and puts the repositories back as they were when a transaction
+// fails. Nested calls join the transaction already running.
+type rollbackTransactor struct {
+	mu    sync.Mutex
+	repos []snapshotter
+}
+
+func newRollbackTransactor(repos ...snapshotter) *rollbackTransactor {
+	return &rollbackTransactor{repos: repos}
+}
+
+func (t *rollbackTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(rollbackTxKey{}) != nil {
+		return fn(ctx)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	restores := make([]func(), len(t.repos))
+	for i, repo := range t.repos {
+		restores[i] = repo.snapshot()
+	}
+	if err := fn(context.WithValue(ctx, rollbackTxKey{}, true)); err != nil {
+		for _, restore := range restores {
+			restore()
+		}
+		return err
+	}
+	return nil
+}
+
+type mockTicketSequenceRepository struct {
+	mu        sync.Mutex
+	sequences map[types.ID]int
+}
+
+func newMockTicketSequenceRepository() *mockTicketSequenceRepository {
+	return &mockTicketSequenceRepository{sequences: make(map[types.ID]int)}
+}
+
+func (r *mockTicketSequenceRepository) Next(ctx context.Context, salesSlotID types.ID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sequences[salesSlotID]++
+	return r.sequences[salesSlotID], nil
+}
+
+// orderTest is an order service built on fresh mock repositories. Options
+// passed to newTestOrderService may swap the dependencies in the second group
+// before the service is built.
+type orderTest struct {
+	service   OrderService
+	orderRepo *mockOrderRepository
+	slotRepo  *mockSalesSlotRepository
+	invRepo   *mockInventoryRepository
+	prodRepo  *mockProductRepository
+
+	orders     repositories.OrderRepository
+	transactor repositories.Transactor
+	publisher  events.Publisher
+	clock      Clock
+}
+
+func newTestOrderService(options ...func(*orderTest)) *orderTest {
+	o := &orderTest{
+		orderRepo:  newMockOrderRepository(),
+		slotRepo:   newMockSalesSlotRepository(),
+		invRepo:    newMockInventoryRepository(),
+		prodRepo:   newMockProductRepository(),
+		transactor: &mockTransactor{},
+		publisher:  &mockPublisher{},
+		clock:      NewSystemClock(),
+	}
+	o.orders = o.orderRepo
+	for _, option := range options {
+		option(o)
+	}
+	tickets := NewTicketNumberGenerator(newMockTicketSequenceRepository(), o.orders, 3)
+	o.service = NewOrderService(o.orders, o.orderRepo.payments, o.slotRepo, o.invRepo, o.prodRepo, o.transactor, o.publisher, o.clock, tickets, newTestAuditLogService())
+	return o
+}
+
 func TestOrderService_CreateOrder(t *testing.T) {
-	orderRepo := newMockOrderRepository()
-	slotRepo := newMockSalesSlotRepository()
-	invRepo := newMockInventoryRepository()
-	prodRepo := newMockProductRepository()
-	service := NewOrderService(orderRepo, slotRepo, invRepo, prodRepo)
+	o := newTestOrderService()
+	service, slotRepo, invRepo, prodRepo := o.service, o.slotRepo, o.invRepo, o.prodRepo
 	ctx := context.Background()
 
 	slot := &models.SalesSlot{
@@ -175,11 +626,8 @@ func TestOrderService_CreateOrder(t *testing.T) {
 }
 
 func TestOrderService_UpdatePaymentStatus(t *testing.T) {
-	orderRepo := newMockOrderRepository()
-	slotRepo := newMockSalesSlotRepository()
-	invRepo := newMockInventoryRepository()
-	prodRepo := newMockProductRepository()
-	service := NewOrderService(orderRepo, slotRepo, invRepo, prodRepo)
+	o := newTestOrderService()
+	service, slotRepo, invRepo, prodRepo := o.service, o.slotRepo, o.invRepo, o.prodRepo
 	ctx := context.Background()
 
 	slot := &models.SalesSlot{
@@ -228,11 +676,8 @@ func TestOrderService_UpdatePaymentStatus(t *testing.T) {
 }
 
 func TestOrderService_CancelOrder(t *testing.T) {
-	orderRepo := newMockOrderRepository()
-	slotRepo := newMockSalesSlotRepository()
-	invRepo := newMockInventoryRepository()
-	prodRepo := newMockProductRepository()
-	service := NewOrderService(orderRepo, slotRepo, invRepo, prodRepo)
+	o := newTestOrderService()
+	service, slotRepo, invRepo, prodRepo := o.service, o.slotRepo, o.invRepo, o.prodRepo
 	ctx := context.Background()
 
 	slot := &models.SalesSlot{
@@ -265,7 +710,7 @@ func TestOrderService_CancelOrder(t *testing.T) {
 
 	order, _ := service.CreateOrder(ctx, slot.ID, items, "TICKET002", types.CASH)
 
-	err := service.CancelOrder(ctx, order.ID)
+	err := service.CancelOrder(ctx, order.ID, "お客様都合")
 	if err != nil {
 		t.Errorf("CancelOrder failed: %v", err)
 	}
@@ -274,14 +719,19 @@ func TestOrderService_CancelOrder(t *testing.T) {
 	if order.Status != types.CANCELLED {
 		t.Errorf("Expected order status %v, got %v", types.CANCELLED, order.Status)
 	}
+	if order.CancelReason == nil || *order.CancelReason != "お客様都合" {
+		t.Errorf("Expected cancel reason to be recorded, got %v", order.CancelReason)
+	}
+
+	inventory, _ = invRepo.FindByID(ctx, inventory.ID)
+	if inventory.ReservedQuantity != 0 {
+		t.Errorf("Expected reserved quantity 0, got %d", inventory.ReservedQuantity)
+	}
 }
 
 func TestOrderService_GetOrderByTicketNumber(t *testing.T) {
-	orderRepo := newMockOrderRepository()
-	slotRepo := newMockSalesSlotRepository()
-	invRepo := newMockInventoryRepository()
-	prodRepo := newMockProductRepository()
-	service := NewOrderService(orderRepo, slotRepo, invRepo, prodRepo)
+	o := newTestOrderService()
+	service, slotRepo, invRepo, prodRepo := o.service, o.slotRepo, o.invRepo, o.prodRepo
 	ctx := context.Background()
 
 	slot := &models.SalesSlot{
@@ -315,7 +765,7 @@ func TestOrderService_GetOrderByTicketNumber(t *testing.T) {
 	ticketNumber := "TICKET003"
 	originalOrder, _ := service.CreateOrder(ctx, slot.ID, items, ticketNumber, types.CASH)
 
-	foundOrder, err := service.GetOrderByTicketNumber(ctx, ticketNumber)
+	foundOrder, err := service.GetOrderByTicketNumber(ctx, slot.ID, ticketNumber)
 	if err != nil {
 		t.Errorf("GetOrderByTicketNumber failed: %v", err)
 	}
@@ -327,3 +777,673 @@ func TestOrderService_GetOrderByTicketNumber(t *testing.T) {
 		t.Errorf("Expected ticket number %s, got %s", ticketNumber, foundOrder.TicketNumber)
 	}
 }
+
+func setupConcurrencyTest(t *testing.T, initialQuantity int) (OrderService, *mockInventoryRepository, *models.SalesSlot, *models.Product) {
+	t.Helper()
+	o := newTestOrderService()
+	service, slotRepo, invRepo, prodRepo := o.service, o.slotRepo, o.invRepo, o.prodRepo
+	ctx := context.Background()
+
+	slot := &models.SalesSlot{
+		ID:       types.ID("slot1"),
+		IsActive: true,
+	}
+	slotRepo.Create(ctx, slot)
+
+	product := &models.Product{
+		ID:    types.ID("prod1"),
+		Name:  "焼きそば",
+		Price: 400,
+	}
+	prodRepo.Create(ctx, product)
+
+	invRepo.Create(ctx, &models.ProductInventory{
+		ID:              types.ID("inv1"),
+		SalesSlotID:     slot.ID,
+		ProductID:       product.ID,
+		InitialQuantity: initialQuantity,
+	})
+
+	return service, invRepo, slot, product
+}
+
+func TestOrderService_CreateOrder_Concurrent(t *testing.T) {
+	service, invRepo, slot, product := setupConcurrencyTest(t, 5)
+	ctx := context.Background()
+
+	const terminals = 20
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded, rejected := 0, 0
+
+	for i := 0; i < terminals; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			items := []OrderItemInput{{ProductID: product.ID, Quantity: 1}}
+			_, err := service.CreateOrder(ctx, slot.ID, items, fmt.Sprintf("TICKET%03d", i), types.CASH)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, ErrInsufficientInventory):
+				rejected++
+			default:
+				t.Errorf("CreateOrder failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if succeeded != 5 {
+		t.Errorf("Expected 5 orders to succeed, got %d", succeeded)
+	}
+	if rejected != terminals-5 {
+		t.Errorf("Expected %d orders to be rejected, got %d", terminals-5, rejected)
+	}
+
+	inventory, _ := invRepo.FindByID(ctx, types.ID("inv1"))
+	if inventory.ReservedQuantity != 5 {
+		t.Errorf("Expected reserved quantity 5, got %d", inventory.ReservedQuantity)
+	}
+	if inventory.GetAvailableQuantity() != 0 {
+		t.Errorf("Expected available quantity 0, got %d", inventory.GetAvailableQuantity())
+	}
+}
+
+func TestOrderService_ConfirmAndCancel_Concurrent(t *testing.T) {
+	service, invRepo, slot, product := setupConcurrencyTest(t, 10)
+	ctx := context.Background()
+
+	items := []OrderItemInput{{ProductID: product.ID, Quantity: 2}}
+	order, err := service.CreateOrder(ctx, slot.ID, items, "TICKET001", types.CASH)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		status := types.CONFIRMED
+		if i%2 == 1 {
+			status = types.CANCELLED
+		}
+		wg.Add(1)
+		go func(status types.OrderStatus) {
+			defer wg.Done()
+			errs <- service.UpdateOrderStatus(ctx, order.ID, status)
+		}(status)
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+		} else if !errors.Is(err, ErrInvalidOrderStatus) {
+			t.Errorf("UpdateOrderStatus failed: %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("Expected exactly 1 status change to succeed, got %d", succeeded)
+	}
+
+	updated, _ := service.GetOrder(ctx, order.ID)
+	inventory, _ := invRepo.FindByID(ctx, types.ID("inv1"))
+	if inventory.ReservedQuantity != 0 {
+		t.Errorf("Expected reserved quantity 0, got %d", inventory.ReservedQuantity)
+	}
+
+	expectedSold := 0
+	if updated.Status == types.CONFIRMED {
+		expectedSold = 2
+	}
+	if inventory.SoldQuantity != expectedSold {
+		t.Errorf("Expected sold quantity %d for %v order, got %d", expectedSold, updated.Status, inventory.SoldQuantity)
+	}
+}
+
+func TestOrderService_AddOrderItems_Concurrent(t *testing.T) {
+	service, invRepo, slot, product := setupConcurrencyTest(t, 4)
+	ctx := context.Background()
+
+	items := []OrderItemInput{{ProductID: product.ID, Quantity: 1}}
+	order, err := service.CreateOrder(ctx, slot.ID, items, "TICKET001", types.CASH)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := service.AddOrderItems(ctx, order.ID, items)
+			if err != nil && !errors.Is(err, ErrInsufficientInventory) {
+				t.Errorf("AddOrderItems failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	updated, _ := service.GetOrder(ctx, order.ID)
+	if len(updated.Items) != 1 || updated.Items[0].Quantity != 4 {
+		t.Errorf("Expected a single line of 4, got %+v", updated.Items)
+	}
+	if updated.TotalAmount != 4*product.Price {
+		t.Errorf("Expected total amount %d, got %d", 4*product.Price, updated.TotalAmount)
+	}
+
+	inventory, _ := invRepo.FindByID(ctx, types.ID("inv1"))
+	if inventory.ReservedQuantity != 4 {
+		t.Errorf("Expected reserved quantity 4, got %d", inventory.ReservedQuantity)
+	}
+}
+
+func TestOrderService_AddOrderItems_ConfirmedOrder(t *testing.T) {
+	service, invRepo, slot, product := setupConcurrencyTest(t, 10)
+	ctx := context.Background()
+
+	items := []OrderItemInput{{ProductID: product.ID, Quantity: 1}}
+	order, _ := service.CreateOrder(ctx, slot.ID, items, "TICKET001", types.CASH)
+	service.UpdateOrderStatus(ctx, order.ID, types.CONFIRMED)
+
+	err := service.AddOrderItems(ctx, order.ID, items)
+	if !errors.Is(err, ErrInvalidOrderStatus) {
+		t.Errorf("Expected ErrInvalidOrderStatus, got %v", err)
+	}
+
+	inventory, _ := invRepo.FindByID(ctx, types.ID("inv1"))
+	if inventory.ReservedQuantity != 0 || inventory.SoldQuantity != 1 {
+		t.Errorf("Expected reserved 0 and sold 1, got reserved %d and sold %d", inventory.ReservedQuantity, inventory.SoldQuantity)
+	}
+}
+
+func TestOrderService_UpdateAndRemoveOrderItems(t *testing.T) {
+	service, invRepo, slot, product := setupConcurrencyTest(t, 10)
+	ctx := context.Background()
+
+	order, err := service.CreateOrder(ctx, slot.ID, []OrderItemInput{
+		{ProductID: product.ID, Quantity: 1},
+		{ProductID: product.ID, Quantity: 2},
+	}, "", types.CASH)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	if len(order.Items) != 1 || order.Items[0].Quantity != 3 {
+		t.Fatalf("Expected repeated products to be merged into one line of 3, got %+v", order.Items)
+	}
+	itemID := order.Items[0].ID
+
+	if err := service.UpdateOrderItem(ctx, order.ID, itemID, 5); err != nil {
+		t.Fatalf("UpdateOrderItem failed: %v", err)
+	}
+	if err := service.UpdateOrderItem(ctx, order.ID, itemID, 11); !errors.Is(err, ErrInsufficientInventory) {
+		t.Errorf("Expected ErrInsufficientInventory, got %v", err)
+	}
+	if err := service.UpdateOrderItem(ctx, order.ID, itemID, 0); !errors.Is(err, ErrInvalidQuantity) {
+		t.Errorf("Expected ErrInvalidQuantity, got %v", err)
+	}
+	if err := service.RemoveOrderItem(ctx, order.ID, itemID); !errors.Is(err, ErrLastOrderItem) {
+		t.Errorf("Expected ErrLastOrderItem, got %v", err)
+	}
+
+	updated, _ := service.GetOrder(ctx, order.ID)
+	if updated.Items[0].Quantity != 5 || updated.TotalAmount != 5*product.Price {
+		t.Errorf("Expected 5 units for %d, got %d for %d", 5*product.Price, updated.Items[0].Quantity, updated.TotalAmount)
+	}
+	inventory, _ := invRepo.FindByID(ctx, types.ID("inv1"))
+	if inventory.ReservedQuantity != 5 {
+		t.Errorf("Expected reserved quantity 5, got %d", inventory.ReservedQuantity)
+	}
+
+	// A line added at another price, e.g. after a price change, is kept
+	// apart and can be removed.
+	extra := models.OrderItem{ProductID: product.ID, Quantity: 1, Price: product.Price + 50}
+	service.(*orderService).orderRepo.AddItems(ctx, order.ID, []models.OrderItem{extra})
+	service.(*orderService).orderRepo.AddTotalAmount(ctx, order.ID, types.RESERVED, extra.Price)
+	invRepo.AdjustQuantities(ctx, "inv1", 1, 0)
+	updated, _ = service.GetOrder(ctx, order.ID)
+	if err := service.RemoveOrderItem(ctx, order.ID, updated.Items[1].ID); err != nil {
+		t.Fatalf("RemoveOrderItem failed: %v", err)
+	}
+	updated, _ = service.GetOrder(ctx, order.ID)
+	inventory, _ = invRepo.FindByID(ctx, types.ID("inv1"))
+	if len(updated.Items) != 1 || updated.TotalAmount != 5*product.Price || inventory.ReservedQuantity != 5 {
+		t.Errorf("Expected the extra line to be removed, got %d lines, total %d, reserved %d", len(updated.Items), updated.TotalAmount, inventory.ReservedQuantity)
+	}
+
+	service.UpdateOrderStatus(ctx, order.ID, types.CONFIRMED)
+	if err := service.UpdateOrderItem(ctx, order.ID, itemID, 1); !errors.Is(err, ErrInvalidOrderStatus) {
+		t.Errorf("Expected ErrInvalidOrderStatus for a confirmed order, got %v", err)
+	}
+}
+
+func TestOrderService_PublishesEvents(t *testing.T) {
+	publisher := &mockPublisher{}
+	o := newTestOrderService(func(o *orderTest) { o.publisher = publisher })
+	service, slotRepo, invRepo, prodRepo := o.service, o.slotRepo, o.invRepo, o.prodRepo
+	ctx := context.Background()
+
+	slotRepo.Create(ctx, &models.SalesSlot{ID: types.ID("slot1"), IsActive: true})
+	prodRepo.Create(ctx, &models.Product{ID: types.ID("prod1"), Name: "焼きそば", Price: 400})
+	invRepo.Create(ctx, &models.ProductInventory{
+		ID:              types.ID("inv1"),
+		SalesSlotID:     types.ID("slot1"),
+		ProductID:       types.ID("prod1"),
+		InitialQuantity: 10,
+	})
+
+	items := []OrderItemInput{{ProductID: types.ID("prod1"), Quantity: 2}}
+	order, err := service.CreateOrder(ctx, types.ID("slot1"), items, "TICKET001", types.CASH)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	if err := service.UpdateOrderStatus(ctx, order.ID, types.CONFIRMED); err != nil {
+		t.Fatalf("UpdateOrderStatus failed: %v", err)
+	}
+
+	expected := []events.Type{
+		events.OrderCreated,
+		events.InventoryChanged,
+		events.OrderConfirmed,
+		events.InventoryChanged,
+	}
+	got := publisher.types()
+	if len(got) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Expected event %d to be %s, got %s", i, expected[i], got[i])
+		}
+	}
+
+	last := publisher.events[len(publisher.events)-1]
+	if last.SalesSlotID != types.ID("slot1") {
+		t.Errorf("Expected event for slot1, got %s", last.SalesSlotID)
+	}
+	payload := last.Data.(events.InventoryPayload)
+	if payload.SoldQuantity != 2 || payload.AvailableQuantity != 8 {
+		t.Errorf("Expected sold 2 and available 8, got sold %d and available %d", payload.SoldQuantity, payload.AvailableQuantity)
+	}
+
+	if err := service.UpdateOrderStatus(ctx, order.ID, types.CANCELLED); err == nil {
+		t.Error("Expected cancelling a confirmed order to fail")
+	}
+	if len(publisher.types()) != len(expected) {
+		t.Error("Expected no event for a rejected status change")
+	}
+}
+
+func TestOrderService_KitchenWorkflow(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 9, 12, 11, 0, 0, 0, time.Local)}
+	o := newTestOrderService(func(o *orderTest) { o.clock = clock })
+	service, slotRepo, invRepo, prodRepo := o.service, o.slotRepo, o.invRepo, o.prodRepo
+	ctx := context.Background()
+
+	slotRepo.Create(ctx, &models.SalesSlot{ID: types.ID("slot1"), IsActive: true})
+	prodRepo.Create(ctx, &models.Product{ID: types.ID("prod1"), Name: "焼きそば", Price: 400})
+	invRepo.Create(ctx, &models.ProductInventory{
+		ID:              types.ID("inv1"),
+		SalesSlotID:     types.ID("slot1"),
+		ProductID:       types.ID("prod1"),
+		InitialQuantity: 10,
+	})
+
+	items := []OrderItemInput{{ProductID: types.ID("prod1"), Quantity: 1}}
+	first, _ := service.CreateOrder(ctx, types.ID("slot1"), items, "TICKET001", types.CASH)
+	second, _ := service.CreateOrder(ctx, types.ID("slot1"), items, "TICKET002", types.CASH)
+
+	if err := service.UpdateOrderStatus(ctx, first.ID, types.PREPARING); !errors.Is(err, ErrInvalidOrderStatus) {
+		t.Errorf("Expected ErrInvalidOrderStatus for a reserved order, got %v", err)
+	}
+
+	service.UpdateOrderStatus(ctx, second.ID, types.CONFIRMED)
+	clock.now = clock.now.Add(time.Minute)
+	service.UpdateOrderStatus(ctx, first.ID, types.CONFIRMED)
+
+	queue, err := service.GetKitchenQueue(ctx, types.ID("slot1"))
+	if err != nil {
+		t.Fatalf("GetKitchenQueue failed: %v", err)
+	}
+	if len(queue) != 2 || queue[0].ID != second.ID {
+		t.Fatalf("Expected the first confirmed order at the head of the queue, got %+v", queue)
+	}
+
+	steps := []types.OrderStatus{types.PREPARING, types.READY}
+	for _, status := range steps {
+		clock.now = clock.now.Add(time.Minute)
+		if err := service.UpdateOrderStatus(ctx, second.ID, status); err != nil {
+			t.Fatalf("UpdateOrderStatus(%v) failed: %v", status, err)
+		}
+	}
+	if err := service.UpdateDeliveryStatus(ctx, second.ID); err != nil {
+		t.Fatalf("UpdateDeliveryStatus failed: %v", err)
+	}
+
+	order, _ := service.GetOrder(ctx, second.ID)
+	if order.Status != types.DELIVERED || !order.IsDelivered {
+		t.Errorf("Expected a delivered order, got %v", order.Status)
+	}
+	if order.ReadyAt == nil || !order.ReadyAt.Equal(clock.now) {
+		t.Errorf("Expected ready time %v, got %v", clock.now, order.ReadyAt)
+	}
+	if order.PreparingAt == nil || !order.PreparingAt.Before(*order.ReadyAt) {
+		t.Errorf("Expected preparing time before ready time, got %v", order.PreparingAt)
+	}
+
+	if err := service.UpdateDeliveryStatus(ctx, first.ID); !errors.Is(err, ErrDeliveryNotAllowed) {
+		t.Errorf("Expected ErrDeliveryNotAllowed for an order that is not ready, got %v", err)
+	}
+
+	queue, _ = service.GetKitchenQueue(ctx, types.ID("slot1"))
+	if len(queue) != 1 || queue[0].ID != first.ID {
+		t.Errorf("Expected only the remaining confirmed order in the queue, got %+v", queue)
+	}
+}
+
+func TestOrderService_CreateOrder_TicketNumbers(t *testing.T) {
+	o := newTestOrderService()
+	service, slotRepo, invRepo, prodRepo := o.service, o.slotRepo, o.invRepo, o.prodRepo
+	ctx := context.Background()
+
+	prodRepo.Create(ctx, &models.Product{ID: types.ID("prod1"), Name: "焼きそば", Price: 400})
+	for _, slot := range []*models.SalesSlot{
+		{ID: types.ID("slot1"), IsActive: true, TicketPrefix: "A"},
+		{ID: types.ID("slot2"), IsActive: true, TicketPrefix: "B"},
+	} {
+		slotRepo.Create(ctx, slot)
+		invRepo.Create(ctx, &models.ProductInventory{
+			ID:              types.ID("inv-" + string(slot.ID)),
+			SalesSlotID:     slot.ID,
+			ProductID:       types.ID("prod1"),
+			InitialQuantity: 100,
+		})
+	}
+	items := []OrderItemInput{{ProductID: types.ID("prod1"), Quantity: 1}}
+
+	first, err := service.CreateOrder(ctx, types.ID("slot1"), items, "", types.CASH)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	if first.TicketNumber != "A001" {
+		t.Errorf("Expected ticket number A001, got %s", first.TicketNumber)
+	}
+
+	// A pre-printed paper ticket takes the next number, which is then skipped.
+	if _, err := service.CreateOrder(ctx, types.ID("slot1"), items, "A002", types.CASH); err != nil {
+		t.Fatalf("CreateOrder with manual ticket number failed: %v", err)
+	}
+	third, _ := service.CreateOrder(ctx, types.ID("slot1"), items, "", types.CASH)
+	if third.TicketNumber != "A003" {
+		t.Errorf("Expected ticket number A003, got %s", third.TicketNumber)
+	}
+
+	if _, err := service.CreateOrder(ctx, types.ID("slot1"), items, "A001", types.CASH); !errors.Is(err, ErrDuplicateTicketNumber) {
+		t.Errorf("Expected ErrDuplicateTicketNumber, got %v", err)
+	}
+	inventory, _ := invRepo.FindByID(ctx, types.ID("inv-slot1"))
+	if inventory.ReservedQuantity != 3 {
+		t.Errorf("Expected the rejected order to release its reservation, got reserved quantity %d", inventory.ReservedQuantity)
+	}
+
+	// Each slot has its own sequence, and numbers may repeat across slots.
+	other, _ := service.CreateOrder(ctx, types.ID("slot2"), items, "", types.CASH)
+	if other.TicketNumber != "B001" {
+		t.Errorf("Expected ticket number B001, got %s", other.TicketNumber)
+	}
+	if _, err := service.CreateOrder(ctx, types.ID("slot2"), items, "A001", types.CASH); err != nil {
+		t.Errorf("Expected A001 to be accepted in another slot, got %v", err)
+	}
+}
+
+// racingOrderRepository misses the ticket numbers taken by orders that are
+// still being created, as another transaction would.
+type racingOrderRepository struct {
+	*mockOrderRepository
+}
+
+func (r *racingOrderRepository) TicketNumberExists(ctx context.Context, salesSlotID types.ID, ticketNumber string) (bool, error) {
+	return false, nil
+}
+
+func TestOrderService_CreateOrder_DuplicateTicketNumberRace(t *testing.T) {
+	o := newTestOrderService(func(o *orderTest) { o.orders = &racingOrderRepository{o.orderRepo} })
+	service, slotRepo, invRepo, prodRepo := o.service, o.slotRepo, o.invRepo, o.prodRepo
+	ctx := context.Background()
+
+	prodRepo.Create(ctx, &models.Product{ID: types.ID("prod1"), Name: "焼きそば", Price: 400})
+	slotRepo.Create(ctx, &models.SalesSlot{ID: types.ID("slot1"), IsActive: true, TicketPrefix: "A"})
+	invRepo.Create(ctx, &models.ProductInventory{ID: types.ID("inv1"), SalesSlotID: types.ID("slot1"), ProductID: types.ID("prod1"), InitialQuantity: 10})
+	items := []OrderItemInput{{ProductID: types.ID("prod1"), Quantity: 1}}
+
+	if _, err := service.CreateOrder(ctx, types.ID("slot1"), items, "A050", types.CASH); err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	if _, err := service.CreateOrder(ctx, types.ID("slot1"), items, "A050", types.CASH); !errors.Is(err, ErrDuplicateTicketNumber) {
+		t.Errorf("Expected the unique index violation as ErrDuplicateTicketNumber, got %v", err)
+	}
+}
+
+func TestOrderService_CreateOrder_RollsBackReservation(t *testing.T) {
+	o := newTestOrderService(func(o *orderTest) {
+		o.orders = &racingOrderRepository{o.orderRepo}
+		o.transactor = newRollbackTransactor(o.orderRepo, o.invRepo)
+	})
+	service, orderRepo, slotRepo, invRepo, prodRepo := o.service, o.orderRepo, o.slotRepo, o.invRepo, o.prodRepo
+	ctx := context.Background()
+
+	prodRepo.Create(ctx, &models.Product{ID: types.ID("prod1"), Name: "焼きそば", Price: 400})
+	slotRepo.Create(ctx, &models.SalesSlot{ID: types.ID("slot1"), IsActive: true, TicketPrefix: "A"})
+	invRepo.Create(ctx, &models.ProductInventory{ID: types.ID("inv1"), SalesSlotID: types.ID("slot1"), ProductID: types.ID("prod1"), InitialQuantity: 10})
+
+	if _, err := service.CreateOrder(ctx, types.ID("slot1"), []OrderItemInput{{ProductID: types.ID("prod1"), Quantity: 1}}, "A050", types.CASH); err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	// The stock is reserved before the order is inserted and found to clash.
+	if _, err := service.CreateOrder(ctx, types.ID("slot1"), []OrderItemInput{{ProductID: types.ID("prod1"), Quantity: 3}}, "A050", types.CASH); !errors.Is(err, ErrDuplicateTicketNumber) {
+		t.Fatalf("Expected ErrDuplicateTicketNumber, got %v", err)
+	}
+	inventory, _ := invRepo.FindByID(ctx, types.ID("inv1"))
+	if inventory.ReservedQuantity != 1 {
+		t.Errorf("Expected the failed order's reservation to be rolled back, got reserved quantity %d", inventory.ReservedQuantity)
+	}
+	if orders, _ := orderRepo.FindAll(ctx); len(orders) != 1 {
+		t.Errorf("Expected only the first order, got %d", len(orders))
+	}
+}
+
+func TestOrderService_CreateOrder_TicketNumbersConcurrent(t *testing.T) {
+	service, _, slot, product := setupConcurrencyTest(t, 100)
+	ctx := context.Background()
+
+	const terminals = 20
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+
+	for i := 0; i < terminals; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			items := []OrderItemInput{{ProductID: product.ID, Quantity: 1}}
+			order, err := service.CreateOrder(ctx, slot.ID, items, "", types.CASH)
+			if err != nil {
+				t.Errorf("CreateOrder failed: %v", err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if seen[order.TicketNumber] {
+				t.Errorf("Ticket number %s was handed out twice", order.TicketNumber)
+			}
+			seen[order.TicketNumber] = true
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != terminals {
+		t.Errorf("Expected %d distinct ticket numbers, got %d", terminals, len(seen))
+	}
+}
+
+func TestOrderService_StampsTerminalAndStaff(t *testing.T) {
+	service, _, slot, product := setupConcurrencyTest(t, 10)
+	cashier := &models.Staff{ID: types.ID("cashier1"), Role: types.CASHIER}
+	register1 := &models.Terminal{ID: types.ID("register1")}
+	register2 := &models.Terminal{ID: types.ID("register2")}
+
+	ctx := WithTerminal(WithActor(context.Background(), cashier), register1)
+	items := []OrderItemInput{{ProductID: product.ID, Quantity: 1}}
+	order, err := service.CreateOrder(ctx, slot.ID, items, "", types.CASH)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	service.CreateOrder(WithTerminal(context.Background(), register2), slot.ID, items, "", types.CASH)
+
+	if order.TerminalID == nil || *order.TerminalID != register1.ID {
+		t.Errorf("Expected terminal %s, got %v", register1.ID, order.TerminalID)
+	}
+	if order.CreatedByID == nil || *order.CreatedByID != cashier.ID {
+		t.Errorf("Expected cashier %s, got %v", cashier.ID, order.CreatedByID)
+	}
+
+	if err := service.UpdatePaymentStatus(WithTerminal(ctx, register2), order.ID, "TX1"); err != nil {
+		t.Fatalf("UpdatePaymentStatus failed: %v", err)
+	}
+	paid, _ := service.GetOrder(ctx, order.ID)
+	if !paid.IsPaid || paid.PaidAt == nil {
+		t.Errorf("Expected the order to be paid with a payment time, got %+v", paid)
+	}
+	if paid.PaidTerminalID == nil || *paid.PaidTerminalID != register2.ID {
+		t.Errorf("Expected payment at %s, got %v", register2.ID, paid.PaidTerminalID)
+	}
+
+	orders, _ := service.GetAllOrders(ctx, repositories.OrderFilter{TerminalID: register1.ID})
+	if len(orders) != 1 || orders[0].ID != order.ID {
+		t.Errorf("Expected only the order taken at %s, got %d orders", register1.ID, len(orders))
+	}
+	orders, _ = service.GetAllOrders(ctx, repositories.OrderFilter{TerminalID: register2.ID})
+	if len(orders) != 2 {
+		t.Errorf("Expected the orders taken or paid at %s, got %d orders", register2.ID, len(orders))
+	}
+}
+
+func TestOrderService_CreateOrder_WithOptions(t *testing.T) {
+	service, _, slot, product := setupConcurrencyTest(t, 10)
+	ctx := context.Background()
+
+	product.ModifierGroups = []models.ModifierGroup{
+		{ID: "size", Name: "サイズ", MinSelect: 1, MaxSelect: 1, Options: []models.ModifierOption{
+			{ID: "regular", Name: "普通"},
+			{ID: "large", Name: "大盛り", PriceDelta: 100},
+		}},
+		{ID: "topping", Name: "トッピング", MaxSelect: 2, Options: []models.ModifierOption{
+			{ID: "egg", Name: "目玉焼き", PriceDelta: 80},
+			{ID: "cheese", Name: "チーズ", PriceDelta: 50},
+			{ID: "mayo", Name: "マヨネーズ"},
+		}},
+	}
+
+	order, err := service.CreateOrder(ctx, slot.ID, []OrderItemInput{
+		{ProductID: product.ID, Quantity: 1, OptionIDs: []types.ID{"large", "egg"}},
+		{ProductID: product.ID, Quantity: 1, OptionIDs: []types.ID{"egg", "large"}},
+		{ProductID: product.ID, Quantity: 1, OptionIDs: []types.ID{"regular"}},
+	}, "", types.CASH)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	if len(order.Items) != 2 {
+		t.Fatalf("Expected lines with the same options to be merged into 2 lines, got %d", len(order.Items))
+	}
+
+	line := order.Items[0]
+	if line.Quantity != 2 || line.OptionsAmount != 180 || line.Price != product.Price+180 || len(line.Options) != 2 {
+		t.Errorf("Expected 2 units at %d with options worth 180, got %+v", product.Price+180, line)
+	}
+	if order.TotalAmount != 2*(product.Price+180)+product.Price {
+		t.Errorf("Expected total %d, got %d", 2*(product.Price+180)+product.Price, order.TotalAmount)
+	}
+
+	if err := service.AddOrderItems(ctx, order.ID, []OrderItemInput{
+		{ProductID: product.ID, Quantity: 1, OptionIDs: []types.ID{"egg", "large"}},
+	}); err != nil {
+		t.Fatalf("AddOrderItems failed: %v", err)
+	}
+	updated, _ := service.GetOrder(ctx, order.ID)
+	if len(updated.Items) != 2 || updated.Items[0].Quantity != 3 {
+		t.Errorf("Expected the added item to join the line with the same options, got %+v", updated.Items)
+	}
+
+	invalid := [][]types.ID{
+		nil,                                  // size is required
+		{"regular", "large"},                 // one size only
+		{"regular", "egg", "cheese", "mayo"}, // at most two toppings
+		{"regular", "regular"},               // repeated option
+		{"regular", "unknown"},               // not an option of the product
+	}
+	for _, optionIDs := range invalid {
+		_, err := service.CreateOrder(ctx, slot.ID, []OrderItemInput{
+			{ProductID: product.ID, Quantity: 1, OptionIDs: optionIDs},
+		}, "", types.CASH)
+		var serviceErr *ServiceError
+		if !errors.As(err, &serviceErr) {
+			t.Errorf("Expected a service error for options %v, got %v", optionIDs, err)
+		}
+	}
+}
+
+func TestOrderService_CreateOrder_Combo(t *testing.T) {
+	o := newTestOrderService()
+	service, slotRepo, invRepo, prodRepo := o.service, o.slotRepo, o.invRepo, o.prodRepo
+	ctx := context.Background()
+
+	slotRepo.Create(ctx, &models.SalesSlot{ID: "slot1", IsActive: true})
+	prodRepo.Create(ctx, &models.Product{ID: "yakisoba", Name: "焼きそば", Price: 400})
+	prodRepo.Create(ctx, &models.Product{ID: "drink", Name: "お茶", Price: 150})
+	prodRepo.Create(ctx, &models.Product{ID: "set", Name: "焼きそばセット", Price: 500, Components: []models.ComboComponent{
+		{ComboID: "set", ProductID: "yakisoba", Quantity: 1},
+		{ComboID: "set", ProductID: "drink", Quantity: 2},
+	}})
+	invRepo.Create(ctx, &models.ProductInventory{ID: "inv-yakisoba", SalesSlotID: "slot1", ProductID: "yakisoba", InitialQuantity: 10})
+	invRepo.Create(ctx, &models.ProductInventory{ID: "inv-drink", SalesSlotID: "slot1", ProductID: "drink", InitialQuantity: 5})
+
+	order, err := service.CreateOrder(ctx, "slot1", []OrderItemInput{
+		{ProductID: "set", Quantity: 2},
+		{ProductID: "yakisoba", Quantity: 1},
+	}, "", types.CASH)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	if order.TotalAmount != 2*500+400 {
+		t.Errorf("Expected the combo to be charged at its own price, got total %d", order.TotalAmount)
+	}
+	if len(order.Items[0].Components) != 2 {
+		t.Errorf("Expected the combo line to record its components, got %+v", order.Items[0].Components)
+	}
+
+	yakisoba, _ := invRepo.FindByID(ctx, "inv-yakisoba")
+	drink, _ := invRepo.FindByID(ctx, "inv-drink")
+	if yakisoba.ReservedQuantity != 3 || drink.ReservedQuantity != 4 {
+		t.Errorf("Expected 3 yakisoba and 4 drinks reserved, got %d and %d", yakisoba.ReservedQuantity, drink.ReservedQuantity)
+	}
+
+	if err := service.UpdateOrderStatus(ctx, order.ID, types.CONFIRMED); err != nil {
+		t.Fatalf("UpdateOrderStatus failed: %v", err)
+	}
+	yakisoba, _ = invRepo.FindByID(ctx, "inv-yakisoba")
+	drink, _ = invRepo.FindByID(ctx, "inv-drink")
+	if yakisoba.ReservedQuantity != 0 || yakisoba.SoldQuantity != 3 || drink.ReservedQuantity != 0 || drink.SoldQuantity != 4 {
+		t.Errorf("Expected the reservations to become sales, got yakisoba %+v and drink %+v", yakisoba, drink)
+	}
+
+	// Only one drink is left, and a combo needs two.
+	if _, err := service.CreateOrder(ctx, "slot1", []OrderItemInput{{ProductID: "set", Quantity: 1}}, "", types.CASH); !errors.Is(err, ErrInsufficientInventory) {
+		t.Errorf("Expected ErrInsufficientInventory, got %v", err)
+	}
+}
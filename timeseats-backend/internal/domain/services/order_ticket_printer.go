@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
+)
+
+// OrderTicketPrinter prints the kitchen tickets of each order as it is
+// confirmed.
+type OrderTicketPrinter struct {
+	bus      *events.Bus
+	printers PrinterService
+}
+
+func NewOrderTicketPrinter(bus *events.Bus, printers PrinterService) *OrderTicketPrinter {
+	return &OrderTicketPrinter{bus: bus, printers: printers}
+}
+
+// Run prints until ctx is cancelled. If the bus drops the subscription for
+// falling behind, it resubscribes and replays what it missed.
+func (p *OrderTicketPrinter) Run(ctx context.Context) {
+	var lastID uint64
+	for ctx.Err() == nil {
+		sub := p.bus.Subscribe(events.Filter{}, lastID)
+		lastID = p.consume(ctx, sub, lastID)
+		sub.Close()
+	}
+}
+
+// consume handles events until the subscription is closed or ctx is
+// cancelled and returns the ID of the last event seen.
+func (p *OrderTicketPrinter) consume(ctx context.Context, sub *events.Subscription, lastID uint64) uint64 {
+	for {
+		select {
+		case <-ctx.Done():
+			return lastID
+		case event, ok := <-sub.C:
+			if !ok {
+				return lastID
+			}
+			lastID = event.ID
+			p.handle(ctx, event)
+		}
+	}
+}
+
+func (p *OrderTicketPrinter) handle(ctx context.Context, event events.Event) {
+	switch event.Type {
+	case events.Resync:
+		log.Printf("order ticket printer fell behind; tickets of some confirmed orders may not have printed")
+	case events.OrderConfirmed:
+		payload, ok := event.Data.(events.OrderPayload)
+		if !ok {
+			return
+		}
+		if _, err := p.printers.PrintKitchenTickets(ctx, payload.OrderID, false); err != nil {
+			log.Printf("failed to print the ticket of order %s: %v", payload.TicketNumber, err)
+		}
+	}
+}
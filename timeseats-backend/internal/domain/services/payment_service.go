@@ -0,0 +1,723 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+)
+
+// GatewayPayment is a payment as the provider reports it. OrderID is the
+// provider's reference field, which is set to the order's ID.
+type GatewayPayment struct {
+	ID      string
+	OrderID types.ID
+	Amount  int
+	Status  types.GatewayPaymentStatus
+}
+
+// GatewayPaymentRequest charges Amount yen to SourceID, the card or device
+// token the POS got from the provider's SDK. Retrying with the same
+// IdempotencyKey does not charge twice.
+type GatewayPaymentRequest struct {
+	OrderID        types.ID
+	Amount         int
+	SourceID       string
+	IdempotencyKey string
+}
+
+// GatewayError is a failure of a payment provider's API, wrapped so callers
+// need not know the provider. Declined is set when the provider refused the
+// customer's card rather than failing or rejecting the request.
+type GatewayError struct {
+	Declined bool
+	Err      error
+}
+
+func (e *GatewayError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *GatewayError) Unwrap() error {
+	return e.Err
+}
+
+// gatewayError wraps an error returned by a gateway. A provider's error
+// tells that a card was declined with a Declined method.
+func gatewayError(err error) error {
+	var declined interface{ Declined() bool }
+	return &GatewayError{Declined: errors.As(err, &declined) && declined.Declined(), Err: err}
+}
+
+// PaymentGateway takes payments through a card payment provider. An error
+// about a refused card has a Declined method returning true.
+type PaymentGateway interface {
+	CreatePayment(ctx context.Context, request GatewayPaymentRequest) (*GatewayPayment, error)
+	GetPayment(ctx context.Context, paymentID string) (*GatewayPayment, error)
+	// ParseWebhook checks the signature of a webhook notification and
+	// returns the ID of the payment it is about, or "" for notifications
+	// about anything else. It returns ErrInvalidSignature when the
+	// signature does not match.
+	ParseWebhook(body []byte, signature string) (string, error)
+}
+
+// QRCodeRequest asks for a code that takes Amount yen once. Every code needs
+// its own MerchantPaymentID.
+type QRCodeRequest struct {
+	MerchantPaymentID string
+	OrderID           types.ID
+	Amount            int
+	Description       string
+}
+
+// GatewayQRCode is a code as the provider created it.
+type GatewayQRCode struct {
+	CodeID    string
+	Payload   string
+	DeepLink  string
+	ExpiresAt time.Time
+}
+
+// QRCodeGateway takes payments through codes the customer scans with a
+// payment app.
+type QRCodeGateway interface {
+	CreateCode(ctx context.Context, request QRCodeRequest) (*GatewayQRCode, error)
+	// GetCodePayment reports what became of the code with the merchant
+	// payment ID. The status is types.GATEWAY_PAYMENT_PENDING until the customer
+	// has paid, and the payment's OrderID is left empty.
+	GetCodePayment(ctx context.Context, merchantPaymentID string) (*GatewayPayment, error)
+	DeleteCode(ctx context.Context, codeID string) error
+	// ParseWebhook returns the merchant payment ID a notification is about,
+	// or "" for notifications about anything else. Notifications are not
+	// signed, so they are only a reason to ask GetCodePayment.
+	ParseWebhook(body []byte) (string, error)
+}
+
+// qrCodeExpiryGrace is how long after its expiry a code the provider still
+// reports unpaid is kept, in case a payment made at the last moment has not
+// been reported yet.
+const qrCodeExpiryGrace = time.Minute
+
+// PaymentInput is a payment taken at the counter. A zero Amount pays the
+// balance due. Reference is the transaction ID of a payment taken outside
+// this system, such as on a separate PayPay terminal. Tendered is the cash
+// the customer handed over, if the cashier entered it; the change is worked
+// out from it.
+type PaymentInput struct {
+	Method    types.PaymentMethod
+	Amount    int
+	Reference string
+	Tendered  int
+}
+
+type PaymentService interface {
+	// RecordPayment adds a cash or PayPay payment to the order's ledger. An
+	// order may be paid with several payments in different methods, and is
+	// paid once they cover its total. Card payments are recorded only when
+	// Square confirms them.
+	RecordPayment(ctx context.Context, orderID types.ID, input PaymentInput) (*models.Payment, error)
+	// GetPayments returns the order's payments, including pending and
+	// failed card payments, in the order they were taken.
+	GetPayments(ctx context.Context, orderID types.ID) ([]models.Payment, error)
+
+	// CreateCardPayment charges the order's balance due through the gateway.
+	// The payment counts toward the order once the provider reports it
+	// completed, either in its answer or later through a webhook.
+	CreateCardPayment(ctx context.Context, orderID types.ID, sourceID string) (*GatewayPayment, error)
+	// HandleGatewayWebhook verifies a webhook notification and, if it is
+	// about a payment, fetches the payment from the provider and records it
+	// when it has completed or failed.
+	HandleGatewayWebhook(ctx context.Context, body []byte, signature string) error
+
+	// CreateQRCode returns a PayPay code for the order's balance due. The
+	// order's active code is returned again while it is valid for the
+	// current balance; otherwise it is cancelled and a new one created.
+	CreateQRCode(ctx context.Context, orderID types.ID) (*models.QRCode, error)
+	// RefreshQRCode returns the order's latest code after asking PayPay
+	// about it, recording the payment if the code has been paid.
+	RefreshQRCode(ctx context.Context, orderID types.ID) (*models.QRCode, error)
+	// CancelQRCode withdraws the order's active code. It returns
+	// ErrAlreadyPaid if the customer paid before the code was withdrawn.
+	CancelQRCode(ctx context.Context, orderID types.ID) (*models.QRCode, error)
+	HandleQRCodeWebhook(ctx context.Context, body []byte) error
+	// RefreshActiveQRCodes asks PayPay about every active code and returns
+	// how many are no longer active.
+	RefreshActiveQRCodes(ctx context.Context) (int, error)
+}
+
+type paymentService struct {
+	orderRepo   repositories.OrderRepository
+	paymentRepo repositories.PaymentRepository
+	qrCodeRepo  repositories.QRCodeRepository
+	gateway     PaymentGateway
+	qrGateway   QRCodeGateway
+	transactor  repositories.Transactor
+	publisher   events.Publisher
+	clock       Clock
+	audit       AuditLogService
+}
+
+// NewPaymentService returns a service without card payments when gateway
+// is nil, and without QR code payments when qrGateway is.
+func NewPaymentService(
+	orderRepo repositories.OrderRepository,
+	paymentRepo repositories.PaymentRepository,
+	qrCodeRepo repositories.QRCodeRepository,
+	gateway PaymentGateway,
+	qrGateway QRCodeGateway,
+	transactor repositories.Transactor,
+	publisher events.Publisher,
+	clock Clock,
+	audit AuditLogService,
+) PaymentService {
+	return &paymentService{
+		orderRepo:   orderRepo,
+		paymentRepo: paymentRepo,
+		qrCodeRepo:  qrCodeRepo,
+		gateway:     gateway,
+		qrGateway:   qrGateway,
+		transactor:  transactor,
+		publisher:   publisher,
+		clock:       clock,
+		audit:       audit,
+	}
+}
+
+func (s *paymentService) RecordPayment(ctx context.Context, orderID types.ID, input PaymentInput) (*models.Payment, error) {
+	switch input.Method {
+	case types.CASH, types.PAYPAY:
+	case types.SQUARE:
+		return nil, ErrPaymentNotConfirmed
+	default:
+		return nil, ErrInvalidPaymentMethod
+	}
+	if input.Amount < 0 || input.Tendered < 0 {
+		return nil, ErrInvalidPaymentAmount
+	}
+	if input.Tendered > 0 && input.Method != types.CASH {
+		return nil, ErrTenderNotCash
+	}
+
+	payment := &models.Payment{
+		OrderID: orderID,
+		Amount:  input.Amount,
+		Method:  input.Method,
+	}
+	if input.Reference != "" {
+		payment.Reference = &input.Reference
+	}
+
+	var order models.Order
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.orderRepo.FindByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if input.Amount == 0 {
+			payment.Amount = before.BalanceDue()
+		}
+		if input.Tendered > 0 {
+			if input.Tendered < payment.Amount {
+				return ErrInsufficientTender
+			}
+			// No customer hands over more than a 10,000 yen note beyond
+			// what they owe; anything more is a typo.
+			if input.Tendered-payment.Amount > YenDenominations[0] {
+				return ErrInvalidPaymentAmount
+			}
+			payment.TenderedAmount = input.Tendered
+			payment.ChangeAmount = input.Tendered - payment.Amount
+		}
+
+		order = *before
+		if err := recordPayment(ctx, s.orderRepo, s.paymentRepo, &order, payment, s.clock.Now()); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityOrder, order.ID, "pay", before, &order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publisher.Publish(events.NewOrderEvent(paymentEventType(&order), &order))
+	return payment, nil
+}
+
+func (s *paymentService) GetPayments(ctx context.Context, orderID types.ID) ([]models.Payment, error) {
+	if _, err := s.orderRepo.FindByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.paymentRepo.FindByOrderID(ctx, orderID)
+}
+
+// recordPayment completes a payment toward the order, which must be locked,
+// and saves both; a payment not stored yet is created. A payment may not
+// exceed the balance due, and may be zero only for an order with nothing to
+// pay.
+func recordPayment(
+	ctx context.Context,
+	orderRepo repositories.OrderRepository,
+	paymentRepo repositories.PaymentRepository,
+	order *models.Order,
+	payment *models.Payment,
+	at time.Time,
+) error {
+	if order.IsPaid {
+		return ErrAlreadyPaid
+	}
+	if order.Status == types.CANCELLED {
+		return ErrInvalidOrderStatus
+	}
+	if payment.Amount < 0 || (payment.Amount == 0 && order.BalanceDue() > 0) {
+		return ErrInvalidPaymentAmount
+	}
+	if payment.Amount > order.BalanceDue() {
+		return ErrPaymentExceedsBalance
+	}
+
+	staffID, terminalID := actorIDs(ctx)
+	payment.OrderID = order.ID
+	payment.Complete(at, staffID, terminalID)
+	if payment.ID == "" {
+		err := paymentRepo.Create(ctx, payment)
+		if errors.Is(err, repositories.ErrDuplicate) {
+			return ErrDuplicatePayment
+		}
+		if err != nil {
+			return err
+		}
+	} else if err := paymentRepo.Update(ctx, payment); err != nil {
+		return err
+	}
+
+	order.AddPayment(payment)
+	return orderRepo.UpdatePayment(ctx, order)
+}
+
+// paymentEventType tells whether a payment paid the order or only part of
+// it.
+func paymentEventType(order *models.Order) events.Type {
+	if order.IsPaid {
+		return events.OrderPaid
+	}
+	return events.OrderPaymentAdded
+}
+
+func (s *paymentService) CreateCardPayment(ctx context.Context, orderID types.ID, sourceID string) (*GatewayPayment, error) {
+	if s.gateway == nil {
+		return nil, ErrGatewayUnavailable
+	}
+	if sourceID == "" {
+		return nil, ErrPaymentSourceRequired
+	}
+
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPayable(order); err != nil {
+		return nil, err
+	}
+
+	payment, err := s.gateway.CreatePayment(ctx, GatewayPaymentRequest{
+		OrderID:        order.ID,
+		Amount:         order.BalanceDue(),
+		SourceID:       sourceID,
+		IdempotencyKey: uuid.New().String(),
+	})
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	switch payment.Status {
+	case types.GATEWAY_PAYMENT_COMPLETED:
+		err = s.completePayment(ctx, types.SQUARE, payment)
+	case types.GATEWAY_PAYMENT_PENDING:
+		// Kept in the ledger so the webhook can complete it.
+		err = s.addGatewayPayment(ctx, payment, types.PAYMENT_PENDING)
+	default:
+		err = s.addGatewayPayment(ctx, payment, types.PAYMENT_FAILED)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// addGatewayPayment adds a card payment that is not completed to its order's
+// ledger. The order is locked as completePayment does, so a webhook that
+// has recorded the payment meanwhile is seen and its entry left alone.
+func (s *paymentService) addGatewayPayment(ctx context.Context, gatewayPayment *GatewayPayment, status types.PaymentStatus) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.orderRepo.FindByIDForUpdate(ctx, gatewayPayment.OrderID); err != nil {
+			return err
+		}
+
+		_, err := s.paymentRepo.FindByReference(ctx, gatewayPayment.ID)
+		var notFound *repositories.ErrNotFound
+		if !errors.As(err, &notFound) {
+			return err
+		}
+		return s.paymentRepo.Create(ctx, &models.Payment{
+			OrderID:   gatewayPayment.OrderID,
+			Amount:    gatewayPayment.Amount,
+			Method:    types.SQUARE,
+			Reference: &gatewayPayment.ID,
+			Status:    status,
+		})
+	})
+}
+
+// checkPayable rejects payments toward an order that is paid or cancelled.
+func checkPayable(order *models.Order) error {
+	if order.IsPaid {
+		return ErrAlreadyPaid
+	}
+	if order.Status == types.CANCELLED {
+		return ErrInvalidOrderStatus
+	}
+	return nil
+}
+
+func (s *paymentService) HandleGatewayWebhook(ctx context.Context, body []byte, signature string) error {
+	if s.gateway == nil {
+		return ErrGatewayUnavailable
+	}
+
+	paymentID, err := s.gateway.ParseWebhook(body, signature)
+	if err != nil || paymentID == "" {
+		return err
+	}
+
+	// The notification is only a hint; what the provider's API says now is
+	// what counts.
+	payment, err := s.gateway.GetPayment(ctx, paymentID)
+	if err != nil {
+		return gatewayError(err)
+	}
+	if payment.OrderID == "" {
+		return nil
+	}
+
+	switch payment.Status {
+	case types.GATEWAY_PAYMENT_COMPLETED:
+		err = s.completePayment(ctx, types.SQUARE, payment)
+	case types.GATEWAY_PAYMENT_FAILED, types.GATEWAY_PAYMENT_CANCELLED:
+		err = s.failPayment(ctx, payment)
+	default:
+		return nil
+	}
+	var notFound *repositories.ErrNotFound
+	if errors.As(err, &notFound) {
+		// A payment taken outside this system.
+		return nil
+	}
+	return err
+}
+
+// completePayment records a payment the provider has completed in its
+// order's ledger, completing the pending entry if there is one. Doing it
+// twice for the same payment, as when the answer and the webhook both report
+// it, is harmless. A payment the order cannot take is kept in the ledger as
+// unapplied and the reason returned.
+func (s *paymentService) completePayment(ctx context.Context, method types.PaymentMethod, gatewayPayment *GatewayPayment) error {
+	var order models.Order
+	recorded := false
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.orderRepo.FindByIDForUpdate(ctx, gatewayPayment.OrderID)
+		if err != nil {
+			return err
+		}
+
+		payment, err := s.paymentRepo.FindByReference(ctx, gatewayPayment.ID)
+		var notFound *repositories.ErrNotFound
+		switch {
+		case errors.As(err, &notFound):
+			payment = &models.Payment{Method: method, Reference: &gatewayPayment.ID}
+		case err != nil:
+			return err
+		case payment.IsCompleted():
+			return nil
+		}
+		payment.Amount = gatewayPayment.Amount
+
+		order = *before
+		if err := recordPayment(ctx, s.orderRepo, s.paymentRepo, &order, payment, s.clock.Now()); err != nil {
+			return err
+		}
+		recorded = true
+		return s.audit.Record(ctx, AuditEntityOrder, order.ID, "pay", before, &order)
+	})
+	var serviceErr *ServiceError
+	if errors.As(err, &serviceErr) {
+		if keepErr := s.keepUnapplied(ctx, method, gatewayPayment); keepErr != nil {
+			return keepErr
+		}
+		return err
+	}
+	if err != nil || !recorded {
+		return err
+	}
+
+	s.publisher.Publish(events.NewOrderEvent(paymentEventType(&order), &order))
+	return nil
+}
+
+// keepUnapplied records a payment the provider has taken but its order could
+// not, so that the money shows up to be refunded.
+func (s *paymentService) keepUnapplied(ctx context.Context, method types.PaymentMethod, gatewayPayment *GatewayPayment) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		payment, err := s.paymentRepo.FindByReference(ctx, gatewayPayment.ID)
+		var notFound *repositories.ErrNotFound
+		switch {
+		case errors.As(err, &notFound):
+			payment = &models.Payment{
+				OrderID:   gatewayPayment.OrderID,
+				Method:    method,
+				Reference: &gatewayPayment.ID,
+			}
+		case err != nil:
+			return err
+		case payment.Status == types.PAYMENT_UNAPPLIED || payment.IsCompleted():
+			return nil
+		}
+		payment.Amount = gatewayPayment.Amount
+		payment.Status = types.PAYMENT_UNAPPLIED
+
+		if payment.ID == "" {
+			err = s.paymentRepo.Create(ctx, payment)
+		} else {
+			err = s.paymentRepo.Update(ctx, payment)
+		}
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityOrder, payment.OrderID, "unapplied_payment", nil, payment)
+	})
+}
+
+// failPayment marks the pending ledger entry of a payment the provider
+// declined.
+func (s *paymentService) failPayment(ctx context.Context, gatewayPayment *GatewayPayment) error {
+	payment, err := s.paymentRepo.FindByReference(ctx, gatewayPayment.ID)
+	if err != nil {
+		return err
+	}
+	if payment.Status != types.PAYMENT_PENDING {
+		return nil
+	}
+	payment.Status = types.PAYMENT_FAILED
+	return s.paymentRepo.Update(ctx, payment)
+}
+
+func (s *paymentService) CreateQRCode(ctx context.Context, orderID types.ID) (*models.QRCode, error) {
+	if s.qrGateway == nil {
+		return nil, ErrGatewayUnavailable
+	}
+
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPayable(order); err != nil {
+		return nil, err
+	}
+
+	latest, err := s.qrCodeRepo.FindLatestByOrderID(ctx, orderID)
+	var notFound *repositories.ErrNotFound
+	if err != nil && !errors.As(err, &notFound) {
+		return nil, err
+	}
+	if latest != nil && latest.IsActive() {
+		if latest.Amount == order.BalanceDue() && s.clock.Now().Before(latest.ExpiresAt) {
+			return latest, nil
+		}
+		// The balance changed or the code ran out; make sure it was not paid
+		// before replacing it.
+		if err := s.withdrawCode(ctx, latest); err != nil {
+			return nil, err
+		}
+	}
+
+	merchantPaymentID := uuid.New().String()
+	created, err := s.qrGateway.CreateCode(ctx, QRCodeRequest{
+		MerchantPaymentID: merchantPaymentID,
+		OrderID:           order.ID,
+		Amount:            order.BalanceDue(),
+		Description:       fmt.Sprintf("注文 %s", order.TicketNumber),
+	})
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	code := &models.QRCode{
+		OrderID:           order.ID,
+		MerchantPaymentID: merchantPaymentID,
+		CodeID:            created.CodeID,
+		Payload:           created.Payload,
+		DeepLink:          created.DeepLink,
+		Amount:            order.BalanceDue(),
+		Status:            types.QR_CODE_ACTIVE,
+		ExpiresAt:         created.ExpiresAt,
+	}
+	if err := s.qrCodeRepo.Create(ctx, code); err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+func (s *paymentService) RefreshQRCode(ctx context.Context, orderID types.ID) (*models.QRCode, error) {
+	if s.qrGateway == nil {
+		return nil, ErrGatewayUnavailable
+	}
+
+	code, err := s.qrCodeRepo.FindLatestByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if code.IsActive() {
+		if err := s.refreshCode(ctx, code); err != nil {
+			return nil, err
+		}
+	}
+	return code, nil
+}
+
+func (s *paymentService) CancelQRCode(ctx context.Context, orderID types.ID) (*models.QRCode, error) {
+	if s.qrGateway == nil {
+		return nil, ErrGatewayUnavailable
+	}
+
+	code, err := s.qrCodeRepo.FindLatestByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !code.IsActive() {
+		return nil, ErrQRCodeNotActive
+	}
+	if err := s.withdrawCode(ctx, code); err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+func (s *paymentService) HandleQRCodeWebhook(ctx context.Context, body []byte) error {
+	if s.qrGateway == nil {
+		return ErrGatewayUnavailable
+	}
+
+	merchantPaymentID, err := s.qrGateway.ParseWebhook(body)
+	if err != nil || merchantPaymentID == "" {
+		return err
+	}
+
+	code, err := s.qrCodeRepo.FindByMerchantPaymentID(ctx, merchantPaymentID)
+	var notFound *repositories.ErrNotFound
+	if errors.As(err, &notFound) {
+		// A payment taken outside this system.
+		return nil
+	}
+	if err != nil || !code.IsActive() {
+		return err
+	}
+	return s.refreshCode(ctx, code)
+}
+
+func (s *paymentService) RefreshActiveQRCodes(ctx context.Context) (int, error) {
+	if s.qrGateway == nil {
+		return 0, nil
+	}
+
+	codes, err := s.qrCodeRepo.FindActive(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	var errs []error
+	for i := range codes {
+		if err := s.refreshCode(ctx, &codes[i]); err != nil {
+			errs = append(errs, fmt.Errorf("QR code %s: %w", codes[i].MerchantPaymentID, err))
+		}
+		if !codes[i].IsActive() {
+			settled++
+		}
+	}
+	return settled, errors.Join(errs...)
+}
+
+// withdrawCode deletes an active code at PayPay unless it turns out to have
+// been paid, in which case the payment is recorded and ErrAlreadyPaid
+// returned.
+func (s *paymentService) withdrawCode(ctx context.Context, code *models.QRCode) error {
+	if err := s.refreshCode(ctx, code); err != nil {
+		return err
+	}
+	switch code.Status {
+	case types.QR_CODE_ACTIVE:
+	case types.QR_CODE_COMPLETED:
+		return ErrAlreadyPaid
+	default:
+		return nil
+	}
+
+	if err := s.qrGateway.DeleteCode(ctx, code.CodeID); err != nil {
+		return gatewayError(err)
+	}
+	code.Status = types.QR_CODE_CANCELLED
+	return s.qrCodeRepo.UpdateStatus(ctx, code)
+}
+
+// refreshCode asks PayPay about an active code and saves what became of it.
+// A paid code is recorded in its order's ledger; an unpaid code for an order
+// cancelled in the meantime is withdrawn.
+func (s *paymentService) refreshCode(ctx context.Context, code *models.QRCode) error {
+	payment, err := s.qrGateway.GetCodePayment(ctx, code.MerchantPaymentID)
+	if err != nil {
+		return gatewayError(err)
+	}
+
+	var completeErr error
+	switch payment.Status {
+	case types.GATEWAY_PAYMENT_COMPLETED:
+		payment.OrderID = code.OrderID
+		// The code is paid even if the order cannot take the payment; the
+		// error is passed on once the code is saved, so the money can be
+		// refunded.
+		completeErr = s.completePayment(ctx, types.PAYPAY, payment)
+		code.Status = types.QR_CODE_COMPLETED
+		code.PaymentID = &payment.ID
+	case types.GATEWAY_PAYMENT_EXPIRED:
+		code.Status = types.QR_CODE_EXPIRED
+	case types.GATEWAY_PAYMENT_CANCELLED:
+		code.Status = types.QR_CODE_CANCELLED
+	case types.GATEWAY_PAYMENT_FAILED:
+		code.Status = types.QR_CODE_FAILED
+	default:
+		order, err := s.orderRepo.FindByID(ctx, code.OrderID)
+		if err != nil {
+			return err
+		}
+		if order.Status == types.CANCELLED {
+			if err := s.qrGateway.DeleteCode(ctx, code.CodeID); err != nil {
+				return gatewayError(err)
+			}
+			code.Status = types.QR_CODE_CANCELLED
+		} else if s.clock.Now().After(code.ExpiresAt.Add(qrCodeExpiryGrace)) {
+			code.Status = types.QR_CODE_EXPIRED
+		} else {
+			return nil
+		}
+	}
+
+	if err := s.qrCodeRepo.UpdateStatus(ctx, code); err != nil {
+		return err
+	}
+	return completeErr
+}
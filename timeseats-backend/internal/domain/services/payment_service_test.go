@@ -0,0 +1,872 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// mockGateway holds payments the way a provider would. Payments are created
+// with status, and webhooks name a payment by its ID as the body.
+type mockGateway struct {
+	mu       sync.Mutex
+	status   types.GatewayPaymentStatus
+	amount   int
+	err      error
+	payments map[string]GatewayPayment
+}
+
+// declinedError is a provider's error about a refused card.
+type declinedError struct{}
+
+func (declinedError) Error() string  { return "card declined" }
+func (declinedError) Declined() bool { return true }
+
+func newMockGateway(status types.GatewayPaymentStatus) *mockGateway {
+	return &mockGateway{status: status, payments: make(map[string]GatewayPayment)}
+}
+
+func (g *mockGateway) CreatePayment(ctx context.Context, request GatewayPaymentRequest) (*GatewayPayment, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.err != nil {
+		return nil, g.err
+	}
+	amount := request.Amount
+	if g.amount != 0 {
+		amount = g.amount
+	}
+	payment := GatewayPayment{
+		ID:      fmt.Sprintf("pay-%d", len(g.payments)+1),
+		OrderID: request.OrderID,
+		Amount:  amount,
+		Status:  g.status,
+	}
+	g.payments[payment.ID] = payment
+	return &payment, nil
+}
+
+func (g *mockGateway) GetPayment(ctx context.Context, paymentID string) (*GatewayPayment, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	payment, exists := g.payments[paymentID]
+	if !exists {
+		return nil, errors.New("payment not found")
+	}
+	return &payment, nil
+}
+
+func (g *mockGateway) ParseWebhook(body []byte, signature string) (string, error) {
+	if signature != "valid" {
+		return "", ErrInvalidSignature
+	}
+	return string(body), nil
+}
+
+func (g *mockGateway) complete(paymentID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	payment := g.payments[paymentID]
+	payment.Status = types.GATEWAY_PAYMENT_COMPLETED
+	g.payments[paymentID] = payment
+}
+
+type mockPaymentRepository struct {
+	mu       sync.Mutex
+	payments []*models.Payment
+}
+
+func newMockPaymentRepository() *mockPaymentRepository {
+	return &mockPaymentRepository{}
+}
+
+func (r *mockPaymentRepository) snapshot() func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := make([]models.Payment, len(r.payments))
+	for i, payment := range r.payments {
+		saved[i] = *payment
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.payments = make([]*models.Payment, len(saved))
+		for i := range saved {
+			r.payments[i] = &saved[i]
+		}
+	}
+}
+
+func (r *mockPaymentRepository) Create(ctx context.Context, payment *models.Payment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.payments {
+		if payment.Reference != nil && existing.Reference != nil && *existing.Reference == *payment.Reference && existing.Method == payment.Method {
+			return repositories.ErrDuplicate
+		}
+	}
+	payment.BeforeCreate(nil)
+	stored := *payment
+	r.payments = append(r.payments, &stored)
+	return nil
+}
+
+func (r *mockPaymentRepository) FindByOrderID(ctx context.Context, orderID types.ID) ([]models.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var payments []models.Payment
+	for _, payment := range r.payments {
+		if payment.OrderID == orderID {
+			payments = append(payments, *payment)
+		}
+	}
+	return payments, nil
+}
+
+func (r *mockPaymentRepository) FindByReference(ctx context.Context, reference string) (*models.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, payment := range r.payments {
+		if payment.Reference != nil && *payment.Reference == reference {
+			found := *payment
+			return &found, nil
+		}
+	}
+	return nil, repositories.NewErrNotFound("Payment", types.ID(reference))
+}
+
+func (r *mockPaymentRepository) Update(ctx context.Context, payment *models.Payment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.payments {
+		if stored.ID == payment.ID {
+			stored.Status = payment.Status
+			stored.TakenByID = payment.TakenByID
+			stored.TerminalID = payment.TerminalID
+			stored.CompletedAt = payment.CompletedAt
+			return nil
+		}
+	}
+	return repositories.NewErrNotFound("Payment", payment.ID)
+}
+
+func (r *mockPaymentRepository) AddRefund(ctx context.Context, id types.ID, amount int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.payments {
+		if stored.ID == id {
+			if !stored.IsCompleted() || stored.RefundedAmount+amount > stored.Amount {
+				return repositories.ErrConflict
+			}
+			stored.RefundedAmount += amount
+			return nil
+		}
+	}
+	return repositories.NewErrNotFound("Payment", id)
+}
+
+func (r *mockPaymentRepository) SumPayments(ctx context.Context, terminalID types.ID, method types.PaymentMethod, from, to time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	total := 0
+	for _, payment := range r.payments {
+		if !payment.IsCompleted() || payment.Method != method || !matchesID(payment.TerminalID, terminalID) {
+			continue
+		}
+		if payment.CompletedAt.Before(from) || (!to.IsZero() && !payment.CompletedAt.Before(to)) {
+			continue
+		}
+		total += payment.Amount
+	}
+	return total, nil
+}
+
+func (r *mockPaymentRepository) SumCashTendered(ctx context.Context, terminalID types.ID, from, to time.Time) (*repositories.CashTotals, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var totals repositories.CashTotals
+	for _, payment := range r.payments {
+		if !payment.IsCompleted() || payment.Method != types.CASH || !matchesID(payment.TerminalID, terminalID) {
+			continue
+		}
+		if payment.CompletedAt.Before(from) || (!to.IsZero() && !payment.CompletedAt.Before(to)) {
+			continue
+		}
+		totals.Tendered += payment.TenderedAmount
+		totals.Change += payment.ChangeAmount
+	}
+	return &totals, nil
+}
+
+type mockQRCodeRepository struct {
+	mu    sync.Mutex
+	codes []models.QRCode
+}
+
+func newMockQRCodeRepository() *mockQRCodeRepository {
+	return &mockQRCodeRepository{}
+}
+
+func (r *mockQRCodeRepository) Create(ctx context.Context, code *models.QRCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	code.ID = types.ID(fmt.Sprintf("code-%d", len(r.codes)+1))
+	r.codes = append(r.codes, *code)
+	return nil
+}
+
+func (r *mockQRCodeRepository) FindLatestByOrderID(ctx context.Context, orderID types.ID) (*models.QRCode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.codes) - 1; i >= 0; i-- {
+		if r.codes[i].OrderID == orderID {
+			code := r.codes[i]
+			return &code, nil
+		}
+	}
+	return nil, repositories.NewErrNotFound("QRCode", orderID)
+}
+
+func (r *mockQRCodeRepository) FindByMerchantPaymentID(ctx context.Context, merchantPaymentID string) (*models.QRCode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, code := range r.codes {
+		if code.MerchantPaymentID == merchantPaymentID {
+			return &code, nil
+		}
+	}
+	return nil, repositories.NewErrNotFound("QRCode", types.ID(merchantPaymentID))
+}
+
+func (r *mockQRCodeRepository) FindActive(ctx context.Context) ([]models.QRCode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var codes []models.QRCode
+	for _, code := range r.codes {
+		if code.IsActive() {
+			codes = append(codes, code)
+		}
+	}
+	return codes, nil
+}
+
+func (r *mockQRCodeRepository) UpdateStatus(ctx context.Context, code *models.QRCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.codes {
+		if r.codes[i].ID == code.ID {
+			r.codes[i].Status = code.Status
+			r.codes[i].PaymentID = code.PaymentID
+		}
+	}
+	return nil
+}
+
+// mockQRGateway creates codes valid for five minutes of clock. Their status
+// is what the customer did with them; webhooks name a code by its merchant
+// payment ID as the body.
+type mockQRGateway struct {
+	mu      sync.Mutex
+	clock   *fakeClock
+	amounts map[string]int
+	status  map[string]types.GatewayPaymentStatus
+	deleted map[string]bool
+}
+
+func newMockQRGateway(clock *fakeClock) *mockQRGateway {
+	return &mockQRGateway{
+		clock:   clock,
+		amounts: make(map[string]int),
+		status:  make(map[string]types.GatewayPaymentStatus),
+		deleted: make(map[string]bool),
+	}
+}
+
+func (g *mockQRGateway) CreateCode(ctx context.Context, request QRCodeRequest) (*GatewayQRCode, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.amounts[request.MerchantPaymentID] = request.Amount
+	g.status[request.MerchantPaymentID] = types.GATEWAY_PAYMENT_PENDING
+	return &GatewayQRCode{
+		CodeID:    "code-" + request.MerchantPaymentID,
+		Payload:   "https://qr.paypay.ne.jp/" + request.MerchantPaymentID,
+		ExpiresAt: g.clock.Now().Add(5 * time.Minute),
+	}, nil
+}
+
+func (g *mockQRGateway) GetCodePayment(ctx context.Context, merchantPaymentID string) (*GatewayPayment, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	payment := &GatewayPayment{Status: g.status[merchantPaymentID]}
+	if payment.Status == types.GATEWAY_PAYMENT_COMPLETED {
+		payment.ID = "paypay-" + merchantPaymentID
+		payment.Amount = g.amounts[merchantPaymentID]
+	}
+	return payment, nil
+}
+
+func (g *mockQRGateway) DeleteCode(ctx context.Context, codeID string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.deleted[codeID] = true
+	return nil
+}
+
+func (g *mockQRGateway) ParseWebhook(body []byte) (string, error) {
+	return string(body), nil
+}
+
+func (g *mockQRGateway) set(merchantPaymentID string, status types.GatewayPaymentStatus) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.status[merchantPaymentID] = status
+}
+
+func setupQRCodeTest(t *testing.T) (PaymentService, OrderService, *mockQRGateway, *fakeClock, *models.Order) {
+	t.Helper()
+	clock := &fakeClock{now: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)}
+	gateway := newMockQRGateway(clock)
+	service, orders, _, order := setupPaymentTest(t, nil, gateway, types.PAYPAY)
+	service.(*paymentService).clock = clock
+	return service, orders, gateway, clock, order
+}
+
+func setupPaymentTest(t *testing.T, gateway PaymentGateway, qrGateway QRCodeGateway, method types.PaymentMethod) (PaymentService, OrderService, *mockPublisher, *models.Order) {
+	t.Helper()
+	orders, _, slot, product := setupConcurrencyTest(t, 10)
+	publisher := &mockPublisher{}
+	service := NewPaymentService(orders.(*orderService).orderRepo, orders.(*orderService).paymentRepo, newMockQRCodeRepository(), gateway, qrGateway, &mockTransactor{}, publisher, NewSystemClock(), newTestAuditLogService())
+
+	order, err := orders.CreateOrder(context.Background(), slot.ID, []OrderItemInput{{ProductID: product.ID, Quantity: 2}}, "", method)
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+	return service, orders, publisher, order
+}
+
+func TestOrderService_CancelOrder_PartlyPaid(t *testing.T) {
+	service, orders, _, order := setupPaymentTest(t, nil, nil, types.CASH)
+	ctx := context.Background()
+
+	if _, err := service.RecordPayment(ctx, order.ID, PaymentInput{Method: types.CASH, Amount: 300}); err != nil {
+		t.Fatalf("RecordPayment failed: %v", err)
+	}
+	// The cash taken has to be refunded rather than dropped with the order.
+	if err := orders.CancelOrder(ctx, order.ID, "客都合"); !errors.Is(err, ErrOrderPartiallyPaid) {
+		t.Errorf("Expected ErrOrderPartiallyPaid, got %v", err)
+	}
+}
+
+func TestPaymentService_RecordPayment_DuplicateReference(t *testing.T) {
+	service, _, _, order := setupPaymentTest(t, nil, nil, types.PAYPAY)
+	ctx := context.Background()
+
+	if _, err := service.RecordPayment(ctx, order.ID, PaymentInput{Method: types.PAYPAY, Amount: 300, Reference: "tx-9"}); err != nil {
+		t.Fatalf("RecordPayment failed: %v", err)
+	}
+	if _, err := service.RecordPayment(ctx, order.ID, PaymentInput{Method: types.PAYPAY, Amount: 300, Reference: "tx-9"}); !errors.Is(err, ErrDuplicatePayment) {
+		t.Errorf("Expected ErrDuplicatePayment, got %v", err)
+	}
+}
+
+func TestPaymentService_RecordPayment_SplitTender(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)}
+	gateway := newMockQRGateway(clock)
+	service, orders, publisher, order := setupPaymentTest(t, nil, gateway, types.PAYPAY)
+	service.(*paymentService).clock = clock
+	ctx := WithTerminal(WithActor(context.Background(), &models.Staff{ID: "cashier-1", Role: types.CASHIER}), &models.Terminal{ID: "terminal-1"})
+
+	if _, err := service.RecordPayment(ctx, order.ID, PaymentInput{Method: types.SQUARE, Amount: 300}); !errors.Is(err, ErrPaymentNotConfirmed) {
+		t.Errorf("Expected ErrPaymentNotConfirmed for a card payment, got %v", err)
+	}
+	cash, err := service.RecordPayment(ctx, order.ID, PaymentInput{Method: types.CASH, Amount: 300})
+	if err != nil {
+		t.Fatalf("RecordPayment failed: %v", err)
+	}
+	if !cash.IsCompleted() || cash.TerminalID == nil || *cash.TerminalID != "terminal-1" {
+		t.Errorf("Expected a completed cash payment at the terminal, got %+v", cash)
+	}
+	partly, _ := orders.GetOrder(ctx, order.ID)
+	if partly.IsPaid || partly.PaidAmount != 300 || partly.BalanceDue() != 500 {
+		t.Errorf("Expected 500 left to pay, got paid %d of %d", partly.PaidAmount, partly.TotalAmount)
+	}
+	if got := publisher.types(); len(got) != 1 || got[0] != events.OrderPaymentAdded {
+		t.Errorf("Expected one order.payment_added event, got %v", got)
+	}
+
+	if err := orders.AddOrderItems(ctx, order.ID, []OrderItemInput{{ProductID: "prod1", Quantity: 1}}); !errors.Is(err, ErrOrderPartiallyPaid) {
+		t.Errorf("Expected ErrOrderPartiallyPaid when adding items, got %v", err)
+	}
+	if _, err := service.RecordPayment(ctx, order.ID, PaymentInput{Method: types.CASH, Amount: 600}); !errors.Is(err, ErrPaymentExceedsBalance) {
+		t.Errorf("Expected ErrPaymentExceedsBalance, got %v", err)
+	}
+
+	// The rest is paid with PayPay.
+	code, err := service.CreateQRCode(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("CreateQRCode failed: %v", err)
+	}
+	if code.Amount != 500 {
+		t.Errorf("Expected a code for the balance of 500, got %d", code.Amount)
+	}
+	gateway.set(code.MerchantPaymentID, types.GATEWAY_PAYMENT_COMPLETED)
+	if _, err := service.RefreshQRCode(ctx, order.ID); err != nil {
+		t.Fatalf("RefreshQRCode failed: %v", err)
+	}
+
+	paid, _ := orders.GetOrder(ctx, order.ID)
+	if !paid.IsPaid || paid.PaidAmount != 800 || paid.BalanceDue() != 0 {
+		t.Errorf("Expected the order to be paid, got paid %d of %d", paid.PaidAmount, paid.TotalAmount)
+	}
+	if got := publisher.types(); len(got) != 2 || got[1] != events.OrderPaid {
+		t.Errorf("Expected order.paid once the balance was paid, got %v", got)
+	}
+	payments, err := service.GetPayments(ctx, order.ID)
+	if err != nil || len(payments) != 2 || payments[0].Method != types.CASH || payments[1].Method != types.PAYPAY || payments[1].Amount != 500 {
+		t.Errorf("Expected a cash and a PayPay payment, got %+v, %v", payments, err)
+	}
+	if _, err := service.RecordPayment(ctx, order.ID, PaymentInput{Method: types.CASH}); !errors.Is(err, ErrAlreadyPaid) {
+		t.Errorf("Expected ErrAlreadyPaid, got %v", err)
+	}
+}
+
+func TestPaymentService_RecordPayment_Tendered(t *testing.T) {
+	service, orders, _, order := setupPaymentTest(t, nil, nil, types.CASH)
+	ctx := WithTerminal(WithActor(context.Background(), &models.Staff{ID: "cashier-1", Role: types.CASHIER}), &models.Terminal{ID: "terminal-1"})
+
+	if _, err := service.RecordPayment(ctx, order.ID, PaymentInput{Method: types.PAYPAY, Tendered: 1000}); !errors.Is(err, ErrTenderNotCash) {
+		t.Errorf("Expected ErrTenderNotCash, got %v", err)
+	}
+	if _, err := service.RecordPayment(ctx, order.ID, PaymentInput{Method: types.CASH, Tendered: 500}); !errors.Is(err, ErrInsufficientTender) {
+		t.Errorf("Expected ErrInsufficientTender below the total, got %v", err)
+	}
+	if _, err := service.RecordPayment(ctx, order.ID, PaymentInput{Method: types.CASH, Tendered: 100000000}); !errors.Is(err, ErrInvalidPaymentAmount) {
+		t.Errorf("Expected ErrInvalidPaymentAmount for a mistyped amount tendered, got %v", err)
+	}
+	if unpaid, _ := orders.GetOrder(ctx, order.ID); unpaid.PaidAmount != 0 {
+		t.Errorf("Expected nothing paid after the rejected tender, got %d", unpaid.PaidAmount)
+	}
+
+	payment, err := service.RecordPayment(ctx, order.ID, PaymentInput{Method: types.CASH, Tendered: 10000})
+	if err != nil {
+		t.Fatalf("RecordPayment failed: %v", err)
+	}
+	if payment.Amount != 800 || payment.TenderedAmount != 10000 || payment.ChangeAmount != 9200 {
+		t.Errorf("Expected 800 paid from 10000 with 9200 change, got %d, %d and %d", payment.Amount, payment.TenderedAmount, payment.ChangeAmount)
+	}
+	if paid, _ := orders.GetOrder(ctx, order.ID); !paid.IsPaid {
+		t.Errorf("Expected the order to be paid")
+	}
+}
+
+func TestChangeDenominations(t *testing.T) {
+	plenty := map[int]int{10000: 5, 5000: 5, 2000: 5, 1000: 20, 500: 20, 100: 50, 50: 50, 10: 50, 5: 50, 1: 50}
+	tests := []struct {
+		name      string
+		change    int
+		available map[int]int
+		want      []models.DrawerCount
+		ok        bool
+	}{
+		{
+			name:      "fewest pieces",
+			change:    2660,
+			available: plenty,
+			want:      []models.DrawerCount{{Denomination: 2000, Quantity: 1}, {Denomination: 500, Quantity: 1}, {Denomination: 100, Quantity: 1}, {Denomination: 50, Quantity: 1}, {Denomination: 10, Quantity: 1}},
+			ok:        true,
+		},
+		{
+			name:      "no change",
+			change:    0,
+			available: nil,
+			ok:        true,
+		},
+		{
+			name:      "out of 500 yen coins",
+			change:    700,
+			available: map[int]int{100: 10},
+			want:      []models.DrawerCount{{Denomination: 100, Quantity: 7}},
+			ok:        true,
+		},
+		{
+			name:      "out of 1000 yen notes",
+			change:    2660,
+			available: map[int]int{1000: 0, 500: 5, 100: 10, 50: 2, 10: 20},
+			want:      []models.DrawerCount{{Denomination: 500, Quantity: 5}, {Denomination: 100, Quantity: 1}, {Denomination: 50, Quantity: 1}, {Denomination: 10, Quantity: 1}},
+			ok:        true,
+		},
+		{
+			name:      "only one 500 yen coin left",
+			change:    1500,
+			available: map[int]int{500: 1, 100: 20},
+			want:      []models.DrawerCount{{Denomination: 500, Quantity: 1}, {Denomination: 100, Quantity: 10}},
+			ok:        true,
+		},
+		{
+			name:      "5000 yen note left unused",
+			change:    6000,
+			available: map[int]int{5000: 1, 2000: 3},
+			want:      []models.DrawerCount{{Denomination: 2000, Quantity: 3}},
+			ok:        true,
+		},
+		{
+			name:      "10000 yen note held back",
+			change:    11000,
+			available: map[int]int{10000: 1, 5000: 1, 2000: 3},
+			want:      []models.DrawerCount{{Denomination: 5000, Quantity: 1}, {Denomination: 2000, Quantity: 3}},
+			ok:        true,
+		},
+		{
+			name:      "huge change",
+			change:    100000000,
+			available: plenty,
+			ok:        false,
+		},
+		{
+			name:      "drawer short",
+			change:    700,
+			available: map[int]int{500: 1, 100: 1},
+			ok:        false,
+		},
+		{
+			name:      "no coins small enough",
+			change:    30,
+			available: map[int]int{50: 10},
+			ok:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ChangeDenominations(tt.change, tt.available)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok %v, got %v", tt.ok, ok)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, got)
+			}
+			for i := range tt.want {
+				if got[i].Denomination != tt.want[i].Denomination || got[i].Quantity != tt.want[i].Quantity {
+					t.Errorf("Expected %v, got %v", tt.want, got)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestPaymentService_CreateCardPayment(t *testing.T) {
+	gateway := newMockGateway(types.GATEWAY_PAYMENT_COMPLETED)
+	service, orders, publisher, order := setupPaymentTest(t, gateway, nil, types.SQUARE)
+	orderID := order.ID
+	ctx := context.Background()
+
+	if _, err := service.CreateCardPayment(ctx, orderID, ""); !errors.Is(err, ErrPaymentSourceRequired) {
+		t.Errorf("Expected ErrPaymentSourceRequired, got %v", err)
+	}
+
+	payment, err := service.CreateCardPayment(ctx, orderID, "cnon:card-ok")
+	if err != nil {
+		t.Fatalf("CreateCardPayment failed: %v", err)
+	}
+	if payment.Amount != 800 || payment.OrderID != orderID {
+		t.Errorf("Expected the order's total to be charged, got %+v", payment)
+	}
+
+	order, _ = orders.GetOrder(ctx, orderID)
+	if !order.IsPaid || order.TransactionID == nil || *order.TransactionID != payment.ID {
+		t.Errorf("Expected the order to be paid by %s, got %+v", payment.ID, order)
+	}
+	if got := publisher.types(); len(got) != 1 || got[0] != events.OrderPaid {
+		t.Errorf("Expected one OrderPaid event, got %v", got)
+	}
+
+	if _, err := service.CreateCardPayment(ctx, orderID, "cnon:card-ok"); !errors.Is(err, ErrAlreadyPaid) {
+		t.Errorf("Expected ErrAlreadyPaid for a second charge, got %v", err)
+	}
+}
+
+func TestPaymentService_CreateCardPayment_Webhook(t *testing.T) {
+	gateway := newMockGateway(types.GATEWAY_PAYMENT_PENDING)
+	service, orders, publisher, order := setupPaymentTest(t, gateway, nil, types.SQUARE)
+	orderID := order.ID
+	ctx := context.Background()
+
+	payment, err := service.CreateCardPayment(ctx, orderID, "cnon:card-ok")
+	if err != nil {
+		t.Fatalf("CreateCardPayment failed: %v", err)
+	}
+	if order, _ := orders.GetOrder(ctx, orderID); order.IsPaid {
+		t.Error("Expected a pending payment to leave the order unpaid")
+	}
+
+	if err := service.HandleGatewayWebhook(ctx, []byte(payment.ID), "forged"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+	// A notification claiming completion before the provider agrees.
+	if err := service.HandleGatewayWebhook(ctx, []byte(payment.ID), "valid"); err != nil {
+		t.Fatalf("HandleGatewayWebhook failed: %v", err)
+	}
+	if order, _ := orders.GetOrder(ctx, orderID); order.IsPaid {
+		t.Error("Expected the order to stay unpaid until the provider reports completion")
+	}
+
+	gateway.complete(payment.ID)
+	for i := 0; i < 2; i++ {
+		if err := service.HandleGatewayWebhook(ctx, []byte(payment.ID), "valid"); err != nil {
+			t.Fatalf("HandleGatewayWebhook failed: %v", err)
+		}
+	}
+	order, _ = orders.GetOrder(ctx, orderID)
+	if !order.IsPaid || *order.TransactionID != payment.ID {
+		t.Errorf("Expected the order to be paid by %s, got %+v", payment.ID, order)
+	}
+	if got := publisher.types(); len(got) != 1 {
+		t.Errorf("Expected a repeated webhook to publish once, got %v", got)
+	}
+
+	// The charge's answer coming in after the webhook leaves its entry be.
+	if err := service.(*paymentService).addGatewayPayment(ctx, payment, types.PAYMENT_PENDING); err != nil {
+		t.Fatalf("addGatewayPayment failed: %v", err)
+	}
+	if payments, _ := service.GetPayments(ctx, orderID); len(payments) != 1 || !payments[0].IsCompleted() {
+		t.Errorf("Expected the one completed payment, got %+v", payments)
+	}
+}
+
+func TestPaymentService_CreateCardPayment_WebhookForCancelledOrder(t *testing.T) {
+	gateway := newMockGateway(types.GATEWAY_PAYMENT_PENDING)
+	service, orders, _, order := setupPaymentTest(t, gateway, nil, types.SQUARE)
+	ctx := context.Background()
+
+	payment, err := service.CreateCardPayment(ctx, order.ID, "cnon:card-ok")
+	if err != nil {
+		t.Fatalf("CreateCardPayment failed: %v", err)
+	}
+	if err := orders.CancelOrder(ctx, order.ID, ""); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+
+	gateway.complete(payment.ID)
+	if err := service.HandleGatewayWebhook(ctx, []byte(payment.ID), "valid"); !errors.Is(err, ErrInvalidOrderStatus) {
+		t.Errorf("Expected ErrInvalidOrderStatus, got %v", err)
+	}
+	payments, _ := service.GetPayments(ctx, order.ID)
+	if len(payments) != 1 || payments[0].Status != types.PAYMENT_UNAPPLIED || payments[0].Amount != 800 {
+		t.Errorf("Expected the pending payment to be kept as unapplied, got %+v", payments)
+	}
+	// A repeated notification leaves it as it is.
+	service.HandleGatewayWebhook(ctx, []byte(payment.ID), "valid")
+	if again, _ := service.GetPayments(ctx, order.ID); len(again) != 1 || again[0].Status != types.PAYMENT_UNAPPLIED {
+		t.Errorf("Expected one unapplied payment, got %+v", again)
+	}
+}
+
+func TestPaymentService_CreateCardPayment_Rejected(t *testing.T) {
+	ctx := context.Background()
+
+	service, orders, _, order := setupPaymentTest(t, nil, nil, types.SQUARE)
+	orderID := order.ID
+	if _, err := service.CreateCardPayment(ctx, orderID, "cnon:card-ok"); !errors.Is(err, ErrGatewayUnavailable) {
+		t.Errorf("Expected ErrGatewayUnavailable without a gateway, got %v", err)
+	}
+	if err := orders.UpdatePaymentStatus(ctx, orderID, "manual"); !errors.Is(err, ErrPaymentNotConfirmed) {
+		t.Errorf("Expected ErrPaymentNotConfirmed when marking a card order paid by hand, got %v", err)
+	}
+
+	gateway := newMockGateway(types.GATEWAY_PAYMENT_COMPLETED)
+	gateway.amount = 1000
+	service, orders, _, order = setupPaymentTest(t, gateway, nil, types.SQUARE)
+	orderID = order.ID
+	if _, err := service.CreateCardPayment(ctx, orderID, "cnon:card-ok"); !errors.Is(err, ErrPaymentExceedsBalance) {
+		t.Errorf("Expected ErrPaymentExceedsBalance, got %v", err)
+	}
+	if order, _ := orders.GetOrder(ctx, orderID); order.IsPaid || order.PaidAmount != 0 {
+		t.Error("Expected a payment over the balance to leave the order unpaid")
+	}
+	// The card was charged all the same, so the payment stays on record.
+	if payments, _ := service.GetPayments(ctx, orderID); len(payments) != 1 || payments[0].Status != types.PAYMENT_UNAPPLIED || payments[0].Amount != 1000 {
+		t.Errorf("Expected the charge to be kept as an unapplied payment, got %+v", payments)
+	}
+
+	var gatewayErr *GatewayError
+	gateway.err = declinedError{}
+	if _, err := service.CreateCardPayment(ctx, orderID, "cnon:card-declined"); !errors.As(err, &gatewayErr) || !gatewayErr.Declined {
+		t.Errorf("Expected a declined GatewayError, got %v", err)
+	}
+	gateway.err = errors.New("square: HTTP 500")
+	if _, err := service.CreateCardPayment(ctx, orderID, "cnon:card-ok"); !errors.As(err, &gatewayErr) || gatewayErr.Declined {
+		t.Errorf("Expected a GatewayError that is not a decline, got %v", err)
+	}
+}
+
+func TestPaymentService_CreateQRCode(t *testing.T) {
+	service, orders, gateway, clock, order := setupQRCodeTest(t)
+	ctx := context.Background()
+
+	code, err := service.CreateQRCode(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("CreateQRCode failed: %v", err)
+	}
+	if !code.IsActive() || code.Amount != 800 || code.Payload == "" || code.OrderID != order.ID {
+		t.Errorf("Unexpected code: %+v", code)
+	}
+
+	again, err := service.CreateQRCode(ctx, order.ID)
+	if err != nil || again.MerchantPaymentID != code.MerchantPaymentID {
+		t.Errorf("Expected the valid code to be returned again, got %+v, %v", again, err)
+	}
+
+	clock.now = clock.now.Add(6 * time.Minute)
+	replaced, err := service.CreateQRCode(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("CreateQRCode failed: %v", err)
+	}
+	if replaced.MerchantPaymentID == code.MerchantPaymentID || !gateway.deleted[code.CodeID] {
+		t.Errorf("Expected the expired code to be withdrawn and replaced, got %+v", replaced)
+	}
+
+	if refreshed, err := service.RefreshQRCode(ctx, order.ID); err != nil || !refreshed.IsActive() {
+		t.Errorf("Expected the unpaid code to stay active, got %+v, %v", refreshed, err)
+	}
+	if paid, _ := orders.GetOrder(ctx, order.ID); paid.IsPaid {
+		t.Error("Expected the order to stay unpaid")
+	}
+
+	gateway.set(replaced.MerchantPaymentID, types.GATEWAY_PAYMENT_COMPLETED)
+	refreshed, err := service.RefreshQRCode(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("RefreshQRCode failed: %v", err)
+	}
+	if refreshed.Status != types.QR_CODE_COMPLETED || refreshed.PaymentID == nil {
+		t.Errorf("Expected the code to be completed, got %+v", refreshed)
+	}
+	paid, _ := orders.GetOrder(ctx, order.ID)
+	if !paid.IsPaid || *paid.TransactionID != *refreshed.PaymentID {
+		t.Errorf("Expected the order to be paid by %s, got %+v", *refreshed.PaymentID, paid)
+	}
+
+	if _, err := service.CreateQRCode(ctx, order.ID); !errors.Is(err, ErrAlreadyPaid) {
+		t.Errorf("Expected ErrAlreadyPaid, got %v", err)
+	}
+}
+
+func TestPaymentService_CreateQRCode_Rejected(t *testing.T) {
+	ctx := context.Background()
+
+	service, _, _, order := setupPaymentTest(t, nil, nil, types.PAYPAY)
+	if _, err := service.CreateQRCode(ctx, order.ID); !errors.Is(err, ErrGatewayUnavailable) {
+		t.Errorf("Expected ErrGatewayUnavailable without a gateway, got %v", err)
+	}
+
+	service, orders, _, order := setupPaymentTest(t, nil, newMockQRGateway(&fakeClock{}), types.PAYPAY)
+	orders.CancelOrder(ctx, order.ID, "")
+	if _, err := service.CreateQRCode(ctx, order.ID); !errors.Is(err, ErrInvalidOrderStatus) {
+		t.Errorf("Expected ErrInvalidOrderStatus for a cancelled order, got %v", err)
+	}
+}
+
+func TestPaymentService_CancelQRCode(t *testing.T) {
+	service, orders, gateway, _, order := setupQRCodeTest(t)
+	ctx := context.Background()
+
+	code, _ := service.CreateQRCode(ctx, order.ID)
+	cancelled, err := service.CancelQRCode(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("CancelQRCode failed: %v", err)
+	}
+	if cancelled.Status != types.QR_CODE_CANCELLED || !gateway.deleted[code.CodeID] {
+		t.Errorf("Expected the code to be withdrawn, got %+v", cancelled)
+	}
+	if _, err := service.CancelQRCode(ctx, order.ID); !errors.Is(err, ErrQRCodeNotActive) {
+		t.Errorf("Expected ErrQRCodeNotActive, got %v", err)
+	}
+
+	// The customer pays just before the cashier gives up on the code.
+	code, _ = service.CreateQRCode(ctx, order.ID)
+	gateway.set(code.MerchantPaymentID, types.GATEWAY_PAYMENT_COMPLETED)
+	if _, err := service.CancelQRCode(ctx, order.ID); !errors.Is(err, ErrAlreadyPaid) {
+		t.Errorf("Expected ErrAlreadyPaid, got %v", err)
+	}
+	if gateway.deleted[code.CodeID] {
+		t.Error("Expected the paid code not to be deleted")
+	}
+	if paid, _ := orders.GetOrder(ctx, order.ID); !paid.IsPaid {
+		t.Error("Expected the order to be paid")
+	}
+}
+
+func TestPaymentService_HandleQRCodeWebhook(t *testing.T) {
+	service, orders, gateway, _, order := setupQRCodeTest(t)
+	ctx := context.Background()
+
+	code, _ := service.CreateQRCode(ctx, order.ID)
+	if err := service.HandleQRCodeWebhook(ctx, []byte(code.MerchantPaymentID)); err != nil {
+		t.Fatalf("HandleQRCodeWebhook failed: %v", err)
+	}
+	if paid, _ := orders.GetOrder(ctx, order.ID); paid.IsPaid {
+		t.Error("Expected a notification to count only once PayPay reports the payment")
+	}
+
+	gateway.set(code.MerchantPaymentID, types.GATEWAY_PAYMENT_COMPLETED)
+	if err := service.HandleQRCodeWebhook(ctx, []byte(code.MerchantPaymentID)); err != nil {
+		t.Fatalf("HandleQRCodeWebhook failed: %v", err)
+	}
+	if paid, _ := orders.GetOrder(ctx, order.ID); !paid.IsPaid {
+		t.Error("Expected the order to be paid")
+	}
+
+	if err := service.HandleQRCodeWebhook(ctx, []byte("unknown")); err != nil {
+		t.Errorf("Expected notifications about other payments to be ignored, got %v", err)
+	}
+}
+
+func TestPaymentService_RefreshActiveQRCodes(t *testing.T) {
+	service, orders, gateway, clock, order := setupQRCodeTest(t)
+	ctx := context.Background()
+	newOrder := func() *models.Order {
+		created, err := orders.CreateOrder(ctx, order.SalesSlotID, []OrderItemInput{{ProductID: order.Items[0].ProductID, Quantity: 1}}, "", types.PAYPAY)
+		if err != nil {
+			t.Fatalf("CreateOrder failed: %v", err)
+		}
+		return created
+	}
+
+	expired, _ := service.CreateQRCode(ctx, order.ID)
+	gateway.set(expired.MerchantPaymentID, types.GATEWAY_PAYMENT_EXPIRED)
+
+	cancelledOrder := newOrder()
+	withdrawn, _ := service.CreateQRCode(ctx, cancelledOrder.ID)
+	orders.CancelOrder(ctx, cancelledOrder.ID, "お客様都合")
+
+	waiting := newOrder()
+	service.CreateQRCode(ctx, waiting.ID)
+
+	settled, err := service.RefreshActiveQRCodes(ctx)
+	if err != nil || settled != 2 {
+		t.Errorf("Expected 2 codes settled, got %d, %v", settled, err)
+	}
+	if code, _ := service.RefreshQRCode(ctx, order.ID); code.Status != types.QR_CODE_EXPIRED {
+		t.Errorf("Expected the code to be expired, got %s", code.Status)
+	}
+	if code, _ := service.RefreshQRCode(ctx, cancelledOrder.ID); code.Status != types.QR_CODE_CANCELLED || !gateway.deleted[withdrawn.CodeID] {
+		t.Errorf("Expected the cancelled order's code to be withdrawn, got %s", code.Status)
+	}
+
+	// PayPay has not reported the code expired, but its time is up.
+	clock.now = clock.now.Add(5*time.Minute + qrCodeExpiryGrace + time.Second)
+	if settled, err := service.RefreshActiveQRCodes(ctx); err != nil || settled != 1 {
+		t.Errorf("Expected the overdue code to be settled, got %d, %v", settled, err)
+	}
+	if code, _ := service.RefreshQRCode(ctx, waiting.ID); code.Status != types.QR_CODE_EXPIRED {
+		t.Errorf("Expected the overdue code to be expired, got %s", code.Status)
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// PickupBoard lists the ticket numbers of the active sales slots that are
+// still in the kitchen and that can be collected. It is shown to the public
+// and must not carry anything but ticket numbers.
+type PickupBoard struct {
+	Preparing []string
+	Ready     []string
+	UpdatedAt time.Time
+}
+
+type PickupBoardService interface {
+	GetPickupBoard(ctx context.Context) (*PickupBoard, error)
+}
+
+type pickupBoardService struct {
+	orderRepo repositories.OrderRepository
+	slotRepo  repositories.SalesSlotRepository
+	clock     Clock
+}
+
+func NewPickupBoardService(
+	orderRepo repositories.OrderRepository,
+	slotRepo repositories.SalesSlotRepository,
+	clock Clock,
+) PickupBoardService {
+	return &pickupBoardService{
+		orderRepo: orderRepo,
+		slotRepo:  slotRepo,
+		clock:     clock,
+	}
+}
+
+func (s *pickupBoardService) GetPickupBoard(ctx context.Context) (*PickupBoard, error) {
+	slots, err := s.slotRepo.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &PickupBoard{
+		Preparing: []string{},
+		Ready:     []string{},
+		UpdatedAt: s.clock.Now(),
+	}
+	statuses := []types.OrderStatus{types.CONFIRMED, types.PREPARING, types.READY}
+	for _, slot := range slots {
+		orders, err := s.orderRepo.FindBySalesSlotAndStatuses(ctx, slot.ID, statuses)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, order := range orders {
+			if order.Status == types.READY {
+				board.Ready = append(board.Ready, order.TicketNumber)
+			} else {
+				board.Preparing = append(board.Preparing, order.TicketNumber)
+			}
+		}
+	}
+
+	return board, nil
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+func TestPickupBoardService_GetPickupBoard(t *testing.T) {
+	orderRepo := newMockOrderRepository()
+	slotRepo := newMockSalesSlotRepository()
+	ctx := context.Background()
+
+	slot := &models.SalesSlot{ID: types.ID("slot1"), IsActive: true}
+	slotRepo.Create(ctx, slot)
+	slotRepo.Create(ctx, &models.SalesSlot{ID: types.ID("closed"), IsActive: false})
+	board := NewPickupBoardService(orderRepo, slotRepo, NewSystemClock())
+
+	orders := []models.Order{
+		{SalesSlotID: slot.ID, Status: types.RESERVED, TicketNumber: "A001"},
+		{SalesSlotID: slot.ID, Status: types.CONFIRMED, TicketNumber: "A002"},
+		{SalesSlotID: slot.ID, Status: types.PREPARING, TicketNumber: "A003"},
+		{SalesSlotID: slot.ID, Status: types.READY, TicketNumber: "A004"},
+		{SalesSlotID: slot.ID, Status: types.DELIVERED, TicketNumber: "A005"},
+		{SalesSlotID: types.ID("closed"), Status: types.READY, TicketNumber: "Z001"},
+	}
+	for i := range orders {
+		orders[i].CreatedAt = time.Now().Add(time.Duration(i) * time.Second)
+		orderRepo.CreateWithItems(ctx, &orders[i], []models.OrderItem{{ProductID: types.ID("prod1"), Quantity: 1}})
+	}
+
+	result, err := board.GetPickupBoard(ctx)
+	if err != nil {
+		t.Fatalf("GetPickupBoard failed: %v", err)
+	}
+	if len(result.Preparing) != 2 || result.Preparing[0] != "A002" || result.Preparing[1] != "A003" {
+		t.Errorf("Expected A002 and A003 to be preparing, got %v", result.Preparing)
+	}
+	if len(result.Ready) != 1 || result.Ready[0] != "A004" {
+		t.Errorf("Expected only A004 to be ready, got %v", result.Ready)
+	}
+}
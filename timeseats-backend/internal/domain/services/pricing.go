@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// priceOrder works out the amount of each discount line from the items of
+// the order and sets DiscountAmount and TotalAmount. Lines on items come
+// first, then those on the whole order, each in the order they were added
+// and each taken off what the earlier ones left, so a percentage off the
+// order applies to the already discounted items and the total never goes
+// below zero. Amounts are rounded down to whole yen.
+//
+// Each line is also shared out over the items it covers, in proportion to
+// what is left of them, and the shares are kept in the items'
+// DiscountAmount for working out tax and refunds.
+func priceOrder(order *models.Order) {
+	for i := range order.Items {
+		order.Items[i].DiscountAmount = 0
+	}
+
+	lines := make([]*models.OrderDiscount, len(order.Discounts))
+	for i := range order.Discounts {
+		lines[i] = &order.Discounts[i]
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].IsItemLevel() && !lines[j].IsItemLevel()
+	})
+
+	for _, line := range lines {
+		covered := coveredItems(line, order.Items)
+		remaining := 0
+		for _, item := range covered {
+			remaining += item.NetAmount()
+		}
+		line.Amount = min(discountAmount(line, order.Items, remaining), remaining)
+		shareDiscount(line.Amount, covered, remaining)
+	}
+
+	order.DiscountAmount = 0
+	order.TotalAmount = 0
+	for _, item := range order.Items {
+		order.DiscountAmount += item.DiscountAmount
+		order.TotalAmount += item.NetAmount()
+	}
+}
+
+// coveredItems returns the items a discount line is taken off.
+func coveredItems(line *models.OrderDiscount, items []models.OrderItem) []*models.OrderItem {
+	var covered []*models.OrderItem
+	for i := range items {
+		item := &items[i]
+		switch {
+		case line.OrderItemID != nil:
+			if item.ID != *line.OrderItemID {
+				continue
+			}
+		case line.ProductID != nil:
+			if item.ProductID != *line.ProductID {
+				continue
+			}
+		}
+		covered = append(covered, item)
+	}
+	return covered
+}
+
+// shareDiscount adds amount to the DiscountAmount of the items in
+// proportion to what is left of each, remaining in total. The yen lost to
+// rounding go to the last items that still have room for them.
+func shareDiscount(amount int, items []*models.OrderItem, remaining int) {
+	if amount == 0 {
+		return
+	}
+	shares := make([]int, len(items))
+	left := amount
+	for i, item := range items {
+		shares[i] = amount * item.NetAmount() / remaining
+		left -= shares[i]
+	}
+	for i := len(items) - 1; i >= 0 && left > 0; i-- {
+		extra := min(left, items[i].NetAmount()-shares[i])
+		shares[i] += extra
+		left -= extra
+	}
+	for i, item := range items {
+		item.DiscountAmount += shares[i]
+	}
+}
+
+// discountAmount is what the line takes off given the items and what the
+// lines before it left of the items it covers.
+func discountAmount(line *models.OrderDiscount, items []models.OrderItem, remaining int) int {
+	switch line.Type {
+	case types.PRICE_OVERRIDE:
+		for _, item := range items {
+			if line.OrderItemID != nil && item.ID == *line.OrderItemID {
+				return max(item.Price-line.Value, 0) * item.Quantity
+			}
+		}
+		return 0
+
+	case types.FIXED_AMOUNT:
+		if line.ProductID == nil {
+			return line.Value
+		}
+		amount := 0
+		for _, item := range items {
+			if item.ProductID == *line.ProductID {
+				amount += min(line.Value, item.Price) * item.Quantity
+			}
+		}
+		return amount
+
+	case types.PERCENTAGE:
+		return remaining * line.Value / 100
+
+	case types.BUY_X_GET_Y:
+		if line.ProductID == nil || line.BuyQuantity <= 0 || line.FreeQuantity <= 0 {
+			return 0
+		}
+		// The cheapest units go free when lines of the product differ in
+		// price, e.g. because of options.
+		var prices []int
+		for _, item := range items {
+			if item.ProductID == *line.ProductID {
+				for range item.Quantity {
+					prices = append(prices, item.Price)
+				}
+			}
+		}
+		slices.Sort(prices)
+		free := len(prices) / (line.BuyQuantity + line.FreeQuantity) * line.FreeQuantity
+		amount := 0
+		for _, price := range prices[:free] {
+			amount += price
+		}
+		return amount
+	}
+	return 0
+}
+
+// repriceOrder recalculates the discounts of a reserved order after its
+// items or discount lines changed and saves the result.
+func repriceOrder(ctx context.Context, orderRepo repositories.OrderRepository, orderID types.ID) (*models.Order, error) {
+	order, err := orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(order.Discounts) == 0 && order.DiscountAmount == 0 {
+		return order, nil
+	}
+
+	priceOrder(order)
+	err = orderRepo.UpdatePricing(ctx, order, types.RESERVED)
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil, ErrInvalidOrderStatus
+	}
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+)
+
+const defaultPrinterPort = 9100
+
+// KitchenTicket is the part of an order made at one kitchen station.
+type KitchenTicket struct {
+	Station   string
+	Order     *models.Order
+	Items     []models.OrderItem
+	PrintedAt time.Time
+	// IsReprint is set when staff ask for the ticket again, so that the
+	// kitchen does not make the order twice.
+	IsReprint bool
+}
+
+// PrinterStatus is the state of a printer's queue. LastError is cleared
+// once a job gets through.
+type PrinterStatus struct {
+	Pending     int
+	LastError   string
+	LastErrorAt *time.Time
+}
+
+// PrintSpooler renders documents for a printer and queues them. Each
+// printer's jobs are sent in order and retried while it is offline.
+type PrintSpooler interface {
+	PrintKitchenTicket(printer *models.Printer, ticket *KitchenTicket) error
+	PrintReceipt(printer *models.Printer, receipt *ReceiptDocument) error
+	Status(printerID types.ID) PrinterStatus
+}
+
+// PrinterInput describes a printer; see models.Printer for the meaning of
+// the fields. Port defaults to 9100, the usual raw printing port.
+type PrinterInput struct {
+	Name       string
+	Kind       types.PrinterKind
+	Host       string
+	Port       int
+	CategoryID *types.ID
+	TerminalID *types.ID
+}
+
+type PrinterService interface {
+	CreatePrinter(ctx context.Context, input PrinterInput) (*models.Printer, error)
+	GetPrinter(ctx context.Context, id types.ID) (*models.Printer, error)
+	GetAllPrinters(ctx context.Context) ([]models.Printer, error)
+	UpdatePrinter(ctx context.Context, id types.ID, input PrinterInput) (*models.Printer, error)
+	DeletePrinter(ctx context.Context, id types.ID) error
+	PrinterStatus(id types.ID) PrinterStatus
+	// PrintKitchenTickets queues the order's ticket on every kitchen printer
+	// whose station makes one of its items and returns how many were
+	// queued.
+	PrintKitchenTickets(ctx context.Context, orderID types.ID, reprint bool) (int, error)
+	// PrintReceipt issues the order's receipt, or a copy of it, and queues it
+	// on printerID. Without a printer it goes to the receipt printer of the
+	// caller's terminal, or else to the one shared by all counters.
+	PrintReceipt(ctx context.Context, orderID types.ID, printerID *types.ID) (*ReceiptDocument, error)
+}
+
+type printerService struct {
+	printerRepo    repositories.PrinterRepository
+	orderRepo      repositories.OrderRepository
+	categoryRepo   repositories.CategoryRepository
+	terminalRepo   repositories.TerminalRepository
+	receiptService ReceiptService
+	spooler        PrintSpooler
+	transactor     repositories.Transactor
+	clock          Clock
+	audit          AuditLogService
+}
+
+func NewPrinterService(
+	printerRepo repositories.PrinterRepository,
+	orderRepo repositories.OrderRepository,
+	categoryRepo repositories.CategoryRepository,
+	terminalRepo repositories.TerminalRepository,
+	receiptService ReceiptService,
+	spooler PrintSpooler,
+	transactor repositories.Transactor,
+	clock Clock,
+	audit AuditLogService,
+) PrinterService {
+	return &printerService{
+		printerRepo:    printerRepo,
+		orderRepo:      orderRepo,
+		categoryRepo:   categoryRepo,
+		terminalRepo:   terminalRepo,
+		receiptService: receiptService,
+		spooler:        spooler,
+		transactor:     transactor,
+		clock:          clock,
+		audit:          audit,
+	}
+}
+
+func (s *printerService) CreatePrinter(ctx context.Context, input PrinterInput) (*models.Printer, error) {
+	printer := &models.Printer{ID: types.ID(uuid.New().String())}
+	applyPrinterInput(printer, input)
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.validatePrinter(ctx, printer); err != nil {
+			return err
+		}
+		if err := s.printerRepo.Create(ctx, printer); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityPrinter, printer.ID, "create", nil, printer)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.printerRepo.FindByID(ctx, printer.ID)
+}
+
+func (s *printerService) GetPrinter(ctx context.Context, id types.ID) (*models.Printer, error) {
+	return s.printerRepo.FindByID(ctx, id)
+}
+
+func (s *printerService) GetAllPrinters(ctx context.Context) ([]models.Printer, error) {
+	return s.printerRepo.FindAll(ctx)
+}
+
+func (s *printerService) UpdatePrinter(ctx context.Context, id types.ID, input PrinterInput) (*models.Printer, error) {
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.printerRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		after := *before
+		after.Category = nil
+		after.Terminal = nil
+		applyPrinterInput(&after, input)
+		if err := s.validatePrinter(ctx, &after); err != nil {
+			return err
+		}
+		if err := s.printerRepo.Update(ctx, &after); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityPrinter, id, "update", before, &after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.printerRepo.FindByID(ctx, id)
+}
+
+// DeletePrinter leaves jobs already queued for the printer to be sent.
+func (s *printerService) DeletePrinter(ctx context.Context, id types.ID) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.printerRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.printerRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityPrinter, id, "delete", before, nil)
+	})
+}
+
+func (s *printerService) PrinterStatus(id types.ID) PrinterStatus {
+	return s.spooler.Status(id)
+}
+
+func (s *printerService) PrintKitchenTickets(ctx context.Context, orderID types.ID, reprint bool) (int, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return 0, err
+	}
+	if order.Status == types.RESERVED || order.Status == types.CANCELLED {
+		return 0, ErrInvalidOrderStatus
+	}
+
+	printers, err := s.printerRepo.FindByKind(ctx, types.KITCHEN_PRINTER)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.clock.Now()
+	printed := 0
+	for i := range printers {
+		printer := &printers[i]
+		var items []models.OrderItem
+		for _, item := range order.Items {
+			if printer.Prints(&item) {
+				items = append(items, item)
+			}
+		}
+		if len(items) == 0 {
+			continue
+		}
+
+		ticket := &KitchenTicket{
+			Station:   printer.Name,
+			Order:     order,
+			Items:     items,
+			PrintedAt: now,
+			IsReprint: reprint,
+		}
+		if err := s.spooler.PrintKitchenTicket(printer, ticket); err != nil {
+			return printed, err
+		}
+		printed++
+	}
+
+	return printed, nil
+}
+
+func (s *printerService) PrintReceipt(ctx context.Context, orderID types.ID, printerID *types.ID) (*ReceiptDocument, error) {
+	printer, err := s.receiptPrinter(ctx, printerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Enqueueing inside the transaction rolls the issue or reprint back when
+	// the printer cannot take the job, so a receipt that never prints is not
+	// counted as printed.
+	var receipt *ReceiptDocument
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		receipt, err = s.receiptService.PrintReceipt(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		return s.spooler.PrintReceipt(printer, receipt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+func (s *printerService) receiptPrinter(ctx context.Context, printerID *types.ID) (*models.Printer, error) {
+	if printerID != nil {
+		printer, err := s.printerRepo.FindByID(ctx, *printerID)
+		if err != nil {
+			return nil, err
+		}
+		if printer.Kind != types.RECEIPT_PRINTER {
+			return nil, ErrNoReceiptPrinter
+		}
+		return printer, nil
+	}
+
+	printers, err := s.printerRepo.FindByKind(ctx, types.RECEIPT_PRINTER)
+	if err != nil {
+		return nil, err
+	}
+	_, terminalID := actorIDs(ctx)
+	var shared *models.Printer
+	for i := range printers {
+		printer := &printers[i]
+		if printer.TerminalID == nil {
+			if shared == nil {
+				shared = printer
+			}
+			continue
+		}
+		if terminalID != nil && *printer.TerminalID == *terminalID {
+			return printer, nil
+		}
+	}
+	if shared == nil {
+		return nil, ErrNoReceiptPrinter
+	}
+	return shared, nil
+}
+
+func (s *printerService) validatePrinter(ctx context.Context, printer *models.Printer) error {
+	if printer.Name == "" || printer.Host == "" || printer.Port <= 0 || printer.Port > 65535 {
+		return ErrInvalidPrinter
+	}
+
+	switch printer.Kind {
+	case types.KITCHEN_PRINTER:
+		if printer.TerminalID != nil {
+			return ErrInvalidPrinter
+		}
+	case types.RECEIPT_PRINTER:
+		if printer.CategoryID != nil {
+			return ErrInvalidPrinter
+		}
+	default:
+		return ErrInvalidPrinter
+	}
+
+	if printer.CategoryID != nil {
+		if _, err := s.categoryRepo.FindByID(ctx, *printer.CategoryID); err != nil {
+			return err
+		}
+	}
+	if printer.TerminalID != nil {
+		if _, err := s.terminalRepo.FindByID(ctx, *printer.TerminalID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func applyPrinterInput(printer *models.Printer, input PrinterInput) {
+	printer.Name = input.Name
+	printer.Kind = input.Kind
+	printer.Host = input.Host
+	printer.Port = input.Port
+	if printer.Port == 0 {
+		printer.Port = defaultPrinterPort
+	}
+	printer.CategoryID = input.CategoryID
+	printer.TerminalID = input.TerminalID
+}
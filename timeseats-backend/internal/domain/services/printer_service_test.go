@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type mockPrinterRepository struct {
+	mu       sync.Mutex
+	printers map[types.ID]*models.Printer
+}
+
+func newMockPrinterRepository() *mockPrinterRepository {
+	return &mockPrinterRepository{printers: make(map[types.ID]*models.Printer)}
+}
+
+func (r *mockPrinterRepository) Create(ctx context.Context, printer *models.Printer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *printer
+	r.printers[printer.ID] = &stored
+	return nil
+}
+
+func (r *mockPrinterRepository) FindByID(ctx context.Context, id types.ID) (*models.Printer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	printer, exists := r.printers[id]
+	if !exists {
+		return nil, repositories.NewErrNotFound("Printer", id)
+	}
+	found := *printer
+	return &found, nil
+}
+
+func (r *mockPrinterRepository) FindAll(ctx context.Context) ([]models.Printer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var printers []models.Printer
+	for _, printer := range r.printers {
+		printers = append(printers, *printer)
+	}
+	sort.Slice(printers, func(i, j int) bool { return printers[i].Name < printers[j].Name })
+	return printers, nil
+}
+
+func (r *mockPrinterRepository) FindByKind(ctx context.Context, kind types.PrinterKind) ([]models.Printer, error) {
+	printers, _ := r.FindAll(ctx)
+	var found []models.Printer
+	for _, printer := range printers {
+		if printer.Kind == kind {
+			found = append(found, printer)
+		}
+	}
+	return found, nil
+}
+
+func (r *mockPrinterRepository) Update(ctx context.Context, printer *models.Printer) error {
+	return r.Create(ctx, printer)
+}
+
+func (r *mockPrinterRepository) Delete(ctx context.Context, id types.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.printers[id]; !exists {
+		return repositories.NewErrNotFound("Printer", id)
+	}
+	delete(r.printers, id)
+	return nil
+}
+
+type mockSpooler struct {
+	mu       sync.Mutex
+	full     bool
+	tickets  map[types.ID][]*KitchenTicket
+	receipts map[types.ID][]*ReceiptDocument
+}
+
+func newMockSpooler() *mockSpooler {
+	return &mockSpooler{
+		tickets:  make(map[types.ID][]*KitchenTicket),
+		receipts: make(map[types.ID][]*ReceiptDocument),
+	}
+}
+
+func (s *mockSpooler) PrintKitchenTicket(printer *models.Printer, ticket *KitchenTicket) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tickets[printer.ID] = append(s.tickets[printer.ID], ticket)
+	return nil
+}
+
+func (s *mockSpooler) PrintReceipt(printer *models.Printer, receipt *ReceiptDocument) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.full {
+		return ErrPrintQueueFull
+	}
+	s.receipts[printer.ID] = append(s.receipts[printer.ID], receipt)
+	return nil
+}
+
+func (s *mockSpooler) Status(printerID types.ID) PrinterStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return PrinterStatus{Pending: len(s.tickets[printerID]) + len(s.receipts[printerID])}
+}
+
+type printerTest struct {
+	service   PrinterService
+	orderRepo *mockOrderRepository
+	spooler   *mockSpooler
+}
+
+func setupPrinterTest(t *testing.T) printerTest {
+	t.Helper()
+	ctx := context.Background()
+
+	categoryRepo := newMockCategoryRepository()
+	categoryRepo.Create(ctx, &models.Category{ID: "mains", Name: "フード"})
+	categoryRepo.Create(ctx, &models.Category{ID: "drinks", Name: "ドリンク"})
+	terminalRepo := newMockTerminalRepository()
+	terminalRepo.Create(ctx, &models.Terminal{ID: "counter-1", Name: "レジ1"})
+
+	orderRepo := newMockOrderRepository()
+	spooler := newMockSpooler()
+	receiptRepo := newMockReceiptRepository()
+	transactor := newRollbackTransactor(orderRepo, receiptRepo)
+	receipts := NewReceiptService(receiptRepo, orderRepo, transactor, NewSystemClock(), newTestAuditLogService(), ReceiptIssuer{Name: "生徒会"}, nil)
+	service := NewPrinterService(newMockPrinterRepository(), orderRepo, categoryRepo, terminalRepo, receipts, spooler, transactor, NewSystemClock(), newTestAuditLogService())
+
+	return printerTest{service: service, orderRepo: orderRepo, spooler: spooler}
+}
+
+func (pt printerTest) addPrinter(t *testing.T, input PrinterInput) *models.Printer {
+	t.Helper()
+	printer, err := pt.service.CreatePrinter(context.Background(), input)
+	if err != nil {
+		t.Fatalf("CreatePrinter failed: %v", err)
+	}
+	return printer
+}
+
+func TestPrinterService_CreatePrinter(t *testing.T) {
+	pt := setupPrinterTest(t)
+	ctx := context.Background()
+	mains, counter := types.ID("mains"), types.ID("counter-1")
+
+	printer := pt.addPrinter(t, PrinterInput{Name: "焼きそば", Kind: types.KITCHEN_PRINTER, Host: "192.168.1.50", CategoryID: &mains})
+	if printer.Port != 9100 || printer.Address() != "192.168.1.50:9100" {
+		t.Errorf("Expected the default port, got %s", printer.Address())
+	}
+
+	invalid := []PrinterInput{
+		{Kind: types.KITCHEN_PRINTER, Host: "192.168.1.50"},
+		{Name: "焼きそば", Kind: types.KITCHEN_PRINTER},
+		{Name: "焼きそば", Kind: types.KITCHEN_PRINTER, Host: "192.168.1.50", Port: 70000},
+		{Name: "焼きそば", Host: "192.168.1.50"},
+		{Name: "焼きそば", Kind: types.KITCHEN_PRINTER, Host: "192.168.1.50", TerminalID: &counter},
+		{Name: "レジ", Kind: types.RECEIPT_PRINTER, Host: "192.168.1.60", CategoryID: &mains},
+	}
+	for _, input := range invalid {
+		if _, err := pt.service.CreatePrinter(ctx, input); !errors.Is(err, ErrInvalidPrinter) {
+			t.Errorf("Expected ErrInvalidPrinter for %+v, got %v", input, err)
+		}
+	}
+
+	unknown := types.ID("unknown")
+	var notFound *repositories.ErrNotFound
+	_, err := pt.service.CreatePrinter(ctx, PrinterInput{Name: "デザート", Kind: types.KITCHEN_PRINTER, Host: "192.168.1.51", CategoryID: &unknown})
+	if !errors.As(err, &notFound) || notFound.Entity != "Category" {
+		t.Errorf("Expected the category not to be found, got %v", err)
+	}
+}
+
+func TestPrinterService_PrintKitchenTickets(t *testing.T) {
+	pt := setupPrinterTest(t)
+	ctx := context.Background()
+	mains, drinks := types.ID("mains"), types.ID("drinks")
+
+	grill := pt.addPrinter(t, PrinterInput{Name: "焼きそば", Kind: types.KITCHEN_PRINTER, Host: "192.168.1.50", CategoryID: &mains})
+	bar := pt.addPrinter(t, PrinterInput{Name: "ドリンク", Kind: types.KITCHEN_PRINTER, Host: "192.168.1.51", CategoryID: &drinks})
+	expo := pt.addPrinter(t, PrinterInput{Name: "全体", Kind: types.KITCHEN_PRINTER, Host: "192.168.1.52"})
+	pt.addPrinter(t, PrinterInput{Name: "レジ", Kind: types.RECEIPT_PRINTER, Host: "192.168.1.60"})
+
+	// The combo is uncategorized, but its drink is made at the bar.
+	pt.orderRepo.Create(ctx, &models.Order{
+		ID:           "order-1",
+		TicketNumber: "A001",
+		Status:       types.CONFIRMED,
+		Items: []models.OrderItem{
+			{ID: "item1", ProductID: "yakisoba", Quantity: 2, Product: &models.Product{Name: "焼きそば", CategoryID: &mains}},
+			{ID: "item2", ProductID: "combo", Quantity: 1, Product: &models.Product{Name: "セット"}, Components: []models.OrderItemComponent{
+				{ProductID: "cola", Quantity: 1, Product: &models.Product{Name: "コーラ", CategoryID: &drinks}},
+			}},
+		},
+	})
+
+	printed, err := pt.service.PrintKitchenTickets(ctx, "order-1", false)
+	if err != nil {
+		t.Fatalf("PrintKitchenTickets failed: %v", err)
+	}
+	if printed != 3 {
+		t.Errorf("Expected 3 tickets, got %d", printed)
+	}
+
+	expected := map[types.ID][]types.ID{
+		grill.ID: {"item1"},
+		bar.ID:   {"item2"},
+		expo.ID:  {"item1", "item2"},
+	}
+	for printerID, itemIDs := range expected {
+		tickets := pt.spooler.tickets[printerID]
+		if len(tickets) != 1 {
+			t.Fatalf("Expected one ticket on %s, got %d", printerID, len(tickets))
+		}
+		var got []types.ID
+		for _, item := range tickets[0].Items {
+			got = append(got, item.ID)
+		}
+		if len(got) != len(itemIDs) || got[0] != itemIDs[0] {
+			t.Errorf("Expected items %v on %s, got %v", itemIDs, tickets[0].Station, got)
+		}
+		if tickets[0].Order.TicketNumber != "A001" || tickets[0].IsReprint {
+			t.Errorf("Unexpected ticket: %+v", tickets[0])
+		}
+	}
+
+	pt.orderRepo.Create(ctx, &models.Order{ID: "order-2", Status: types.RESERVED})
+	if _, err := pt.service.PrintKitchenTickets(ctx, "order-2", false); !errors.Is(err, ErrInvalidOrderStatus) {
+		t.Errorf("Expected ErrInvalidOrderStatus for a reservation, got %v", err)
+	}
+}
+
+func TestPrinterService_PrintReceipt(t *testing.T) {
+	pt := setupPrinterTest(t)
+	counterID := types.ID("counter-1")
+
+	shared := pt.addPrinter(t, PrinterInput{Name: "レジ共通", Kind: types.RECEIPT_PRINTER, Host: "192.168.1.60"})
+	counter := pt.addPrinter(t, PrinterInput{Name: "レジ1", Kind: types.RECEIPT_PRINTER, Host: "192.168.1.61", TerminalID: &counterID})
+	kitchen := pt.addPrinter(t, PrinterInput{Name: "焼きそば", Kind: types.KITCHEN_PRINTER, Host: "192.168.1.50"})
+
+	pt.orderRepo.Create(context.Background(), &models.Order{ID: "order-1", Status: types.CONFIRMED, IsPaid: true, TotalAmount: 400})
+
+	atCounter := WithTerminal(context.Background(), &models.Terminal{ID: counterID})
+	receipt, err := pt.service.PrintReceipt(atCounter, "order-1", nil)
+	if err != nil {
+		t.Fatalf("PrintReceipt failed: %v", err)
+	}
+	if receipt.IsCopy || len(pt.spooler.receipts[counter.ID]) != 1 {
+		t.Errorf("Expected the original on the counter's printer, got %+v", pt.spooler.receipts)
+	}
+
+	elsewhere := WithTerminal(context.Background(), &models.Terminal{ID: "counter-2"})
+	receipt, err = pt.service.PrintReceipt(elsewhere, "order-1", nil)
+	if err != nil {
+		t.Fatalf("PrintReceipt failed: %v", err)
+	}
+	if !receipt.IsCopy || len(pt.spooler.receipts[shared.ID]) != 1 {
+		t.Errorf("Expected a copy on the shared printer, got %+v", pt.spooler.receipts)
+	}
+
+	if _, err := pt.service.PrintReceipt(atCounter, "order-1", &kitchen.ID); !errors.Is(err, ErrNoReceiptPrinter) {
+		t.Errorf("Expected ErrNoReceiptPrinter for a kitchen printer, got %v", err)
+	}
+	if err := pt.service.DeletePrinter(context.Background(), shared.ID); err != nil {
+		t.Fatalf("DeletePrinter failed: %v", err)
+	}
+	if _, err := pt.service.PrintReceipt(elsewhere, "order-1", nil); !errors.Is(err, ErrNoReceiptPrinter) {
+		t.Errorf("Expected ErrNoReceiptPrinter without a printer for the counter, got %v", err)
+	}
+}
+
+func TestPrinterService_PrintReceipt_QueueFull(t *testing.T) {
+	pt := setupPrinterTest(t)
+	printer := pt.addPrinter(t, PrinterInput{Name: "レジ共通", Kind: types.RECEIPT_PRINTER, Host: "192.168.1.60"})
+	pt.orderRepo.Create(context.Background(), &models.Order{ID: "order-1", Status: types.CONFIRMED, IsPaid: true, TotalAmount: 400})
+	ctx := context.Background()
+
+	pt.spooler.full = true
+	if _, err := pt.service.PrintReceipt(ctx, "order-1", nil); !errors.Is(err, ErrPrintQueueFull) {
+		t.Fatalf("Expected ErrPrintQueueFull, got %v", err)
+	}
+
+	pt.spooler.full = false
+	receipt, err := pt.service.PrintReceipt(ctx, "order-1", nil)
+	if err != nil {
+		t.Fatalf("PrintReceipt failed: %v", err)
+	}
+	if receipt.IsCopy || len(pt.spooler.receipts[printer.ID]) != 1 {
+		t.Errorf("Expected the original once the queue drained, got %+v", receipt)
+	}
+}
+
+func TestOrderTicketPrinter(t *testing.T) {
+	pt := setupPrinterTest(t)
+	kitchen := pt.addPrinter(t, PrinterInput{Name: "焼きそば", Kind: types.KITCHEN_PRINTER, Host: "192.168.1.50"})
+	order := &models.Order{ID: "order-1", TicketNumber: "A001", Status: types.CONFIRMED, Items: []models.OrderItem{{ID: "item1", ProductID: "prod1", Quantity: 1}}}
+	pt.orderRepo.Create(context.Background(), order)
+
+	bus := events.NewBus(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewOrderTicketPrinter(bus, pt.service).Run(ctx)
+		close(done)
+	}()
+
+	// The printer subscribes in the background, so the order is confirmed
+	// until it is listening.
+	deadline := time.Now().Add(2 * time.Second)
+	for pt.spooler.Status(kitchen.ID).Pending == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the ticket to print when the order is confirmed")
+		}
+		bus.Publish(events.NewOrderEvent(events.OrderConfirmed, order))
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	<-done
+	for _, ticket := range pt.spooler.tickets[kitchen.ID] {
+		if ticket.Order.ID != order.ID || ticket.IsReprint {
+			t.Errorf("Unexpected ticket: %+v", ticket)
+		}
+	}
+}
@@ -10,29 +10,69 @@ import (
 )
 
 type ProductService interface {
-	CreateProduct(ctx context.Context, name string, price int) (*models.Product, error)
+	CreateProduct(ctx context.Context, name string, price int, taxRate types.TaxRate, categoryID *types.ID, sortOrder int) (*models.Product, error)
 	GetProduct(ctx context.Context, id types.ID) (*models.Product, error)
+	// GetAllProducts returns products in menu order: grouped by category,
+	// with uncategorized products last.
 	GetAllProducts(ctx context.Context) ([]models.Product, error)
-	UpdateProduct(ctx context.Context, id types.ID, name string, price int) (*models.Product, error)
+	UpdateProduct(ctx context.Context, id types.ID, name string, price int, taxRate types.TaxRate, categoryID *types.ID, sortOrder int) (*models.Product, error)
+	// SetComboComponents makes the product a combo of the given products,
+	// or an ordinary product again when components is empty.
+	SetComboComponents(ctx context.Context, id types.ID, components []ComboComponentInput) (*models.Product, error)
 	DeleteProduct(ctx context.Context, id types.ID) error
 }
 
+type ComboComponentInput struct {
+	ProductID types.ID
+	Quantity  int
+}
+
 type productService struct {
-	repo repositories.ProductRepository
+	repo         repositories.ProductRepository
+	categoryRepo repositories.CategoryRepository
+	transactor   repositories.Transactor
+	audit        AuditLogService
 }
 
-func NewProductService(repo repositories.ProductRepository) ProductService {
-	return &productService{repo: repo}
+func NewProductService(
+	repo repositories.ProductRepository,
+	categoryRepo repositories.CategoryRepository,
+	transactor repositories.Transactor,
+	audit AuditLogService,
+) ProductService {
+	return &productService{
+		repo:         repo,
+		categoryRepo: categoryRepo,
+		transactor:   transactor,
+		audit:        audit,
+	}
 }
 
-func (s *productService) CreateProduct(ctx context.Context, name string, price int) (*models.Product, error) {
+func (s *productService) CreateProduct(ctx context.Context, name string, price int, taxRate types.TaxRate, categoryID *types.ID, sortOrder int) (*models.Product, error) {
+	if !taxRate.IsValid() {
+		return nil, ErrInvalidTaxRate
+	}
 	product := &models.Product{
-		ID:    types.ID(uuid.New().String()),
-		Name:  name,
-		Price: price,
+		ID:         types.ID(uuid.New().String()),
+		Name:       name,
+		Price:      price,
+		TaxRate:    taxRate,
+		CategoryID: categoryID,
+		SortOrder:  sortOrder,
 	}
 
-	if err := s.repo.Create(ctx, product); err != nil {
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		category, err := s.findCategory(ctx, categoryID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Create(ctx, product); err != nil {
+			return err
+		}
+		product.Category = category
+		return s.audit.Record(ctx, AuditEntityProduct, product.ID, "create", nil, product)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -47,16 +87,104 @@ func (s *productService) GetAllProducts(ctx context.Context) ([]models.Product,
 	return s.repo.FindAll(ctx)
 }
 
-func (s *productService) UpdateProduct(ctx context.Context, id types.ID, name string, price int) (*models.Product, error) {
-	product, err := s.repo.FindByID(ctx, id)
+func (s *productService) UpdateProduct(ctx context.Context, id types.ID, name string, price int, taxRate types.TaxRate, categoryID *types.ID, sortOrder int) (*models.Product, error) {
+	if !taxRate.IsValid() {
+		return nil, ErrInvalidTaxRate
+	}
+	var product *models.Product
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		updated := *before
+		updated.Name = name
+		updated.Price = price
+		updated.TaxRate = taxRate
+		updated.CategoryID = categoryID
+		updated.SortOrder = sortOrder
+		updated.Category = nil
+
+		category, err := s.findCategory(ctx, categoryID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Update(ctx, &updated); err != nil {
+			return err
+		}
+		updated.Category = category
+		product = &updated
+
+		return s.audit.Record(ctx, AuditEntityProduct, id, "update", before, product)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	product.Name = name
-	product.Price = price
+	return product, nil
+}
+
+// findCategory checks that a product's category exists. A nil ID means the
+// product is uncategorized.
+func (s *productService) findCategory(ctx context.Context, categoryID *types.ID) (*models.Category, error) {
+	if categoryID == nil {
+		return nil, nil
+	}
+	return s.categoryRepo.FindByID(ctx, *categoryID)
+}
+
+func (s *productService) SetComboComponents(ctx context.Context, id types.ID, components []ComboComponentInput) (*models.Product, error) {
+	var product *models.Product
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
 
-	if err := s.repo.Update(ctx, product); err != nil {
+		// Combos cannot be nested.
+		if len(components) > 0 {
+			isComponent, err := s.repo.IsComponent(ctx, id)
+			if err != nil {
+				return err
+			}
+			if isComponent {
+				return ErrInvalidCombo
+			}
+		}
+
+		seen := make(map[types.ID]bool, len(components))
+		comboComponents := make([]models.ComboComponent, 0, len(components))
+		for _, in := range components {
+			if in.Quantity <= 0 || in.ProductID == id || seen[in.ProductID] {
+				return ErrInvalidCombo
+			}
+			seen[in.ProductID] = true
+
+			component, err := s.repo.FindByID(ctx, in.ProductID)
+			if err != nil {
+				return err
+			}
+			if component.IsCombo() {
+				return ErrInvalidCombo
+			}
+			comboComponents = append(comboComponents, models.ComboComponent{
+				ComboID:   id,
+				ProductID: in.ProductID,
+				Quantity:  in.Quantity,
+			})
+		}
+
+		if err := s.repo.ReplaceComponents(ctx, id, comboComponents); err != nil {
+			return err
+		}
+
+		if product, err = s.repo.FindByID(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityProduct, id, "set_components", before, product)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -64,5 +192,21 @@ func (s *productService) UpdateProduct(ctx context.Context, id types.ID, name st
 }
 
 func (s *productService) DeleteProduct(ctx context.Context, id types.ID) error {
-	return s.repo.Delete(ctx, id)
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		isComponent, err := s.repo.IsComponent(ctx, id)
+		if err != nil {
+			return err
+		}
+		if isComponent {
+			return ErrProductInCombo
+		}
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityProduct, id, "delete", before, nil)
+	})
 }
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
@@ -71,12 +72,45 @@ func (r *mockProductRepository) FindByName(ctx context.Context, name string) (*m
 	}
 }
 
+func (r *mockProductRepository) ClearCategory(ctx context.Context, categoryID types.ID) error {
+	for _, product := range r.products {
+		if product.CategoryID != nil && *product.CategoryID == categoryID {
+			product.CategoryID = nil
+		}
+	}
+	return nil
+}
+
+func (r *mockProductRepository) ReplaceComponents(ctx context.Context, comboID types.ID, components []models.ComboComponent) error {
+	combo, exists := r.products[comboID]
+	if !exists {
+		return repositories.NewErrNotFound("Product", comboID)
+	}
+	combo.Components = nil
+	for _, component := range components {
+		component.Product = r.products[component.ProductID]
+		combo.Components = append(combo.Components, component)
+	}
+	return nil
+}
+
+func (r *mockProductRepository) IsComponent(ctx context.Context, productID types.ID) (bool, error) {
+	for _, product := range r.products {
+		for _, component := range product.Components {
+			if component.ProductID == productID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
 func TestProductService_CreateProduct(t *testing.T) {
 	repo := newMockProductRepository()
-	service := NewProductService(repo)
+	service := NewProductService(repo, newMockCategoryRepository(), &mockTransactor{}, newTestAuditLogService())
 	ctx := context.Background()
 
-	product, err := service.CreateProduct(ctx, "Test Product", 1000)
+	product, err := service.CreateProduct(ctx, "Test Product", 1000, types.REDUCED_TAX_RATE, nil, 0)
 	if err != nil {
 		t.Errorf("CreateProduct failed: %v", err)
 	}
@@ -94,12 +128,20 @@ func TestProductService_CreateProduct(t *testing.T) {
 	}
 }
 
+func TestProductService_CreateProduct_InvalidTaxRate(t *testing.T) {
+	service := NewProductService(newMockProductRepository(), newMockCategoryRepository(), &mockTransactor{}, newTestAuditLogService())
+
+	if _, err := service.CreateProduct(context.Background(), "Test Product", 1000, 5, nil, 0); !errors.Is(err, ErrInvalidTaxRate) {
+		t.Errorf("Expected ErrInvalidTaxRate, got %v", err)
+	}
+}
+
 func TestProductService_GetProduct(t *testing.T) {
 	repo := newMockProductRepository()
-	service := NewProductService(repo)
+	service := NewProductService(repo, newMockCategoryRepository(), &mockTransactor{}, newTestAuditLogService())
 	ctx := context.Background()
 
-	created, _ := service.CreateProduct(ctx, "Test Product", 1000)
+	created, _ := service.CreateProduct(ctx, "Test Product", 1000, types.REDUCED_TAX_RATE, nil, 0)
 
 	product, err := service.GetProduct(ctx, created.ID)
 	if err != nil {
@@ -117,11 +159,11 @@ func TestProductService_GetProduct(t *testing.T) {
 
 func TestProductService_GetAllProducts(t *testing.T) {
 	repo := newMockProductRepository()
-	service := NewProductService(repo)
+	service := NewProductService(repo, newMockCategoryRepository(), &mockTransactor{}, newTestAuditLogService())
 	ctx := context.Background()
 
-	p1, _ := service.CreateProduct(ctx, "Product 1", 1000)
-	p2, _ := service.CreateProduct(ctx, "Product 2", 2000)
+	p1, _ := service.CreateProduct(ctx, "Product 1", 1000, types.REDUCED_TAX_RATE, nil, 0)
+	p2, _ := service.CreateProduct(ctx, "Product 2", 2000, types.REDUCED_TAX_RATE, nil, 0)
 
 	products, err := service.GetAllProducts(ctx)
 	if err != nil {
@@ -144,12 +186,12 @@ func TestProductService_GetAllProducts(t *testing.T) {
 
 func TestProductService_UpdateProduct(t *testing.T) {
 	repo := newMockProductRepository()
-	service := NewProductService(repo)
+	service := NewProductService(repo, newMockCategoryRepository(), &mockTransactor{}, newTestAuditLogService())
 	ctx := context.Background()
 
-	created, _ := service.CreateProduct(ctx, "Test Product", 1000)
+	created, _ := service.CreateProduct(ctx, "Test Product", 1000, types.REDUCED_TAX_RATE, nil, 0)
 
-	updated, err := service.UpdateProduct(ctx, created.ID, "Updated Product", 2000)
+	updated, err := service.UpdateProduct(ctx, created.ID, "Updated Product", 2000, types.REDUCED_TAX_RATE, nil, 0)
 	if err != nil {
 		t.Errorf("UpdateProduct failed: %v", err)
 	}
@@ -165,10 +207,10 @@ func TestProductService_UpdateProduct(t *testing.T) {
 
 func TestProductService_DeleteProduct(t *testing.T) {
 	repo := newMockProductRepository()
-	service := NewProductService(repo)
+	service := NewProductService(repo, newMockCategoryRepository(), &mockTransactor{}, newTestAuditLogService())
 	ctx := context.Background()
 
-	created, _ := service.CreateProduct(ctx, "Test Product", 1000)
+	created, _ := service.CreateProduct(ctx, "Test Product", 1000, types.REDUCED_TAX_RATE, nil, 0)
 
 	err := service.DeleteProduct(ctx, created.ID)
 	if err != nil {
@@ -180,3 +222,51 @@ func TestProductService_DeleteProduct(t *testing.T) {
 		t.Error("Expected error when getting deleted product")
 	}
 }
+
+func TestProductService_SetComboComponents(t *testing.T) {
+	repo := newMockProductRepository()
+	service := NewProductService(repo, newMockCategoryRepository(), &mockTransactor{}, newTestAuditLogService())
+	ctx := context.Background()
+
+	yakisoba, _ := service.CreateProduct(ctx, "焼きそば", 400, types.REDUCED_TAX_RATE, nil, 0)
+	drink, _ := service.CreateProduct(ctx, "お茶", 150, types.REDUCED_TAX_RATE, nil, 0)
+	set, _ := service.CreateProduct(ctx, "焼きそばセット", 500, types.REDUCED_TAX_RATE, nil, 0)
+
+	combo, err := service.SetComboComponents(ctx, set.ID, []ComboComponentInput{
+		{ProductID: yakisoba.ID, Quantity: 1},
+		{ProductID: drink.ID, Quantity: 1},
+	})
+	if err != nil {
+		t.Fatalf("SetComboComponents failed: %v", err)
+	}
+	if !combo.IsCombo() || len(combo.Components) != 2 {
+		t.Errorf("Expected a combo of 2 products, got %+v", combo.Components)
+	}
+
+	invalid := map[string]struct {
+		id         types.ID
+		components []ComboComponentInput
+	}{
+		"itself":          {set.ID, []ComboComponentInput{{ProductID: set.ID, Quantity: 1}}},
+		"zero quantity":   {set.ID, []ComboComponentInput{{ProductID: drink.ID, Quantity: 0}}},
+		"repeated":        {set.ID, []ComboComponentInput{{ProductID: drink.ID, Quantity: 1}, {ProductID: drink.ID, Quantity: 1}}},
+		"nested combo":    {drink.ID, []ComboComponentInput{{ProductID: set.ID, Quantity: 1}}},
+		"combo component": {yakisoba.ID, []ComboComponentInput{{ProductID: drink.ID, Quantity: 1}}},
+	}
+	for name, tc := range invalid {
+		if _, err := service.SetComboComponents(ctx, tc.id, tc.components); !errors.Is(err, ErrInvalidCombo) {
+			t.Errorf("%s: expected ErrInvalidCombo, got %v", name, err)
+		}
+	}
+
+	if err := service.DeleteProduct(ctx, drink.ID); !errors.Is(err, ErrProductInCombo) {
+		t.Errorf("Expected ErrProductInCombo when deleting a component, got %v", err)
+	}
+
+	if _, err := service.SetComboComponents(ctx, set.ID, nil); err != nil {
+		t.Fatalf("SetComboComponents failed: %v", err)
+	}
+	if err := service.DeleteProduct(ctx, drink.ID); err != nil {
+		t.Errorf("Expected a product no longer in a combo to be deletable, got %v", err)
+	}
+}
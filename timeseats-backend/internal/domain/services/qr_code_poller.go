@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// QRCodePoller asks PayPay about active QR codes every interval, so orders
+// are marked paid and codes expired even when a webhook notification is
+// lost.
+type QRCodePoller struct {
+	paymentService PaymentService
+	interval       time.Duration
+}
+
+func NewQRCodePoller(paymentService PaymentService, interval time.Duration) *QRCodePoller {
+	return &QRCodePoller{
+		paymentService: paymentService,
+		interval:       interval,
+	}
+}
+
+// Run polls every interval until ctx is cancelled.
+func (p *QRCodePoller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			settled, err := p.paymentService.RefreshActiveQRCodes(ctx)
+			if err != nil {
+				log.Printf("QR code poll failed: %v", err)
+			}
+			if settled > 0 {
+				log.Printf("settled %d QR codes", settled)
+			}
+		}
+	}
+}
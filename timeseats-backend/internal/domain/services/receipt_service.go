@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// ReceiptIssuer is printed on every receipt. RegistrationNumber is the
+// qualified invoice issuer number, "T" and 13 digits; without it the
+// receipt is an ordinary one rather than a qualified invoice.
+type ReceiptIssuer struct {
+	Name               string
+	RegistrationNumber string
+}
+
+// ReceiptDocument is what goes on a printed receipt. Taxes splits the
+// order's total by tax rate.
+type ReceiptDocument struct {
+	Issuer  ReceiptIssuer
+	Receipt models.Receipt
+	Order   *models.Order
+	Taxes   []TaxAmount
+	// IsCopy is set on every print after the first, which must say that it
+	// is a copy.
+	IsCopy bool
+}
+
+// Tenders are the completed payments of the order. Receipts list them one by
+// one when there are several.
+func (d *ReceiptDocument) Tenders() []models.Payment {
+	var tenders []models.Payment
+	for _, payment := range d.Order.Payments {
+		if payment.IsCompleted() {
+			tenders = append(tenders, payment)
+		}
+	}
+	return tenders
+}
+
+// Tendered is the cash the customer handed over and Change what they got
+// back, summed over the cash tenders taken with the amount tendered. Both are
+// zero when no amount tendered was entered.
+func (d *ReceiptDocument) Tendered() int {
+	tendered, _ := d.cashTendered()
+	return tendered
+}
+
+func (d *ReceiptDocument) Change() int {
+	_, change := d.cashTendered()
+	return change
+}
+
+func (d *ReceiptDocument) cashTendered() (tendered, change int) {
+	for _, tender := range d.Tenders() {
+		if tender.Method == types.CASH && tender.TenderedAmount > 0 {
+			tendered += tender.TenderedAmount
+			change += tender.ChangeAmount
+		}
+	}
+	return tendered, change
+}
+
+// PaymentMethodName is how the payment method of an order paid in one
+// method is printed.
+func (d *ReceiptDocument) PaymentMethodName() string {
+	if tenders := d.Tenders(); len(tenders) == 1 {
+		return d.MethodName(tenders[0].Method)
+	}
+	return d.MethodName(d.Order.PaymentMethod)
+}
+
+// MethodName is how a payment method is printed.
+func (d *ReceiptDocument) MethodName(method types.PaymentMethod) string {
+	switch method {
+	case types.PAYPAY:
+		return "PayPay"
+	case types.SQUARE:
+		return "クレジットカード等（Square）"
+	default:
+		return "現金"
+	}
+}
+
+// HasReducedRate reports whether any line is taxed at the reduced rate, so
+// the receipt has to explain its mark.
+func (d *ReceiptDocument) HasReducedRate() bool {
+	for _, item := range d.Order.Items {
+		if item.TaxRate == types.REDUCED_TAX_RATE {
+			return true
+		}
+	}
+	return false
+}
+
+// ReceiptRenderer writes a receipt as a file, such as a PDF.
+type ReceiptRenderer func(w io.Writer, receipt *ReceiptDocument) error
+
+type ReceiptService interface {
+	// PrintReceipt issues the receipt of a paid order, with a new serial
+	// number, the first time it is printed and returns copies of it after.
+	PrintReceipt(ctx context.Context, orderID types.ID) (*ReceiptDocument, error)
+	// GetReceipt returns the order's receipt as it was last printed, without
+	// printing it again. It returns ErrReceiptNotIssued until PrintReceipt
+	// has issued one.
+	GetReceipt(ctx context.Context, orderID types.ID) (*ReceiptDocument, error)
+	// WriteReceiptPDF writes the receipt GetReceipt returns to w as a PDF.
+	WriteReceiptPDF(ctx context.Context, orderID types.ID, w io.Writer) (*ReceiptDocument, error)
+}
+
+type receiptService struct {
+	receiptRepo repositories.ReceiptRepository
+	orderRepo   repositories.OrderRepository
+	transactor  repositories.Transactor
+	clock       Clock
+	audit       AuditLogService
+	issuer      ReceiptIssuer
+	pdf         ReceiptRenderer
+}
+
+func NewReceiptService(
+	receiptRepo repositories.ReceiptRepository,
+	orderRepo repositories.OrderRepository,
+	transactor repositories.Transactor,
+	clock Clock,
+	audit AuditLogService,
+	issuer ReceiptIssuer,
+	pdf ReceiptRenderer,
+) ReceiptService {
+	return &receiptService{
+		receiptRepo: receiptRepo,
+		orderRepo:   orderRepo,
+		transactor:  transactor,
+		clock:       clock,
+		audit:       audit,
+		issuer:      issuer,
+		pdf:         pdf,
+	}
+}
+
+func (s *receiptService) PrintReceipt(ctx context.Context, orderID types.ID) (*ReceiptDocument, error) {
+	if s.issuer.Name == "" {
+		return nil, ErrReceiptIssuerMissing
+	}
+
+	var document *ReceiptDocument
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Locked so that two first prints cannot both issue a receipt.
+		order, err := s.orderRepo.FindByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if !order.IsPaid {
+			return ErrReceiptNotPaid
+		}
+		if order.Status == types.CANCELLED {
+			return ErrInvalidOrderStatus
+		}
+
+		now := s.clock.Now()
+		document = &ReceiptDocument{Issuer: s.issuer, Order: order, Taxes: TaxBreakdown(order)}
+
+		before, err := s.receiptRepo.FindByOrderIDForUpdate(ctx, orderID)
+		var notFound *repositories.ErrNotFound
+		if errors.As(err, &notFound) {
+			return s.issue(ctx, document, now)
+		}
+		if err != nil {
+			return err
+		}
+
+		receipt := *before
+		receipt.PrintCount++
+		receipt.LastPrintedAt = now
+		if err := s.receiptRepo.UpdatePrints(ctx, &receipt); err != nil {
+			return err
+		}
+		document.Receipt = receipt
+		document.IsCopy = true
+		return s.audit.Record(ctx, AuditEntityReceipt, receipt.ID, "reprint", before, &receipt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+func (s *receiptService) GetReceipt(ctx context.Context, orderID types.ID) (*ReceiptDocument, error) {
+	if s.issuer.Name == "" {
+		return nil, ErrReceiptIssuerMissing
+	}
+
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := s.receiptRepo.FindByOrderID(ctx, orderID)
+	var notFound *repositories.ErrNotFound
+	if errors.As(err, &notFound) {
+		return nil, ErrReceiptNotIssued
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReceiptDocument{
+		Issuer:  s.issuer,
+		Receipt: *receipt,
+		Order:   order,
+		Taxes:   TaxBreakdown(order),
+		IsCopy:  receipt.PrintCount > 1,
+	}, nil
+}
+
+func (s *receiptService) WriteReceiptPDF(ctx context.Context, orderID types.ID, w io.Writer) (*ReceiptDocument, error) {
+	document, err := s.GetReceipt(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.pdf(w, document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+func (s *receiptService) issue(ctx context.Context, document *ReceiptDocument, now time.Time) error {
+	serialNumber, err := s.receiptRepo.NextSerialNumber(ctx)
+	if err != nil {
+		return err
+	}
+
+	issuedByID, _ := actorIDs(ctx)
+	receipt := models.Receipt{
+		OrderID:       document.Order.ID,
+		SerialNumber:  serialNumber,
+		IssuedByID:    issuedByID,
+		IssuedAt:      now,
+		PrintCount:    1,
+		LastPrintedAt: now,
+	}
+	if err := s.receiptRepo.Create(ctx, &receipt); err != nil {
+		return err
+	}
+	document.Receipt = receipt
+	return s.audit.Record(ctx, AuditEntityReceipt, receipt.ID, "issue", nil, &receipt)
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+)
+
+type mockReceiptRepository struct {
+	mu       sync.Mutex
+	receipts map[types.ID]models.Receipt
+	serial   int
+}
+
+func newMockReceiptRepository() *mockReceiptRepository {
+	return &mockReceiptRepository{receipts: make(map[types.ID]models.Receipt)}
+}
+
+func (r *mockReceiptRepository) snapshot() func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved, serial := maps.Clone(r.receipts), r.serial
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.receipts, r.serial = saved, serial
+	}
+}
+
+func (r *mockReceiptRepository) Create(ctx context.Context, receipt *models.Receipt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	receipt.ID = types.ID(uuid.New().String())
+	r.receipts[receipt.OrderID] = *receipt
+	return nil
+}
+
+func (r *mockReceiptRepository) FindByOrderID(ctx context.Context, orderID types.ID) (*models.Receipt, error) {
+	return r.FindByOrderIDForUpdate(ctx, orderID)
+}
+
+func (r *mockReceiptRepository) FindByOrderIDForUpdate(ctx context.Context, orderID types.ID) (*models.Receipt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	receipt, exists := r.receipts[orderID]
+	if !exists {
+		return nil, repositories.NewErrNotFound("Receipt", orderID)
+	}
+	return &receipt, nil
+}
+
+func (r *mockReceiptRepository) UpdatePrints(ctx context.Context, receipt *models.Receipt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.receipts[receipt.OrderID] = *receipt
+	return nil
+}
+
+func (r *mockReceiptRepository) NextSerialNumber(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.serial++
+	return r.serial, nil
+}
+
+func TestReceiptService_PrintReceipt(t *testing.T) {
+	orders, _, slot, product := setupConcurrencyTest(t, 10)
+	orderRepo := orders.(*orderService).orderRepo
+	clock := &fakeClock{now: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)}
+	issuer := ReceiptIssuer{Name: "生徒会", RegistrationNumber: "T1234567890123"}
+	render := func(w io.Writer, receipt *ReceiptDocument) error {
+		_, err := fmt.Fprintf(w, "No. %06d", receipt.Receipt.SerialNumber)
+		return err
+	}
+	service := NewReceiptService(newMockReceiptRepository(), orderRepo, &mockTransactor{}, clock, newTestAuditLogService(), issuer, render)
+	ctx := context.Background()
+
+	order, _ := orders.CreateOrder(ctx, slot.ID, []OrderItemInput{{ProductID: product.ID, Quantity: 2}}, "", types.CASH)
+	if _, err := service.PrintReceipt(ctx, order.ID); !errors.Is(err, ErrReceiptNotPaid) {
+		t.Errorf("Expected ErrReceiptNotPaid for an unpaid order, got %v", err)
+	}
+	orders.UpdatePaymentStatus(ctx, order.ID, "")
+	other, _ := orders.CreateOrder(ctx, slot.ID, []OrderItemInput{{ProductID: product.ID, Quantity: 1}}, "", types.CASH)
+	orders.UpdatePaymentStatus(ctx, other.ID, "")
+
+	first, err := service.PrintReceipt(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("PrintReceipt failed: %v", err)
+	}
+	if first.IsCopy || first.Receipt.SerialNumber != 1 || first.Receipt.PrintCount != 1 || first.Issuer != issuer {
+		t.Errorf("Expected the original receipt No. 1, got %+v", first.Receipt)
+	}
+	if len(first.Taxes) != 1 || first.Taxes[0].Amount != order.TotalAmount {
+		t.Errorf("Expected the total under one tax rate, got %+v", first.Taxes)
+	}
+
+	clock.now = clock.now.Add(time.Hour)
+	reprint, err := service.PrintReceipt(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("PrintReceipt failed: %v", err)
+	}
+	if !reprint.IsCopy || reprint.Receipt.SerialNumber != 1 || reprint.Receipt.PrintCount != 2 {
+		t.Errorf("Expected a copy of receipt No. 1, got %+v", reprint.Receipt)
+	}
+	if !reprint.Receipt.IssuedAt.Equal(first.Receipt.IssuedAt) || !reprint.Receipt.LastPrintedAt.Equal(clock.now) {
+		t.Errorf("Expected the copy to keep the issue date, got %+v", reprint.Receipt)
+	}
+
+	if _, err := service.GetReceipt(ctx, other.ID); !errors.Is(err, ErrReceiptNotIssued) {
+		t.Errorf("Expected ErrReceiptNotIssued before the first print, got %v", err)
+	}
+	next, err := service.PrintReceipt(ctx, other.ID)
+	if err != nil || next.Receipt.SerialNumber != 2 || next.IsCopy {
+		t.Errorf("Expected receipt No. 2 for another order, got %+v, %v", next, err)
+	}
+
+	// Viewing the receipt does not print it again.
+	for range 2 {
+		viewed, err := service.GetReceipt(ctx, other.ID)
+		if err != nil || viewed.Receipt.SerialNumber != 2 || viewed.Receipt.PrintCount != 1 || viewed.IsCopy {
+			t.Errorf("Expected the original receipt No. 2, got %+v, %v", viewed, err)
+		}
+	}
+	if viewed, _ := service.GetReceipt(ctx, order.ID); !viewed.IsCopy {
+		t.Errorf("Expected a reprinted receipt to be shown as a copy")
+	}
+
+	var buf bytes.Buffer
+	if _, err := service.WriteReceiptPDF(ctx, other.ID, &buf); err != nil || buf.String() != "No. 000002" {
+		t.Errorf("Expected receipt No. 2 to be rendered, got %q, %v", buf.String(), err)
+	}
+}
+
+func TestReceiptService_PrintReceipt_IssuerMissing(t *testing.T) {
+	service := NewReceiptService(newMockReceiptRepository(), newMockOrderRepository(), &mockTransactor{}, NewSystemClock(), newTestAuditLogService(), ReceiptIssuer{}, nil)
+	if _, err := service.PrintReceipt(context.Background(), "order-1"); !errors.Is(err, ErrReceiptIssuerMissing) {
+		t.Errorf("Expected ErrReceiptIssuerMissing, got %v", err)
+	}
+}
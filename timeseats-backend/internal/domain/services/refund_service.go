@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type RefundService interface {
+	RefundOrder(ctx context.Context, orderID types.ID, input RefundInput) (*models.Refund, error)
+	GetRefunds(ctx context.Context, orderID types.ID) ([]models.Refund, error)
+}
+
+// RefundInput describes a refund. Without Items everything not refunded yet
+// is refunded, and Restock decides whether it goes back into stock. The
+// amount is taken off the order's payments, those in Method first and then
+// the latest. A zero Method pays back each part the way it was paid.
+type RefundInput struct {
+	Items   []RefundItemInput
+	Restock bool
+	Reason  string
+	Method  types.PaymentMethod
+}
+
+type RefundItemInput struct {
+	OrderItemID types.ID
+	Quantity    int
+	Restock     bool
+}
+
+// refundableStatuses are those of orders whose items have been sold.
+var refundableStatuses = []types.OrderStatus{
+	types.CONFIRMED,
+	types.PREPARING,
+	types.READY,
+	types.DELIVERED,
+}
+
+type refundService struct {
+	orderRepo   repositories.OrderRepository
+	paymentRepo repositories.PaymentRepository
+	refundRepo  repositories.RefundRepository
+	invRepo     repositories.ProductInventoryRepository
+	transactor  repositories.Transactor
+	publisher   events.Publisher
+	clock       Clock
+	audit       AuditLogService
+}
+
+func NewRefundService(
+	orderRepo repositories.OrderRepository,
+	paymentRepo repositories.PaymentRepository,
+	refundRepo repositories.RefundRepository,
+	invRepo repositories.ProductInventoryRepository,
+	transactor repositories.Transactor,
+	publisher events.Publisher,
+	clock Clock,
+	audit AuditLogService,
+) RefundService {
+	return &refundService{
+		orderRepo:   orderRepo,
+		paymentRepo: paymentRepo,
+		refundRepo:  refundRepo,
+		invRepo:     invRepo,
+		transactor:  transactor,
+		publisher:   publisher,
+		clock:       clock,
+		audit:       audit,
+	}
+}
+
+func (s *refundService) RefundOrder(ctx context.Context, orderID types.ID, input RefundInput) (*models.Refund, error) {
+	if input.Reason == "" {
+		return nil, ErrRefundReasonRequired
+	}
+
+	var refund *models.Refund
+	var restocked []models.OrderItem
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.FindByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if !isRefundable(order.Status) {
+			return ErrRefundNotAllowed
+		}
+
+		items, err := refundItems(order, input)
+		if err != nil {
+			return err
+		}
+
+		approvedByID, terminalID := actorIDs(ctx)
+		refund = &models.Refund{
+			OrderID:      orderID,
+			Reason:       input.Reason,
+			ApprovedByID: approvedByID,
+			TerminalID:   terminalID,
+			CreatedAt:    s.clock.Now(),
+			Items:        items,
+		}
+
+		restocked = nil
+		for _, item := range items {
+			refund.Amount += item.Amount
+
+			err := s.orderRepo.AddRefundedQuantity(ctx, item.OrderItemID, item.Quantity)
+			if errors.Is(err, repositories.ErrConflict) {
+				return ErrRefundExceedsOrder
+			}
+			if err != nil {
+				return err
+			}
+
+			if !item.Restocked {
+				continue
+			}
+			orderItem := findOrderItem(order.Items, item.OrderItemID)
+			for _, unit := range orderItem.StockUnits() {
+				inventory, err := s.invRepo.FindBySalesSlotAndProduct(ctx, order.SalesSlotID, unit.ProductID)
+				if err != nil {
+					return err
+				}
+				err = s.invRepo.AdjustQuantities(ctx, inventory.ID, 0, -item.Quantity*unit.Quantity)
+				if errors.Is(err, repositories.ErrInsufficientQuantity) {
+					return ErrInsufficientInventory
+				}
+				if err != nil {
+					return err
+				}
+			}
+			restocked = append(restocked, *orderItem)
+		}
+
+		err = s.orderRepo.AddRefund(ctx, orderID, refundableStatuses, refund.Amount)
+		if errors.Is(err, repositories.ErrConflict) {
+			return ErrRefundNotAllowed
+		}
+		if err != nil {
+			return err
+		}
+
+		refund.Payments = allocateRefund(order, refund.Amount, input.Method)
+		refund.Method = refund.Payments[0].Method
+		for _, line := range refund.Payments {
+			if line.PaymentID == nil {
+				continue
+			}
+			err := s.paymentRepo.AddRefund(ctx, *line.PaymentID, line.Amount)
+			if errors.Is(err, repositories.ErrConflict) {
+				return ErrRefundExceedsOrder
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := s.refundRepo.Create(ctx, refund); err != nil {
+			return err
+		}
+
+		after, err := s.orderRepo.FindByID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityOrder, orderID, "refund", order, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if order, err := s.orderRepo.FindByID(ctx, orderID); err == nil {
+		s.publisher.Publish(events.NewOrderEvent(events.OrderRefunded, order))
+		publishInventories(ctx, s.invRepo, s.publisher, order.SalesSlotID, restocked)
+	}
+
+	return refund, nil
+}
+
+func (s *refundService) GetRefunds(ctx context.Context, orderID types.ID) ([]models.Refund, error) {
+	if _, err := s.orderRepo.FindByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.refundRepo.FindByOrderID(ctx, orderID)
+}
+
+// allocateRefund splits a refund over the order's payments, taking it off
+// those in method first and then the latest ones. Whatever the payments do
+// not cover, as for orders sold without a recorded payment, is paid back
+// without one.
+func allocateRefund(order *models.Order, amount int, method types.PaymentMethod) []models.RefundPayment {
+	payments := make([]models.Payment, 0, len(order.Payments))
+	for i := len(order.Payments) - 1; i >= 0; i-- {
+		if order.Payments[i].Refundable() > 0 {
+			payments = append(payments, order.Payments[i])
+		}
+	}
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].Method == method && payments[j].Method != method
+	})
+
+	var lines []models.RefundPayment
+	for _, payment := range payments {
+		if amount == 0 {
+			break
+		}
+		part := min(amount, payment.Refundable())
+		line := models.RefundPayment{PaymentID: &payment.ID, Method: payment.Method, Amount: part}
+		if method != 0 {
+			line.Method = method
+		}
+		lines = append(lines, line)
+		amount -= part
+	}
+	if amount > 0 || len(lines) == 0 {
+		if method == 0 {
+			method = order.PaymentMethod
+		}
+		lines = append(lines, models.RefundPayment{Method: method, Amount: amount})
+	}
+	return lines
+}
+
+func isRefundable(status types.OrderStatus) bool {
+	for _, s := range refundableStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func findOrderItem(items []models.OrderItem, id types.ID) *models.OrderItem {
+	for i := range items {
+		if items[i].ID == id {
+			return &items[i]
+		}
+	}
+	return nil
+}
+
+// refundItems resolves the requested quantities against the order items.
+func refundItems(order *models.Order, input RefundInput) ([]models.RefundItem, error) {
+	items, err := requestedRefundItems(order, input)
+	if err != nil {
+		return nil, err
+	}
+	if order.DiscountAmount == 0 {
+		return items, nil
+	}
+
+	// Each unit pays back its share of what was charged for the line after
+	// discounts, and refunding everything left pays back exactly what is
+	// left.
+	refunded := 0
+	for i := range items {
+		item := findOrderItem(order.Items, items[i].OrderItemID)
+		items[i].Amount = item.NetAmount() * items[i].Quantity / item.Quantity
+		refunded += items[i].Amount
+	}
+	if len(input.Items) == 0 {
+		items[len(items)-1].Amount += order.TotalAmount - order.RefundedAmount - refunded
+	}
+	return items, nil
+}
+
+func requestedRefundItems(order *models.Order, input RefundInput) ([]models.RefundItem, error) {
+	if len(input.Items) == 0 {
+		var items []models.RefundItem
+		for _, item := range order.Items {
+			remaining := item.Quantity - item.RefundedQuantity
+			if remaining <= 0 {
+				continue
+			}
+			items = append(items, models.RefundItem{
+				OrderItemID: item.ID,
+				ProductID:   item.ProductID,
+				Quantity:    remaining,
+				Amount:      item.Price * remaining,
+				Restocked:   input.Restock,
+			})
+		}
+		if len(items) == 0 {
+			return nil, ErrRefundExceedsOrder
+		}
+		return items, nil
+	}
+
+	orderItems := make(map[types.ID]models.OrderItem, len(order.Items))
+	for _, item := range order.Items {
+		orderItems[item.ID] = item
+	}
+
+	requested := make(map[types.ID]int)
+	items := make([]models.RefundItem, 0, len(input.Items))
+	for _, in := range input.Items {
+		if in.Quantity <= 0 {
+			return nil, ErrInvalidQuantity
+		}
+		item, ok := orderItems[in.OrderItemID]
+		if !ok {
+			return nil, repositories.NewErrNotFound("OrderItem", in.OrderItemID)
+		}
+		requested[item.ID] += in.Quantity
+		if requested[item.ID] > item.Quantity-item.RefundedQuantity {
+			return nil, ErrRefundExceedsOrder
+		}
+		items = append(items, models.RefundItem{
+			OrderItemID: item.ID,
+			ProductID:   item.ProductID,
+			Quantity:    in.Quantity,
+			Amount:      item.Price * in.Quantity,
+			Restocked:   in.Restock,
+		})
+	}
+	return items, nil
+}
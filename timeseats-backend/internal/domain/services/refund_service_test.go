@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+)
+
+type mockRefundRepository struct {
+	mu      sync.Mutex
+	refunds []models.Refund
+}
+
+func newMockRefundRepository() *mockRefundRepository {
+	return &mockRefundRepository{}
+}
+
+func (r *mockRefundRepository) Create(ctx context.Context, refund *models.Refund) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	refund.ID = types.ID(uuid.New().String())
+	r.refunds = append(r.refunds, *refund)
+	return nil
+}
+
+func (r *mockRefundRepository) FindByOrderID(ctx context.Context, orderID types.ID) ([]models.Refund, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var refunds []models.Refund
+	for _, refund := range r.refunds {
+		if refund.OrderID == orderID {
+			refunds = append(refunds, refund)
+		}
+	}
+	sort.Slice(refunds, func(i, j int) bool { return refunds[i].CreatedAt.Before(refunds[j].CreatedAt) })
+	return refunds, nil
+}
+
+func (r *mockRefundRepository) SumRefunds(ctx context.Context, terminalID types.ID, method types.PaymentMethod, from, to time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	total := 0
+	for _, refund := range r.refunds {
+		if !matchesID(refund.TerminalID, terminalID) {
+			continue
+		}
+		if refund.CreatedAt.Before(from) || (!to.IsZero() && !refund.CreatedAt.Before(to)) {
+			continue
+		}
+		for _, line := range refund.Payments {
+			if line.Method == method {
+				total += line.Amount
+			}
+		}
+	}
+	return total, nil
+}
+
+func TestRefundService_RefundOrder(t *testing.T) {
+	orders, invRepo, slot, product := setupConcurrencyTest(t, 10)
+	orderRepo := orders.(*orderService).orderRepo
+	publisher := &mockPublisher{}
+	service := NewRefundService(orderRepo, orders.(*orderService).paymentRepo, newMockRefundRepository(), invRepo, &mockTransactor{}, publisher, NewSystemClock(), newTestAuditLogService())
+	ctx := WithActor(context.Background(), &models.Staff{ID: "admin-1", Role: types.ADMIN})
+
+	order, _ := orders.CreateOrder(ctx, slot.ID, []OrderItemInput{{ProductID: product.ID, Quantity: 3}}, "", types.CASH)
+	if _, err := service.RefundOrder(ctx, order.ID, RefundInput{Reason: "誤注文"}); !errors.Is(err, ErrRefundNotAllowed) {
+		t.Errorf("Expected ErrRefundNotAllowed for a reserved order, got %v", err)
+	}
+	orders.UpdateOrderStatus(ctx, order.ID, types.CONFIRMED)
+	order, _ = orders.GetOrder(ctx, order.ID)
+	itemID := order.Items[0].ID
+
+	if _, err := service.RefundOrder(ctx, order.ID, RefundInput{}); !errors.Is(err, ErrRefundReasonRequired) {
+		t.Errorf("Expected ErrRefundReasonRequired, got %v", err)
+	}
+	excess := RefundInput{Reason: "誤注文", Items: []RefundItemInput{{OrderItemID: itemID, Quantity: 4}}}
+	if _, err := service.RefundOrder(ctx, order.ID, excess); !errors.Is(err, ErrRefundExceedsOrder) {
+		t.Errorf("Expected ErrRefundExceedsOrder, got %v", err)
+	}
+
+	// One unit goes back into stock.
+	refund, err := service.RefundOrder(ctx, order.ID, RefundInput{
+		Reason: "誤注文",
+		Items:  []RefundItemInput{{OrderItemID: itemID, Quantity: 1, Restock: true}},
+	})
+	if err != nil {
+		t.Fatalf("RefundOrder failed: %v", err)
+	}
+	if refund.Amount != product.Price || refund.Method != types.CASH || refund.ApprovedByID == nil || *refund.ApprovedByID != "admin-1" {
+		t.Errorf("Unexpected refund: %+v", refund)
+	}
+	inventory, _ := invRepo.FindByID(ctx, "inv1")
+	if inventory.SoldQuantity != 2 {
+		t.Errorf("Expected sold quantity 2 after restocking, got %d", inventory.SoldQuantity)
+	}
+	if got := publisher.types(); len(got) != 2 || got[0] != events.OrderRefunded || got[1] != events.InventoryChanged {
+		t.Errorf("Expected order.refunded and inventory.changed events, got %v", got)
+	}
+
+	// The rest is written off.
+	if _, err := service.RefundOrder(ctx, order.ID, RefundInput{Reason: "品質不良"}); err != nil {
+		t.Fatalf("RefundOrder failed: %v", err)
+	}
+	inventory, _ = invRepo.FindByID(ctx, "inv1")
+	if inventory.SoldQuantity != 2 {
+		t.Errorf("Expected wasted units to stay sold, got sold quantity %d", inventory.SoldQuantity)
+	}
+
+	refunded, _ := orders.GetOrder(ctx, order.ID)
+	if !refunded.IsFullyRefunded() || refunded.Items[0].RefundedQuantity != 3 {
+		t.Errorf("Expected the order to be fully refunded, got %d of %d", refunded.RefundedAmount, refunded.TotalAmount)
+	}
+	if _, err := service.RefundOrder(ctx, order.ID, RefundInput{Reason: "誤注文"}); !errors.Is(err, ErrRefundExceedsOrder) {
+		t.Errorf("Expected ErrRefundExceedsOrder once everything is refunded, got %v", err)
+	}
+
+	refunds, err := service.GetRefunds(ctx, order.ID)
+	if err != nil || len(refunds) != 2 {
+		t.Errorf("Expected 2 refunds, got %d, %v", len(refunds), err)
+	}
+}
+
+func TestRefundService_RefundOrder_RestockShortfall(t *testing.T) {
+	orders, invRepo, slot, product := setupConcurrencyTest(t, 10)
+	service := NewRefundService(orders.(*orderService).orderRepo, orders.(*orderService).paymentRepo, newMockRefundRepository(), invRepo, &mockTransactor{}, &mockPublisher{}, NewSystemClock(), newTestAuditLogService())
+	ctx := WithActor(context.Background(), &models.Staff{ID: "admin-1", Role: types.ADMIN})
+
+	order, _ := orders.CreateOrder(ctx, slot.ID, []OrderItemInput{{ProductID: product.ID, Quantity: 2}}, "", types.CASH)
+	orders.UpdateOrderStatus(ctx, order.ID, types.CONFIRMED)
+	order, _ = orders.GetOrder(ctx, order.ID)
+
+	// The sold units were written off by hand meanwhile, so restocking would
+	// take the sold quantity below zero.
+	invRepo.AdjustQuantities(ctx, "inv1", 0, -2)
+
+	_, err := service.RefundOrder(ctx, order.ID, RefundInput{
+		Reason: "誤注文",
+		Items:  []RefundItemInput{{OrderItemID: order.Items[0].ID, Quantity: 1, Restock: true}},
+	})
+	if !errors.Is(err, ErrInsufficientInventory) {
+		t.Errorf("Expected ErrInsufficientInventory, got %v", err)
+	}
+}
+
+func TestRefundService_RefundOrder_SplitTender(t *testing.T) {
+	orders, invRepo, slot, product := setupConcurrencyTest(t, 10)
+	orderRepo := orders.(*orderService).orderRepo
+	paymentRepo := orders.(*orderService).paymentRepo
+	service := NewRefundService(orderRepo, paymentRepo, newMockRefundRepository(), invRepo, &mockTransactor{}, &mockPublisher{}, NewSystemClock(), newTestAuditLogService())
+	payments := NewPaymentService(orderRepo, paymentRepo, newMockQRCodeRepository(), nil, nil, &mockTransactor{}, &mockPublisher{}, NewSystemClock(), newTestAuditLogService())
+	ctx := WithActor(context.Background(), &models.Staff{ID: "admin-1", Role: types.ADMIN})
+
+	order, _ := orders.CreateOrder(ctx, slot.ID, []OrderItemInput{{ProductID: product.ID, Quantity: 3}}, "", types.CASH)
+	cash, _ := payments.RecordPayment(ctx, order.ID, PaymentInput{Method: types.CASH, Amount: 500})
+	paypay, _ := payments.RecordPayment(ctx, order.ID, PaymentInput{Method: types.PAYPAY, Reference: "paypay-1"})
+	orders.UpdateOrderStatus(ctx, order.ID, types.CONFIRMED)
+	order, _ = orders.GetOrder(ctx, order.ID)
+	itemID := order.Items[0].ID
+	oneUnit := []RefundItemInput{{OrderItemID: itemID, Quantity: 1}}
+
+	tests := []struct {
+		name   string
+		method types.PaymentMethod
+		want   []models.RefundPayment
+	}{
+		{"latest payment first", 0, []models.RefundPayment{{PaymentID: &paypay.ID, Method: types.PAYPAY, Amount: 400}}},
+		{"requested method first", types.CASH, []models.RefundPayment{{PaymentID: &cash.ID, Method: types.CASH, Amount: 400}}},
+		{"spread over payments", 0, []models.RefundPayment{
+			{PaymentID: &paypay.ID, Method: types.PAYPAY, Amount: 300},
+			{PaymentID: &cash.ID, Method: types.CASH, Amount: 100},
+		}},
+	}
+	for _, tt := range tests {
+		refund, err := service.RefundOrder(ctx, order.ID, RefundInput{Reason: "誤注文", Items: oneUnit, Method: tt.method})
+		if err != nil {
+			t.Fatalf("%s: RefundOrder failed: %v", tt.name, err)
+		}
+		if len(refund.Payments) != len(tt.want) {
+			t.Errorf("%s: expected %d payment lines, got %+v", tt.name, len(tt.want), refund.Payments)
+			continue
+		}
+		for i, want := range tt.want {
+			got := refund.Payments[i]
+			if *got.PaymentID != *want.PaymentID || got.Method != want.Method || got.Amount != want.Amount {
+				t.Errorf("%s: line %d: expected %+v, got %+v", tt.name, i, want, got)
+			}
+		}
+		if refund.Method != tt.want[0].Method {
+			t.Errorf("%s: expected method %v, got %v", tt.name, tt.want[0].Method, refund.Method)
+		}
+	}
+
+	ledger, _ := paymentRepo.FindByOrderID(ctx, order.ID)
+	for _, payment := range ledger {
+		if payment.Refundable() != 0 {
+			t.Errorf("Expected payment %v to be refunded in full, %d left", payment.Method, payment.Refundable())
+		}
+	}
+}
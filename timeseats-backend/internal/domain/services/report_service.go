@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+)
+
+// SalesSummary covers paid or confirmed orders; the Cancelled fields cover
+// cancelled orders in the same range. GrossSales less DiscountAmount is what
+// was charged, and Revenue is that net of RefundedAmount.
+type SalesSummary struct {
+	Orders            int
+	GrossSales        int
+	DiscountAmount    int
+	Revenue           int
+	AverageOrderValue int
+	RefundedAmount    int
+	CancelledOrders   int
+	CancelledValue    int
+	// Taxes splits Revenue by tax rate. The tax is worked out on the total
+	// of each rate, so it can differ by a few yen from the sum of the tax on
+	// the receipts.
+	Taxes []TaxAmount
+}
+
+type ReportService interface {
+	GetSummary(ctx context.Context, filter repositories.ReportFilter) (*SalesSummary, error)
+	GetProductSales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.ProductSales, error)
+	GetComponentSales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.ComponentSales, error)
+	GetPaymentMethodSales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.PaymentMethodSales, error)
+	GetHourlySales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.HourlySales, error)
+}
+
+type reportService struct {
+	reportRepo repositories.ReportRepository
+}
+
+func NewReportService(reportRepo repositories.ReportRepository) ReportService {
+	return &reportService{reportRepo: reportRepo}
+}
+
+func (s *reportService) GetSummary(ctx context.Context, filter repositories.ReportFilter) (*SalesSummary, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+
+	sales, err := s.reportRepo.SalesTotals(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	cancelled, err := s.reportRepo.CancelledTotals(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	taxSales, err := s.reportRepo.TaxSales(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &SalesSummary{
+		Orders:          sales.Orders,
+		GrossSales:      sales.Gross,
+		DiscountAmount:  sales.Discounts,
+		Revenue:         sales.Revenue,
+		RefundedAmount:  sales.Refunded,
+		CancelledOrders: cancelled.Orders,
+		CancelledValue:  cancelled.Revenue,
+		Taxes:           make([]TaxAmount, len(taxSales)),
+	}
+	for i, row := range taxSales {
+		amount := row.Sales - row.Refunded
+		summary.Taxes[i] = TaxAmount{Rate: row.Rate, Amount: amount, Tax: IncludedTax(amount, row.Rate)}
+	}
+	if sales.Orders > 0 {
+		// Rounded to the nearest yen.
+		summary.AverageOrderValue = (sales.Revenue + sales.Orders/2) / sales.Orders
+	}
+
+	return summary, nil
+}
+
+func (s *reportService) GetProductSales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.ProductSales, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+	return s.reportRepo.ProductSales(ctx, filter)
+}
+
+func (s *reportService) GetComponentSales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.ComponentSales, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+	return s.reportRepo.ComponentSales(ctx, filter)
+}
+
+func (s *reportService) GetPaymentMethodSales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.PaymentMethodSales, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+	return s.reportRepo.PaymentMethodSales(ctx, filter)
+}
+
+func (s *reportService) GetHourlySales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.HourlySales, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+	return s.reportRepo.HourlySales(ctx, filter)
+}
+
+func validateReportFilter(filter repositories.ReportFilter) error {
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return ErrInvalidTimeRange
+	}
+	return nil
+}
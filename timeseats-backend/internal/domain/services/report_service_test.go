@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type mockReportRepository struct {
+	sales     repositories.SalesTotals
+	cancelled repositories.SalesTotals
+	taxes     []repositories.TaxSales
+}
+
+func (r *mockReportRepository) SalesTotals(ctx context.Context, filter repositories.ReportFilter) (*repositories.SalesTotals, error) {
+	return &r.sales, nil
+}
+
+func (r *mockReportRepository) CancelledTotals(ctx context.Context, filter repositories.ReportFilter) (*repositories.SalesTotals, error) {
+	return &r.cancelled, nil
+}
+
+func (r *mockReportRepository) ProductSales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.ProductSales, error) {
+	return nil, nil
+}
+
+func (r *mockReportRepository) ComponentSales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.ComponentSales, error) {
+	return nil, nil
+}
+
+func (r *mockReportRepository) TaxSales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.TaxSales, error) {
+	return r.taxes, nil
+}
+
+func (r *mockReportRepository) PaymentMethodSales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.PaymentMethodSales, error) {
+	return nil, nil
+}
+
+func (r *mockReportRepository) HourlySales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.HourlySales, error) {
+	return nil, nil
+}
+
+func TestReportService_GetSummary(t *testing.T) {
+	repo := &mockReportRepository{
+		sales:     repositories.SalesTotals{Orders: 3, Revenue: 1000},
+		cancelled: repositories.SalesTotals{Orders: 1, Revenue: 400},
+		taxes: []repositories.TaxSales{
+			{Rate: types.REDUCED_TAX_RATE, Sales: 900, Refunded: 200},
+			{Rate: types.STANDARD_TAX_RATE, Sales: 300},
+		},
+	}
+	service := NewReportService(repo)
+
+	summary, err := service.GetSummary(context.Background(), repositories.ReportFilter{})
+	if err != nil {
+		t.Fatalf("GetSummary failed: %v", err)
+	}
+
+	if summary.AverageOrderValue != 333 {
+		t.Errorf("Expected average order value 333, got %d", summary.AverageOrderValue)
+	}
+	if summary.CancelledOrders != 1 || summary.CancelledValue != 400 {
+		t.Errorf("Expected 1 cancelled order worth 400, got %d worth %d", summary.CancelledOrders, summary.CancelledValue)
+	}
+	// 700 at 8% holds 51.85 yen of tax and 300 at 10% 27.27 yen.
+	expected := []TaxAmount{{Rate: types.REDUCED_TAX_RATE, Amount: 700, Tax: 51}, {Rate: types.STANDARD_TAX_RATE, Amount: 300, Tax: 27}}
+	if !slices.Equal(summary.Taxes, expected) {
+		t.Errorf("Expected taxes %+v, got %+v", expected, summary.Taxes)
+	}
+}
+
+func TestReportService_GetSummaryWithoutSales(t *testing.T) {
+	service := NewReportService(&mockReportRepository{})
+
+	summary, err := service.GetSummary(context.Background(), repositories.ReportFilter{})
+	if err != nil {
+		t.Fatalf("GetSummary failed: %v", err)
+	}
+	if summary.AverageOrderValue != 0 {
+		t.Errorf("Expected average order value 0, got %d", summary.AverageOrderValue)
+	}
+}
+
+func TestReportService_InvalidTimeRange(t *testing.T) {
+	service := NewReportService(&mockReportRepository{})
+
+	now := time.Now()
+	filter := repositories.ReportFilter{From: now, To: now.Add(-time.Hour)}
+	if _, err := service.GetHourlySales(context.Background(), filter); !errors.Is(err, ErrInvalidTimeRange) {
+		t.Errorf("Expected ErrInvalidTimeRange, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ReservationSweeper cancels RESERVED orders that stay unpaid for longer than
+// the reservation TTL, releasing the inventory they hold.
+type ReservationSweeper struct {
+	orderService OrderService
+	ttl          time.Duration
+	interval     time.Duration
+	clock        Clock
+}
+
+func NewReservationSweeper(orderService OrderService, ttl, interval time.Duration, clock Clock) *ReservationSweeper {
+	return &ReservationSweeper{
+		orderService: orderService,
+		ttl:          ttl,
+		interval:     interval,
+		clock:        clock,
+	}
+}
+
+func (s *ReservationSweeper) Sweep(ctx context.Context) (int, error) {
+	return s.orderService.CancelExpiredReservations(ctx, s.clock.Now().Add(-s.ttl))
+}
+
+// Run sweeps every interval until ctx is cancelled.
+func (s *ReservationSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cancelled, err := s.Sweep(ctx)
+			if err != nil {
+				log.Printf("reservation sweep failed: %v", err)
+			}
+			if cancelled > 0 {
+				log.Printf("cancelled %d expired reservations", cancelled)
+			}
+		}
+	}
+}
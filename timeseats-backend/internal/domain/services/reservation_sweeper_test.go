@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestReservationSweeper_Sweep(t *testing.T) {
+	start := time.Date(2026, 9, 12, 10, 0, 0, 0, time.Local)
+	clock := &fakeClock{now: start}
+	o := newTestOrderService(func(o *orderTest) { o.clock = clock })
+	service, orderRepo, invRepo := o.service, o.orderRepo, o.invRepo
+	ctx := context.Background()
+
+	sweeper := NewReservationSweeper(service, 10*time.Minute, time.Minute, clock)
+
+	invRepo.Create(ctx, &models.ProductInventory{
+		ID:               types.ID("inv1"),
+		SalesSlotID:      types.ID("slot1"),
+		ProductID:        types.ID("prod1"),
+		InitialQuantity:  10,
+		ReservedQuantity: 6,
+	})
+
+	items := []models.OrderItem{{ProductID: types.ID("prod1"), Quantity: 2, Price: 400}}
+	orderRepo.Create(ctx, &models.Order{
+		ID:          types.ID("stale"),
+		SalesSlotID: types.ID("slot1"),
+		Status:      types.RESERVED,
+		Items:       items,
+		CreatedAt:   start.Add(-15 * time.Minute),
+	})
+	orderRepo.Create(ctx, &models.Order{
+		ID:          types.ID("paid"),
+		SalesSlotID: types.ID("slot1"),
+		Status:      types.RESERVED,
+		TotalAmount: 800,
+		PaidAmount:  800,
+		IsPaid:      true,
+		Items:       items,
+		CreatedAt:   start.Add(-15 * time.Minute),
+	})
+	orderRepo.Create(ctx, &models.Order{
+		ID:          types.ID("fresh"),
+		SalesSlotID: types.ID("slot1"),
+		Status:      types.RESERVED,
+		Items:       items,
+		CreatedAt:   start.Add(-5 * time.Minute),
+	})
+
+	cancelled, err := sweeper.Sweep(ctx)
+	if err != nil {
+		t.Fatalf("Sweep failed: %v", err)
+	}
+	if cancelled != 1 {
+		t.Errorf("Expected 1 cancelled reservation, got %d", cancelled)
+	}
+
+	stale, _ := orderRepo.FindByID(ctx, types.ID("stale"))
+	if stale.Status != types.CANCELLED {
+		t.Errorf("Expected stale order to be cancelled, got %v", stale.Status)
+	}
+	if stale.CancelReason == nil || *stale.CancelReason != ReservationExpiredReason {
+		t.Errorf("Expected cancel reason %q, got %v", ReservationExpiredReason, stale.CancelReason)
+	}
+
+	paid, _ := orderRepo.FindByID(ctx, types.ID("paid"))
+	if paid.Status != types.RESERVED {
+		t.Errorf("Expected paid order to stay reserved, got %v", paid.Status)
+	}
+
+	inventory, _ := invRepo.FindByID(ctx, types.ID("inv1"))
+	if inventory.ReservedQuantity != 4 {
+		t.Errorf("Expected reserved quantity 4, got %d", inventory.ReservedQuantity)
+	}
+
+	clock.now = start.Add(6 * time.Minute)
+	cancelled, err = sweeper.Sweep(ctx)
+	if err != nil {
+		t.Fatalf("Sweep failed: %v", err)
+	}
+	if cancelled != 1 {
+		t.Errorf("Expected 1 cancelled reservation after the clock advanced, got %d", cancelled)
+	}
+
+	fresh, _ := orderRepo.FindByID(ctx, types.ID("fresh"))
+	if fresh.Status != types.CANCELLED {
+		t.Errorf("Expected fresh order to expire, got %v", fresh.Status)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
@@ -11,48 +12,79 @@ import (
 )
 
 type SalesSlotService interface {
-	CreateSalesSlot(ctx context.Context, startTime, endTime time.Time) (*models.SalesSlot, error)
+	CreateSalesSlot(ctx context.Context, startTime, endTime time.Time, ticketPrefix string) (*models.SalesSlot, error)
 	GetSalesSlot(ctx context.Context, id types.ID) (*models.SalesSlot, error)
 	GetAllSalesSlots(ctx context.Context) ([]models.SalesSlot, error)
 	FindByTimeRange(ctx context.Context, startTime, endTime time.Time) ([]models.SalesSlot, error)
+	UpdateTicketPrefix(ctx context.Context, id types.ID, ticketPrefix string) (*models.SalesSlot, error)
 	ActivateSalesSlot(ctx context.Context, id types.ID) error
 	DeactivateSalesSlot(ctx context.Context, id types.ID) error
 	AddProductToSlot(ctx context.Context, slotID types.ID, productID types.ID, initialQuantity int) (*models.ProductInventory, error)
 	UpdateInventory(ctx context.Context, slotID types.ID, productID types.ID, reserved, sold int) error
 	GetSlotInventories(ctx context.Context, slotID types.ID) ([]models.ProductInventory, error)
+	// GetSlotMenu lists the products on sale in the slot in menu order,
+	// including combos whose components are all stocked in the slot.
+	GetSlotMenu(ctx context.Context, slotID types.ID) ([]MenuItem, error)
+}
+
+// MenuItem is a product on sale in a slot with the number still available.
+// A combo is available as many times as its scarcest component allows.
+type MenuItem struct {
+	Product           models.Product
+	AvailableQuantity int
+}
+
+func (m MenuItem) IsSoldOut() bool {
+	return m.AvailableQuantity <= 0
 }
 
 type salesSlotService struct {
-	slotRepo repositories.SalesSlotRepository
-	invRepo  repositories.ProductInventoryRepository
-	prodRepo repositories.ProductRepository
+	slotRepo   repositories.SalesSlotRepository
+	invRepo    repositories.ProductInventoryRepository
+	prodRepo   repositories.ProductRepository
+	transactor repositories.Transactor
+	publisher  events.Publisher
+	audit      AuditLogService
 }
 
 func NewSalesSlotService(
 	slotRepo repositories.SalesSlotRepository,
 	invRepo repositories.ProductInventoryRepository,
 	prodRepo repositories.ProductRepository,
+	transactor repositories.Transactor,
+	publisher events.Publisher,
+	audit AuditLogService,
 ) SalesSlotService {
 	return &salesSlotService{
-		slotRepo: slotRepo,
-		invRepo:  invRepo,
-		prodRepo: prodRepo,
+		slotRepo:   slotRepo,
+		invRepo:    invRepo,
+		prodRepo:   prodRepo,
+		transactor: transactor,
+		publisher:  publisher,
+		audit:      audit,
 	}
 }
 
-func (s *salesSlotService) CreateSalesSlot(ctx context.Context, startTime, endTime time.Time) (*models.SalesSlot, error) {
+func (s *salesSlotService) CreateSalesSlot(ctx context.Context, startTime, endTime time.Time, ticketPrefix string) (*models.SalesSlot, error) {
 	if endTime.Before(startTime) {
 		return nil, ErrInvalidTimeRange
 	}
 
 	slot := &models.SalesSlot{
-		ID:        types.ID(uuid.New().String()),
-		StartTime: startTime,
-		EndTime:   endTime,
-		IsActive:  false,
+		ID:           types.ID(uuid.New().String()),
+		StartTime:    startTime,
+		EndTime:      endTime,
+		IsActive:     false,
+		TicketPrefix: ticketPrefix,
 	}
 
-	if err := s.slotRepo.Create(ctx, slot); err != nil {
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.slotRepo.Create(ctx, slot); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntitySalesSlot, slot.ID, "create", nil, slot)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -71,12 +103,72 @@ func (s *salesSlotService) FindByTimeRange(ctx context.Context, startTime, endTi
 	return s.slotRepo.FindByTimeRange(ctx, startTime, endTime)
 }
 
+func (s *salesSlotService) UpdateTicketPrefix(ctx context.Context, id types.ID, ticketPrefix string) (*models.SalesSlot, error) {
+	var slot *models.SalesSlot
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.slotRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		updated := *before
+		updated.TicketPrefix = ticketPrefix
+		if err := s.slotRepo.Update(ctx, &updated); err != nil {
+			return err
+		}
+		slot = &updated
+
+		return s.audit.Record(ctx, AuditEntitySalesSlot, id, "update_ticket_prefix", before, slot)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slot, nil
+}
+
 func (s *salesSlotService) ActivateSalesSlot(ctx context.Context, id types.ID) error {
-	return s.slotRepo.ActivateSlot(ctx, id)
+	if err := s.setSlotActive(ctx, id, true); err != nil {
+		return err
+	}
+
+	s.publisher.Publish(events.NewSalesSlotEvent(&models.SalesSlot{ID: id, IsActive: true}))
+
+	return nil
 }
 
 func (s *salesSlotService) DeactivateSalesSlot(ctx context.Context, id types.ID) error {
-	return s.slotRepo.DeactivateSlot(ctx, id)
+	if err := s.setSlotActive(ctx, id, false); err != nil {
+		return err
+	}
+
+	s.publisher.Publish(events.NewSalesSlotEvent(&models.SalesSlot{ID: id, IsActive: false}))
+
+	return nil
+}
+
+func (s *salesSlotService) setSlotActive(ctx context.Context, id types.ID, active bool) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.slotRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		action := "deactivate"
+		if active {
+			err = s.slotRepo.ActivateSlot(ctx, id)
+			action = "activate"
+		} else {
+			err = s.slotRepo.DeactivateSlot(ctx, id)
+		}
+		if err != nil {
+			return err
+		}
+
+		after := *before
+		after.IsActive = active
+		return s.audit.Record(ctx, AuditEntitySalesSlot, id, action, before, &after)
+	})
 }
 
 func (s *salesSlotService) AddProductToSlot(ctx context.Context, slotID types.ID, productID types.ID, initialQuantity int) (*models.ProductInventory, error) {
@@ -85,10 +177,13 @@ func (s *salesSlotService) AddProductToSlot(ctx context.Context, slotID types.ID
 		return nil, err
 	}
 
-	_, err = s.prodRepo.FindByID(ctx, productID)
+	product, err := s.prodRepo.FindByID(ctx, productID)
 	if err != nil {
 		return nil, err
 	}
+	if product.IsCombo() {
+		return nil, ErrComboInventory
+	}
 
 	existing, err := s.invRepo.FindBySalesSlotAndProduct(ctx, slotID, productID)
 	if err == nil && existing != nil {
@@ -101,26 +196,102 @@ func (s *salesSlotService) AddProductToSlot(ctx context.Context, slotID types.ID
 		InitialQuantity: initialQuantity,
 	}
 
-	if err := s.invRepo.Create(ctx, inventory); err != nil {
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.invRepo.Create(ctx, inventory); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityInventory, inventory.ID, "create", nil, inventory)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	s.publisher.Publish(events.NewInventoryEvent(inventory))
+
 	return inventory, nil
 }
 
 func (s *salesSlotService) UpdateInventory(ctx context.Context, slotID types.ID, productID types.ID, reserved, sold int) error {
-	inventory, err := s.invRepo.FindBySalesSlotAndProduct(ctx, slotID, productID)
+	var inventory models.ProductInventory
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.invRepo.FindBySalesSlotAndProduct(ctx, slotID, productID)
+		if err != nil {
+			return err
+		}
+
+		if reserved+sold > before.InitialQuantity {
+			return ErrInsufficientInventory
+		}
+
+		if err := s.invRepo.UpdateQuantities(ctx, before.ID, reserved, sold); err != nil {
+			return err
+		}
+
+		inventory = *before
+		inventory.ReservedQuantity = reserved
+		inventory.SoldQuantity = sold
+		return s.audit.Record(ctx, AuditEntityInventory, inventory.ID, "override_quantities", before, &inventory)
+	})
 	if err != nil {
 		return err
 	}
 
-	if reserved+sold > inventory.InitialQuantity {
-		return ErrInsufficientInventory
-	}
+	s.publisher.Publish(events.NewInventoryEvent(&inventory))
 
-	return s.invRepo.UpdateQuantities(ctx, inventory.ID, reserved, sold)
+	return nil
 }
 
 func (s *salesSlotService) GetSlotInventories(ctx context.Context, slotID types.ID) ([]models.ProductInventory, error) {
 	return s.invRepo.FindBySalesSlotID(ctx, slotID)
 }
+
+func (s *salesSlotService) GetSlotMenu(ctx context.Context, slotID types.ID) ([]MenuItem, error) {
+	if _, err := s.slotRepo.FindByID(ctx, slotID); err != nil {
+		return nil, err
+	}
+
+	inventories, err := s.invRepo.FindBySalesSlotID(ctx, slotID)
+	if err != nil {
+		return nil, err
+	}
+	available := make(map[types.ID]int, len(inventories))
+	for _, inventory := range inventories {
+		available[inventory.ProductID] = inventory.GetAvailableQuantity()
+	}
+
+	products, err := s.prodRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var menu []MenuItem
+	for _, product := range products {
+		quantity, ok := menuAvailability(&product, available)
+		if !ok {
+			continue
+		}
+		menu = append(menu, MenuItem{Product: product, AvailableQuantity: quantity})
+	}
+	return menu, nil
+}
+
+// menuAvailability reports how many of the product can still be sold, and
+// whether it is on sale in the slot at all.
+func menuAvailability(product *models.Product, available map[types.ID]int) (int, bool) {
+	if !product.IsCombo() {
+		quantity, ok := available[product.ID]
+		return quantity, ok
+	}
+
+	quantity := -1
+	for _, component := range product.Components {
+		stock, ok := available[component.ProductID]
+		if !ok {
+			return 0, false
+		}
+		if n := max(stock, 0) / component.Quantity; quantity < 0 || n < quantity {
+			quantity = n
+		}
+	}
+	return quantity, true
+}
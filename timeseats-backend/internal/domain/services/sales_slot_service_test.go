@@ -2,9 +2,12 @@ package services
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/events"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
@@ -98,6 +101,7 @@ func (r *mockSalesSlotRepository) DeactivateSlot(ctx context.Context, id types.I
 }
 
 type mockInventoryRepository struct {
+	mu          sync.Mutex
 	inventories map[types.ID]*models.ProductInventory
 }
 
@@ -107,19 +111,43 @@ func newMockInventoryRepository() *mockInventoryRepository {
 	}
 }
 
+func (r *mockInventoryRepository) snapshot() func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := make(map[types.ID]models.ProductInventory, len(r.inventories))
+	for id, inventory := range r.inventories {
+		saved[id] = *inventory
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.inventories = make(map[types.ID]*models.ProductInventory, len(saved))
+		for id, inventory := range saved {
+			r.inventories[id] = &inventory
+		}
+	}
+}
+
 func (r *mockInventoryRepository) Create(ctx context.Context, inventory *models.ProductInventory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.inventories[inventory.ID] = inventory
 	return nil
 }
 
 func (r *mockInventoryRepository) FindByID(ctx context.Context, id types.ID) (*models.ProductInventory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	if inv, exists := r.inventories[id]; exists {
-		return inv, nil
+		found := *inv
+		return &found, nil
 	}
 	return nil, repositories.NewErrNotFound("ProductInventory", id)
 }
 
 func (r *mockInventoryRepository) FindAll(ctx context.Context) ([]models.ProductInventory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	var invs []models.ProductInventory
 	for _, inv := range r.inventories {
 		invs = append(invs, *inv)
@@ -128,6 +156,8 @@ func (r *mockInventoryRepository) FindAll(ctx context.Context) ([]models.Product
 }
 
 func (r *mockInventoryRepository) Update(ctx context.Context, inventory *models.ProductInventory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	if _, exists := r.inventories[inventory.ID]; !exists {
 		return repositories.NewErrNotFound("ProductInventory", inventory.ID)
 	}
@@ -136,6 +166,8 @@ func (r *mockInventoryRepository) Update(ctx context.Context, inventory *models.
 }
 
 func (r *mockInventoryRepository) Delete(ctx context.Context, id types.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	if _, exists := r.inventories[id]; !exists {
 		return repositories.NewErrNotFound("ProductInventory", id)
 	}
@@ -144,6 +176,8 @@ func (r *mockInventoryRepository) Delete(ctx context.Context, id types.ID) error
 }
 
 func (r *mockInventoryRepository) FindBySalesSlotID(ctx context.Context, salesSlotID types.ID) ([]models.ProductInventory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	var result []models.ProductInventory
 	for _, inv := range r.inventories {
 		if inv.SalesSlotID == salesSlotID {
@@ -154,6 +188,8 @@ func (r *mockInventoryRepository) FindBySalesSlotID(ctx context.Context, salesSl
 }
 
 func (r *mockInventoryRepository) FindByProductID(ctx context.Context, productID types.ID) ([]models.ProductInventory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	var result []models.ProductInventory
 	for _, inv := range r.inventories {
 		if inv.ProductID == productID {
@@ -164,15 +200,20 @@ func (r *mockInventoryRepository) FindByProductID(ctx context.Context, productID
 }
 
 func (r *mockInventoryRepository) FindBySalesSlotAndProduct(ctx context.Context, salesSlotID, productID types.ID) (*models.ProductInventory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	for _, inv := range r.inventories {
 		if inv.SalesSlotID == salesSlotID && inv.ProductID == productID {
-			return inv, nil
+			found := *inv
+			return &found, nil
 		}
 	}
 	return nil, repositories.NewErrNotFound("ProductInventory", "")
 }
 
 func (r *mockInventoryRepository) UpdateQuantities(ctx context.Context, id types.ID, reserved, sold int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	inv, exists := r.inventories[id]
 	if !exists {
 		return repositories.NewErrNotFound("ProductInventory", id)
@@ -182,17 +223,35 @@ func (r *mockInventoryRepository) UpdateQuantities(ctx context.Context, id types
 	return nil
 }
 
+func (r *mockInventoryRepository) AdjustQuantities(ctx context.Context, id types.ID, reservedDelta, soldDelta int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	inv, exists := r.inventories[id]
+	if !exists {
+		return repositories.NewErrNotFound("ProductInventory", id)
+	}
+	if inv.ReservedQuantity+reservedDelta < 0 || inv.SoldQuantity+soldDelta < 0 {
+		return repositories.ErrInsufficientQuantity
+	}
+	if reservedDelta+soldDelta > 0 && inv.GetAvailableQuantity() < reservedDelta+soldDelta {
+		return repositories.ErrInsufficientQuantity
+	}
+	inv.ReservedQuantity += reservedDelta
+	inv.SoldQuantity += soldDelta
+	return nil
+}
+
 func TestSalesSlotService_CreateSalesSlot(t *testing.T) {
 	slotRepo := newMockSalesSlotRepository()
 	invRepo := newMockInventoryRepository()
 	productRepo := newMockProductRepository()
-	service := NewSalesSlotService(slotRepo, invRepo, productRepo)
+	service := NewSalesSlotService(slotRepo, invRepo, productRepo, &mockTransactor{}, &mockPublisher{}, newTestAuditLogService())
 	ctx := context.Background()
 
 	start := time.Now()
 	end := start.Add(2 * time.Hour)
 
-	slot, err := service.CreateSalesSlot(ctx, start, end)
+	slot, err := service.CreateSalesSlot(ctx, start, end, "")
 	if err != nil {
 		t.Errorf("CreateSalesSlot failed: %v", err)
 	}
@@ -214,10 +273,11 @@ func TestSalesSlotService_ActivateDeactivate(t *testing.T) {
 	slotRepo := newMockSalesSlotRepository()
 	invRepo := newMockInventoryRepository()
 	productRepo := newMockProductRepository()
-	service := NewSalesSlotService(slotRepo, invRepo, productRepo)
+	publisher := &mockPublisher{}
+	service := NewSalesSlotService(slotRepo, invRepo, productRepo, &mockTransactor{}, publisher, newTestAuditLogService())
 	ctx := context.Background()
 
-	slot, _ := service.CreateSalesSlot(ctx, time.Now(), time.Now().Add(2*time.Hour))
+	slot, _ := service.CreateSalesSlot(ctx, time.Now(), time.Now().Add(2*time.Hour), "")
 
 	err := service.ActivateSalesSlot(ctx, slot.ID)
 	if err != nil {
@@ -238,16 +298,21 @@ func TestSalesSlotService_ActivateDeactivate(t *testing.T) {
 	if slot.IsActive {
 		t.Error("Expected slot to be inactive")
 	}
+
+	published := publisher.types()
+	if len(published) != 2 || published[0] != events.SalesSlotActivated || published[1] != events.SalesSlotDeactivated {
+		t.Errorf("Expected activated and deactivated events, got %v", published)
+	}
 }
 
 func TestSalesSlotService_AddProductToSlot(t *testing.T) {
 	slotRepo := newMockSalesSlotRepository()
 	invRepo := newMockInventoryRepository()
 	productRepo := newMockProductRepository()
-	service := NewSalesSlotService(slotRepo, invRepo, productRepo)
+	service := NewSalesSlotService(slotRepo, invRepo, productRepo, &mockTransactor{}, &mockPublisher{}, newTestAuditLogService())
 	ctx := context.Background()
 
-	slot, _ := service.CreateSalesSlot(ctx, time.Now(), time.Now().Add(2*time.Hour))
+	slot, _ := service.CreateSalesSlot(ctx, time.Now(), time.Now().Add(2*time.Hour), "")
 
 	product := &models.Product{
 		Name:  "Test Product",
@@ -280,15 +345,15 @@ func TestSalesSlotService_FindByTimeRange(t *testing.T) {
 	slotRepo := newMockSalesSlotRepository()
 	invRepo := newMockInventoryRepository()
 	productRepo := newMockProductRepository()
-	service := NewSalesSlotService(slotRepo, invRepo, productRepo)
+	service := NewSalesSlotService(slotRepo, invRepo, productRepo, &mockTransactor{}, &mockPublisher{}, newTestAuditLogService())
 	ctx := context.Background()
 
 	start := time.Now()
 	mid := start.Add(1 * time.Hour)
 	end := start.Add(2 * time.Hour)
 
-	service.CreateSalesSlot(ctx, start, mid)
-	service.CreateSalesSlot(ctx, mid, end)
+	service.CreateSalesSlot(ctx, start, mid, "")
+	service.CreateSalesSlot(ctx, mid, end, "")
 
 	slots, err := service.FindByTimeRange(ctx, start, end)
 	if err != nil {
@@ -299,3 +364,53 @@ func TestSalesSlotService_FindByTimeRange(t *testing.T) {
 		t.Errorf("Expected 2 slots, got %d", len(slots))
 	}
 }
+
+func TestSalesSlotService_GetSlotMenu(t *testing.T) {
+	slotRepo := newMockSalesSlotRepository()
+	invRepo := newMockInventoryRepository()
+	productRepo := newMockProductRepository()
+	service := NewSalesSlotService(slotRepo, invRepo, productRepo, &mockTransactor{}, &mockPublisher{}, newTestAuditLogService())
+	ctx := context.Background()
+
+	slot, _ := service.CreateSalesSlot(ctx, time.Now(), time.Now().Add(2*time.Hour), "")
+	productRepo.Create(ctx, &models.Product{ID: "yakisoba", Name: "焼きそば", Price: 400})
+	productRepo.Create(ctx, &models.Product{ID: "drink", Name: "お茶", Price: 150})
+	productRepo.Create(ctx, &models.Product{ID: "crepe", Name: "クレープ", Price: 300})
+	productRepo.Create(ctx, &models.Product{ID: "set", Name: "焼きそばセット", Price: 500, Components: []models.ComboComponent{
+		{ComboID: "set", ProductID: "yakisoba", Quantity: 1},
+		{ComboID: "set", ProductID: "drink", Quantity: 2},
+	}})
+	productRepo.Create(ctx, &models.Product{ID: "crepe-set", Name: "クレープセット", Price: 400, Components: []models.ComboComponent{
+		{ComboID: "crepe-set", ProductID: "crepe", Quantity: 1},
+		{ComboID: "crepe-set", ProductID: "drink", Quantity: 1},
+	}})
+	invRepo.Create(ctx, &models.ProductInventory{ID: "inv-yakisoba", SalesSlotID: slot.ID, ProductID: "yakisoba", InitialQuantity: 10})
+	invRepo.Create(ctx, &models.ProductInventory{ID: "inv-drink", SalesSlotID: slot.ID, ProductID: "drink", InitialQuantity: 5})
+
+	if _, err := service.AddProductToSlot(ctx, slot.ID, "set", 10); !errors.Is(err, ErrComboInventory) {
+		t.Errorf("Expected ErrComboInventory for a combo, got %v", err)
+	}
+
+	menu, err := service.GetSlotMenu(ctx, slot.ID)
+	if err != nil {
+		t.Fatalf("GetSlotMenu failed: %v", err)
+	}
+	available := make(map[types.ID]int)
+	for _, item := range menu {
+		available[item.Product.ID] = item.AvailableQuantity
+	}
+	if len(available) != 3 {
+		t.Errorf("Expected yakisoba, drink and the yakisoba set on the menu, got %v", available)
+	}
+	if available["set"] != 2 {
+		t.Errorf("Expected 2 sets available from 5 drinks, got %d", available["set"])
+	}
+
+	invRepo.inventories["inv-drink"].SoldQuantity = 4
+	menu, _ = service.GetSlotMenu(ctx, slot.ID)
+	for _, item := range menu {
+		if item.Product.ID == "set" && !item.IsSoldOut() {
+			t.Errorf("Expected the set to be sold out once a drink runs short, got %d available", item.AvailableQuantity)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const minPasswordLength = 8
+
+type StaffService interface {
+	CreateStaff(ctx context.Context, username, password string, role types.Role) (*models.Staff, error)
+	GetStaff(ctx context.Context, id types.ID) (*models.Staff, error)
+	GetAllStaff(ctx context.Context) ([]models.Staff, error)
+	UpdateStaff(ctx context.Context, id types.ID, role types.Role, isActive bool) (*models.Staff, error)
+	ChangePassword(ctx context.Context, id types.ID, password string) error
+	DeleteStaff(ctx context.Context, id types.ID) error
+}
+
+type staffService struct {
+	staffRepo   repositories.StaffRepository
+	sessionRepo repositories.StaffSessionRepository
+	transactor  repositories.Transactor
+	audit       AuditLogService
+}
+
+func NewStaffService(
+	staffRepo repositories.StaffRepository,
+	sessionRepo repositories.StaffSessionRepository,
+	transactor repositories.Transactor,
+	audit AuditLogService,
+) StaffService {
+	return &staffService{
+		staffRepo:   staffRepo,
+		sessionRepo: sessionRepo,
+		transactor:  transactor,
+		audit:       audit,
+	}
+}
+
+func (s *staffService) CreateStaff(ctx context.Context, username, password string, role types.Role) (*models.Staff, error) {
+	if username == "" {
+		return nil, &ServiceError{Message: "ユーザー名は必須です"}
+	}
+	_, err := s.staffRepo.FindByUsername(ctx, username)
+	if err == nil {
+		return nil, ErrDuplicateUsername
+	}
+	var notFound *repositories.ErrNotFound
+	if !errors.As(err, &notFound) {
+		return nil, err
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	staff := &models.Staff{
+		Username:     username,
+		PasswordHash: hash,
+		Role:         role,
+		IsActive:     true,
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.staffRepo.Create(ctx, staff); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityStaff, staff.ID, "create", nil, staff)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return staff, nil
+}
+
+func (s *staffService) GetStaff(ctx context.Context, id types.ID) (*models.Staff, error) {
+	return s.staffRepo.FindByID(ctx, id)
+}
+
+func (s *staffService) GetAllStaff(ctx context.Context) ([]models.Staff, error) {
+	return s.staffRepo.FindAll(ctx)
+}
+
+// UpdateStaff changes the role and active flag. Deactivating an account ends
+// its sessions.
+func (s *staffService) UpdateStaff(ctx context.Context, id types.ID, role types.Role, isActive bool) (*models.Staff, error) {
+	var staff *models.Staff
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.staffRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		updated := *before
+		updated.Role = role
+		updated.IsActive = isActive
+		if err := s.staffRepo.Update(ctx, &updated); err != nil {
+			return err
+		}
+		staff = &updated
+
+		if !isActive {
+			if err := s.sessionRepo.DeleteByStaffID(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		return s.audit.Record(ctx, AuditEntityStaff, id, "update", before, staff)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return staff, nil
+}
+
+// ChangePassword sets a new password and ends all sessions of the account.
+func (s *staffService) ChangePassword(ctx context.Context, id types.ID, password string) error {
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		staff, err := s.staffRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		staff.PasswordHash = hash
+		if err := s.staffRepo.Update(ctx, staff); err != nil {
+			return err
+		}
+
+		if err := s.sessionRepo.DeleteByStaffID(ctx, id); err != nil {
+			return err
+		}
+
+		// The snapshots omit the hash, so only the action is recorded.
+		return s.audit.Record(ctx, AuditEntityStaff, id, "change_password", nil, nil)
+	})
+}
+
+func (s *staffService) DeleteStaff(ctx context.Context, id types.ID) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.staffRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.sessionRepo.DeleteByStaffID(ctx, id); err != nil {
+			return err
+		}
+		if err := s.staffRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityStaff, id, "delete", before, nil)
+	})
+}
+
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", ErrPasswordTooShort
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"sort"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// TaxAmount is the part of an order, or of a report, charged at one rate.
+// Amount includes Tax.
+type TaxAmount struct {
+	Rate   types.TaxRate
+	Amount int
+	Tax    int
+}
+
+// ExcludingTax is Amount without the tax.
+func (t TaxAmount) ExcludingTax() int {
+	return t.Amount - t.Tax
+}
+
+// IncludedTax returns the consumption tax contained in a tax-inclusive
+// amount, rounded down to the yen.
+func IncludedTax(amount int, rate types.TaxRate) int {
+	return amount * int(rate) / (100 + int(rate))
+}
+
+// TaxBreakdown totals the order's items by tax rate after discounts, lowest
+// rate first. The tax is worked out once for each rate over the whole order
+// rather than for each item, as a qualified invoice requires, so it can
+// differ by a yen from the sum of the items' tax.
+func TaxBreakdown(order *models.Order) []TaxAmount {
+	amounts := make(map[types.TaxRate]int)
+	for _, item := range order.Items {
+		amounts[item.TaxRate] += item.NetAmount()
+	}
+	return taxAmounts(amounts)
+}
+
+func taxAmounts(amounts map[types.TaxRate]int) []TaxAmount {
+	breakdown := make([]TaxAmount, 0, len(amounts))
+	for rate, amount := range amounts {
+		breakdown = append(breakdown, TaxAmount{Rate: rate, Amount: amount, Tax: IncludedTax(amount, rate)})
+	}
+	sort.Slice(breakdown, func(i, j int) bool { return breakdown[i].Rate < breakdown[j].Rate })
+	return breakdown
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+func TestIncludedTax(t *testing.T) {
+	tests := []struct {
+		amount int
+		rate   types.TaxRate
+		tax    int
+	}{
+		{1080, types.REDUCED_TAX_RATE, 80},
+		{1100, types.STANDARD_TAX_RATE, 100},
+		// 7.40 and 9.09 yen are rounded down.
+		{100, types.REDUCED_TAX_RATE, 7},
+		{100, types.STANDARD_TAX_RATE, 9},
+		{0, types.REDUCED_TAX_RATE, 0},
+	}
+
+	for _, tt := range tests {
+		if tax := IncludedTax(tt.amount, tt.rate); tax != tt.tax {
+			t.Errorf("Expected %d yen of %d%% tax in %d, got %d", tt.tax, tt.rate, tt.amount, tax)
+		}
+	}
+}
+
+func TestTaxBreakdown(t *testing.T) {
+	t.Run("tax is rounded once per rate", func(t *testing.T) {
+		// Each 130 yen item holds 9.63 yen of tax, but the tax on the order
+		// is that of 910 yen, 67.41, not 7 times 9.
+		order := &models.Order{Items: []models.OrderItem{
+			{ProductID: "prod1", Quantity: 7, Price: 130, TaxRate: types.REDUCED_TAX_RATE},
+		}}
+		expected := []TaxAmount{{Rate: types.REDUCED_TAX_RATE, Amount: 910, Tax: 67}}
+		if breakdown := TaxBreakdown(order); !slices.Equal(breakdown, expected) {
+			t.Errorf("Expected %+v, got %+v", expected, breakdown)
+		}
+	})
+
+	t.Run("rates are kept apart", func(t *testing.T) {
+		order := &models.Order{Items: []models.OrderItem{
+			{ProductID: "prod2", Quantity: 1, Price: 220, TaxRate: types.STANDARD_TAX_RATE},
+			{ProductID: "prod1", Quantity: 2, Price: 540, TaxRate: types.REDUCED_TAX_RATE},
+		}}
+		expected := []TaxAmount{
+			{Rate: types.REDUCED_TAX_RATE, Amount: 1080, Tax: 80},
+			{Rate: types.STANDARD_TAX_RATE, Amount: 220, Tax: 20},
+		}
+		breakdown := TaxBreakdown(order)
+		if !slices.Equal(breakdown, expected) {
+			t.Errorf("Expected %+v, got %+v", expected, breakdown)
+		}
+		if breakdown[0].ExcludingTax() != 1000 {
+			t.Errorf("Expected 1000 excluding tax, got %d", breakdown[0].ExcludingTax())
+		}
+	})
+
+	t.Run("order discounts are shared between rates", func(t *testing.T) {
+		order := &models.Order{
+			Items: []models.OrderItem{
+				{ID: "item1", ProductID: "prod1", Quantity: 2, Price: 400, TaxRate: types.REDUCED_TAX_RATE},
+				{ID: "item2", ProductID: "prod2", Quantity: 1, Price: 200, TaxRate: types.STANDARD_TAX_RATE},
+			},
+			Discounts: []models.OrderDiscount{{Type: types.PERCENTAGE, Value: 10}},
+		}
+		priceOrder(order)
+
+		expected := []TaxAmount{
+			{Rate: types.REDUCED_TAX_RATE, Amount: 720, Tax: 53},
+			{Rate: types.STANDARD_TAX_RATE, Amount: 180, Tax: 16},
+		}
+		if breakdown := TaxBreakdown(order); !slices.Equal(breakdown, expected) {
+			t.Errorf("Expected %+v, got %+v", expected, breakdown)
+		}
+	})
+
+	t.Run("shares add up to the discount", func(t *testing.T) {
+		order := &models.Order{
+			Items: []models.OrderItem{
+				{ID: "item1", ProductID: "prod1", Quantity: 1, Price: 100, TaxRate: types.REDUCED_TAX_RATE},
+				{ID: "item2", ProductID: "prod2", Quantity: 1, Price: 100, TaxRate: types.STANDARD_TAX_RATE},
+				{ID: "item3", ProductID: "prod3", Quantity: 1, Price: 100, TaxRate: types.REDUCED_TAX_RATE},
+			},
+			Discounts: []models.OrderDiscount{{Type: types.FIXED_AMOUNT, Value: 100}},
+		}
+		priceOrder(order)
+
+		total := 0
+		for _, amount := range TaxBreakdown(order) {
+			total += amount.Amount
+		}
+		if total != 200 || order.TotalAmount != 200 {
+			t.Errorf("Expected 200 after the discount, got %d over the rates and a total of %d", total, order.TotalAmount)
+		}
+		if order.Items[2].DiscountAmount != 34 {
+			t.Errorf("Expected the last item to take the odd yen, got %d", order.Items[2].DiscountAmount)
+		}
+	})
+}
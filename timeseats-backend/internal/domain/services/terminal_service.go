@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+const (
+	terminalKeyPrefix       = "tsk_"
+	terminalKeyDisplayChars = 8
+)
+
+type TerminalService interface {
+	// RegisterTerminal returns the new terminal and its API key. The key is
+	// not stored and cannot be shown again.
+	RegisterTerminal(ctx context.Context, name string) (*models.Terminal, string, error)
+	GetAllTerminals(ctx context.Context) ([]models.Terminal, error)
+	RotateKey(ctx context.Context, id types.ID) (*models.Terminal, string, error)
+	RevokeTerminal(ctx context.Context, id types.ID) (*models.Terminal, error)
+	// Authenticate returns the unrevoked terminal the API key belongs to.
+	Authenticate(ctx context.Context, key string) (*models.Terminal, error)
+}
+
+type terminalService struct {
+	terminalRepo repositories.TerminalRepository
+	transactor   repositories.Transactor
+	clock        Clock
+	audit        AuditLogService
+}
+
+func NewTerminalService(
+	terminalRepo repositories.TerminalRepository,
+	transactor repositories.Transactor,
+	clock Clock,
+	audit AuditLogService,
+) TerminalService {
+	return &terminalService{
+		terminalRepo: terminalRepo,
+		transactor:   transactor,
+		clock:        clock,
+		audit:        audit,
+	}
+}
+
+func (s *terminalService) RegisterTerminal(ctx context.Context, name string) (*models.Terminal, string, error) {
+	if name == "" {
+		return nil, "", &ServiceError{Message: "端末名は必須です"}
+	}
+
+	terminal := &models.Terminal{Name: name}
+	key, err := setTerminalKey(terminal)
+	if err != nil {
+		return nil, "", err
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.terminalRepo.Create(ctx, terminal); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, AuditEntityTerminal, terminal.ID, "register", nil, terminal)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return terminal, key, nil
+}
+
+func (s *terminalService) GetAllTerminals(ctx context.Context) ([]models.Terminal, error) {
+	return s.terminalRepo.FindAll(ctx)
+}
+
+// RotateKey replaces the API key, which also reinstates a revoked terminal.
+func (s *terminalService) RotateKey(ctx context.Context, id types.ID) (*models.Terminal, string, error) {
+	var terminal *models.Terminal
+	var key string
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.terminalRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		updated := *before
+		if key, err = setTerminalKey(&updated); err != nil {
+			return err
+		}
+		updated.RevokedAt = nil
+		if err := s.terminalRepo.Update(ctx, &updated); err != nil {
+			return err
+		}
+		terminal = &updated
+
+		return s.audit.Record(ctx, AuditEntityTerminal, id, "rotate_key", before, terminal)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return terminal, key, nil
+}
+
+func (s *terminalService) RevokeTerminal(ctx context.Context, id types.ID) (*models.Terminal, error) {
+	var terminal *models.Terminal
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.terminalRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		terminal = before
+		if before.IsRevoked() {
+			return nil
+		}
+
+		updated := *before
+		now := s.clock.Now()
+		updated.RevokedAt = &now
+		if err := s.terminalRepo.Update(ctx, &updated); err != nil {
+			return err
+		}
+		terminal = &updated
+
+		return s.audit.Record(ctx, AuditEntityTerminal, id, "revoke", before, terminal)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return terminal, nil
+}
+
+func (s *terminalService) Authenticate(ctx context.Context, key string) (*models.Terminal, error) {
+	if key == "" {
+		return nil, ErrInvalidTerminalKey
+	}
+
+	terminal, err := s.terminalRepo.FindByKeyHash(ctx, hashSecretToken(key))
+	if err != nil {
+		var notFound *repositories.ErrNotFound
+		if errors.As(err, &notFound) {
+			return nil, ErrInvalidTerminalKey
+		}
+		return nil, err
+	}
+	if terminal.IsRevoked() {
+		return nil, ErrInvalidTerminalKey
+	}
+
+	return terminal, nil
+}
+
+func setTerminalKey(terminal *models.Terminal) (string, error) {
+	key, err := newSecretToken(terminalKeyPrefix)
+	if err != nil {
+		return "", err
+	}
+	terminal.KeyHash = hashSecretToken(key)
+	terminal.KeyPrefix = key[:len(terminalKeyPrefix)+terminalKeyDisplayChars]
+	return key, nil
+}
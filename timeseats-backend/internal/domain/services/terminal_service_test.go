@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"github.com/google/uuid"
+)
+
+type mockTerminalRepository struct {
+	mu        sync.Mutex
+	terminals map[types.ID]*models.Terminal
+}
+
+func newMockTerminalRepository() *mockTerminalRepository {
+	return &mockTerminalRepository{terminals: make(map[types.ID]*models.Terminal)}
+}
+
+func (r *mockTerminalRepository) Create(ctx context.Context, terminal *models.Terminal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if terminal.ID == "" {
+		terminal.ID = types.ID(uuid.New().String())
+	}
+	copied := *terminal
+	r.terminals[terminal.ID] = &copied
+	return nil
+}
+
+func (r *mockTerminalRepository) FindByID(ctx context.Context, id types.ID) (*models.Terminal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if terminal, exists := r.terminals[id]; exists {
+		copied := *terminal
+		return &copied, nil
+	}
+	return nil, repositories.NewErrNotFound("Terminal", id)
+}
+
+func (r *mockTerminalRepository) FindAll(ctx context.Context) ([]models.Terminal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var terminals []models.Terminal
+	for _, t := range r.terminals {
+		terminals = append(terminals, *t)
+	}
+	return terminals, nil
+}
+
+func (r *mockTerminalRepository) Update(ctx context.Context, terminal *models.Terminal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *terminal
+	r.terminals[terminal.ID] = &copied
+	return nil
+}
+
+func (r *mockTerminalRepository) Delete(ctx context.Context, id types.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.terminals, id)
+	return nil
+}
+
+func (r *mockTerminalRepository) FindByKeyHash(ctx context.Context, keyHash string) (*models.Terminal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.terminals {
+		if t.KeyHash == keyHash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, repositories.NewErrNotFound("Terminal", "")
+}
+
+func TestTerminalService_KeyLifecycle(t *testing.T) {
+	service := NewTerminalService(newMockTerminalRepository(), &mockTransactor{}, &fakeClock{now: time.Now()}, newTestAuditLogService())
+	ctx := context.Background()
+
+	terminal, key, err := service.RegisterTerminal(ctx, "レジ1")
+	if err != nil {
+		t.Fatalf("RegisterTerminal failed: %v", err)
+	}
+	if !strings.HasPrefix(key, terminal.KeyPrefix) || terminal.KeyHash == key {
+		t.Errorf("Expected only a hash and a display prefix of the key to be kept, got %+v", terminal)
+	}
+
+	authenticated, err := service.Authenticate(ctx, key)
+	if err != nil || authenticated.ID != terminal.ID {
+		t.Fatalf("Expected the key to identify the terminal, got %v, %v", authenticated, err)
+	}
+
+	_, rotated, err := service.RotateKey(ctx, terminal.ID)
+	if err != nil {
+		t.Fatalf("RotateKey failed: %v", err)
+	}
+	if _, err := service.Authenticate(ctx, key); !errors.Is(err, ErrInvalidTerminalKey) {
+		t.Errorf("Expected the old key to stop working, got %v", err)
+	}
+
+	if _, err := service.RevokeTerminal(ctx, terminal.ID); err != nil {
+		t.Fatalf("RevokeTerminal failed: %v", err)
+	}
+	if _, err := service.Authenticate(ctx, rotated); !errors.Is(err, ErrInvalidTerminalKey) {
+		t.Errorf("Expected a revoked terminal to be rejected, got %v", err)
+	}
+}
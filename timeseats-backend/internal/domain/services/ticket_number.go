@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+)
+
+// TicketNumberGenerator hands out ticket numbers that are unique within a
+// sales slot. It must run inside the transaction that creates the order.
+type TicketNumberGenerator interface {
+	Next(ctx context.Context, slot *models.SalesSlot) (string, error)
+}
+
+type ticketNumberGenerator struct {
+	seqRepo   repositories.TicketSequenceRepository
+	orderRepo repositories.OrderRepository
+	digits    int
+}
+
+// NewTicketNumberGenerator formats numbers as the slot's ticket prefix
+// followed by the sequence zero-padded to digits.
+func NewTicketNumberGenerator(seqRepo repositories.TicketSequenceRepository, orderRepo repositories.OrderRepository, digits int) TicketNumberGenerator {
+	return &ticketNumberGenerator{
+		seqRepo:   seqRepo,
+		orderRepo: orderRepo,
+		digits:    digits,
+	}
+}
+
+func (g *ticketNumberGenerator) Next(ctx context.Context, slot *models.SalesSlot) (string, error) {
+	for {
+		seq, err := g.seqRepo.Next(ctx, slot.ID)
+		if err != nil {
+			return "", err
+		}
+
+		ticketNumber := fmt.Sprintf("%s%0*d", slot.TicketPrefix, g.digits, seq)
+		// Skip numbers already taken by a manually entered paper ticket.
+		exists, err := g.orderRepo.TicketNumberExists(ctx, slot.ID, ticketNumber)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return ticketNumber, nil
+		}
+	}
+}
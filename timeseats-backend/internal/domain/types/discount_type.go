@@ -0,0 +1,37 @@
+package types
+
+// DiscountType tells how a discount is worked out. PRICE_OVERRIDE is only
+// used for manual price overrides on an order line.
+type DiscountType int
+
+const (
+	_ DiscountType = iota
+	FIXED_AMOUNT
+	PERCENTAGE
+	BUY_X_GET_Y
+	PRICE_OVERRIDE
+)
+
+func (t DiscountType) String() string {
+	switch t {
+	case FIXED_AMOUNT:
+		return "FIXED_AMOUNT"
+	case PERCENTAGE:
+		return "PERCENTAGE"
+	case BUY_X_GET_Y:
+		return "BUY_X_GET_Y"
+	case PRICE_OVERRIDE:
+		return "PRICE_OVERRIDE"
+	default:
+		return "FIXED_AMOUNT"
+	}
+}
+
+func ParseDiscountType(s string) (DiscountType, bool) {
+	for t := FIXED_AMOUNT; t <= PRICE_OVERRIDE; t++ {
+		if t.String() == s {
+			return t, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,31 @@
+package types
+
+// DrawerMovementType tells whether cash was put into or taken out of a cash
+// drawer outside of a sale, e.g. adding change or paying for supplies.
+type DrawerMovementType int
+
+const (
+	_ DrawerMovementType = iota
+	PAY_IN
+	PAY_OUT
+)
+
+func (t DrawerMovementType) String() string {
+	switch t {
+	case PAY_IN:
+		return "PAY_IN"
+	case PAY_OUT:
+		return "PAY_OUT"
+	default:
+		return "PAY_IN"
+	}
+}
+
+func ParseDrawerMovementType(s string) (DrawerMovementType, bool) {
+	for t := PAY_IN; t <= PAY_OUT; t++ {
+		if t.String() == s {
+			return t, true
+		}
+	}
+	return 0, false
+}
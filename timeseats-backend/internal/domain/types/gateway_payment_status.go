@@ -0,0 +1,29 @@
+package types
+
+type GatewayPaymentStatus int
+
+const (
+	_ GatewayPaymentStatus = iota
+	GATEWAY_PAYMENT_PENDING
+	GATEWAY_PAYMENT_COMPLETED
+	GATEWAY_PAYMENT_FAILED
+	GATEWAY_PAYMENT_EXPIRED
+	GATEWAY_PAYMENT_CANCELLED
+)
+
+func (s GatewayPaymentStatus) String() string {
+	switch s {
+	case GATEWAY_PAYMENT_PENDING:
+		return "PENDING"
+	case GATEWAY_PAYMENT_COMPLETED:
+		return "COMPLETED"
+	case GATEWAY_PAYMENT_FAILED:
+		return "FAILED"
+	case GATEWAY_PAYMENT_EXPIRED:
+		return "EXPIRED"
+	case GATEWAY_PAYMENT_CANCELLED:
+		return "CANCELLED"
+	default:
+		return "PENDING"
+	}
+}
@@ -7,8 +7,20 @@ const (
 	RESERVED
 	CONFIRMED
 	CANCELLED
+	PREPARING
+	READY
+	DELIVERED
 )
 
+// orderStatusTransitions lists the statuses each status may move to. A
+// CONFIRMED order is queued for the kitchen until it starts PREPARING.
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	RESERVED:  {CONFIRMED, CANCELLED},
+	CONFIRMED: {PREPARING},
+	PREPARING: {READY},
+	READY:     {DELIVERED},
+}
+
 func (s OrderStatus) String() string {
 	switch s {
 	case RESERVED:
@@ -17,7 +29,31 @@ func (s OrderStatus) String() string {
 		return "CONFIRMED"
 	case CANCELLED:
 		return "CANCELLED"
+	case PREPARING:
+		return "PREPARING"
+	case READY:
+		return "READY"
+	case DELIVERED:
+		return "DELIVERED"
 	default:
 		return "RESERVED"
 	}
 }
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+func ParseOrderStatus(s string) (OrderStatus, bool) {
+	for status := RESERVED; status <= DELIVERED; status++ {
+		if status.String() == s {
+			return status, true
+		}
+	}
+	return 0, false
+}
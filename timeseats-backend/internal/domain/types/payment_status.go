@@ -0,0 +1,26 @@
+package types
+
+type PaymentStatus int
+
+const (
+	_ PaymentStatus = iota
+	PAYMENT_PENDING
+	PAYMENT_COMPLETED
+	PAYMENT_FAILED
+	PAYMENT_UNAPPLIED
+)
+
+func (s PaymentStatus) String() string {
+	switch s {
+	case PAYMENT_PENDING:
+		return "PENDING"
+	case PAYMENT_COMPLETED:
+		return "COMPLETED"
+	case PAYMENT_FAILED:
+		return "FAILED"
+	case PAYMENT_UNAPPLIED:
+		return "UNAPPLIED"
+	default:
+		return "PENDING"
+	}
+}
@@ -0,0 +1,31 @@
+package types
+
+// PrinterKind tells what a printer prints: order tickets for a kitchen
+// station or receipts at a counter.
+type PrinterKind int
+
+const (
+	_ PrinterKind = iota
+	KITCHEN_PRINTER
+	RECEIPT_PRINTER
+)
+
+func (k PrinterKind) String() string {
+	switch k {
+	case KITCHEN_PRINTER:
+		return "KITCHEN"
+	case RECEIPT_PRINTER:
+		return "RECEIPT"
+	default:
+		return "KITCHEN"
+	}
+}
+
+func ParsePrinterKind(s string) (PrinterKind, bool) {
+	for kind := KITCHEN_PRINTER; kind <= RECEIPT_PRINTER; kind++ {
+		if kind.String() == s {
+			return kind, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,31 @@
+package types
+
+// QRCodeStatus tracks a dynamic payment QR code from the time it is shown
+// until it is paid or can no longer be.
+type QRCodeStatus int
+
+const (
+	_ QRCodeStatus = iota
+	QR_CODE_ACTIVE
+	QR_CODE_COMPLETED
+	QR_CODE_EXPIRED
+	QR_CODE_CANCELLED
+	QR_CODE_FAILED
+)
+
+func (s QRCodeStatus) String() string {
+	switch s {
+	case QR_CODE_ACTIVE:
+		return "ACTIVE"
+	case QR_CODE_COMPLETED:
+		return "COMPLETED"
+	case QR_CODE_EXPIRED:
+		return "EXPIRED"
+	case QR_CODE_CANCELLED:
+		return "CANCELLED"
+	case QR_CODE_FAILED:
+		return "FAILED"
+	default:
+		return "ACTIVE"
+	}
+}
@@ -0,0 +1,37 @@
+package types
+
+// Role decides which staff endpoints an account may call. ADMIN may call
+// all of them.
+type Role int
+
+const (
+	_ Role = iota
+	ADMIN
+	CASHIER
+	KITCHEN
+	PICKUP
+)
+
+func (r Role) String() string {
+	switch r {
+	case ADMIN:
+		return "ADMIN"
+	case CASHIER:
+		return "CASHIER"
+	case KITCHEN:
+		return "KITCHEN"
+	case PICKUP:
+		return "PICKUP"
+	default:
+		return "CASHIER"
+	}
+}
+
+func ParseRole(s string) (Role, bool) {
+	for role := ADMIN; role <= PICKUP; role++ {
+		if role.String() == s {
+			return role, true
+		}
+	}
+	return 0, false
+}
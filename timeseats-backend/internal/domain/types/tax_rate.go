@@ -0,0 +1,16 @@
+package types
+
+// TaxRate is a consumption tax rate in percent. Prices include the tax.
+type TaxRate int
+
+const (
+	// REDUCED_TAX_RATE applies to food and drink sold to take away.
+	REDUCED_TAX_RATE TaxRate = 8
+	// STANDARD_TAX_RATE applies to everything else, including food served
+	// to eat in.
+	STANDARD_TAX_RATE TaxRate = 10
+)
+
+func (r TaxRate) IsValid() bool {
+	return r == REDUCED_TAX_RATE || r == STANDARD_TAX_RATE
+}
@@ -11,18 +11,25 @@ import (
 	"gorm.io/gorm/logger"
 
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
 )
 
 var db *gorm.DB
 
 func Init() error {
 	godotenv.Load()
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
+	// Reports bucket orders by the hour in the session time zone.
+	timeZone := os.Getenv("DB_TIMEZONE")
+	if timeZone == "" {
+		timeZone = "Asia/Tokyo"
+	}
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=%s",
 		os.Getenv("DB_HOST"),
 		os.Getenv("DB_USER"),
 		os.Getenv("DB_PASSWORD"),
 		os.Getenv("DB_NAME"),
 		os.Getenv("DB_PORT"),
+		timeZone,
 	)
 
 	config := &gorm.Config{
@@ -37,21 +44,109 @@ func Init() error {
 
 	db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp";`)
 
+	// Ticket numbers used to be unique across all slots.
+	db.Exec(`ALTER TABLE IF EXISTS orders DROP CONSTRAINT IF EXISTS uni_orders_ticket_number;`)
+
+	// Payment references used to be indexed without being unique.
+	db.Exec(`DROP INDEX IF EXISTS idx_payments_reference;`)
+
 	err = db.AutoMigrate(
+		&models.Category{},
 		&models.Product{},
+		&models.ModifierGroup{},
+		&models.ModifierOption{},
+		&models.ComboComponent{},
 		&models.SalesSlot{},
 		&models.ProductInventory{},
 		&models.Order{},
 		&models.OrderItem{},
+		&models.OrderItemOption{},
+		&models.OrderItemComponent{},
+		&models.TicketSequence{},
+		&models.Staff{},
+		&models.StaffSession{},
+		&models.Terminal{},
+		&models.AuditLog{},
+		&models.DrawerSession{},
+		&models.DrawerMovement{},
+		&models.DrawerCount{},
+		&models.Refund{},
+		&models.RefundItem{},
+		&models.Discount{},
+		&models.OrderDiscount{},
+		&models.Receipt{},
+		&models.Printer{},
+		&models.QRCode{},
+		&models.Payment{},
+		&models.RefundPayment{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	if err := backfillPayments(db); err != nil {
+		return fmt.Errorf("failed to backfill payments: %w", err)
+	}
+
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_drawer_sessions_open_terminal
+		ON drawer_sessions (terminal_id) WHERE closed_at IS NULL`).Error; err != nil {
+		return fmt.Errorf("failed to create drawer session index: %w", err)
+	}
+
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_discounts_code
+		ON discounts (code) WHERE deleted_at IS NULL`).Error; err != nil {
+		return fmt.Errorf("failed to create discount code index: %w", err)
+	}
+
+	if err := db.Exec(`CREATE SEQUENCE IF NOT EXISTS receipt_serial_numbers`).Error; err != nil {
+		return fmt.Errorf("failed to create receipt serial number sequence: %w", err)
+	}
+
+	if err := db.Exec(`
+		CREATE OR REPLACE FUNCTION reject_audit_log_change() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit_logs is append-only';
+		END;
+		$$ LANGUAGE plpgsql;
+		DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs;
+		CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE ON audit_logs
+			FOR EACH ROW EXECUTE FUNCTION reject_audit_log_change();
+	`).Error; err != nil {
+		return fmt.Errorf("failed to protect audit log: %w", err)
+	}
+
 	log.Println("Database connected and migrated successfully")
 	return nil
 }
 
+// backfillPayments gives the orders paid before the payment ledger one
+// payment for their total, and the refunds made before it one payment line.
+func backfillPayments(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO payments (id, order_id, amount, method, reference, status, refunded_amount,
+				taken_by_id, terminal_id, completed_at, created_at, updated_at)
+			SELECT uuid_generate_v4(), o.id, o.total_amount, o.payment_method, o.transaction_id, ?, o.refunded_amount,
+				o.paid_by_id, o.paid_terminal_id, o.paid_at, COALESCE(o.paid_at, o.created_at), now()
+			FROM orders o
+			WHERE o.is_paid AND o.paid_amount = 0
+				AND NOT EXISTS (SELECT 1 FROM payments p WHERE p.order_id = o.id)`,
+			types.PAYMENT_COMPLETED).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`UPDATE orders SET paid_amount = total_amount WHERE is_paid AND paid_amount = 0`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`
+			INSERT INTO refund_payments (id, refund_id, payment_id, method, amount)
+			SELECT uuid_generate_v4(), r.id,
+				(SELECT p.id FROM payments p WHERE p.order_id = r.order_id ORDER BY p.created_at LIMIT 1),
+				r.method, r.amount
+			FROM refunds r
+			WHERE NOT EXISTS (SELECT 1 FROM refund_payments rp WHERE rp.refund_id = r.id)`).Error
+	})
+}
+
 func GetDB() *gorm.DB {
 	return db
 }
@@ -0,0 +1,170 @@
+package escpos
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// KitchenTicket renders the ticket a kitchen station works from, with the
+// ticket number large enough to read across the counter.
+func KitchenTicket(t *services.KitchenTicket) []byte {
+	b := NewBuilder()
+
+	b.Align(AlignCenter)
+	b.Bold(true)
+	b.Line(t.Station)
+	b.Bold(false)
+	if t.IsReprint {
+		b.Line("【再印刷】")
+	}
+	b.Feed(1)
+	b.Size(4, 4)
+	b.Line(t.Order.TicketNumber)
+	b.Size(1, 1)
+	b.Feed(1)
+
+	b.Align(AlignLeft)
+	orderedAt := t.Order.CreatedAt
+	if t.Order.ConfirmedAt != nil {
+		orderedAt = *t.Order.ConfirmedAt
+	}
+	b.Row("注文 "+orderedAt.Local().Format("15:04"), "印刷 "+t.PrintedAt.Local().Format("15:04"))
+	b.Rule()
+
+	for _, item := range t.Items {
+		name := string(item.ProductID)
+		if item.Product != nil {
+			name = item.Product.Name
+		}
+		b.Size(1, 2)
+		b.Bold(true)
+		b.Row(name, fmt.Sprintf("x%d", item.Quantity))
+		b.Bold(false)
+		b.Size(1, 1)
+		for _, option := range item.Options {
+			b.Line("  ・" + option.GroupName + ": " + option.Name)
+		}
+		for _, component := range item.Components {
+			componentName := string(component.ProductID)
+			if component.Product != nil {
+				componentName = component.Product.Name
+			}
+			b.Row("  - "+componentName, fmt.Sprintf("x%d", component.Quantity*item.Quantity))
+		}
+	}
+	b.Rule()
+
+	b.Feed(3)
+	b.Cut()
+	return b.Bytes()
+}
+
+// Receipt renders a receipt with the same content as the PDF one. Items
+// taxed at the reduced rate are marked with ※.
+func Receipt(r *services.ReceiptDocument) []byte {
+	b := NewBuilder()
+
+	b.Align(AlignCenter)
+	b.Size(2, 2)
+	b.Line("領収書")
+	b.Size(1, 1)
+	if r.IsCopy {
+		b.Line("【再発行（控え）】")
+	}
+	b.Feed(1)
+
+	b.Align(AlignLeft)
+	b.Row(fmt.Sprintf("No. %06d", r.Receipt.SerialNumber), "発行日 "+r.Receipt.IssuedAt.Local().Format("2006/01/02 15:04"))
+	if r.IsCopy {
+		b.Row("", "再発行日 "+r.Receipt.LastPrintedAt.Local().Format("2006/01/02 15:04"))
+	}
+	b.Line("注文番号 " + r.Order.TicketNumber)
+	b.Feed(1)
+	b.Bold(true)
+	b.Line(r.Issuer.Name)
+	b.Bold(false)
+	if r.Issuer.RegistrationNumber != "" {
+		b.Line("登録番号 " + r.Issuer.RegistrationNumber)
+	}
+	b.Rule()
+
+	for _, item := range r.Order.Items {
+		name := string(item.ProductID)
+		if item.Product != nil {
+			name = item.Product.Name
+		}
+		if item.TaxRate == types.REDUCED_TAX_RATE {
+			name += " ※"
+		}
+		b.Row(fmt.Sprintf("%s x%d", name, item.Quantity), formatYen(item.GetSubtotal()))
+		for _, option := range item.Options {
+			b.Line("  " + option.GroupName + ": " + option.Name)
+		}
+	}
+	b.Rule()
+
+	b.Row("小計", formatYen(r.Order.Subtotal()))
+	for _, discount := range r.Order.Discounts {
+		b.Row(discount.Name, formatYen(-discount.Amount))
+	}
+	b.Size(1, 2)
+	b.Bold(true)
+	b.Row("合計", formatYen(r.Order.TotalAmount))
+	b.Bold(false)
+	b.Size(1, 1)
+	for _, tax := range r.Taxes {
+		b.Row(fmt.Sprintf("  %d%%対象", tax.Rate), formatYen(tax.Amount))
+		b.Row("    内消費税等", formatYen(tax.Tax))
+	}
+	if r.Order.RefundedAmount > 0 {
+		b.Row("返金済み", formatYen(-r.Order.RefundedAmount))
+	}
+	b.Feed(1)
+	if tenders := r.Tenders(); len(tenders) > 1 {
+		b.Line("お支払方法")
+		for _, tender := range tenders {
+			b.Row("  "+r.MethodName(tender.Method), formatYen(tender.Amount))
+		}
+	} else {
+		b.Row("お支払方法", r.PaymentMethodName())
+	}
+	if r.Tendered() > 0 {
+		b.Row("お預かり", formatYen(r.Tendered()))
+		b.Row("お釣り", formatYen(r.Change()))
+	}
+	if r.Order.TransactionID != nil && *r.Order.TransactionID != "" {
+		b.Row("取引番号", *r.Order.TransactionID)
+	}
+	b.Feed(1)
+
+	b.Line("上記正に領収いたしました。")
+	if r.HasReducedRate() {
+		b.Line("※は軽減税率（8%）対象商品です。")
+	}
+
+	b.Feed(3)
+	b.Cut()
+	return b.Bytes()
+}
+
+// formatYen formats an amount as "¥1,234".
+func formatYen(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.Itoa(amount)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return sign + "¥" + b.String()
+}
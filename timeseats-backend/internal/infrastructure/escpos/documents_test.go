@@ -0,0 +1,88 @@
+package escpos
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilder()
+	b.Size(4, 4)
+	b.Line("A001")
+	b.Size(1, 1)
+	b.Row("焼きそば", "¥500")
+	b.Cut()
+	data := b.Bytes()
+
+	if !bytes.HasPrefix(data, []byte{0x1b, 0x40}) {
+		t.Error("Expected the job to start by initializing the printer")
+	}
+	if !bytes.Contains(data, []byte{0x1d, 0x21, 0x33, 'A', '0', '0', '1', '\n'}) {
+		t.Error("Expected the ticket number in quadruple size")
+	}
+	// 焼きそば in Shift_JIS, padded to the full line, and ¥ as a backslash.
+	row := append([]byte{0x8f, 0xc4, 0x82, 0xab, 0x82, 0xbb, 0x82, 0xce}, bytes.Repeat([]byte(" "), LineWidth-8-4)...)
+	row = append(row, '\\', '5', '0', '0', '\n')
+	if !bytes.Contains(data, row) {
+		t.Errorf("Expected the row to fill the line, got % x", data)
+	}
+	if !bytes.HasSuffix(data, []byte{0x1d, 0x56, 0x42, 0x00}) {
+		t.Error("Expected the job to end with a cut")
+	}
+}
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+	}{
+		{"A001", 4},
+		{"焼きそば", 8},
+		{"ｶﾚｰ", 3},
+		{"¥1,000", 6},
+		// Emoji are not in Shift_JIS and print as a question mark.
+		{"🍜", 1},
+	}
+	for _, tt := range tests {
+		if width := Width(tt.s); width != tt.width {
+			t.Errorf("Expected %q to take %d columns, got %d", tt.s, tt.width, width)
+		}
+	}
+	if s := Truncate("焼きそば大盛り", 9); s != "焼きそば" {
+		t.Errorf("Expected the text cut to 9 columns, got %q", s)
+	}
+}
+
+func TestReceipt(t *testing.T) {
+	order := &models.Order{
+		TicketNumber:  "A001",
+		TotalAmount:   540,
+		PaymentMethod: types.CASH,
+		Items: []models.OrderItem{
+			{ProductID: "prod1", Product: &models.Product{Name: "焼きそば"}, Quantity: 2, Price: 270, TaxRate: types.REDUCED_TAX_RATE},
+		},
+		Payments: []models.Payment{
+			{Method: types.CASH, Amount: 300, TenderedAmount: 1000, ChangeAmount: 700, Status: types.PAYMENT_COMPLETED},
+			{Method: types.SQUARE, Amount: 240, Status: types.PAYMENT_FAILED},
+			{Method: types.PAYPAY, Amount: 240, Status: types.PAYMENT_COMPLETED},
+		},
+	}
+	data := Receipt(&services.ReceiptDocument{
+		Issuer:  services.ReceiptIssuer{Name: "生徒会", RegistrationNumber: "T1234567890123"},
+		Receipt: models.Receipt{SerialNumber: 7, IssuedAt: time.Now(), LastPrintedAt: time.Now()},
+		Order:   order,
+		Taxes:   services.TaxBreakdown(order),
+		IsCopy:  true,
+	})
+
+	for _, want := range []string{"No. 000007", "T1234567890123", "焼きそば ※ x2", "8%対象", "\\540", "\\40", "【再発行（控え）】", "  現金", "\\300", "  PayPay", "お預かり", "\\1,000", "お釣り", "\\700"} {
+		if !bytes.Contains(data, encode(want)) {
+			t.Errorf("Expected the receipt to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Package escpos prints to network thermal printers in ESC/POS, the command
+// language of Epson receipt printers and their many compatibles.
+package escpos
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+// LineWidth is how many half-width characters fit on a line of 80 mm paper
+// in the standard font. Full-width characters take two.
+const LineWidth = 48
+
+type Alignment byte
+
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+)
+
+// Builder writes a print job. Text is sent in Shift_JIS with the printer in
+// kanji mode, which is what Japanese models expect.
+type Builder struct {
+	buf bytes.Buffer
+	// width is the current character width multiplier, for Row.
+	width int
+}
+
+// NewBuilder starts a job by resetting the printer, selecting the Japanese
+// character set, so that 0x5C prints as ¥, and turning on kanji mode.
+func NewBuilder() *Builder {
+	b := &Builder{width: 1}
+	b.buf.Write([]byte{
+		0x1b, 0x40, // ESC @: initialize
+		0x1b, 0x52, 0x08, // ESC R 8: Japanese international character set
+		0x1c, 0x43, 0x01, // FS C 1: Shift_JIS kanji code
+		0x1c, 0x26, // FS &: kanji mode on
+	})
+	return b
+}
+
+func (b *Builder) Align(a Alignment) {
+	b.buf.Write([]byte{0x1b, 0x61, byte(a)})
+}
+
+// Size magnifies characters width and height times, each from 1 to 8.
+func (b *Builder) Size(width, height int) {
+	width = min(max(width, 1), 8)
+	height = min(max(height, 1), 8)
+	b.width = width
+	b.buf.Write([]byte{0x1d, 0x21, byte((width-1)<<4 | (height - 1))})
+}
+
+func (b *Builder) Bold(on bool) {
+	n := byte(0)
+	if on {
+		n = 1
+	}
+	b.buf.Write([]byte{0x1b, 0x45, n})
+}
+
+// Text writes s without ending the line.
+func (b *Builder) Text(s string) {
+	b.buf.Write(encode(s))
+}
+
+// Line writes s and ends the line.
+func (b *Builder) Line(s string) {
+	b.Text(s)
+	b.buf.WriteByte('\n')
+}
+
+// Row writes left and right on the two ends of a line, cutting left short
+// if they do not fit together.
+func (b *Builder) Row(left, right string) {
+	width := LineWidth / b.width
+	room := width - Width(right) - 1
+	left = Truncate(left, room)
+	b.Line(left + strings.Repeat(" ", width-Width(left)-Width(right)) + right)
+}
+
+// Rule draws a line of dashes across the paper.
+func (b *Builder) Rule() {
+	b.Line(strings.Repeat("-", LineWidth/b.width))
+}
+
+// Feed advances the paper n lines.
+func (b *Builder) Feed(n int) {
+	b.buf.Write([]byte{0x1b, 0x64, byte(min(max(n, 0), 255))})
+}
+
+// Cut feeds the paper past the cutter and cuts it, leaving a tab so the
+// slip does not fall.
+func (b *Builder) Cut() {
+	b.buf.Write([]byte{0x1d, 0x56, 0x42, 0x00})
+}
+
+func (b *Builder) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+// encode converts s to Shift_JIS. The yen sign is sent as the backslash
+// byte, which prints as ¥ with the Japanese character set, and characters
+// Shift_JIS lacks become question marks.
+func encode(s string) []byte {
+	encoder := japanese.ShiftJIS.NewEncoder()
+	var buf bytes.Buffer
+	for _, r := range s {
+		if r == '¥' {
+			buf.WriteByte('\\')
+			continue
+		}
+		encoded, err := encoder.String(string(r))
+		if err != nil {
+			buf.WriteByte('?')
+			continue
+		}
+		buf.WriteString(encoded)
+	}
+	return buf.Bytes()
+}
+
+// Width returns how many half-width columns s takes. In Shift_JIS that is
+// the number of bytes: ASCII and half-width kana take one, the rest two.
+func Width(s string) int {
+	return len(encode(s))
+}
+
+// Truncate shortens s to at most width columns.
+func Truncate(s string, width int) string {
+	if Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && Width(string(runes)) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes)
+}
@@ -0,0 +1,174 @@
+package escpos
+
+import (
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+const (
+	// queueSize caps the jobs waiting for one printer, so that a printer
+	// left offline all day does not print a day of tickets when it returns.
+	queueSize    = 100
+	dialTimeout  = 3 * time.Second
+	writeTimeout = 10 * time.Second
+)
+
+// Spooler sends print jobs to printers over raw TCP, usually port 9100. Each
+// printer has its own queue and worker, so one that is offline holds up only
+// its own jobs; they are retried every retry interval until it is back.
+// Queues are kept in memory and lost on restart.
+type Spooler struct {
+	retryInterval time.Duration
+
+	mu     sync.Mutex
+	queues map[types.ID]*printQueue
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+type printJob struct {
+	address string
+	data    []byte
+}
+
+type printQueue struct {
+	jobs        []printJob
+	wake        chan struct{}
+	lastError   string
+	lastErrorAt *time.Time
+}
+
+func NewSpooler(retryInterval time.Duration) *Spooler {
+	return &Spooler{
+		retryInterval: retryInterval,
+		queues:        make(map[types.ID]*printQueue),
+		done:          make(chan struct{}),
+	}
+}
+
+func (s *Spooler) PrintKitchenTicket(printer *models.Printer, ticket *services.KitchenTicket) error {
+	return s.enqueue(printer, KitchenTicket(ticket))
+}
+
+func (s *Spooler) PrintReceipt(printer *models.Printer, receipt *services.ReceiptDocument) error {
+	return s.enqueue(printer, Receipt(receipt))
+}
+
+func (s *Spooler) Status(printerID types.ID) services.PrinterStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.queues[printerID]
+	if !ok {
+		return services.PrinterStatus{}
+	}
+	return services.PrinterStatus{
+		Pending:     len(q.jobs),
+		LastError:   q.lastError,
+		LastErrorAt: q.lastErrorAt,
+	}
+}
+
+// Close stops the workers. Jobs still queued are dropped.
+func (s *Spooler) Close() {
+	s.mu.Lock()
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Spooler) enqueue(printer *models.Printer, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.queues[printer.ID]
+	if !ok {
+		q = &printQueue{wake: make(chan struct{}, 1)}
+		s.queues[printer.ID] = q
+		s.wg.Add(1)
+		go s.work(printer.ID, q)
+	}
+	if len(q.jobs) >= queueSize {
+		return services.ErrPrintQueueFull
+	}
+	q.jobs = append(q.jobs, printJob{address: printer.Address(), data: data})
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// work sends the queue's jobs one at a time, keeping a job at the head of
+// the queue until it has been sent.
+func (s *Spooler) work(printerID types.ID, q *printQueue) {
+	defer s.wg.Done()
+
+	for {
+		s.mu.Lock()
+		if len(q.jobs) == 0 {
+			s.mu.Unlock()
+			select {
+			case <-q.wake:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		job := q.jobs[0]
+		s.mu.Unlock()
+
+		err := send(job)
+
+		s.mu.Lock()
+		if err == nil {
+			q.jobs = q.jobs[1:]
+			if q.lastError != "" {
+				log.Printf("printer %s at %s is back online", printerID, job.address)
+			}
+			q.lastError = ""
+			q.lastErrorAt = nil
+		} else {
+			if q.lastError == "" {
+				log.Printf("printer %s at %s is offline, %d jobs queued: %v", printerID, job.address, len(q.jobs), err)
+			}
+			now := time.Now()
+			q.lastError = err.Error()
+			q.lastErrorAt = &now
+		}
+		s.mu.Unlock()
+
+		if err != nil {
+			select {
+			case <-time.After(s.retryInterval):
+			case <-s.done:
+				return
+			}
+		}
+	}
+}
+
+func send(job printJob) error {
+	conn, err := net.DialTimeout("tcp", job.address, dialTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+		return err
+	}
+	_, err = conn.Write(job.data)
+	return err
+}
@@ -0,0 +1,165 @@
+package escpos
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// standIn is a local TCP server in place of a printer. It captures what
+// each connection sends.
+type standIn struct {
+	listener net.Listener
+	jobs     chan []byte
+}
+
+func newStandIn(t *testing.T, address string) *standIn {
+	t.Helper()
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		t.Fatalf("Failed to listen on %s: %v", address, err)
+	}
+	s := &standIn{listener: listener, jobs: make(chan []byte, 10)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			data, _ := io.ReadAll(conn)
+			conn.Close()
+			s.jobs <- data
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *standIn) printer(t *testing.T) *models.Printer {
+	t.Helper()
+	return printerAt(t, s.listener.Addr().String())
+}
+
+func (s *standIn) receive(t *testing.T) []byte {
+	t.Helper()
+	select {
+	case data := <-s.jobs:
+		return data
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the printer to receive a job")
+		return nil
+	}
+}
+
+func printerAt(t *testing.T, address string) *models.Printer {
+	t.Helper()
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		t.Fatalf("Invalid address %s: %v", address, err)
+	}
+	portNumber, _ := strconv.Atoi(port)
+	return &models.Printer{ID: "printer-1", Name: "焼きそば", Kind: types.KITCHEN_PRINTER, Host: host, Port: portNumber}
+}
+
+func testTicket() *services.KitchenTicket {
+	return &services.KitchenTicket{
+		Station: "焼きそば",
+		Order:   &models.Order{TicketNumber: "A001", CreatedAt: time.Now()},
+		Items: []models.OrderItem{
+			{ProductID: "prod1", Product: &models.Product{Name: "焼きそば"}, Quantity: 2},
+		},
+		PrintedAt: time.Now(),
+	}
+}
+
+func TestSpooler_PrintKitchenTicket(t *testing.T) {
+	printer := newStandIn(t, "127.0.0.1:0")
+	spooler := NewSpooler(10 * time.Millisecond)
+	defer spooler.Close()
+
+	ticket := testTicket()
+	if err := spooler.PrintKitchenTicket(printer.printer(t), ticket); err != nil {
+		t.Fatalf("PrintKitchenTicket failed: %v", err)
+	}
+
+	data := printer.receive(t)
+	if !bytes.Equal(data, KitchenTicket(ticket)) {
+		t.Errorf("Expected the rendered ticket to be sent as is")
+	}
+	if status := spooler.Status("printer-1"); status.Pending != 0 || status.LastError != "" {
+		t.Errorf("Expected an empty queue, got %+v", status)
+	}
+}
+
+func TestSpooler_RetriesOfflinePrinter(t *testing.T) {
+	// Take a free port and leave nothing listening on it.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	spooler := NewSpooler(10 * time.Millisecond)
+	defer spooler.Close()
+	printer := printerAt(t, address)
+
+	first, second := testTicket(), testTicket()
+	second.Order = &models.Order{TicketNumber: "A002", CreatedAt: time.Now()}
+	spooler.PrintKitchenTicket(printer, first)
+	spooler.PrintKitchenTicket(printer, second)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for spooler.Status(printer.ID).LastError == "" {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the printer to be reported offline")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if status := spooler.Status(printer.ID); status.Pending != 2 || status.LastErrorAt == nil {
+		t.Errorf("Expected both jobs to wait, got %+v", status)
+	}
+
+	// The printer comes back and gets the jobs in order.
+	standIn := newStandIn(t, address)
+	if data := standIn.receive(t); !bytes.Equal(data, KitchenTicket(first)) {
+		t.Error("Expected the first ticket first")
+	}
+	if data := standIn.receive(t); !bytes.Equal(data, KitchenTicket(second)) {
+		t.Error("Expected the second ticket second")
+	}
+
+	deadline = time.Now().Add(2 * time.Second)
+	for spooler.Status(printer.ID).Pending != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the queue to empty")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if status := spooler.Status(printer.ID); status.LastError != "" {
+		t.Errorf("Expected the error to be cleared, got %+v", status)
+	}
+}
+
+func TestSpooler_QueueFull(t *testing.T) {
+	// Nothing is listening, so no job leaves the queue.
+	spooler := NewSpooler(time.Hour)
+	defer spooler.Close()
+	printer := printerAt(t, "127.0.0.1:1")
+
+	for i := 0; i < queueSize; i++ {
+		if err := spooler.PrintKitchenTicket(printer, testTicket()); err != nil {
+			t.Fatalf("Expected job %d to be queued, got %v", i+1, err)
+		}
+	}
+	if err := spooler.PrintKitchenTicket(printer, testTicket()); err != services.ErrPrintQueueFull {
+		t.Errorf("Expected ErrPrintQueueFull, got %v", err)
+	}
+}
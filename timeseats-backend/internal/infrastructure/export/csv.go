@@ -0,0 +1,63 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// utf8BOM makes Excel open the file as UTF-8 instead of Shift_JIS.
+const utf8BOM = "\ufeff"
+
+const csvTimeLayout = "2006-01-02 15:04:05"
+
+// CSVWriter writes rows as UTF-8 CSV with a byte order mark.
+type CSVWriter struct {
+	w       *csv.Writer
+	started bool
+	out     io.Writer
+	record  []string
+}
+
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w), out: w}
+}
+
+func (c *CSVWriter) WriteRow(values []any) error {
+	if !c.started {
+		c.started = true
+		if _, err := io.WriteString(c.out, utf8BOM); err != nil {
+			return err
+		}
+	}
+
+	c.record = c.record[:0]
+	for _, v := range values {
+		c.record = append(c.record, formatCSVValue(v))
+	}
+	return c.w.Write(c.record)
+}
+
+// Close flushes buffered rows. It does not close the underlying writer.
+func (c *CSVWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func formatCSVValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Local().Format(csvTimeLayout)
+	default:
+		return ""
+	}
+}
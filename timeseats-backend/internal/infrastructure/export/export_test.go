@@ -0,0 +1,59 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf)
+
+	at := time.Date(2025, 11, 3, 10, 30, 0, 0, time.Local)
+	w.WriteRow([]any{"商品名", "Quantity", "At", "Note"})
+	w.WriteRow([]any{"焼きそば, 大盛り", 2, at, nil})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, utf8BOM) {
+		t.Error("Expected the output to start with a BOM")
+	}
+	want := utf8BOM + "商品名,Quantity,At,Note\n\"焼きそば, 大盛り\",2,2025-11-03 10:30:00,\n"
+	if out != want {
+		t.Errorf("Expected %q, got %q", want, out)
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewXLSXWriter(&buf)
+	if err != nil {
+		t.Fatalf("NewXLSXWriter failed: %v", err)
+	}
+
+	w.WriteRow([]any{"Product", "Quantity"})
+	w.WriteRow([]any{"焼きそば", 2})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatalf("Failed to open workbook: %v", err)
+	}
+	defer f.Close()
+
+	rows, err := f.GetRows(xlsxSheetName)
+	if err != nil {
+		t.Fatalf("GetRows failed: %v", err)
+	}
+	if len(rows) != 2 || rows[1][0] != "焼きそば" || rows[1][1] != "2" {
+		t.Errorf("Unexpected rows: %v", rows)
+	}
+}
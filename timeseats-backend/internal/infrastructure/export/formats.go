@@ -0,0 +1,25 @@
+package export
+
+import (
+	"io"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+)
+
+// Formats are the export formats this package writes, by name.
+func Formats() map[string]services.ExportFormat {
+	return map[string]services.ExportFormat{
+		"csv": {
+			ContentType: "text/csv; charset=utf-8",
+			NewWriter: func(w io.Writer) (services.ExportWriter, error) {
+				return NewCSVWriter(w), nil
+			},
+		},
+		"xlsx": {
+			ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			NewWriter: func(w io.Writer) (services.ExportWriter, error) {
+				return NewXLSXWriter(w)
+			},
+		},
+	}
+}
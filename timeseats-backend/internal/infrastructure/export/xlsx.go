@@ -0,0 +1,62 @@
+package export
+
+import (
+	"io"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const xlsxSheetName = "Sheet1"
+
+// XLSXWriter writes rows to a single-sheet workbook. Rows are streamed into
+// the workbook, which spills to a temporary file when it grows large, and the
+// workbook is written out on Close.
+type XLSXWriter struct {
+	out  io.Writer
+	file *excelize.File
+	sw   *excelize.StreamWriter
+	row  int
+}
+
+func NewXLSXWriter(w io.Writer) (*XLSXWriter, error) {
+	file := excelize.NewFile()
+	sw, err := file.NewStreamWriter(xlsxSheetName)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &XLSXWriter{out: w, file: file, sw: sw}, nil
+}
+
+func (x *XLSXWriter) WriteRow(values []any) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+
+	row := make([]any, len(values))
+	for i, v := range values {
+		if t, ok := v.(time.Time); ok {
+			// Excel has no time zones; store the local wall clock.
+			v = wallClock(t)
+		}
+		row[i] = v
+	}
+	return x.sw.SetRow(cell, row)
+}
+
+func (x *XLSXWriter) Close() error {
+	defer x.file.Close()
+	if err := x.sw.Flush(); err != nil {
+		return err
+	}
+	_, err := x.file.WriteTo(x.out)
+	return err
+}
+
+func wallClock(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
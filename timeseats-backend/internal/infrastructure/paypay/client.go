@@ -0,0 +1,258 @@
+// Package paypay takes payments with dynamic QR codes through the PayPay
+// Open Payment API.
+package paypay
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+const (
+	DefaultBaseURL = "https://api.paypay.ne.jp"
+	contentType    = "application/json;charset=UTF-8"
+	requestTimeout = 15 * time.Second
+)
+
+// Config holds the credentials of a PayPay merchant. The sandbox is at
+// https://stg-api.sandbox.paypay.ne.jp.
+type Config struct {
+	BaseURL    string
+	APIKey     string
+	APISecret  string
+	MerchantID string
+}
+
+// Client implements services.QRCodeGateway for PayPay.
+type Client struct {
+	config Config
+	http   *http.Client
+	now    func() time.Time
+}
+
+func NewClient(config Config) *Client {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultBaseURL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	return &Client{
+		config: config,
+		http:   &http.Client{Timeout: requestTimeout},
+		now:    time.Now,
+	}
+}
+
+type money struct {
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type createCodeRequest struct {
+	MerchantPaymentID string `json:"merchantPaymentId"`
+	Amount            money  `json:"amount"`
+	CodeType          string `json:"codeType"`
+	OrderDescription  string `json:"orderDescription,omitempty"`
+	IsAuthorization   bool   `json:"isAuthorization"`
+	RequestedAt       int64  `json:"requestedAt"`
+}
+
+type code struct {
+	CodeID     string `json:"codeId"`
+	URL        string `json:"url"`
+	DeepLink   string `json:"deeplink"`
+	ExpiryDate int64  `json:"expiryDate"`
+}
+
+type payment struct {
+	PaymentID         string `json:"paymentId"`
+	MerchantPaymentID string `json:"merchantPaymentId"`
+	Status            string `json:"status"`
+	Amount            money  `json:"amount"`
+}
+
+type resultInfo struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	CodeID  string `json:"codeId"`
+}
+
+type response struct {
+	ResultInfo resultInfo      `json:"resultInfo"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// Error is an error answer of the PayPay API.
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("paypay: %s: %s (HTTP %d)", e.Code, e.Message, e.StatusCode)
+}
+
+// paymentNotFound is the answer about a code nobody has paid with yet.
+const paymentNotFound = "DYNAMIC_QR_PAYMENT_NOT_FOUND"
+
+func (c *Client) CreateCode(ctx context.Context, request services.QRCodeRequest) (*services.GatewayQRCode, error) {
+	body := createCodeRequest{
+		MerchantPaymentID: request.MerchantPaymentID,
+		Amount:            money{Amount: request.Amount, Currency: "JPY"},
+		CodeType:          "ORDER_QR",
+		OrderDescription:  request.Description,
+		RequestedAt:       c.now().Unix(),
+	}
+	var created code
+	if err := c.do(ctx, http.MethodPost, "/v2/codes", body, &created); err != nil {
+		return nil, err
+	}
+	return &services.GatewayQRCode{
+		CodeID:    created.CodeID,
+		Payload:   created.URL,
+		DeepLink:  created.DeepLink,
+		ExpiresAt: time.Unix(created.ExpiryDate, 0),
+	}, nil
+}
+
+func (c *Client) GetCodePayment(ctx context.Context, merchantPaymentID string) (*services.GatewayPayment, error) {
+	var p payment
+	err := c.do(ctx, http.MethodGet, "/v2/codes/payments/"+url.PathEscape(merchantPaymentID), nil, &p)
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.Code == paymentNotFound {
+		return &services.GatewayPayment{Status: types.GATEWAY_PAYMENT_PENDING}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	status := types.GATEWAY_PAYMENT_PENDING
+	switch p.Status {
+	case "COMPLETED":
+		status = types.GATEWAY_PAYMENT_COMPLETED
+	case "EXPIRED":
+		status = types.GATEWAY_PAYMENT_EXPIRED
+	case "CANCELED":
+		status = types.GATEWAY_PAYMENT_CANCELLED
+	case "FAILED":
+		status = types.GATEWAY_PAYMENT_FAILED
+	}
+	return &services.GatewayPayment{
+		ID:     p.PaymentID,
+		Amount: p.Amount.Amount,
+		Status: status,
+	}, nil
+}
+
+func (c *Client) DeleteCode(ctx context.Context, codeID string) error {
+	return c.do(ctx, http.MethodDelete, "/v2/codes/"+url.PathEscape(codeID), nil, nil)
+}
+
+type webhookEvent struct {
+	NotificationType string `json:"notification_type"`
+	MerchantOrderID  string `json:"merchant_order_id"`
+}
+
+// ParseWebhook reads a transaction event, whose merchant_order_id is the
+// merchant payment ID of the code.
+func (c *Client) ParseWebhook(body []byte) (string, error) {
+	var event webhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return "", fmt.Errorf("paypay: invalid webhook body: %w", err)
+	}
+	if event.NotificationType != "Transaction" {
+		return "", nil
+	}
+	return event.MerchantOrderID, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, data any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.config.BaseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	authorization, err := c.authorization(method, path, payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("X-ASSUME-MERCHANT", c.config.MerchantID)
+	if payload != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("paypay: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var decoded response
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return fmt.Errorf("paypay: invalid response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if decoded.ResultInfo.Code != "SUCCESS" {
+		return &Error{StatusCode: resp.StatusCode, Code: decoded.ResultInfo.Code, Message: decoded.ResultInfo.Message}
+	}
+	if data == nil {
+		return nil
+	}
+	if err := json.Unmarshal(decoded.Data, data); err != nil {
+		return fmt.Errorf("paypay: invalid response data: %w", err)
+	}
+	return nil
+}
+
+// authorization signs a request the way the Open Payment API expects: an
+// HMAC-SHA256 over the path, method, a nonce, the time and a digest of the
+// body, under the API secret.
+func (c *Client) authorization(method, path string, payload []byte) (string, error) {
+	nonce := make([]byte, 4)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	epoch := strconv.FormatInt(c.now().Unix(), 10)
+	return signature(c.config.APIKey, c.config.APISecret, method, path, hex.EncodeToString(nonce), epoch, payload), nil
+}
+
+func signature(apiKey, apiSecret, method, path, nonce, epoch string, payload []byte) string {
+	bodyType, bodyHash := "empty", "empty"
+	if payload != nil {
+		bodyType = contentType
+		digest := md5.New()
+		digest.Write([]byte(bodyType))
+		digest.Write(payload)
+		bodyHash = base64.StdEncoding.EncodeToString(digest.Sum(nil))
+	}
+
+	mac := hmac.New(sha256.New, []byte(apiSecret))
+	mac.Write([]byte(strings.Join([]string{path, method, nonce, epoch, bodyType, bodyHash}, "\n")))
+	macData := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	return fmt.Sprintf("hmac OPA-Auth:%s:%s:%s:%s:%s", apiKey, macData, nonce, epoch, bodyHash)
+}
@@ -0,0 +1,176 @@
+package paypay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// standIn is a local stand-in for the PayPay API. It checks the signature of
+// every request and keeps the codes created through it.
+type standIn struct {
+	t        *testing.T
+	server   *httptest.Server
+	codes    map[string]createCodeRequest
+	status   map[string]string
+	deleted  []string
+	requests []createCodeRequest
+}
+
+func newStandIn(t *testing.T) *standIn {
+	t.Helper()
+	s := &standIn{t: t, codes: make(map[string]createCodeRequest), status: make(map[string]string)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v2/codes", func(w http.ResponseWriter, r *http.Request) {
+		var req createCodeRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		s.requests = append(s.requests, req)
+		s.codes[req.MerchantPaymentID] = req
+		w.WriteHeader(http.StatusCreated)
+		s.reply(w, "SUCCESS", code{
+			CodeID:     "04-" + req.MerchantPaymentID,
+			URL:        "https://qr.paypay.ne.jp/28180104" + req.MerchantPaymentID,
+			DeepLink:   "paypay://payment?link_key=" + req.MerchantPaymentID,
+			ExpiryDate: req.RequestedAt + 300,
+		})
+	})
+	mux.HandleFunc("GET /v2/codes/payments/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		req, ok := s.codes[id]
+		if !ok || s.status[id] == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			s.reply(w, paymentNotFound, nil)
+			return
+		}
+		s.reply(w, "SUCCESS", payment{PaymentID: "pay-" + id, MerchantPaymentID: id, Status: s.status[id], Amount: req.Amount})
+	})
+	mux.HandleFunc("DELETE /v2/codes/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.deleted = append(s.deleted, r.PathValue("id"))
+		s.reply(w, "SUCCESS", nil)
+	})
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.authorized(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			s.reply(w, "UNAUTHORIZED", nil)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *standIn) reply(w http.ResponseWriter, result string, data any) {
+	body := map[string]any{"resultInfo": resultInfo{Code: result, Message: strings.ToLower(result)}}
+	if data != nil {
+		body["data"] = data
+	}
+	json.NewEncoder(w).Encode(body)
+}
+
+// authorized recomputes the signature from the request as PayPay would.
+func (s *standIn) authorized(r *http.Request) bool {
+	if r.Header.Get("X-ASSUME-MERCHANT") != "merchant-1" {
+		return false
+	}
+	fields := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "hmac OPA-Auth:"), ":")
+	if len(fields) != 5 || fields[0] != "api-key" {
+		return false
+	}
+	var payload []byte
+	if r.ContentLength > 0 {
+		payload, _ = io.ReadAll(r.Body)
+		r.Body = io.NopCloser(strings.NewReader(string(payload)))
+		if r.Header.Get("Content-Type") != contentType {
+			return false
+		}
+	}
+	expected := signature("api-key", "api-secret", r.Method, r.URL.Path, fields[2], fields[3], payload)
+	return r.Header.Get("Authorization") == expected
+}
+
+func (s *standIn) client(secret string) *Client {
+	client := NewClient(Config{BaseURL: s.server.URL + "/", APIKey: "api-key", APISecret: secret, MerchantID: "merchant-1"})
+	client.now = func() time.Time { return time.Unix(1_800_000_000, 0) }
+	return client
+}
+
+func TestClient_Codes(t *testing.T) {
+	paypay := newStandIn(t)
+	client := paypay.client("api-secret")
+	ctx := context.Background()
+
+	code, err := client.CreateCode(ctx, services.QRCodeRequest{MerchantPaymentID: "mp-1", OrderID: "order-1", Amount: 800, Description: "注文 A001"})
+	if err != nil {
+		t.Fatalf("CreateCode failed: %v", err)
+	}
+	if code.CodeID != "04-mp-1" || !strings.HasPrefix(code.Payload, "https://qr.paypay.ne.jp/") || code.DeepLink == "" {
+		t.Errorf("Unexpected code: %+v", code)
+	}
+	if !code.ExpiresAt.Equal(time.Unix(1_800_000_300, 0)) {
+		t.Errorf("Expected the code to expire at the time PayPay gave, got %v", code.ExpiresAt)
+	}
+	sent := paypay.requests[0]
+	if sent.Amount != (money{Amount: 800, Currency: "JPY"}) || sent.CodeType != "ORDER_QR" || sent.IsAuthorization || sent.OrderDescription != "注文 A001" {
+		t.Errorf("Unexpected request: %+v", sent)
+	}
+
+	payment, err := client.GetCodePayment(ctx, "mp-1")
+	if err != nil || payment.Status != types.GATEWAY_PAYMENT_PENDING {
+		t.Errorf("Expected an unpaid code to be pending, got %+v, %v", payment, err)
+	}
+
+	for status, want := range map[string]types.GatewayPaymentStatus{
+		"CREATED":   types.GATEWAY_PAYMENT_PENDING,
+		"COMPLETED": types.GATEWAY_PAYMENT_COMPLETED,
+		"EXPIRED":   types.GATEWAY_PAYMENT_EXPIRED,
+		"CANCELED":  types.GATEWAY_PAYMENT_CANCELLED,
+		"FAILED":    types.GATEWAY_PAYMENT_FAILED,
+	} {
+		paypay.status["mp-1"] = status
+		payment, err := client.GetCodePayment(ctx, "mp-1")
+		if err != nil || payment.Status != want {
+			t.Errorf("%s: expected %s, got %+v, %v", status, want, payment, err)
+		}
+	}
+	if payment, _ := client.GetCodePayment(ctx, "mp-1"); payment.ID != "pay-mp-1" || payment.Amount != 800 {
+		t.Errorf("Unexpected payment: %+v", payment)
+	}
+
+	if err := client.DeleteCode(ctx, code.CodeID); err != nil || len(paypay.deleted) != 1 || paypay.deleted[0] != code.CodeID {
+		t.Errorf("Expected the code to be deleted, got %v, %v", paypay.deleted, err)
+	}
+
+	_, err = paypay.client("wrong-secret").GetCodePayment(ctx, "mp-1")
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected a wrong secret to be refused, got %v", err)
+	}
+}
+
+func TestClient_ParseWebhook(t *testing.T) {
+	client := NewClient(Config{})
+
+	merchantPaymentID, err := client.ParseWebhook([]byte(`{"notification_type":"Transaction","merchant_id":"m1","order_id":"o1","merchant_order_id":"mp-1","state":"COMPLETED","order_amount":800}`))
+	if err != nil || merchantPaymentID != "mp-1" {
+		t.Errorf("Expected mp-1, got %q, %v", merchantPaymentID, err)
+	}
+
+	merchantPaymentID, err = client.ParseWebhook([]byte(`{"notification_type":"File","merchant_id":"m1"}`))
+	if err != nil || merchantPaymentID != "" {
+		t.Errorf("Expected other notifications to be ignored, got %q, %v", merchantPaymentID, err)
+	}
+
+	if _, err := client.ParseWebhook([]byte(`not json`)); err == nil {
+		t.Error("Expected an invalid body to fail")
+	}
+}
@@ -0,0 +1,172 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// Page sizes in points.
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+	A5Width  = 419.53
+	A5Height = 595.28
+)
+
+// The text is set in Heisei Kaku Gothic, one of the standard Japanese fonts
+// every PDF viewer supplies, so no font needs to be embedded. The -HW-
+// encoding maps ASCII to half-width glyphs, which lets text be measured
+// without the font: half-width characters are half an em wide, everything
+// else a full em.
+const (
+	fontName     = "HeiseiKakuGo-W5"
+	fontEncoding = "UniJIS-UCS2-HW-H"
+)
+
+// Document is a PDF with text and lines. Positions are in points from the
+// top left corner of the page, and y is the baseline of text.
+type Document struct {
+	width  float64
+	height float64
+	pages  []*bytes.Buffer
+}
+
+func NewDocument(width, height float64) *Document {
+	d := &Document{width: width, height: height}
+	d.AddPage()
+	return d
+}
+
+func (d *Document) Width() float64 {
+	return d.width
+}
+
+func (d *Document) Height() float64 {
+	return d.height
+}
+
+// AddPage starts a new page; everything drawn after goes on it.
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// Text draws s with its left end at x.
+func (d *Document) Text(x, y, size float64, s string) {
+	fmt.Fprintf(d.page(), "BT /F1 %s Tf %s %s Td <%s> Tj ET\n",
+		number(size), number(x), number(d.height-y), encodeText(s))
+}
+
+// TextRight draws s with its right end at x.
+func (d *Document) TextRight(x, y, size float64, s string) {
+	d.Text(x-TextWidth(s, size), y, size, s)
+}
+
+// TextCenter draws s centred on x.
+func (d *Document) TextCenter(x, y, size float64, s string) {
+	d.Text(x-TextWidth(s, size)/2, y, size, s)
+}
+
+// Line draws a straight line width points thick.
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.page(), "%s w %s %s m %s %s l S\n",
+		number(width), number(x1), number(d.height-y1), number(x2), number(d.height-y2))
+}
+
+// TextWidth returns how wide s is drawn at size.
+func TextWidth(s string, size float64) float64 {
+	ems := 0.0
+	for _, r := range s {
+		if isHalfWidth(r) {
+			ems += 0.5
+		} else {
+			ems++
+		}
+	}
+	return ems * size
+}
+
+// Truncate shortens s to fit in width, ending it with an ellipsis.
+func Truncate(s string, size, width float64) string {
+	if TextWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && TextWidth(string(runes)+"…", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+func isHalfWidth(r rune) bool {
+	return (r >= 0x20 && r <= 0x7e) || (r >= 0xff61 && r <= 0xff9f)
+}
+
+// encodeText returns s as hex UTF-16, which the UCS2 encoding reads. The
+// encoding has no surrogate pairs, so characters outside the Basic
+// Multilingual Plane become question marks.
+func encodeText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r > 0xffff || utf16.IsSurrogate(r) {
+			r = '?'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	return b.String()
+}
+
+func number(f float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", f), "0"), ".")
+}
+
+// WriteTo writes the document as a PDF file.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1 to 5 are the catalog, page tree and font; each page is
+	// followed by its content stream.
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /%s /DescendantFonts [4 0 R] >>",
+		fontName, fontEncoding))
+	// CIDs 231 to 389 are the half-width glyphs of Adobe-Japan1.
+	object(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /%s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Japan1) /Supplement 2 >> "+
+		"/FontDescriptor 5 0 R /DW 1000 /W [231 389 500] >>", fontName))
+	object(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 4 /FontBBox [-92 -250 1010 922] "+
+		"/ItalicAngle 0 /Ascent 752 /Descent -221 /CapHeight 737 /StemV 114 >>", fontName))
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] "+
+			"/Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			number(d.width), number(d.height), firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.Bytes()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}
@@ -0,0 +1,167 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+const (
+	receiptMargin     = 36.0
+	receiptLineHeight = 16.0
+)
+
+// receiptLayout writes a receipt line by line, starting a new page when the
+// current one is full.
+type receiptLayout struct {
+	doc *Document
+	y   float64
+}
+
+func (l *receiptLayout) left() float64 {
+	return receiptMargin
+}
+
+func (l *receiptLayout) right() float64 {
+	return l.doc.Width() - receiptMargin
+}
+
+// next moves down by height and returns the new baseline.
+func (l *receiptLayout) next(height float64) float64 {
+	l.y += height
+	if l.y > l.doc.Height()-receiptMargin {
+		l.doc.AddPage()
+		l.y = receiptMargin + height
+	}
+	return l.y
+}
+
+// row writes label on the left and amount on the right.
+func (l *receiptLayout) row(size float64, label, amount string) {
+	y := l.next(size + 6)
+	l.doc.Text(l.left(), y, size, label)
+	l.doc.TextRight(l.right(), y, size, amount)
+}
+
+func (l *receiptLayout) rule(width float64) {
+	y := l.next(8)
+	l.doc.Line(l.left(), y, l.right(), y, width)
+}
+
+// WriteReceipt writes a receipt as an A5 PDF. Items taxed at the reduced
+// rate are marked with ※, and copies say so under the title.
+func WriteReceipt(w io.Writer, r *services.ReceiptDocument) error {
+	doc := NewDocument(A5Width, A5Height)
+	l := &receiptLayout{doc: doc, y: receiptMargin}
+	center := doc.Width() / 2
+
+	doc.TextCenter(center, l.next(24), 24, "領収書")
+	if r.IsCopy {
+		doc.TextCenter(center, l.next(18), 12, "【再発行（控え）】")
+	}
+	l.next(8)
+
+	l.row(9, "No. "+serialNumber(r.Receipt.SerialNumber), "発行日 "+r.Receipt.IssuedAt.Local().Format("2006年01月02日 15:04"))
+	if r.IsCopy {
+		l.row(9, "", "再発行日 "+r.Receipt.LastPrintedAt.Local().Format("2006年01月02日 15:04"))
+	}
+	l.row(9, "注文番号 "+r.Order.TicketNumber, "")
+	l.next(6)
+	doc.Text(l.left(), l.next(18), 14, r.Issuer.Name)
+	if r.Issuer.RegistrationNumber != "" {
+		doc.Text(l.left(), l.next(receiptLineHeight), 10, "登録番号 "+r.Issuer.RegistrationNumber)
+	}
+
+	l.next(6)
+	quantityX := l.right() - 90
+	y := l.next(receiptLineHeight)
+	doc.Text(l.left(), y, 9, "品名")
+	doc.TextRight(quantityX, y, 9, "数量")
+	doc.TextRight(l.right(), y, 9, "金額")
+	l.rule(0.5)
+
+	for _, item := range r.Order.Items {
+		name := string(item.ProductID)
+		if item.Product != nil {
+			name = item.Product.Name
+		}
+		if item.TaxRate == types.REDUCED_TAX_RATE {
+			name += " ※"
+		}
+		y := l.next(receiptLineHeight)
+		doc.Text(l.left(), y, 10, Truncate(name, 10, quantityX-l.left()-40))
+		doc.TextRight(quantityX, y, 10, strconv.Itoa(item.Quantity))
+		doc.TextRight(l.right(), y, 10, formatYen(item.GetSubtotal()))
+		for _, option := range item.Options {
+			doc.Text(l.left()+12, l.next(12), 8, Truncate(option.GroupName+": "+option.Name, 8, quantityX-l.left()-52))
+		}
+	}
+	l.rule(0.5)
+
+	l.row(10, "小計", formatYen(r.Order.Subtotal()))
+	for _, discount := range r.Order.Discounts {
+		l.row(10, Truncate(discount.Name, 10, 200), formatYen(-discount.Amount))
+	}
+	l.rule(1)
+	l.row(14, "合計", formatYen(r.Order.TotalAmount))
+	for _, tax := range r.Taxes {
+		l.row(9, fmt.Sprintf("　%d%%対象", tax.Rate), formatYen(tax.Amount))
+		l.row(9, "　　内消費税等", formatYen(tax.Tax))
+	}
+	if r.Order.RefundedAmount > 0 {
+		l.row(10, "返金済み", formatYen(-r.Order.RefundedAmount))
+	}
+
+	l.next(6)
+	if tenders := r.Tenders(); len(tenders) > 1 {
+		doc.Text(l.left(), l.next(receiptLineHeight), 10, "お支払方法")
+		for _, tender := range tenders {
+			l.row(10, "　"+r.MethodName(tender.Method), formatYen(tender.Amount))
+		}
+	} else {
+		l.row(10, "お支払方法", r.PaymentMethodName())
+	}
+	if r.Tendered() > 0 {
+		l.row(10, "お預かり", formatYen(r.Tendered()))
+		l.row(10, "お釣り", formatYen(r.Change()))
+	}
+	if r.Order.TransactionID != nil {
+		l.row(8, "取引番号", *r.Order.TransactionID)
+	}
+
+	l.next(10)
+	doc.Text(l.left(), l.next(receiptLineHeight), 10, "上記正に領収いたしました。")
+	if r.HasReducedRate() {
+		doc.Text(l.left(), l.next(receiptLineHeight), 8, "※は軽減税率（8%）対象商品です。")
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+func serialNumber(n int) string {
+	return fmt.Sprintf("%06d", n)
+}
+
+// formatYen formats an amount as "￥1,234". The full-width yen sign is used
+// because the font's half-width one is not in the half-width encoding.
+func formatYen(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.Itoa(amount)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return sign + "￥" + b.String()
+}
@@ -0,0 +1,78 @@
+package pdf
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+func TestWriteReceipt(t *testing.T) {
+	order := &models.Order{
+		TicketNumber:  "A001",
+		TotalAmount:   760,
+		IsPaid:        true,
+		PaymentMethod: types.CASH,
+		Items: []models.OrderItem{
+			{ProductID: "prod1", Product: &models.Product{Name: "焼きそば"}, Quantity: 2, Price: 270, TaxRate: types.REDUCED_TAX_RATE},
+			{ProductID: "prod2", Product: &models.Product{Name: "Tシャツ"}, Quantity: 1, Price: 220, TaxRate: types.STANDARD_TAX_RATE},
+		},
+	}
+	receipt := &services.ReceiptDocument{
+		Issuer:  services.ReceiptIssuer{Name: "生徒会", RegistrationNumber: "T1234567890123"},
+		Receipt: models.Receipt{SerialNumber: 42, IssuedAt: time.Now(), LastPrintedAt: time.Now(), PrintCount: 2},
+		Order:   order,
+		Taxes:   services.TaxBreakdown(order),
+		IsCopy:  true,
+	}
+
+	var buf bytes.Buffer
+	if err := WriteReceipt(&buf, receipt); err != nil {
+		t.Fatalf("WriteReceipt failed: %v", err)
+	}
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "%PDF-1.4\n") || !strings.HasSuffix(out, "%%EOF\n") {
+		t.Fatalf("Expected a PDF file, got %q...", out[:min(len(out), 20)])
+	}
+	for _, text := range []string{"領収書", "【再発行（控え）】", "No. 000042", "登録番号 T1234567890123", "焼きそば ※", "8%対象", "￥540", "内消費税等", "現金"} {
+		if !strings.Contains(out, encodeText(text)) {
+			t.Errorf("Expected %q in the receipt", text)
+		}
+	}
+
+	// Every object has to start where the cross-reference table says.
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(out)
+	if startxref == nil {
+		t.Fatal("Expected a startxref")
+	}
+	xref, _ := strconv.Atoi(startxref[1])
+	if !strings.HasPrefix(out[xref:], "xref\n") {
+		t.Fatalf("Expected the cross-reference table at %d", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(out[xref:], -1)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if prefix := strconv.Itoa(i+1) + " 0 obj\n"; !strings.HasPrefix(out[offset:], prefix) {
+			t.Errorf("Expected object %d at offset %d", i+1, offset)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if s := Truncate("焼きそば", 10, 40); s != "焼きそば" {
+		t.Errorf("Expected text that fits to be kept, got %q", s)
+	}
+	if s := Truncate("焼きそば大盛り", 10, 40); s != "焼きそ…" || TextWidth(s, 10) > 40 {
+		t.Errorf("Expected the text to be cut to 40 points, got %q", s)
+	}
+	if w := TextWidth("A001", 10); w != 20 {
+		t.Errorf("Expected half-width text to be half an em wide, got %v", w)
+	}
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"gorm.io/gorm"
+)
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) repositories.AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	if err := dbFromContext(ctx, r.db).Create(entry).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Create",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *auditLogRepository) Find(ctx context.Context, filter repositories.AuditLogFilter) ([]models.AuditLog, error) {
+	query := dbFromContext(ctx, r.db)
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var entries []models.AuditLog
+	if err := query.Order("created_at DESC").Find(&entries).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "Find",
+			Err:       err,
+		}
+	}
+	return entries, nil
+}
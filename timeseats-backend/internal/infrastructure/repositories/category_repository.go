@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) repositories.CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) Create(ctx context.Context, category *models.Category) error {
+	if err := dbFromContext(ctx, r.db).Create(category).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Create",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *categoryRepository) FindByID(ctx context.Context, id types.ID) (*models.Category, error) {
+	var category models.Category
+	if err := dbFromContext(ctx, r.db).First(&category, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NewErrNotFound("Category", id)
+		}
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByID",
+			Err:       err,
+		}
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) FindAll(ctx context.Context) ([]models.Category, error) {
+	var categories []models.Category
+	if err := dbFromContext(ctx, r.db).Order("sort_order, name").Find(&categories).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "FindAll",
+			Err:       err,
+		}
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) Update(ctx context.Context, category *models.Category) error {
+	if err := dbFromContext(ctx, r.db).Save(category).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Update",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *categoryRepository) Delete(ctx context.Context, id types.ID) error {
+	result := dbFromContext(ctx, r.db).Delete(&models.Category{}, "id = ?", id)
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "Delete",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return repositories.NewErrNotFound("Category", id)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type discountRepository struct {
+	db *gorm.DB
+}
+
+func NewDiscountRepository(db *gorm.DB) repositories.DiscountRepository {
+	return &discountRepository{db: db}
+}
+
+// withUsageCount selects discounts with the number of orders, other than
+// cancelled ones, each is on.
+func withUsageCount(query *gorm.DB) *gorm.DB {
+	return query.Select(`discounts.*, (
+		SELECT COUNT(DISTINCT order_discounts.order_id) FROM order_discounts
+		JOIN orders ON orders.id = order_discounts.order_id AND orders.deleted_at IS NULL
+		WHERE order_discounts.discount_id = discounts.id AND orders.status <> ?
+	) AS usage_count`, types.CANCELLED)
+}
+
+func (r *discountRepository) Create(ctx context.Context, discount *models.Discount) error {
+	if err := dbFromContext(ctx, r.db).Omit(clause.Associations).Create(discount).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Create",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *discountRepository) FindByID(ctx context.Context, id types.ID) (*models.Discount, error) {
+	return r.findByID(withUsageCount(dbFromContext(ctx, r.db)), id, "FindByID")
+}
+
+// FindByIDForUpdate counts the uses in a statement of its own, after the
+// lock is taken. Counted in the locking statement, they would be read from
+// the snapshot taken before waiting for the lock, missing a use the holder
+// of the lock has just committed.
+func (r *discountRepository) FindByIDForUpdate(ctx context.Context, id types.ID) (*models.Discount, error) {
+	db := dbFromContext(ctx, r.db)
+	discount, err := r.findByID(db.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "discounts"}}), id, "FindByIDForUpdate")
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Raw(`SELECT COUNT(DISTINCT order_discounts.order_id) FROM order_discounts
+		JOIN orders ON orders.id = order_discounts.order_id AND orders.deleted_at IS NULL
+		WHERE order_discounts.discount_id = ? AND orders.status <> ?`, id, types.CANCELLED).
+		Scan(&discount.UsageCount).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByIDForUpdate",
+			Err:       err,
+		}
+	}
+	return discount, nil
+}
+
+func (r *discountRepository) findByID(query *gorm.DB, id types.ID, operation string) (*models.Discount, error) {
+	var discount models.Discount
+	if err := query.Preload("Product").First(&discount, "discounts.id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NewErrNotFound("Discount", id)
+		}
+		return nil, &repositories.RepositoryError{
+			Operation: operation,
+			Err:       err,
+		}
+	}
+	return &discount, nil
+}
+
+func (r *discountRepository) FindByCode(ctx context.Context, code string) (*models.Discount, error) {
+	var discount models.Discount
+	if err := withUsageCount(dbFromContext(ctx, r.db)).Preload("Product").First(&discount, "discounts.code = ?", code).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NewErrNotFound("Discount", types.ID(code))
+		}
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByCode",
+			Err:       err,
+		}
+	}
+	return &discount, nil
+}
+
+func (r *discountRepository) FindAll(ctx context.Context) ([]models.Discount, error) {
+	var discounts []models.Discount
+	if err := withUsageCount(dbFromContext(ctx, r.db)).Preload("Product").Order("discounts.name").Find(&discounts).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "FindAll",
+			Err:       err,
+		}
+	}
+	return discounts, nil
+}
+
+func (r *discountRepository) Update(ctx context.Context, discount *models.Discount) error {
+	if err := dbFromContext(ctx, r.db).Omit(clause.Associations).Save(discount).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Update",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *discountRepository) Delete(ctx context.Context, id types.ID) error {
+	result := dbFromContext(ctx, r.db).Delete(&models.Discount{}, "id = ?", id)
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "Delete",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return repositories.NewErrNotFound("Discount", id)
+	}
+	return nil
+}
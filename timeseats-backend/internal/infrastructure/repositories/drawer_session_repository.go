@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type drawerSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewDrawerSessionRepository(db *gorm.DB) repositories.DrawerSessionRepository {
+	return &drawerSessionRepository{db: db}
+}
+
+func (r *drawerSessionRepository) preloaded(ctx context.Context) *gorm.DB {
+	return dbFromContext(ctx, r.db).
+		Preload("Terminal").
+		Preload("Movements", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		Preload("Counts", func(db *gorm.DB) *gorm.DB { return db.Order("denomination DESC") })
+}
+
+func (r *drawerSessionRepository) Create(ctx context.Context, session *models.DrawerSession) error {
+	if err := dbFromContext(ctx, r.db).Create(session).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Create",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *drawerSessionRepository) FindByID(ctx context.Context, id types.ID) (*models.DrawerSession, error) {
+	return r.findByID(ctx, r.preloaded(ctx), id, "FindByID")
+}
+
+func (r *drawerSessionRepository) FindByIDForUpdate(ctx context.Context, id types.ID) (*models.DrawerSession, error) {
+	return r.findByID(ctx, r.preloaded(ctx).Clauses(clause.Locking{Strength: "UPDATE"}), id, "FindByIDForUpdate")
+}
+
+func (r *drawerSessionRepository) findByID(ctx context.Context, query *gorm.DB, id types.ID, operation string) (*models.DrawerSession, error) {
+	var session models.DrawerSession
+	if err := query.First(&session, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NewErrNotFound("DrawerSession", id)
+		}
+		return nil, &repositories.RepositoryError{
+			Operation: operation,
+			Err:       err,
+		}
+	}
+	return &session, nil
+}
+
+func (r *drawerSessionRepository) FindOpenByTerminal(ctx context.Context, terminalID types.ID) (*models.DrawerSession, error) {
+	var session models.DrawerSession
+	if err := r.preloaded(ctx).
+		Where("terminal_id = ? AND closed_at IS NULL", terminalID).
+		First(&session).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NewErrNotFound("DrawerSession", terminalID)
+		}
+		return nil, &repositories.RepositoryError{
+			Operation: "FindOpenByTerminal",
+			Err:       err,
+		}
+	}
+	return &session, nil
+}
+
+func (r *drawerSessionRepository) FindByTerminal(ctx context.Context, terminalID types.ID) ([]models.DrawerSession, error) {
+	query := r.preloaded(ctx)
+	if terminalID != "" {
+		query = query.Where("terminal_id = ?", terminalID)
+	}
+
+	var sessions []models.DrawerSession
+	if err := query.Order("opened_at DESC").Find(&sessions).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByTerminal",
+			Err:       err,
+		}
+	}
+	return sessions, nil
+}
+
+func (r *drawerSessionRepository) AddMovement(ctx context.Context, movement *models.DrawerMovement) error {
+	if err := dbFromContext(ctx, r.db).Create(movement).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "AddMovement",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *drawerSessionRepository) Close(ctx context.Context, session *models.DrawerSession) error {
+	db := dbFromContext(ctx, r.db)
+	result := db.Model(session).
+		Where("closed_at IS NULL").
+		Select("ClosedByID", "ClosedAt", "CashSales", "CashTendered", "ChangeGiven", "CashRefunds", "ExpectedAmount", "CountedAmount", "Note", "UpdatedAt").
+		Updates(session)
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "Close",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrConflict
+	}
+
+	for i := range session.Counts {
+		session.Counts[i].DrawerSessionID = session.ID
+	}
+	if len(session.Counts) > 0 {
+		if err := db.Create(&session.Counts).Error; err != nil {
+			return &repositories.RepositoryError{
+				Operation: "Close",
+				Err:       err,
+			}
+		}
+	}
+	return nil
+}
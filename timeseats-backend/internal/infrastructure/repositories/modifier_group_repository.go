@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type modifierGroupRepository struct {
+	db *gorm.DB
+}
+
+func NewModifierGroupRepository(db *gorm.DB) repositories.ModifierGroupRepository {
+	return &modifierGroupRepository{db: db}
+}
+
+// preloadModifierOptions loads the options of modifier groups in menu order.
+func preloadModifierOptions(query *gorm.DB) *gorm.DB {
+	return query.Order("modifier_options.sort_order, modifier_options.name")
+}
+
+func (r *modifierGroupRepository) Create(ctx context.Context, group *models.ModifierGroup) error {
+	if err := dbFromContext(ctx, r.db).Create(group).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Create",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *modifierGroupRepository) FindByID(ctx context.Context, id types.ID) (*models.ModifierGroup, error) {
+	var group models.ModifierGroup
+	if err := dbFromContext(ctx, r.db).
+		Preload("Options", preloadModifierOptions).
+		First(&group, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NewErrNotFound("ModifierGroup", id)
+		}
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByID",
+			Err:       err,
+		}
+	}
+	return &group, nil
+}
+
+func (r *modifierGroupRepository) FindAll(ctx context.Context) ([]models.ModifierGroup, error) {
+	var groups []models.ModifierGroup
+	if err := dbFromContext(ctx, r.db).
+		Preload("Options", preloadModifierOptions).
+		Order("product_id, sort_order, name").
+		Find(&groups).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "FindAll",
+			Err:       err,
+		}
+	}
+	return groups, nil
+}
+
+func (r *modifierGroupRepository) FindByProductID(ctx context.Context, productID types.ID) ([]models.ModifierGroup, error) {
+	var groups []models.ModifierGroup
+	if err := dbFromContext(ctx, r.db).
+		Preload("Options", preloadModifierOptions).
+		Where("product_id = ?", productID).
+		Order("sort_order, name").
+		Find(&groups).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByProductID",
+			Err:       err,
+		}
+	}
+	return groups, nil
+}
+
+func (r *modifierGroupRepository) Update(ctx context.Context, group *models.ModifierGroup) error {
+	err := dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(group).Error; err != nil {
+			return err
+		}
+
+		kept := make([]types.ID, 0, len(group.Options))
+		for i := range group.Options {
+			group.Options[i].GroupID = group.ID
+			if err := tx.Save(&group.Options[i]).Error; err != nil {
+				return err
+			}
+			kept = append(kept, group.Options[i].ID)
+		}
+
+		query := tx.Where("group_id = ?", group.ID)
+		if len(kept) > 0 {
+			query = query.Where("id NOT IN ?", kept)
+		}
+		return query.Delete(&models.ModifierOption{}).Error
+	})
+	if err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Update",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *modifierGroupRepository) Delete(ctx context.Context, id types.ID) error {
+	var rowsAffected int64
+	err := dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.ModifierOption{}, "group_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.ModifierGroup{}, "id = ?", id)
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Delete",
+			Err:       err,
+		}
+	}
+	if rowsAffected == 0 {
+		return repositories.NewErrNotFound("ModifierGroup", id)
+	}
+	return nil
+}
@@ -3,11 +3,13 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type orderRepository struct {
@@ -18,8 +20,24 @@ func NewOrderRepository(db *gorm.DB) repositories.OrderRepository {
 	return &orderRepository{db: db}
 }
 
+// discountsInOrder lists the discount lines of an order in the order they
+// were added.
+func discountsInOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at")
+}
+
+// paymentsInOrder lists the payments of an order in the order they were
+// taken.
+func paymentsInOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at")
+}
+
 func (r *orderRepository) Create(ctx context.Context, order *models.Order) error {
-	if err := r.db.WithContext(ctx).Create(order).Error; err != nil {
+	db := dbFromContext(ctx, r.db)
+	if err := db.Create(order).Error; err != nil {
+		if isDuplicateKey(db, err) {
+			return repositories.ErrDuplicate
+		}
 		return &repositories.RepositoryError{
 			Operation: "Create",
 			Err:       err,
@@ -29,17 +47,29 @@ func (r *orderRepository) Create(ctx context.Context, order *models.Order) error
 }
 
 func (r *orderRepository) FindByID(ctx context.Context, id types.ID) (*models.Order, error) {
+	return r.findByID(dbFromContext(ctx, r.db), id, "FindByID")
+}
+
+func (r *orderRepository) FindByIDForUpdate(ctx context.Context, id types.ID) (*models.Order, error) {
+	return r.findByID(dbFromContext(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}), id, "FindByIDForUpdate")
+}
+
+func (r *orderRepository) findByID(query *gorm.DB, id types.ID, operation string) (*models.Order, error) {
 	var order models.Order
-	if err := r.db.WithContext(ctx).
+	if err := query.
 		Preload("SalesSlot").
 		Preload("Items").
 		Preload("Items.Product").
+		Preload("Items.Options").
+		Preload("Items.Components.Product").
+		Preload("Discounts", discountsInOrder).
+		Preload("Payments", paymentsInOrder).
 		First(&order, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, repositories.NewErrNotFound("Order", id)
 		}
 		return nil, &repositories.RepositoryError{
-			Operation: "FindByID",
+			Operation: operation,
 			Err:       err,
 		}
 	}
@@ -48,10 +78,14 @@ func (r *orderRepository) FindByID(ctx context.Context, id types.ID) (*models.Or
 
 func (r *orderRepository) FindAll(ctx context.Context) ([]models.Order, error) {
 	var orders []models.Order
-	if err := r.db.WithContext(ctx).
+	if err := dbFromContext(ctx, r.db).
 		Preload("SalesSlot").
 		Preload("Items").
 		Preload("Items.Product").
+		Preload("Items.Options").
+		Preload("Items.Components.Product").
+		Preload("Discounts", discountsInOrder).
+		Preload("Payments", paymentsInOrder).
 		Find(&orders).Error; err != nil {
 		return nil, &repositories.RepositoryError{
 			Operation: "FindAll",
@@ -61,8 +95,88 @@ func (r *orderRepository) FindAll(ctx context.Context) ([]models.Order, error) {
 	return orders, nil
 }
 
+func (r *orderRepository) FindByFilter(ctx context.Context, filter repositories.OrderFilter) ([]models.Order, error) {
+	var orders []models.Order
+	query := applyOrderFilter(dbFromContext(ctx, r.db), filter).
+		Preload("SalesSlot").
+		Preload("Items").
+		Preload("Items.Product").
+		Preload("Items.Options").
+		Preload("Items.Components.Product").
+		Preload("Discounts", discountsInOrder).
+		Preload("Payments", paymentsInOrder)
+	if err := query.Order("created_at").Find(&orders).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByFilter",
+			Err:       err,
+		}
+	}
+	return orders, nil
+}
+
+const orderStreamBatchSize = 200
+
+func (r *orderRepository) StreamByFilter(ctx context.Context, filter repositories.OrderFilter, fn func(order *models.Order) error) error {
+	// Keyset pagination on (created_at, id) keeps each batch query cheap and
+	// stable while new orders are being inserted.
+	var lastCreatedAt time.Time
+	var lastID types.ID
+	for {
+		query := applyOrderFilter(dbFromContext(ctx, r.db), filter).
+			Preload("SalesSlot").
+			Preload("Items").
+			Preload("Items.Product").
+			Preload("Items.Options").
+			Preload("Items.Components.Product").
+			Preload("Discounts", discountsInOrder).
+			Preload("Payments", paymentsInOrder)
+		if lastID != "" {
+			query = query.Where("(created_at, id) > (?, ?)", lastCreatedAt, lastID)
+		}
+
+		var orders []models.Order
+		if err := query.Order("created_at, id").Limit(orderStreamBatchSize).Find(&orders).Error; err != nil {
+			return &repositories.RepositoryError{
+				Operation: "StreamByFilter",
+				Err:       err,
+			}
+		}
+
+		for i := range orders {
+			if err := fn(&orders[i]); err != nil {
+				return err
+			}
+		}
+		if len(orders) < orderStreamBatchSize {
+			return nil
+		}
+
+		last := orders[len(orders)-1]
+		lastCreatedAt, lastID = last.CreatedAt, last.ID
+	}
+}
+
+func applyOrderFilter(query *gorm.DB, filter repositories.OrderFilter) *gorm.DB {
+	if filter.SalesSlotID != "" {
+		query = query.Where("sales_slot_id = ?", filter.SalesSlotID)
+	}
+	if filter.TerminalID != "" {
+		query = query.Where("terminal_id = ? OR paid_terminal_id = ?", filter.TerminalID, filter.TerminalID)
+	}
+	if filter.Status != 0 {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	return query
+}
+
 func (r *orderRepository) Update(ctx context.Context, order *models.Order) error {
-	if err := r.db.WithContext(ctx).Save(order).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Save(order).Error; err != nil {
 		return &repositories.RepositoryError{
 			Operation: "Update",
 			Err:       err,
@@ -72,7 +186,17 @@ func (r *orderRepository) Update(ctx context.Context, order *models.Order) error
 }
 
 func (r *orderRepository) Delete(ctx context.Context, id types.ID) error {
-	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err := dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		itemIDs := tx.Model(&models.OrderItem{}).Select("id").Where("order_id = ?", id)
+		if err := tx.Delete(&models.OrderItemOption{}, "order_item_id IN (?)", itemIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.OrderItemComponent{}, "order_item_id IN (?)", itemIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.OrderDiscount{}, "order_id = ?", id).Error; err != nil {
+			return err
+		}
 		if err := tx.Delete(&models.OrderItem{}, "order_id = ?", id).Error; err != nil {
 			return err
 		}
@@ -93,10 +217,14 @@ func (r *orderRepository) Delete(ctx context.Context, id types.ID) error {
 
 func (r *orderRepository) FindBySalesSlotID(ctx context.Context, salesSlotID types.ID) ([]models.Order, error) {
 	var orders []models.Order
-	if err := r.db.WithContext(ctx).
+	if err := dbFromContext(ctx, r.db).
 		Preload("SalesSlot").
 		Preload("Items").
 		Preload("Items.Product").
+		Preload("Items.Options").
+		Preload("Items.Components.Product").
+		Preload("Discounts", discountsInOrder).
+		Preload("Payments", paymentsInOrder).
 		Where("sales_slot_id = ?", salesSlotID).
 		Find(&orders).Error; err != nil {
 		return nil, &repositories.RepositoryError{
@@ -109,10 +237,14 @@ func (r *orderRepository) FindBySalesSlotID(ctx context.Context, salesSlotID typ
 
 func (r *orderRepository) FindByStatus(ctx context.Context, status types.OrderStatus) ([]models.Order, error) {
 	var orders []models.Order
-	if err := r.db.WithContext(ctx).
+	if err := dbFromContext(ctx, r.db).
 		Preload("SalesSlot").
 		Preload("Items").
 		Preload("Items.Product").
+		Preload("Items.Options").
+		Preload("Items.Components.Product").
+		Preload("Discounts", discountsInOrder).
+		Preload("Payments", paymentsInOrder).
 		Where("status = ?", status).
 		Find(&orders).Error; err != nil {
 		return nil, &repositories.RepositoryError{
@@ -123,8 +255,30 @@ func (r *orderRepository) FindByStatus(ctx context.Context, status types.OrderSt
 	return orders, nil
 }
 
+func (r *orderRepository) FindBySalesSlotAndStatuses(ctx context.Context, salesSlotID types.ID, statuses []types.OrderStatus) ([]models.Order, error) {
+	var orders []models.Order
+	query := dbFromContext(ctx, r.db).
+		Preload("Items").
+		Preload("Items.Product").
+		Preload("Items.Options").
+		Preload("Items.Components.Product").
+		Preload("Discounts", discountsInOrder).
+		Preload("Payments", paymentsInOrder).
+		Where("status IN ?", statuses)
+	if salesSlotID != "" {
+		query = query.Where("sales_slot_id = ?", salesSlotID)
+	}
+	if err := query.Order("confirmed_at, created_at").Find(&orders).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "FindBySalesSlotAndStatuses",
+			Err:       err,
+		}
+	}
+	return orders, nil
+}
+
 func (r *orderRepository) UpdateStatus(ctx context.Context, id types.ID, status types.OrderStatus) error {
-	result := r.db.WithContext(ctx).Model(&models.Order{}).
+	result := dbFromContext(ctx, r.db).Model(&models.Order{}).
 		Where("id = ?", id).
 		Update("status", status)
 
@@ -140,8 +294,133 @@ func (r *orderRepository) UpdateStatus(ctx context.Context, id types.ID, status
 	return nil
 }
 
+func (r *orderRepository) TransitionStatus(ctx context.Context, id types.ID, from, to types.OrderStatus, at time.Time) error {
+	var changes models.Order
+	changes.SetStatus(to, at)
+
+	result := dbFromContext(ctx, r.db).Model(&models.Order{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(&changes)
+
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "TransitionStatus",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return r.missingOrConflict(ctx, id)
+	}
+	return nil
+}
+
+func (r *orderRepository) AddTotalAmount(ctx context.Context, id types.ID, status types.OrderStatus, amount int) error {
+	result := dbFromContext(ctx, r.db).Model(&models.Order{}).
+		Where("id = ? AND status = ?", id, status).
+		Update("total_amount", gorm.Expr("total_amount + ?", amount))
+
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "AddTotalAmount",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return r.missingOrConflict(ctx, id)
+	}
+	return nil
+}
+
+func (r *orderRepository) AddItemQuantity(ctx context.Context, itemID types.ID, delta int) error {
+	result := dbFromContext(ctx, r.db).Model(&models.OrderItem{}).
+		Where("id = ?", itemID).
+		Update("quantity", gorm.Expr("quantity + ?", delta))
+
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "AddItemQuantity",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return repositories.NewErrNotFound("OrderItem", itemID)
+	}
+	return nil
+}
+
+func (r *orderRepository) DeleteItem(ctx context.Context, itemID types.ID) error {
+	db := dbFromContext(ctx, r.db)
+	for _, detail := range []any{&models.OrderItemOption{}, &models.OrderItemComponent{}, &models.OrderDiscount{}} {
+		if err := db.Delete(detail, "order_item_id = ?", itemID).Error; err != nil {
+			return &repositories.RepositoryError{
+				Operation: "DeleteItem",
+				Err:       err,
+			}
+		}
+	}
+	result := db.Delete(&models.OrderItem{}, "id = ?", itemID)
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "DeleteItem",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return repositories.NewErrNotFound("OrderItem", itemID)
+	}
+	return nil
+}
+
+func (r *orderRepository) AddRefund(ctx context.Context, id types.ID, statuses []types.OrderStatus, amount int) error {
+	result := dbFromContext(ctx, r.db).Model(&models.Order{}).
+		Where("id = ? AND status IN ? AND refunded_amount + ? <= total_amount", id, statuses, amount).
+		Update("refunded_amount", gorm.Expr("refunded_amount + ?", amount))
+
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "AddRefund",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return r.missingOrConflict(ctx, id)
+	}
+	return nil
+}
+
+func (r *orderRepository) AddRefundedQuantity(ctx context.Context, itemID types.ID, quantity int) error {
+	result := dbFromContext(ctx, r.db).Model(&models.OrderItem{}).
+		Where("id = ? AND refunded_quantity + ? <= quantity", itemID, quantity).
+		Update("refunded_quantity", gorm.Expr("refunded_quantity + ?", quantity))
+
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "AddRefundedQuantity",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrConflict
+	}
+	return nil
+}
+
+func (r *orderRepository) missingOrConflict(ctx context.Context, id types.ID) error {
+	var count int64
+	if err := dbFromContext(ctx, r.db).Model(&models.Order{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Count",
+			Err:       err,
+		}
+	}
+	if count == 0 {
+		return repositories.NewErrNotFound("Order", id)
+	}
+	return repositories.ErrConflict
+}
+
 func (r *orderRepository) AddItems(ctx context.Context, orderID types.ID, items []models.OrderItem) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
 		for i := range items {
 			items[i].OrderID = orderID
 			if err := tx.Create(&items[i]).Error; err != nil {
@@ -156,8 +435,11 @@ func (r *orderRepository) AddItems(ctx context.Context, orderID types.ID, items
 }
 
 func (r *orderRepository) CreateWithItems(ctx context.Context, order *models.Order, items []models.OrderItem) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(order).Error; err != nil {
+			if isDuplicateKey(tx, err) {
+				return repositories.ErrDuplicate
+			}
 			return &repositories.RepositoryError{
 				Operation: "CreateWithItems",
 				Err:       err,
@@ -178,14 +460,21 @@ func (r *orderRepository) CreateWithItems(ctx context.Context, order *models.Ord
 	})
 }
 
-func (r *orderRepository) FindByTicketNumber(ctx context.Context, ticketNumber string) (*models.Order, error) {
+func (r *orderRepository) FindByTicketNumber(ctx context.Context, salesSlotID types.ID, ticketNumber string) (*models.Order, error) {
 	var order models.Order
-	if err := r.db.WithContext(ctx).
+	query := dbFromContext(ctx, r.db).
 		Preload("SalesSlot").
 		Preload("Items").
 		Preload("Items.Product").
-		Where("ticket_number = ?", ticketNumber).
-		First(&order).Error; err != nil {
+		Preload("Items.Options").
+		Preload("Items.Components.Product").
+		Preload("Discounts", discountsInOrder).
+		Preload("Payments", paymentsInOrder).
+		Where("ticket_number = ?", ticketNumber)
+	if salesSlotID != "" {
+		query = query.Where("sales_slot_id = ?", salesSlotID)
+	}
+	if err := query.Order("created_at DESC").First(&order).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, &repositories.RepositoryError{
 				Operation: "FindByTicketNumber",
@@ -199,3 +488,133 @@ func (r *orderRepository) FindByTicketNumber(ctx context.Context, ticketNumber s
 	}
 	return &order, nil
 }
+
+func (r *orderRepository) TicketNumberExists(ctx context.Context, salesSlotID types.ID, ticketNumber string) (bool, error) {
+	var count int64
+	if err := dbFromContext(ctx, r.db).
+		Model(&models.Order{}).
+		Unscoped().
+		Where("sales_slot_id = ? AND ticket_number = ?", salesSlotID, ticketNumber).
+		Count(&count).Error; err != nil {
+		return false, &repositories.RepositoryError{
+			Operation: "TicketNumberExists",
+			Err:       err,
+		}
+	}
+	return count > 0, nil
+}
+
+func (r *orderRepository) FindExpiredReservations(ctx context.Context, createdBefore time.Time) ([]models.Order, error) {
+	var orders []models.Order
+	if err := dbFromContext(ctx, r.db).
+		Where("status = ? AND paid_amount = 0 AND created_at < ?", types.RESERVED, createdBefore).
+		Order("created_at").
+		Find(&orders).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "FindExpiredReservations",
+			Err:       err,
+		}
+	}
+	return orders, nil
+}
+
+func (r *orderRepository) SetCancelReason(ctx context.Context, id types.ID, reason string) error {
+	result := dbFromContext(ctx, r.db).Model(&models.Order{}).
+		Where("id = ?", id).
+		Update("cancel_reason", reason)
+
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "SetCancelReason",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return repositories.NewErrNotFound("Order", id)
+	}
+	return nil
+}
+
+func (r *orderRepository) UpdatePayment(ctx context.Context, order *models.Order) error {
+	result := dbFromContext(ctx, r.db).Model(&models.Order{}).
+		Where("id = ?", order.ID).
+		Select("PaidAmount", "IsPaid", "TransactionID", "PaidAt", "PaidTerminalID", "PaidByID").
+		Updates(order)
+
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "UpdatePayment",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return repositories.NewErrNotFound("Order", order.ID)
+	}
+	return nil
+}
+
+func (r *orderRepository) AddDiscount(ctx context.Context, discount *models.OrderDiscount) error {
+	if err := dbFromContext(ctx, r.db).Create(discount).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "AddDiscount",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *orderRepository) DeleteDiscount(ctx context.Context, discountID types.ID) error {
+	result := dbFromContext(ctx, r.db).Delete(&models.OrderDiscount{}, "id = ?", discountID)
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "DeleteDiscount",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return repositories.NewErrNotFound("OrderDiscount", discountID)
+	}
+	return nil
+}
+
+func (r *orderRepository) UpdatePricing(ctx context.Context, order *models.Order, status types.OrderStatus) error {
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Order{}).
+			Where("id = ? AND status = ?", order.ID, status).
+			Updates(map[string]any{
+				"total_amount":    order.TotalAmount,
+				"discount_amount": order.DiscountAmount,
+			})
+		if result.Error != nil {
+			return &repositories.RepositoryError{
+				Operation: "UpdatePricing",
+				Err:       result.Error,
+			}
+		}
+		if result.RowsAffected == 0 {
+			return r.missingOrConflict(ctx, order.ID)
+		}
+
+		for _, discount := range order.Discounts {
+			if err := tx.Model(&models.OrderDiscount{}).
+				Where("id = ?", discount.ID).
+				Update("amount", discount.Amount).Error; err != nil {
+				return &repositories.RepositoryError{
+					Operation: "UpdatePricing",
+					Err:       err,
+				}
+			}
+		}
+		for _, item := range order.Items {
+			if err := tx.Model(&models.OrderItem{}).
+				Where("id = ?", item.ID).
+				Update("discount_amount", item.DiscountAmount).Error; err != nil {
+				return &repositories.RepositoryError{
+					Operation: "UpdatePricing",
+					Err:       err,
+				}
+			}
+		}
+		return nil
+	})
+}
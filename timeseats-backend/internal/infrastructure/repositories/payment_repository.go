@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) repositories.PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+func (r *paymentRepository) Create(ctx context.Context, payment *models.Payment) error {
+	db := dbFromContext(ctx, r.db)
+	if err := db.Create(payment).Error; err != nil {
+		if isDuplicateKey(db, err) {
+			return repositories.ErrDuplicate
+		}
+		return &repositories.RepositoryError{
+			Operation: "Create",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *paymentRepository) FindByOrderID(ctx context.Context, orderID types.ID) ([]models.Payment, error) {
+	var payments []models.Payment
+	if err := dbFromContext(ctx, r.db).
+		Where("order_id = ?", orderID).
+		Order("created_at").
+		Find(&payments).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByOrderID",
+			Err:       err,
+		}
+	}
+	return payments, nil
+}
+
+func (r *paymentRepository) FindByReference(ctx context.Context, reference string) (*models.Payment, error) {
+	var payment models.Payment
+	if err := dbFromContext(ctx, r.db).First(&payment, "reference = ?", reference).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NewErrNotFound("Payment", types.ID(reference))
+		}
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByReference",
+			Err:       err,
+		}
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) Update(ctx context.Context, payment *models.Payment) error {
+	result := dbFromContext(ctx, r.db).Model(&models.Payment{}).
+		Where("id = ?", payment.ID).
+		Select("Status", "TakenByID", "TerminalID", "CompletedAt").
+		Updates(payment)
+
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "Update",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return repositories.NewErrNotFound("Payment", payment.ID)
+	}
+	return nil
+}
+
+func (r *paymentRepository) AddRefund(ctx context.Context, id types.ID, amount int) error {
+	result := dbFromContext(ctx, r.db).Model(&models.Payment{}).
+		Where("id = ? AND status = ? AND refunded_amount + ? <= amount", id, types.PAYMENT_COMPLETED, amount).
+		Update("refunded_amount", gorm.Expr("refunded_amount + ?", amount))
+
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "AddRefund",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrConflict
+	}
+	return nil
+}
+
+func (r *paymentRepository) SumPayments(ctx context.Context, terminalID types.ID, method types.PaymentMethod, from, to time.Time) (int, error) {
+	var total int
+	if err := r.completed(ctx, terminalID, method, from, to).Select("COALESCE(SUM(payments.amount), 0)").Scan(&total).Error; err != nil {
+		return 0, &repositories.RepositoryError{
+			Operation: "SumPayments",
+			Err:       err,
+		}
+	}
+	return total, nil
+}
+
+func (r *paymentRepository) SumCashTendered(ctx context.Context, terminalID types.ID, from, to time.Time) (*repositories.CashTotals, error) {
+	var totals repositories.CashTotals
+	if err := r.completed(ctx, terminalID, types.CASH, from, to).
+		Select("COALESCE(SUM(payments.tendered_amount), 0) AS tendered, COALESCE(SUM(payments.change_amount), 0) AS change").
+		Scan(&totals).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "SumCashTendered",
+			Err:       err,
+		}
+	}
+	return &totals, nil
+}
+
+// completed selects the completed payments of a method taken at the terminal
+// in [from, to), leaving out those of cancelled orders.
+func (r *paymentRepository) completed(ctx context.Context, terminalID types.ID, method types.PaymentMethod, from, to time.Time) *gorm.DB {
+	query := dbFromContext(ctx, r.db).Model(&models.Payment{}).
+		Joins("JOIN orders ON orders.id = payments.order_id").
+		Where("payments.status = ? AND orders.status <> ?", types.PAYMENT_COMPLETED, types.CANCELLED).
+		Where("payments.terminal_id = ? AND payments.method = ? AND payments.completed_at >= ?", terminalID, method, from)
+	if !to.IsZero() {
+		query = query.Where("payments.completed_at < ?", to)
+	}
+	return query
+}
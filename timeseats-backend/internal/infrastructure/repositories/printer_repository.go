@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type printerRepository struct {
+	db *gorm.DB
+}
+
+func NewPrinterRepository(db *gorm.DB) repositories.PrinterRepository {
+	return &printerRepository{db: db}
+}
+
+func (r *printerRepository) Create(ctx context.Context, printer *models.Printer) error {
+	if err := dbFromContext(ctx, r.db).Omit(clause.Associations).Create(printer).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Create",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *printerRepository) FindByID(ctx context.Context, id types.ID) (*models.Printer, error) {
+	var printer models.Printer
+	err := dbFromContext(ctx, r.db).
+		Preload("Category").
+		Preload("Terminal").
+		First(&printer, "id = ?", id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NewErrNotFound("Printer", id)
+		}
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByID",
+			Err:       err,
+		}
+	}
+	return &printer, nil
+}
+
+func (r *printerRepository) FindAll(ctx context.Context) ([]models.Printer, error) {
+	var printers []models.Printer
+	err := dbFromContext(ctx, r.db).
+		Preload("Category").
+		Preload("Terminal").
+		Order("kind, name").
+		Find(&printers).Error
+	if err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "FindAll",
+			Err:       err,
+		}
+	}
+	return printers, nil
+}
+
+func (r *printerRepository) FindByKind(ctx context.Context, kind types.PrinterKind) ([]models.Printer, error) {
+	var printers []models.Printer
+	err := dbFromContext(ctx, r.db).
+		Preload("Category").
+		Where("kind = ?", kind).
+		Order("name").
+		Find(&printers).Error
+	if err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByKind",
+			Err:       err,
+		}
+	}
+	return printers, nil
+}
+
+func (r *printerRepository) Update(ctx context.Context, printer *models.Printer) error {
+	if err := dbFromContext(ctx, r.db).Omit(clause.Associations).Save(printer).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Update",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *printerRepository) Delete(ctx context.Context, id types.ID) error {
+	result := dbFromContext(ctx, r.db).Delete(&models.Printer{}, "id = ?", id)
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "Delete",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return repositories.NewErrNotFound("Printer", id)
+	}
+	return nil
+}
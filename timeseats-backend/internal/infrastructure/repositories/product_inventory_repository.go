@@ -18,7 +18,7 @@ func NewProductInventoryRepository(db *gorm.DB) repositories.ProductInventoryRep
 }
 
 func (r *productInventoryRepository) Create(ctx context.Context, inventory *models.ProductInventory) error {
-	if err := r.db.WithContext(ctx).Create(inventory).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Create(inventory).Error; err != nil {
 		return &repositories.RepositoryError{
 			Operation: "Create",
 			Err:       err,
@@ -29,7 +29,7 @@ func (r *productInventoryRepository) Create(ctx context.Context, inventory *mode
 
 func (r *productInventoryRepository) FindByID(ctx context.Context, id types.ID) (*models.ProductInventory, error) {
 	var inventory models.ProductInventory
-	if err := r.db.WithContext(ctx).
+	if err := dbFromContext(ctx, r.db).
 		Preload("Product").
 		Preload("SalesSlot").
 		First(&inventory, "id = ?", id).Error; err != nil {
@@ -46,7 +46,7 @@ func (r *productInventoryRepository) FindByID(ctx context.Context, id types.ID)
 
 func (r *productInventoryRepository) FindAll(ctx context.Context) ([]models.ProductInventory, error) {
 	var inventories []models.ProductInventory
-	if err := r.db.WithContext(ctx).
+	if err := dbFromContext(ctx, r.db).
 		Preload("Product").
 		Preload("SalesSlot").
 		Find(&inventories).Error; err != nil {
@@ -59,7 +59,7 @@ func (r *productInventoryRepository) FindAll(ctx context.Context) ([]models.Prod
 }
 
 func (r *productInventoryRepository) Update(ctx context.Context, inventory *models.ProductInventory) error {
-	if err := r.db.WithContext(ctx).Save(inventory).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Save(inventory).Error; err != nil {
 		return &repositories.RepositoryError{
 			Operation: "Update",
 			Err:       err,
@@ -69,7 +69,7 @@ func (r *productInventoryRepository) Update(ctx context.Context, inventory *mode
 }
 
 func (r *productInventoryRepository) Delete(ctx context.Context, id types.ID) error {
-	result := r.db.WithContext(ctx).Delete(&models.ProductInventory{}, "id = ?", id)
+	result := dbFromContext(ctx, r.db).Delete(&models.ProductInventory{}, "id = ?", id)
 	if result.Error != nil {
 		return &repositories.RepositoryError{
 			Operation: "Delete",
@@ -84,10 +84,13 @@ func (r *productInventoryRepository) Delete(ctx context.Context, id types.ID) er
 
 func (r *productInventoryRepository) FindBySalesSlotID(ctx context.Context, salesSlotID types.ID) ([]models.ProductInventory, error) {
 	var inventories []models.ProductInventory
-	if err := r.db.WithContext(ctx).
-		Preload("Product").
+	if err := dbFromContext(ctx, r.db).
+		Preload("Product.Category").
 		Preload("SalesSlot").
-		Where("sales_slot_id = ?", salesSlotID).
+		Joins("JOIN products ON products.id = product_inventories.product_id").
+		Joins(joinCategories).
+		Where("product_inventories.sales_slot_id = ?", salesSlotID).
+		Order(menuOrder).
 		Find(&inventories).Error; err != nil {
 		return nil, &repositories.RepositoryError{
 			Operation: "FindBySalesSlotID",
@@ -99,7 +102,7 @@ func (r *productInventoryRepository) FindBySalesSlotID(ctx context.Context, sale
 
 func (r *productInventoryRepository) FindByProductID(ctx context.Context, productID types.ID) ([]models.ProductInventory, error) {
 	var inventories []models.ProductInventory
-	if err := r.db.WithContext(ctx).
+	if err := dbFromContext(ctx, r.db).
 		Preload("Product").
 		Preload("SalesSlot").
 		Where("product_id = ?", productID).
@@ -114,7 +117,7 @@ func (r *productInventoryRepository) FindByProductID(ctx context.Context, produc
 
 func (r *productInventoryRepository) FindBySalesSlotAndProduct(ctx context.Context, salesSlotID, productID types.ID) (*models.ProductInventory, error) {
 	var inventory models.ProductInventory
-	if err := r.db.WithContext(ctx).
+	if err := dbFromContext(ctx, r.db).
 		Preload("Product").
 		Preload("SalesSlot").
 		Where("sales_slot_id = ? AND product_id = ?", salesSlotID, productID).
@@ -131,7 +134,7 @@ func (r *productInventoryRepository) FindBySalesSlotAndProduct(ctx context.Conte
 }
 
 func (r *productInventoryRepository) UpdateQuantities(ctx context.Context, id types.ID, reserved, sold int) error {
-	result := r.db.WithContext(ctx).Model(&models.ProductInventory{}).
+	result := dbFromContext(ctx, r.db).Model(&models.ProductInventory{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
 			"reserved_quantity": reserved,
@@ -149,3 +152,38 @@ func (r *productInventoryRepository) UpdateQuantities(ctx context.Context, id ty
 	}
 	return nil
 }
+
+func (r *productInventoryRepository) AdjustQuantities(ctx context.Context, id types.ID, reservedDelta, soldDelta int) error {
+	query := dbFromContext(ctx, r.db).Model(&models.ProductInventory{}).
+		Where("id = ?", id).
+		Where("reserved_quantity + ? >= 0 AND sold_quantity + ? >= 0", reservedDelta, soldDelta)
+	if reservedDelta+soldDelta > 0 {
+		query = query.Where("initial_quantity - reserved_quantity - sold_quantity >= ?", reservedDelta+soldDelta)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"reserved_quantity": gorm.Expr("reserved_quantity + ?", reservedDelta),
+		"sold_quantity":     gorm.Expr("sold_quantity + ?", soldDelta),
+	})
+
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "AdjustQuantities",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := dbFromContext(ctx, r.db).Model(&models.ProductInventory{}).Where("id = ?", id).Count(&count).Error; err != nil {
+			return &repositories.RepositoryError{
+				Operation: "AdjustQuantities",
+				Err:       err,
+			}
+		}
+		if count == 0 {
+			return repositories.NewErrNotFound("ProductInventory", id)
+		}
+		return repositories.ErrInsufficientQuantity
+	}
+	return nil
+}
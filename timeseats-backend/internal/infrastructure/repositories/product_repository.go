@@ -7,8 +7,28 @@ import (
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
 	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// menuOrder lists products the way the menu shows them: by category, with
+// uncategorized products last, then by the product's own sort order. Queries
+// using it must join categories with joinCategories.
+const menuOrder = "categories.id IS NULL, categories.sort_order, categories.name, products.sort_order, products.name"
+
+const joinCategories = "LEFT JOIN categories ON categories.id = products.category_id AND categories.deleted_at IS NULL"
+
+// preloadMenu loads what the menu shows with a product: its category, its
+// modifier groups with their options and, for a combo, its components.
+func preloadMenu(query *gorm.DB) *gorm.DB {
+	return query.
+		Preload("Category").
+		Preload("ModifierGroups", func(db *gorm.DB) *gorm.DB {
+			return db.Order("modifier_groups.sort_order, modifier_groups.name")
+		}).
+		Preload("ModifierGroups.Options", preloadModifierOptions).
+		Preload("Components.Product")
+}
+
 type productRepository struct {
 	db *gorm.DB
 }
@@ -18,7 +38,7 @@ func NewProductRepository(db *gorm.DB) repositories.ProductRepository {
 }
 
 func (r *productRepository) Create(ctx context.Context, product *models.Product) error {
-	if err := r.db.WithContext(ctx).Create(product).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Omit(clause.Associations).Create(product).Error; err != nil {
 		return &repositories.RepositoryError{
 			Operation: "Create",
 			Err:       err,
@@ -29,7 +49,7 @@ func (r *productRepository) Create(ctx context.Context, product *models.Product)
 
 func (r *productRepository) FindByID(ctx context.Context, id types.ID) (*models.Product, error) {
 	var product models.Product
-	if err := r.db.WithContext(ctx).First(&product, "id = ?", id).Error; err != nil {
+	if err := preloadMenu(dbFromContext(ctx, r.db)).First(&product, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, repositories.NewErrNotFound("Product", id)
 		}
@@ -43,7 +63,10 @@ func (r *productRepository) FindByID(ctx context.Context, id types.ID) (*models.
 
 func (r *productRepository) FindAll(ctx context.Context) ([]models.Product, error) {
 	var products []models.Product
-	if err := r.db.WithContext(ctx).Find(&products).Error; err != nil {
+	if err := preloadMenu(dbFromContext(ctx, r.db)).
+		Joins(joinCategories).
+		Order(menuOrder).
+		Find(&products).Error; err != nil {
 		return nil, &repositories.RepositoryError{
 			Operation: "FindAll",
 			Err:       err,
@@ -53,7 +76,7 @@ func (r *productRepository) FindAll(ctx context.Context) ([]models.Product, erro
 }
 
 func (r *productRepository) Update(ctx context.Context, product *models.Product) error {
-	if err := r.db.WithContext(ctx).Save(product).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Omit(clause.Associations).Save(product).Error; err != nil {
 		return &repositories.RepositoryError{
 			Operation: "Update",
 			Err:       err,
@@ -63,7 +86,7 @@ func (r *productRepository) Update(ctx context.Context, product *models.Product)
 }
 
 func (r *productRepository) Delete(ctx context.Context, id types.ID) error {
-	result := r.db.WithContext(ctx).Delete(&models.Product{}, "id = ?", id)
+	result := dbFromContext(ctx, r.db).Delete(&models.Product{}, "id = ?", id)
 	if result.Error != nil {
 		return &repositories.RepositoryError{
 			Operation: "Delete",
@@ -78,7 +101,7 @@ func (r *productRepository) Delete(ctx context.Context, id types.ID) error {
 
 func (r *productRepository) FindByName(ctx context.Context, name string) (*models.Product, error) {
 	var product models.Product
-	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&product).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Where("name = ?", name).First(&product).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, &repositories.RepositoryError{
 				Operation: "FindByName",
@@ -92,3 +115,51 @@ func (r *productRepository) FindByName(ctx context.Context, name string) (*model
 	}
 	return &product, nil
 }
+
+func (r *productRepository) ClearCategory(ctx context.Context, categoryID types.ID) error {
+	if err := dbFromContext(ctx, r.db).Model(&models.Product{}).
+		Where("category_id = ?", categoryID).
+		Update("category_id", nil).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "ClearCategory",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *productRepository) ReplaceComponents(ctx context.Context, comboID types.ID, components []models.ComboComponent) error {
+	err := dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.ComboComponent{}, "combo_id = ?", comboID).Error; err != nil {
+			return err
+		}
+		for i := range components {
+			components[i].ComboID = comboID
+			if err := tx.Omit(clause.Associations).Create(&components[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return &repositories.RepositoryError{
+			Operation: "ReplaceComponents",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *productRepository) IsComponent(ctx context.Context, productID types.ID) (bool, error) {
+	var count int64
+	if err := dbFromContext(ctx, r.db).Model(&models.ComboComponent{}).
+		Joins("JOIN products ON products.id = combo_components.combo_id AND products.deleted_at IS NULL").
+		Where("combo_components.product_id = ?", productID).
+		Count(&count).Error; err != nil {
+		return false, &repositories.RepositoryError{
+			Operation: "IsComponent",
+			Err:       err,
+		}
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+type qrCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewQRCodeRepository(db *gorm.DB) repositories.QRCodeRepository {
+	return &qrCodeRepository{db: db}
+}
+
+func (r *qrCodeRepository) Create(ctx context.Context, code *models.QRCode) error {
+	if err := dbFromContext(ctx, r.db).Create(code).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Create",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *qrCodeRepository) FindLatestByOrderID(ctx context.Context, orderID types.ID) (*models.QRCode, error) {
+	var code models.QRCode
+	if err := dbFromContext(ctx, r.db).
+		Where("order_id = ?", orderID).
+		Order("created_at DESC").
+		First(&code).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NewErrNotFound("QRCode", orderID)
+		}
+		return nil, &repositories.RepositoryError{
+			Operation: "FindLatestByOrderID",
+			Err:       err,
+		}
+	}
+	return &code, nil
+}
+
+func (r *qrCodeRepository) FindByMerchantPaymentID(ctx context.Context, merchantPaymentID string) (*models.QRCode, error) {
+	var code models.QRCode
+	if err := dbFromContext(ctx, r.db).First(&code, "merchant_payment_id = ?", merchantPaymentID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NewErrNotFound("QRCode", types.ID(merchantPaymentID))
+		}
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByMerchantPaymentID",
+			Err:       err,
+		}
+	}
+	return &code, nil
+}
+
+func (r *qrCodeRepository) FindActive(ctx context.Context) ([]models.QRCode, error) {
+	var codes []models.QRCode
+	if err := dbFromContext(ctx, r.db).
+		Where("status = ?", types.QR_CODE_ACTIVE).
+		Order("created_at").
+		Find(&codes).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "FindActive",
+			Err:       err,
+		}
+	}
+	return codes, nil
+}
+
+func (r *qrCodeRepository) UpdateStatus(ctx context.Context, code *models.QRCode) error {
+	if err := dbFromContext(ctx, r.db).Model(code).
+		Updates(map[string]any{
+			"status":     code.Status,
+			"payment_id": code.PaymentID,
+		}).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "UpdateStatus",
+			Err:       err,
+		}
+	}
+	return nil
+}
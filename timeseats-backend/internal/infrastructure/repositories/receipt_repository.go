@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type receiptRepository struct {
+	db *gorm.DB
+}
+
+func NewReceiptRepository(db *gorm.DB) repositories.ReceiptRepository {
+	return &receiptRepository{db: db}
+}
+
+func (r *receiptRepository) Create(ctx context.Context, receipt *models.Receipt) error {
+	if err := dbFromContext(ctx, r.db).Create(receipt).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Create",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *receiptRepository) FindByOrderID(ctx context.Context, orderID types.ID) (*models.Receipt, error) {
+	return r.findByOrderID(dbFromContext(ctx, r.db), orderID, "FindByOrderID")
+}
+
+func (r *receiptRepository) FindByOrderIDForUpdate(ctx context.Context, orderID types.ID) (*models.Receipt, error) {
+	return r.findByOrderID(dbFromContext(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}), orderID, "FindByOrderIDForUpdate")
+}
+
+func (r *receiptRepository) findByOrderID(query *gorm.DB, orderID types.ID, operation string) (*models.Receipt, error) {
+	var receipt models.Receipt
+	if err := query.Where("order_id = ?", orderID).First(&receipt).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NewErrNotFound("Receipt", orderID)
+		}
+		return nil, &repositories.RepositoryError{
+			Operation: operation,
+			Err:       err,
+		}
+	}
+	return &receipt, nil
+}
+
+func (r *receiptRepository) UpdatePrints(ctx context.Context, receipt *models.Receipt) error {
+	if err := dbFromContext(ctx, r.db).Model(receipt).
+		Updates(map[string]any{
+			"print_count":     receipt.PrintCount,
+			"last_printed_at": receipt.LastPrintedAt,
+		}).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "UpdatePrints",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *receiptRepository) NextSerialNumber(ctx context.Context) (int, error) {
+	var next int
+	if err := dbFromContext(ctx, r.db).Raw(`SELECT nextval('receipt_serial_numbers')`).Scan(&next).Error; err != nil {
+		return 0, &repositories.RepositoryError{
+			Operation: "NextSerialNumber",
+			Err:       err,
+		}
+	}
+	return next, nil
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+type refundRepository struct {
+	db *gorm.DB
+}
+
+func NewRefundRepository(db *gorm.DB) repositories.RefundRepository {
+	return &refundRepository{db: db}
+}
+
+func (r *refundRepository) Create(ctx context.Context, refund *models.Refund) error {
+	if err := dbFromContext(ctx, r.db).Create(refund).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Create",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *refundRepository) FindByOrderID(ctx context.Context, orderID types.ID) ([]models.Refund, error) {
+	var refunds []models.Refund
+	if err := dbFromContext(ctx, r.db).
+		Preload("Items").
+		Preload("Payments").
+		Where("order_id = ?", orderID).
+		Order("created_at").
+		Find(&refunds).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByOrderID",
+			Err:       err,
+		}
+	}
+	return refunds, nil
+}
+
+func (r *refundRepository) SumRefunds(ctx context.Context, terminalID types.ID, method types.PaymentMethod, from, to time.Time) (int, error) {
+	query := dbFromContext(ctx, r.db).Model(&models.RefundPayment{}).
+		Joins("JOIN refunds ON refunds.id = refund_payments.refund_id").
+		Where("refunds.terminal_id = ? AND refund_payments.method = ? AND refunds.created_at >= ?", terminalID, method, from)
+	if !to.IsZero() {
+		query = query.Where("refunds.created_at < ?", to)
+	}
+
+	var total int
+	if err := query.Select("COALESCE(SUM(refund_payments.amount), 0)").Scan(&total).Error; err != nil {
+		return 0, &repositories.RepositoryError{
+			Operation: "SumRefunds",
+			Err:       err,
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) repositories.ReportRepository {
+	return &reportRepository{db: db}
+}
+
+// orders selects the orders matching filter. sold limits them to sales,
+// those confirmed or whose completed payments cover the total; otherwise
+// only cancelled orders are selected.
+func (r *reportRepository) orders(ctx context.Context, filter repositories.ReportFilter, sold bool) *gorm.DB {
+	query := dbFromContext(ctx, r.db).Table("orders").Where("orders.deleted_at IS NULL")
+	if sold {
+		paid := dbFromContext(ctx, r.db).Table("payments").
+			Select("1").
+			Where("payments.order_id = orders.id AND payments.status = ?", types.PAYMENT_COMPLETED)
+		query = query.Where("orders.status <> ? AND (orders.status IN ? OR (orders.paid_amount >= orders.total_amount AND EXISTS (?)))",
+			types.CANCELLED,
+			[]types.OrderStatus{types.CONFIRMED, types.PREPARING, types.READY, types.DELIVERED},
+			paid)
+	} else {
+		query = query.Where("orders.status = ?", types.CANCELLED)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("orders.created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("orders.created_at < ?", filter.To)
+	}
+	if filter.SalesSlotID != "" {
+		query = query.Where("orders.sales_slot_id = ?", filter.SalesSlotID)
+	}
+	if filter.TerminalID != "" {
+		query = query.Where("COALESCE(orders.paid_terminal_id, orders.terminal_id) = ?", filter.TerminalID)
+	}
+	return query
+}
+
+func (r *reportRepository) SalesTotals(ctx context.Context, filter repositories.ReportFilter) (*repositories.SalesTotals, error) {
+	return r.totals(ctx, filter, true, "SalesTotals")
+}
+
+func (r *reportRepository) CancelledTotals(ctx context.Context, filter repositories.ReportFilter) (*repositories.SalesTotals, error) {
+	return r.totals(ctx, filter, false, "CancelledTotals")
+}
+
+func (r *reportRepository) totals(ctx context.Context, filter repositories.ReportFilter, sold bool, operation string) (*repositories.SalesTotals, error) {
+	var totals repositories.SalesTotals
+	if err := r.orders(ctx, filter, sold).
+		Select(`COUNT(*) AS orders, COALESCE(SUM(orders.total_amount + orders.discount_amount), 0) AS gross,
+			COALESCE(SUM(orders.discount_amount), 0) AS discounts,
+			COALESCE(SUM(orders.total_amount - orders.refunded_amount), 0) AS revenue,
+			COALESCE(SUM(orders.refunded_amount), 0) AS refunded`).
+		Scan(&totals).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: operation,
+			Err:       err,
+		}
+	}
+	return &totals, nil
+}
+
+// refundedItems joins what was refunded for each order item as
+// refunded.amount. Refunds of discounted items pay back each unit's share of
+// the line after discounts, so this is the discounted price of the units
+// refunded.
+const refundedItems = `LEFT JOIN (SELECT order_item_id, SUM(amount) AS amount FROM refund_items GROUP BY order_item_id) AS refunded
+	ON refunded.order_item_id = order_items.id`
+
+func (r *reportRepository) ProductSales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.ProductSales, error) {
+	var rows []repositories.ProductSales
+	if err := r.orders(ctx, filter, true).
+		Joins("JOIN order_items ON order_items.order_id = orders.id").
+		Joins("LEFT JOIN products ON products.id = order_items.product_id").
+		Joins(refundedItems).
+		Select(`orders.sales_slot_id, order_items.product_id, COALESCE(products.name, '') AS product_name,
+			SUM(order_items.quantity - order_items.refunded_quantity) AS units,
+			SUM(order_items.quantity * order_items.price - order_items.discount_amount - COALESCE(refunded.amount, 0)) AS revenue,
+			SUM(order_items.refunded_quantity) AS refunded_units`).
+		Group("orders.sales_slot_id, order_items.product_id, products.name").
+		Order("orders.sales_slot_id, revenue DESC").
+		Scan(&rows).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "ProductSales",
+			Err:       err,
+		}
+	}
+	return rows, nil
+}
+
+func (r *reportRepository) ComponentSales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.ComponentSales, error) {
+	var rows []repositories.ComponentSales
+	if err := r.orders(ctx, filter, true).
+		Joins("JOIN order_items ON order_items.order_id = orders.id").
+		Joins("LEFT JOIN order_item_components ON order_item_components.order_item_id = order_items.id").
+		Joins("LEFT JOIN products ON products.id = COALESCE(order_item_components.product_id, order_items.product_id)").
+		Select(`orders.sales_slot_id,
+			COALESCE(order_item_components.product_id, order_items.product_id) AS product_id,
+			COALESCE(products.name, '') AS product_name,
+			SUM((order_items.quantity - order_items.refunded_quantity) * COALESCE(order_item_components.quantity, 1)) AS units,
+			COALESCE(SUM((order_items.quantity - order_items.refunded_quantity) * order_item_components.quantity), 0) AS combo_units,
+			SUM(order_items.refunded_quantity * COALESCE(order_item_components.quantity, 1)) AS refunded_units`).
+		Group("orders.sales_slot_id, COALESCE(order_item_components.product_id, order_items.product_id), products.name").
+		Order("orders.sales_slot_id, units DESC").
+		Scan(&rows).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "ComponentSales",
+			Err:       err,
+		}
+	}
+	return rows, nil
+}
+
+func (r *reportRepository) TaxSales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.TaxSales, error) {
+	var rows []repositories.TaxSales
+	if err := r.orders(ctx, filter, true).
+		Joins("JOIN order_items ON order_items.order_id = orders.id").
+		Joins(refundedItems).
+		Select(`order_items.tax_rate AS rate,
+			SUM(order_items.quantity * order_items.price - order_items.discount_amount) AS sales,
+			COALESCE(SUM(refunded.amount), 0) AS refunded`).
+		Group("order_items.tax_rate").
+		Order("order_items.tax_rate").
+		Scan(&rows).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "TaxSales",
+			Err:       err,
+		}
+	}
+	return rows, nil
+}
+
+func (r *reportRepository) PaymentMethodSales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.PaymentMethodSales, error) {
+	var rows []repositories.PaymentMethodSales
+	paid := r.orders(ctx, filter, true).
+		Joins("JOIN payments ON payments.order_id = orders.id AND payments.status = ?", types.PAYMENT_COMPLETED).
+		Select("payments.method AS payment_method, orders.id AS order_id, payments.amount - payments.refunded_amount AS amount")
+	// What the completed payments leave unpaid, all of it for orders sold
+	// without a recorded payment, counts under the method the order was
+	// taken with.
+	paidNet := dbFromContext(ctx, r.db).Table("payments").
+		Select("COALESCE(SUM(payments.amount - payments.refunded_amount), 0)").
+		Where("payments.order_id = orders.id AND payments.status = ?", types.PAYMENT_COMPLETED)
+	unpaid := r.orders(ctx, filter, true).
+		Select("orders.payment_method, orders.id, orders.total_amount - orders.refunded_amount - (?)", paidNet).
+		Where("orders.total_amount - orders.refunded_amount > (?)", paidNet)
+	if err := dbFromContext(ctx, r.db).
+		Table("(? UNION ALL ?) AS tenders", paid, unpaid).
+		Select(`tenders.payment_method,
+			COUNT(DISTINCT tenders.order_id) AS orders,
+			SUM(tenders.amount) AS revenue`).
+		Group("tenders.payment_method").
+		Order("tenders.payment_method").
+		Scan(&rows).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "PaymentMethodSales",
+			Err:       err,
+		}
+	}
+	return rows, nil
+}
+
+func (r *reportRepository) HourlySales(ctx context.Context, filter repositories.ReportFilter) ([]repositories.HourlySales, error) {
+	var rows []repositories.HourlySales
+	if err := r.orders(ctx, filter, true).
+		Select("date_trunc('hour', orders.created_at) AS hour, COUNT(*) AS orders, SUM(orders.total_amount - orders.refunded_amount) AS revenue").
+		Group("hour").
+		Order("hour").
+		Scan(&rows).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "HourlySales",
+			Err:       err,
+		}
+	}
+	return rows, nil
+}
@@ -19,7 +19,7 @@ func NewSalesSlotRepository(db *gorm.DB) repositories.SalesSlotRepository {
 }
 
 func (r *salesSlotRepository) Create(ctx context.Context, slot *models.SalesSlot) error {
-	if err := r.db.WithContext(ctx).Create(slot).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Create(slot).Error; err != nil {
 		return &repositories.RepositoryError{
 			Operation: "Create",
 			Err:       err,
@@ -30,7 +30,7 @@ func (r *salesSlotRepository) Create(ctx context.Context, slot *models.SalesSlot
 
 func (r *salesSlotRepository) FindByID(ctx context.Context, id types.ID) (*models.SalesSlot, error) {
 	var slot models.SalesSlot
-	if err := r.db.WithContext(ctx).First(&slot, "id = ?", id).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).First(&slot, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, repositories.NewErrNotFound("SalesSlot", id)
 		}
@@ -44,7 +44,7 @@ func (r *salesSlotRepository) FindByID(ctx context.Context, id types.ID) (*model
 
 func (r *salesSlotRepository) FindAll(ctx context.Context) ([]models.SalesSlot, error) {
 	var slots []models.SalesSlot
-	if err := r.db.WithContext(ctx).Find(&slots).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Find(&slots).Error; err != nil {
 		return nil, &repositories.RepositoryError{
 			Operation: "FindAll",
 			Err:       err,
@@ -54,7 +54,7 @@ func (r *salesSlotRepository) FindAll(ctx context.Context) ([]models.SalesSlot,
 }
 
 func (r *salesSlotRepository) Update(ctx context.Context, slot *models.SalesSlot) error {
-	if err := r.db.WithContext(ctx).Save(slot).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Save(slot).Error; err != nil {
 		return &repositories.RepositoryError{
 			Operation: "Update",
 			Err:       err,
@@ -64,7 +64,7 @@ func (r *salesSlotRepository) Update(ctx context.Context, slot *models.SalesSlot
 }
 
 func (r *salesSlotRepository) Delete(ctx context.Context, id types.ID) error {
-	result := r.db.WithContext(ctx).Delete(&models.SalesSlot{}, "id = ?", id)
+	result := dbFromContext(ctx, r.db).Delete(&models.SalesSlot{}, "id = ?", id)
 	if result.Error != nil {
 		return &repositories.RepositoryError{
 			Operation: "Delete",
@@ -79,7 +79,7 @@ func (r *salesSlotRepository) Delete(ctx context.Context, id types.ID) error {
 
 func (r *salesSlotRepository) FindActive(ctx context.Context) ([]models.SalesSlot, error) {
 	var slots []models.SalesSlot
-	if err := r.db.WithContext(ctx).Where("is_active = ?", true).Find(&slots).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Where("is_active = ?", true).Find(&slots).Error; err != nil {
 		return nil, &repositories.RepositoryError{
 			Operation: "FindActive",
 			Err:       err,
@@ -90,7 +90,7 @@ func (r *salesSlotRepository) FindActive(ctx context.Context) ([]models.SalesSlo
 
 func (r *salesSlotRepository) FindByTimeRange(ctx context.Context, start, end time.Time) ([]models.SalesSlot, error) {
 	var slots []models.SalesSlot
-	if err := r.db.WithContext(ctx).
+	if err := dbFromContext(ctx, r.db).
 		Where("start_time >= ? AND end_time <= ?", start, end).
 		Find(&slots).Error; err != nil {
 		return nil, &repositories.RepositoryError{
@@ -102,7 +102,7 @@ func (r *salesSlotRepository) FindByTimeRange(ctx context.Context, start, end ti
 }
 
 func (r *salesSlotRepository) ActivateSlot(ctx context.Context, id types.ID) error {
-	result := r.db.WithContext(ctx).Model(&models.SalesSlot{}).
+	result := dbFromContext(ctx, r.db).Model(&models.SalesSlot{}).
 		Where("id = ?", id).
 		Update("is_active", true)
 
@@ -119,7 +119,7 @@ func (r *salesSlotRepository) ActivateSlot(ctx context.Context, id types.ID) err
 }
 
 func (r *salesSlotRepository) DeactivateSlot(ctx context.Context, id types.ID) error {
-	result := r.db.WithContext(ctx).Model(&models.SalesSlot{}).
+	result := dbFromContext(ctx, r.db).Model(&models.SalesSlot{}).
 		Where("id = ?", id).
 		Update("is_active", false)
 
@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+type staffRepository struct {
+	db *gorm.DB
+}
+
+func NewStaffRepository(db *gorm.DB) repositories.StaffRepository {
+	return &staffRepository{db: db}
+}
+
+func (r *staffRepository) Create(ctx context.Context, staff *models.Staff) error {
+	if err := dbFromContext(ctx, r.db).Create(staff).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Create",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *staffRepository) FindByID(ctx context.Context, id types.ID) (*models.Staff, error) {
+	var staff models.Staff
+	if err := dbFromContext(ctx, r.db).First(&staff, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NewErrNotFound("Staff", id)
+		}
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByID",
+			Err:       err,
+		}
+	}
+	return &staff, nil
+}
+
+func (r *staffRepository) FindAll(ctx context.Context) ([]models.Staff, error) {
+	var staff []models.Staff
+	if err := dbFromContext(ctx, r.db).Order("username").Find(&staff).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "FindAll",
+			Err:       err,
+		}
+	}
+	return staff, nil
+}
+
+func (r *staffRepository) Update(ctx context.Context, staff *models.Staff) error {
+	if err := dbFromContext(ctx, r.db).Save(staff).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Update",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *staffRepository) Delete(ctx context.Context, id types.ID) error {
+	result := dbFromContext(ctx, r.db).Delete(&models.Staff{}, "id = ?", id)
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "Delete",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return repositories.NewErrNotFound("Staff", id)
+	}
+	return nil
+}
+
+func (r *staffRepository) FindByUsername(ctx context.Context, username string) (*models.Staff, error) {
+	var staff models.Staff
+	if err := dbFromContext(ctx, r.db).Where("username = ?", username).First(&staff).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NewErrNotFound("Staff", types.ID(username))
+		}
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByUsername",
+			Err:       err,
+		}
+	}
+	return &staff, nil
+}
+
+func (r *staffRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	if err := dbFromContext(ctx, r.db).Model(&models.Staff{}).Count(&count).Error; err != nil {
+		return 0, &repositories.RepositoryError{
+			Operation: "Count",
+			Err:       err,
+		}
+	}
+	return count, nil
+}
+
+type staffSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewStaffSessionRepository(db *gorm.DB) repositories.StaffSessionRepository {
+	return &staffSessionRepository{db: db}
+}
+
+func (r *staffSessionRepository) Create(ctx context.Context, session *models.StaffSession) error {
+	if err := dbFromContext(ctx, r.db).Create(session).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Create",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *staffSessionRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*models.StaffSession, error) {
+	var session models.StaffSession
+	if err := dbFromContext(ctx, r.db).
+		Preload("Staff").
+		Where("token_hash = ?", tokenHash).
+		First(&session).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NewErrNotFound("StaffSession", "")
+		}
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByTokenHash",
+			Err:       err,
+		}
+	}
+	return &session, nil
+}
+
+func (r *staffSessionRepository) DeleteByTokenHash(ctx context.Context, tokenHash string) error {
+	if err := dbFromContext(ctx, r.db).Where("token_hash = ?", tokenHash).Delete(&models.StaffSession{}).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "DeleteByTokenHash",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *staffSessionRepository) DeleteByStaffID(ctx context.Context, staffID types.ID) error {
+	if err := dbFromContext(ctx, r.db).Where("staff_id = ?", staffID).Delete(&models.StaffSession{}).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "DeleteByStaffID",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *staffSessionRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	if err := dbFromContext(ctx, r.db).Where("expires_at < ?", now).Delete(&models.StaffSession{}).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "DeleteExpired",
+			Err:       err,
+		}
+	}
+	return nil
+}
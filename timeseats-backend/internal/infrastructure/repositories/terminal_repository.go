@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/models"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+type terminalRepository struct {
+	db *gorm.DB
+}
+
+func NewTerminalRepository(db *gorm.DB) repositories.TerminalRepository {
+	return &terminalRepository{db: db}
+}
+
+func (r *terminalRepository) Create(ctx context.Context, terminal *models.Terminal) error {
+	if err := dbFromContext(ctx, r.db).Create(terminal).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Create",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *terminalRepository) FindByID(ctx context.Context, id types.ID) (*models.Terminal, error) {
+	var terminal models.Terminal
+	if err := dbFromContext(ctx, r.db).First(&terminal, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NewErrNotFound("Terminal", id)
+		}
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByID",
+			Err:       err,
+		}
+	}
+	return &terminal, nil
+}
+
+func (r *terminalRepository) FindAll(ctx context.Context) ([]models.Terminal, error) {
+	var terminals []models.Terminal
+	if err := dbFromContext(ctx, r.db).Order("name").Find(&terminals).Error; err != nil {
+		return nil, &repositories.RepositoryError{
+			Operation: "FindAll",
+			Err:       err,
+		}
+	}
+	return terminals, nil
+}
+
+func (r *terminalRepository) Update(ctx context.Context, terminal *models.Terminal) error {
+	if err := dbFromContext(ctx, r.db).Save(terminal).Error; err != nil {
+		return &repositories.RepositoryError{
+			Operation: "Update",
+			Err:       err,
+		}
+	}
+	return nil
+}
+
+func (r *terminalRepository) Delete(ctx context.Context, id types.ID) error {
+	result := dbFromContext(ctx, r.db).Delete(&models.Terminal{}, "id = ?", id)
+	if result.Error != nil {
+		return &repositories.RepositoryError{
+			Operation: "Delete",
+			Err:       result.Error,
+		}
+	}
+	if result.RowsAffected == 0 {
+		return repositories.NewErrNotFound("Terminal", id)
+	}
+	return nil
+}
+
+func (r *terminalRepository) FindByKeyHash(ctx context.Context, keyHash string) (*models.Terminal, error) {
+	var terminal models.Terminal
+	if err := dbFromContext(ctx, r.db).Where("key_hash = ?", keyHash).First(&terminal).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, repositories.NewErrNotFound("Terminal", "")
+		}
+		return nil, &repositories.RepositoryError{
+			Operation: "FindByKeyHash",
+			Err:       err,
+		}
+	}
+	return &terminal, nil
+}
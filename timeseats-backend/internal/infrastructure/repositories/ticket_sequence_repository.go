@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/repositories"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+	"gorm.io/gorm"
+)
+
+type ticketSequenceRepository struct {
+	db *gorm.DB
+}
+
+func NewTicketSequenceRepository(db *gorm.DB) repositories.TicketSequenceRepository {
+	return &ticketSequenceRepository{db: db}
+}
+
+func (r *ticketSequenceRepository) Next(ctx context.Context, salesSlotID types.ID) (int, error) {
+	var next int
+	if err := dbFromContext(ctx, r.db).Raw(`
+		INSERT INTO ticket_sequences (sales_slot_id, last_number, updated_at)
+		VALUES (?, 1, NOW())
+		ON CONFLICT (sales_slot_id)
+		DO UPDATE SET last_number = ticket_sequences.last_number + 1, updated_at = NOW()
+		RETURNING last_number`, salesSlotID).
+		Scan(&next).Error; err != nil {
+		return 0, &repositories.RepositoryError{
+			Operation: "Next",
+			Err:       err,
+		}
+	}
+	return next, nil
+}
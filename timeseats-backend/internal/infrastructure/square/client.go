@@ -0,0 +1,208 @@
+// Package square takes card payments through the Square Payments API.
+package square
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+const (
+	DefaultBaseURL = "https://connect.squareup.com"
+	apiVersion     = "2025-01-23"
+	requestTimeout = 15 * time.Second
+)
+
+// Config holds the credentials of a Square application. WebhookURL is the
+// notification URL registered for the webhook subscription; Square signs
+// it together with the body.
+type Config struct {
+	BaseURL             string
+	AccessToken         string
+	LocationID          string
+	WebhookSignatureKey string
+	WebhookURL          string
+}
+
+// Client implements services.PaymentGateway for Square.
+type Client struct {
+	config Config
+	http   *http.Client
+}
+
+func NewClient(config Config) *Client {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultBaseURL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	return &Client{
+		config: config,
+		http:   &http.Client{Timeout: requestTimeout},
+	}
+}
+
+type money struct {
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+type payment struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	ReferenceID string `json:"reference_id"`
+	AmountMoney money  `json:"amount_money"`
+}
+
+type createPaymentRequest struct {
+	SourceID       string `json:"source_id"`
+	IdempotencyKey string `json:"idempotency_key"`
+	AmountMoney    money  `json:"amount_money"`
+	ReferenceID    string `json:"reference_id"`
+	LocationID     string `json:"location_id,omitempty"`
+	Autocomplete   bool   `json:"autocomplete"`
+}
+
+type paymentResponse struct {
+	Payment *payment   `json:"payment"`
+	Errors  []apiError `json:"errors"`
+}
+
+type apiError struct {
+	Category string `json:"category"`
+	Code     string `json:"code"`
+	Detail   string `json:"detail"`
+}
+
+// Error is an error answer of the Square API.
+type Error struct {
+	StatusCode int
+	Errors     []apiError
+}
+
+// Declined reports whether the card was refused, as opposed to Square
+// failing or rejecting the request.
+func (e *Error) Declined() bool {
+	for _, err := range e.Errors {
+		if err.Category == "PAYMENT_METHOD_ERROR" {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Error) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("square: HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("square: %s: %s", e.Errors[0].Code, e.Errors[0].Detail)
+}
+
+// CreatePayment charges the card. Yen have no minor unit, so amounts go to
+// Square as they are.
+func (c *Client) CreatePayment(ctx context.Context, request services.GatewayPaymentRequest) (*services.GatewayPayment, error) {
+	body := createPaymentRequest{
+		SourceID:       request.SourceID,
+		IdempotencyKey: request.IdempotencyKey,
+		AmountMoney:    money{Amount: request.Amount, Currency: "JPY"},
+		ReferenceID:    string(request.OrderID),
+		LocationID:     c.config.LocationID,
+		Autocomplete:   true,
+	}
+	return c.do(ctx, http.MethodPost, "/v2/payments", body)
+}
+
+func (c *Client) GetPayment(ctx context.Context, paymentID string) (*services.GatewayPayment, error) {
+	return c.do(ctx, http.MethodGet, "/v2/payments/"+url.PathEscape(paymentID), nil)
+}
+
+type webhookEvent struct {
+	Type string `json:"type"`
+	Data struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+	} `json:"data"`
+}
+
+// ParseWebhook checks the x-square-hmacsha256-signature header, the
+// base64 HMAC-SHA256 of the notification URL followed by the body.
+func (c *Client) ParseWebhook(body []byte, signature string) (string, error) {
+	mac := hmac.New(sha256.New, []byte(c.config.WebhookSignatureKey))
+	mac.Write([]byte(c.config.WebhookURL))
+	mac.Write(body)
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	if c.config.WebhookSignatureKey == "" || !hmac.Equal([]byte(expected), []byte(signature)) {
+		return "", services.ErrInvalidSignature
+	}
+
+	var event webhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return "", fmt.Errorf("square: invalid webhook body: %w", err)
+	}
+	if event.Data.Type != "payment" {
+		return "", nil
+	}
+	return event.Data.ID, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body any) (*services.GatewayPayment, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.config.BaseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.config.AccessToken)
+	req.Header.Set("Square-Version", apiVersion)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("square: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var decoded paymentResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("square: invalid response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	// A declined card is an error answer that still carries the failed
+	// payment.
+	if decoded.Payment == nil {
+		return nil, &Error{StatusCode: resp.StatusCode, Errors: decoded.Errors}
+	}
+	return toGatewayPayment(decoded.Payment), nil
+}
+
+func toGatewayPayment(p *payment) *services.GatewayPayment {
+	status := types.GATEWAY_PAYMENT_PENDING
+	switch p.Status {
+	case "COMPLETED":
+		status = types.GATEWAY_PAYMENT_COMPLETED
+	case "FAILED", "CANCELED":
+		status = types.GATEWAY_PAYMENT_FAILED
+	}
+	return &services.GatewayPayment{
+		ID:      p.ID,
+		OrderID: types.ID(p.ReferenceID),
+		Amount:  p.AmountMoney.Amount,
+		Status:  status,
+	}
+}
@@ -0,0 +1,147 @@
+package square
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/services"
+	"github.com/SeikoStudentCouncil/timeseats-backend/internal/domain/types"
+)
+
+// standIn is a local stand-in for the Square API that keeps the payments
+// created through it.
+type standIn struct {
+	server   *httptest.Server
+	payments map[string]payment
+	requests []createPaymentRequest
+}
+
+func newStandIn(t *testing.T) *standIn {
+	t.Helper()
+	s := &standIn{payments: make(map[string]payment)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v2/payments", func(w http.ResponseWriter, r *http.Request) {
+		var req createPaymentRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		s.requests = append(s.requests, req)
+
+		p := payment{ID: "pay-" + req.IdempotencyKey, Status: "COMPLETED", ReferenceID: req.ReferenceID, AmountMoney: req.AmountMoney}
+		if req.SourceID == "cnon:card-declined" {
+			p.Status = "FAILED"
+			w.WriteHeader(http.StatusPaymentRequired)
+			json.NewEncoder(w).Encode(paymentResponse{Payment: &p, Errors: []apiError{{Category: "PAYMENT_METHOD_ERROR", Code: "CARD_DECLINED"}}})
+			return
+		}
+		s.payments[p.ID] = p
+		json.NewEncoder(w).Encode(paymentResponse{Payment: &p})
+	})
+	mux.HandleFunc("GET /v2/payments/{id}", func(w http.ResponseWriter, r *http.Request) {
+		p, ok := s.payments[r.PathValue("id")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(paymentResponse{Errors: []apiError{{Category: "INVALID_REQUEST_ERROR", Code: "NOT_FOUND"}}})
+			return
+		}
+		json.NewEncoder(w).Encode(paymentResponse{Payment: &p})
+	})
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" || r.Header.Get("Square-Version") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(paymentResponse{Errors: []apiError{{Category: "AUTHENTICATION_ERROR", Code: "UNAUTHORIZED"}}})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *standIn) client() *Client {
+	return NewClient(Config{
+		BaseURL:             s.server.URL,
+		AccessToken:         "test-token",
+		LocationID:          "LOC1",
+		WebhookSignatureKey: "signature-key",
+		WebhookURL:          "https://pos.example.com/api/v1/webhooks/square",
+	})
+}
+
+func TestClient_CreatePayment(t *testing.T) {
+	square := newStandIn(t)
+	client := square.client()
+	ctx := context.Background()
+
+	payment, err := client.CreatePayment(ctx, services.GatewayPaymentRequest{OrderID: "order-1", Amount: 1200, SourceID: "cnon:card-ok", IdempotencyKey: "key-1"})
+	if err != nil {
+		t.Fatalf("CreatePayment failed: %v", err)
+	}
+	if payment.ID != "pay-key-1" || payment.OrderID != "order-1" || payment.Amount != 1200 || payment.Status != types.GATEWAY_PAYMENT_COMPLETED {
+		t.Errorf("Unexpected payment: %+v", payment)
+	}
+	sent := square.requests[0]
+	if sent.ReferenceID != "order-1" || sent.AmountMoney != (money{Amount: 1200, Currency: "JPY"}) || sent.LocationID != "LOC1" || !sent.Autocomplete {
+		t.Errorf("Unexpected request: %+v", sent)
+	}
+
+	fetched, err := client.GetPayment(ctx, payment.ID)
+	if err != nil || *fetched != *payment {
+		t.Errorf("Expected to fetch the same payment, got %+v, %v", fetched, err)
+	}
+
+	_, err = client.CreatePayment(ctx, services.GatewayPaymentRequest{OrderID: "order-2", Amount: 500, SourceID: "cnon:card-declined", IdempotencyKey: "key-2"})
+	if err != nil {
+		t.Fatalf("Expected a declined card to come back as a failed payment, got %v", err)
+	}
+
+	_, err = client.GetPayment(ctx, "unknown")
+	var squareErr *Error
+	if !errors.As(err, &squareErr) || squareErr.StatusCode != http.StatusNotFound || squareErr.Declined() {
+		t.Errorf("Expected a Square error, got %v", err)
+	}
+
+	unauthorized := NewClient(Config{BaseURL: square.server.URL, AccessToken: "wrong"})
+	if _, err := unauthorized.GetPayment(ctx, payment.ID); err == nil {
+		t.Error("Expected a wrong token to fail")
+	}
+}
+
+func TestClient_ParseWebhook(t *testing.T) {
+	client := newStandIn(t).client()
+	body := []byte(`{"merchant_id":"M1","type":"payment.updated","event_id":"e1","data":{"type":"payment","id":"pay-1","object":{"payment":{"id":"pay-1","status":"COMPLETED"}}}}`)
+
+	sign := func(key, url string, body []byte) string {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(url + string(body)))
+		return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+
+	paymentID, err := client.ParseWebhook(body, sign("signature-key", "https://pos.example.com/api/v1/webhooks/square", body))
+	if err != nil || paymentID != "pay-1" {
+		t.Errorf("Expected payment pay-1, got %q, %v", paymentID, err)
+	}
+
+	invalid := []string{
+		"",
+		sign("other-key", "https://pos.example.com/api/v1/webhooks/square", body),
+		sign("signature-key", "https://other.example.com/webhooks", body),
+		sign("signature-key", "https://pos.example.com/api/v1/webhooks/square", []byte(`{"data":{"type":"payment","id":"pay-2"}}`)),
+	}
+	for _, signature := range invalid {
+		if _, err := client.ParseWebhook(body, signature); !errors.Is(err, services.ErrInvalidSignature) {
+			t.Errorf("Expected ErrInvalidSignature for %q, got %v", signature, err)
+		}
+	}
+
+	refund := []byte(`{"type":"refund.updated","data":{"type":"refund","id":"ref-1"}}`)
+	paymentID, err = client.ParseWebhook(refund, sign("signature-key", "https://pos.example.com/api/v1/webhooks/square", refund))
+	if err != nil || paymentID != "" {
+		t.Errorf("Expected other notifications to be ignored, got %q, %v", paymentID, err)
+	}
+}